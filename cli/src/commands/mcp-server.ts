@@ -297,7 +297,7 @@ IMPORTANT GUIDELINES:
           message: args.message,
           claude_session_id: sessionInfo.session_id,
           tool_use_id: sessionInfo.tool_use_id,
-        });
+        }, currentPlan.revision);
 
         return {
           content: [
@@ -19,6 +19,7 @@ interface SendTranscriptParams {
   transcriptPath: string;
   cwd?: string;
   isHook: boolean;
+  hookEventName?: string;
 }
 
 function getGitRemoteUrl(cwd: string): string | null {
@@ -52,7 +53,7 @@ function getGitBranch(cwd: string): string | null {
  * Shared between hook-based and manual invocations.
  */
 async function sendTranscript(params: SendTranscriptParams): Promise<void> {
-  const { sessionId, transcriptPath, cwd, isHook } = params;
+  const { sessionId, transcriptPath, cwd, isHook, hookEventName } = params;
 
   const exitWithError = (message: string) => {
     console.error(message);
@@ -71,7 +72,10 @@ async function sendTranscript(params: SendTranscriptParams): Promise<void> {
   // Get new lines from transcript
   const { lines, totalLineCount } = getNewLines(transcriptPath, sessionId);
 
-  if (lines.length === 0) {
+  // SessionEnd is still sent without new lines so that the server records the end
+  const isSessionEnd = hookEventName === "SessionEnd" && hasCursor(sessionId);
+
+  if (lines.length === 0 && !isSessionEnd) {
     if (!isHook) {
       console.log("[agentrace] No new lines to send.");
     }
@@ -88,7 +92,7 @@ async function sendTranscript(params: SendTranscriptParams): Promise<void> {
     }
   }
 
-  if (transcriptLines.length === 0) {
+  if (transcriptLines.length === 0 && !isSessionEnd) {
     if (!isHook) {
       console.log("[agentrace] No valid transcript lines to send.");
     }
@@ -96,13 +100,20 @@ async function sendTranscript(params: SendTranscriptParams): Promise<void> {
   }
 
   // Extract git info only on first send (when cursor doesn't exist yet)
+  const isFirstSend = !hasCursor(sessionId);
   let gitRemoteUrl: string | undefined;
   let gitBranch: string | undefined;
-  if (cwd && !hasCursor(sessionId)) {
+  if (cwd && isFirstSend) {
     gitRemoteUrl = getGitRemoteUrl(cwd) ?? undefined;
     gitBranch = getGitBranch(cwd) ?? undefined;
   }
 
+  // Visibility requested for the session (private, team or public), also set only
+  // on first send so that later changes made on the server are kept
+  const visibility = isFirstSend
+    ? process.env.AGENTRACE_VISIBILITY || undefined
+    : undefined;
+
   // Send to server
   const result = await sendIngest({
     session_id: sessionId,
@@ -110,6 +121,8 @@ async function sendTranscript(params: SendTranscriptParams): Promise<void> {
     cwd: cwd,
     git_remote_url: gitRemoteUrl,
     git_branch: gitBranch,
+    hook_event_name: hookEventName,
+    visibility,
   });
 
   if (result.ok) {
@@ -187,6 +200,7 @@ export async function sendCommand(): Promise<void> {
     transcriptPath,
     cwd: projectDir,
     isHook: true,
+    hookEventName: data.hook_event_name,
   });
 }
 
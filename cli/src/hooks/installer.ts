@@ -29,6 +29,7 @@ interface ClaudeSettings {
     Stop?: ClaudeHookMatcher[];
     UserPromptSubmit?: ClaudeHookMatcher[];
     SubagentStop?: ClaudeHookMatcher[];
+    SessionEnd?: ClaudeHookMatcher[];
     PreToolUse?: ClaudeHookMatcher[];
     PostToolUse?: ClaudeHookMatcher[];
     [key: string]: ClaudeHookMatcher[] | undefined;
@@ -99,6 +100,11 @@ export function installHooks(options: InstallHooksOptions = {}): { success: bool
       settings.hooks.PostToolUse = [];
     }
 
+    // Add SessionEnd hook (the remaining transcript is sent and the session is marked ended)
+    if (!settings.hooks.SessionEnd) {
+      settings.hooks.SessionEnd = [];
+    }
+
     const hasStopHook = settings.hooks.Stop.some((matcher) =>
       matcher.hooks?.some(isAgentraceHook)
     );
@@ -115,7 +121,11 @@ export function installHooks(options: InstallHooksOptions = {}): { success: bool
       matcher.hooks?.some(isAgentraceHook)
     );
 
-    if (hasStopHook && hasUserPromptSubmitHook && hasSubagentStopHook && hasPostToolUseHook) {
+    const hasSessionEndHook = settings.hooks.SessionEnd.some((matcher) =>
+      matcher.hooks?.some(isAgentraceHook)
+    );
+
+    if (hasStopHook && hasUserPromptSubmitHook && hasSubagentStopHook && hasPostToolUseHook && hasSessionEndHook) {
       return { success: true, message: "Hooks already installed (skipped)" };
     }
 
@@ -143,6 +153,12 @@ export function installHooks(options: InstallHooksOptions = {}): { success: bool
       });
     }
 
+    if (!hasSessionEndHook) {
+      settings.hooks.SessionEnd.push({
+        hooks: [agentraceHook],
+      });
+    }
+
     // Ensure directory exists
     const dir = path.dirname(CLAUDE_SETTINGS_PATH);
     if (!fs.existsSync(dir)) {
@@ -212,6 +228,16 @@ export function uninstallHooks(): { success: boolean; message: string } {
       }
     }
 
+    // Remove agentrace hooks from SessionEnd
+    if (settings.hooks.SessionEnd) {
+      settings.hooks.SessionEnd = settings.hooks.SessionEnd.filter(
+        (matcher) => !matcher.hooks?.some(isAgentraceHook)
+      );
+      if (settings.hooks.SessionEnd.length === 0) {
+        delete settings.hooks.SessionEnd;
+      }
+    }
+
     // Clean up empty hooks object
     if (Object.keys(settings.hooks).length === 0) {
       delete settings.hooks;
@@ -254,7 +280,11 @@ export function checkHooksInstalled(): boolean {
       matcher.hooks?.some(isAgentraceHook)
     );
 
-    return !!hasStopHook && !!hasUserPromptSubmitHook && !!hasSubagentStopHook && !!hasPostToolUseHook;
+    const hasSessionEndHook = settings.hooks?.SessionEnd?.some((matcher) =>
+      matcher.hooks?.some(isAgentraceHook)
+    );
+
+    return !!hasStopHook && !!hasUserPromptSubmitHook && !!hasSubagentStopHook && !!hasPostToolUseHook && !!hasSessionEndHook;
   } catch {
     return false;
   }
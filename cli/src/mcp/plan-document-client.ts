@@ -19,6 +19,7 @@ export interface PlanDocument {
   }[];
   created_at: string;
   updated_at: string;
+  revision: number;
 }
 
 export interface PlanDocumentEvent {
@@ -79,12 +80,14 @@ export class PlanDocumentClient {
   private async request<T>(
     method: string,
     path: string,
-    body?: unknown
+    body?: unknown,
+    extraHeaders: Record<string, string> = {}
   ): Promise<T> {
     const url = `${this.serverUrl}${path}`;
     const headers: Record<string, string> = {
       "Authorization": `Bearer ${this.apiKey}`,
       "Content-Type": "application/json",
+      ...extraHeaders,
     };
 
     const response = await fetch(url, {
@@ -140,8 +143,10 @@ export class PlanDocumentClient {
     return this.request<PlanDocument>("POST", "/api/plans", req);
   }
 
-  async updatePlan(id: string, req: UpdatePlanRequest): Promise<PlanDocument> {
-    return this.request<PlanDocument>("PATCH", `/api/plans/${id}`, req);
+  // revision: the revision the update is based on; the server rejects the update with 409 if the plan has changed since
+  async updatePlan(id: string, req: UpdatePlanRequest, revision?: number): Promise<PlanDocument> {
+    const headers: Record<string, string> = revision !== undefined ? { "If-Match": `"${revision}"` } : {};
+    return this.request<PlanDocument>("PATCH", `/api/plans/${id}`, req, headers);
   }
 
   async deletePlan(id: string): Promise<void> {
@@ -6,6 +6,8 @@ export interface IngestPayload {
   cwd?: string;
   git_remote_url?: string;
   git_branch?: string;
+  hook_event_name?: string;
+  visibility?: string;
 }
 
 export interface IngestResponse {
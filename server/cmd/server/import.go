@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/satetsu888/agentrace/server/internal/api"
+	"github.com/satetsu888/agentrace/server/internal/eventbus"
+	"github.com/satetsu888/agentrace/server/internal/pricing"
+	"github.com/satetsu888/agentrace/server/internal/redact"
+	"github.com/satetsu888/agentrace/server/internal/repository"
+	"github.com/satetsu888/agentrace/server/internal/transcript"
+)
+
+const importUsage = `Usage: agentrace-server import --user <email> <path>...
+
+Imports Claude Code transcripts (~/.claude/projects/**/*.jsonl) as sessions of
+the given user. Directories are searched recursively. Lines that were already
+imported or ingested are skipped, so importing again is safe.
+`
+
+// runImport implements the import command
+func runImport(repos *repository.Repositories, redactor *redact.Redactor, prices *pricing.Table, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	email := flags.String("user", "", "email of the user the sessions belong to")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), importUsage)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if *email == "" || flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	user, err := repos.User.FindByEmail(ctx, *email)
+	if err != nil {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return fmt.Errorf("user not found: %s", *email)
+	}
+
+	var paths, subagentPaths []string
+	for _, root := range flags.Args() {
+		found, err := findTranscripts(root)
+		if err != nil {
+			return err
+		}
+		for _, path := range found {
+			if transcript.IsSubagentFile(path) {
+				subagentPaths = append(subagentPaths, path)
+			} else {
+				paths = append(paths, path)
+			}
+		}
+	}
+	// Subagent transcripts go into their parent session, which takes its times from its own transcript
+	paths = append(paths, subagentPaths...)
+
+	// Nothing subscribes to the bus outside the server; it only satisfies the ingest handler
+	ingest := api.NewIngestHandler(repos, redactor, prices, eventbus.New(eventbus.DefaultHistorySize), 0)
+	importer := api.NewImportHandler(repos, ingest, 0)
+
+	failed := 0
+	for _, path := range paths {
+		result, err := importFile(ctx, importer, path, &user.ID)
+		if err != nil {
+			log.Printf("Failed to import %s: %v", path, err)
+			failed++
+			continue
+		}
+		log.Printf("Imported %s: session %s, %d events created, %d skipped", path, result.SessionID, result.EventsCreated, result.EventsSkipped)
+	}
+
+	log.Printf("Imported %d of %d transcripts", len(paths)-failed, len(paths))
+	if failed > 0 {
+		return fmt.Errorf("%d transcripts failed to import", failed)
+	}
+	return nil
+}
+
+// findTranscripts returns root if it is a file, or the transcripts under it if it is a directory
+func findTranscripts(root string) ([]string, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{root}, nil
+	}
+
+	var paths []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != transcript.Extension {
+			return nil
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	return paths, err
+}
+
+func importFile(ctx context.Context, importer *api.ImportHandler, path string, userID *string) (*api.ImportSessionResponse, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	t, err := transcript.Parse(f, path)
+	if err != nil {
+		return nil, err
+	}
+	return importer.Import(ctx, t, userID)
+}
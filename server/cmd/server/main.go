@@ -5,9 +5,12 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"os"
 
 	"github.com/satetsu888/agentrace/server/internal/api"
 	"github.com/satetsu888/agentrace/server/internal/config"
+	"github.com/satetsu888/agentrace/server/internal/pricing"
+	"github.com/satetsu888/agentrace/server/internal/redact"
 	"github.com/satetsu888/agentrace/server/internal/repository"
 	"github.com/satetsu888/agentrace/server/internal/repository/dynamodb"
 	"github.com/satetsu888/agentrace/server/internal/repository/memory"
@@ -28,8 +31,31 @@ func main() {
 		defer closer.Close()
 	}
 
+	// Initialize secret redaction for ingested transcripts
+	var redactor *redact.Redactor
+	if cfg.RedactionEnabled {
+		redactor, err = redact.New(cfg.RedactionKey, cfg.RedactionPatterns)
+		if err != nil {
+			log.Fatalf("Failed to initialize redaction: %v", err)
+		}
+	}
+
+	// Initialize the model price table for session cost estimation
+	prices, err := pricing.New(cfg.ModelPrices)
+	if err != nil {
+		log.Fatalf("Failed to initialize model prices: %v", err)
+	}
+
+	// Commands other than serving run against the same configuration
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := runImport(repos, redactor, prices, os.Args[2:]); err != nil {
+			log.Fatalf("Import failed: %v", err)
+		}
+		return
+	}
+
 	// Create router
-	router := api.NewRouter(cfg, repos)
+	router := api.NewRouter(cfg, repos, redactor, prices)
 
 	addr := fmt.Sprintf(":%s", cfg.Port)
 	log.Printf("Starting server on %s", addr)
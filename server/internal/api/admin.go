@@ -0,0 +1,461 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+	"github.com/satetsu888/agentrace/server/internal/repository"
+)
+
+// AdminHandler serves the user management endpoints. Routes are guarded by
+// RequireRole(domain.UserRoleAdmin).
+type AdminHandler struct {
+	repos *repository.Repositories
+}
+
+func NewAdminHandler(repos *repository.Repositories) *AdminHandler {
+	return &AdminHandler{repos: repos}
+}
+
+// UpdateUserRequest is the request body for changing a user's role or disabling a user
+type UpdateUserRequest struct {
+	Role     *string `json:"role"`
+	Disabled *bool   `json:"disabled"`
+}
+
+// RevokeUserResponse is the response for revoking a user's credentials
+type RevokeUserResponse struct {
+	RevokedKeys int `json:"revoked_keys"`
+}
+
+// TeamResponse is a team with the IDs of its members
+type TeamResponse struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	MemberIDs []string `json:"member_ids"`
+	CreatedAt string   `json:"created_at"`
+}
+
+// TeamsResponse is the response for listing teams
+type TeamsResponse struct {
+	Teams []*TeamResponse `json:"teams"`
+}
+
+// CreateTeamRequest is the request body for creating a team
+type CreateTeamRequest struct {
+	Name string `json:"name"`
+}
+
+// AddTeamMemberRequest is the request body for adding a user to a team
+type AddTeamMemberRequest struct {
+	UserID string `json:"user_id"`
+}
+
+// UpdateProjectTeamRequest is the request body for assigning a project to a team.
+// A null or empty team_id makes the project visible to everyone again.
+type UpdateProjectTeamRequest struct {
+	TeamID *string `json:"team_id"`
+}
+
+// ListUsers returns all users with their roles and disabled state
+func (h *AdminHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	users, err := h.repos.User.FindAll(ctx)
+	if err != nil {
+		http.Error(w, `{"error": "failed to list users"}`, http.StatusInternalServerError)
+		return
+	}
+
+	resp := UsersResponse{Users: users}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// UpdateUser changes a user's role and/or disabled state
+func (h *AdminHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+
+	var req UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "invalid json"}`, http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.repos.User.FindByID(ctx, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to find user"}`, http.StatusInternalServerError)
+		return
+	}
+	if user == nil {
+		http.Error(w, `{"error": "user not found"}`, http.StatusNotFound)
+		return
+	}
+
+	// Admins cannot lock themselves out, so there is always at least one admin left
+	self := id == GetUserIDFromContext(ctx)
+
+	if req.Role != nil {
+		role := domain.UserRole(*req.Role)
+		if !role.IsValid() {
+			http.Error(w, `{"error": "invalid role: must be admin, member or viewer"}`, http.StatusBadRequest)
+			return
+		}
+		if self && role != user.Role {
+			http.Error(w, `{"error": "cannot change your own role"}`, http.StatusBadRequest)
+			return
+		}
+	}
+	if req.Disabled != nil && *req.Disabled && self {
+		http.Error(w, `{"error": "cannot disable yourself"}`, http.StatusBadRequest)
+		return
+	}
+
+	if req.Role != nil {
+		role := domain.UserRole(*req.Role)
+		if err := h.repos.User.UpdateRole(ctx, id, role); err != nil {
+			http.Error(w, `{"error": "failed to update role"}`, http.StatusInternalServerError)
+			return
+		}
+		user.Role = role
+	}
+
+	if req.Disabled != nil {
+		if err := h.repos.User.UpdateDisabled(ctx, id, *req.Disabled); err != nil {
+			http.Error(w, `{"error": "failed to update user"}`, http.StatusInternalServerError)
+			return
+		}
+		user.Disabled = *req.Disabled
+	}
+
+	resp := MeResponse{User: user}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// RevokeUser deletes all API keys of a user and signs them out of every web session
+func (h *AdminHandler) RevokeUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+
+	user, err := h.repos.User.FindByID(ctx, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to find user"}`, http.StatusInternalServerError)
+		return
+	}
+	if user == nil {
+		http.Error(w, `{"error": "user not found"}`, http.StatusNotFound)
+		return
+	}
+
+	keys, err := h.repos.APIKey.FindByUserID(ctx, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to list keys"}`, http.StatusInternalServerError)
+		return
+	}
+	for _, key := range keys {
+		if err := h.repos.APIKey.Delete(ctx, key.ID); err != nil {
+			http.Error(w, `{"error": "failed to delete key"}`, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := h.repos.WebSession.DeleteByUserID(ctx, id); err != nil {
+		http.Error(w, `{"error": "failed to delete web sessions"}`, http.StatusInternalServerError)
+		return
+	}
+
+	resp := RevokeUserResponse{RevokedKeys: len(keys)}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *AdminHandler) teamToResponse(r *http.Request, team *domain.Team) (*TeamResponse, error) {
+	memberships, err := h.repos.TeamMembership.FindByTeamID(r.Context(), team.ID)
+	if err != nil {
+		return nil, err
+	}
+	memberIDs := make([]string, len(memberships))
+	for i, m := range memberships {
+		memberIDs[i] = m.UserID
+	}
+	return &TeamResponse{
+		ID:        team.ID,
+		Name:      team.Name,
+		MemberIDs: memberIDs,
+		CreatedAt: team.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}, nil
+}
+
+// ListTeams returns all teams with their members
+func (h *AdminHandler) ListTeams(w http.ResponseWriter, r *http.Request) {
+	teams, err := h.repos.Team.FindAll(r.Context())
+	if err != nil {
+		http.Error(w, `{"error": "failed to list teams"}`, http.StatusInternalServerError)
+		return
+	}
+
+	responses := make([]*TeamResponse, len(teams))
+	for i, team := range teams {
+		resp, err := h.teamToResponse(r, team)
+		if err != nil {
+			http.Error(w, `{"error": "failed to list team members"}`, http.StatusInternalServerError)
+			return
+		}
+		responses[i] = resp
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(TeamsResponse{Teams: responses})
+}
+
+// CreateTeam creates a team with no members
+func (h *AdminHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req CreateTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "invalid json"}`, http.StatusBadRequest)
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		http.Error(w, `{"error": "name is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	teams, err := h.repos.Team.FindAll(ctx)
+	if err != nil {
+		http.Error(w, `{"error": "failed to list teams"}`, http.StatusInternalServerError)
+		return
+	}
+	for _, team := range teams {
+		if team.Name == name {
+			http.Error(w, `{"error": "team already exists"}`, http.StatusConflict)
+			return
+		}
+	}
+
+	team := &domain.Team{Name: name}
+	if err := h.repos.Team.Create(ctx, team); err != nil {
+		http.Error(w, `{"error": "failed to create team"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(&TeamResponse{
+		ID:        team.ID,
+		Name:      team.Name,
+		MemberIDs: []string{},
+		CreatedAt: team.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+// DeleteTeam deletes a team. Its projects become visible to everyone.
+func (h *AdminHandler) DeleteTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+
+	team, err := h.repos.Team.FindByID(ctx, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to find team"}`, http.StatusInternalServerError)
+		return
+	}
+	if team == nil {
+		http.Error(w, `{"error": "team not found"}`, http.StatusNotFound)
+		return
+	}
+
+	// Not every backend cascades, so release the projects and memberships first
+	projects, err := h.repos.Project.FindTeamOwned(ctx)
+	if err != nil {
+		http.Error(w, `{"error": "failed to list projects"}`, http.StatusInternalServerError)
+		return
+	}
+	for _, p := range projects {
+		if p.TeamID != nil && *p.TeamID == id {
+			if err := h.repos.Project.UpdateTeamID(ctx, p.ID, nil); err != nil {
+				http.Error(w, `{"error": "failed to update project"}`, http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+	if err := h.repos.TeamMembership.DeleteByTeamID(ctx, id); err != nil {
+		http.Error(w, `{"error": "failed to delete team members"}`, http.StatusInternalServerError)
+		return
+	}
+	if err := h.repos.Team.Delete(ctx, id); err != nil {
+		http.Error(w, `{"error": "failed to delete team"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddTeamMember adds a user to a team
+func (h *AdminHandler) AddTeamMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+
+	var req AddTeamMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "invalid json"}`, http.StatusBadRequest)
+		return
+	}
+	if req.UserID == "" {
+		http.Error(w, `{"error": "user_id is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	team, err := h.repos.Team.FindByID(ctx, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to find team"}`, http.StatusInternalServerError)
+		return
+	}
+	if team == nil {
+		http.Error(w, `{"error": "team not found"}`, http.StatusNotFound)
+		return
+	}
+	user, err := h.repos.User.FindByID(ctx, req.UserID)
+	if err != nil {
+		http.Error(w, `{"error": "failed to find user"}`, http.StatusInternalServerError)
+		return
+	}
+	if user == nil {
+		http.Error(w, `{"error": "user not found"}`, http.StatusNotFound)
+		return
+	}
+
+	membership := &domain.TeamMembership{TeamID: team.ID, UserID: user.ID}
+	if err := h.repos.TeamMembership.Create(ctx, membership); err != nil {
+		http.Error(w, `{"error": "failed to add team member"}`, http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := h.teamToResponse(r, team)
+	if err != nil {
+		http.Error(w, `{"error": "failed to list team members"}`, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// RemoveTeamMember removes a user from a team
+func (h *AdminHandler) RemoveTeamMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	team, err := h.repos.Team.FindByID(ctx, vars["id"])
+	if err != nil {
+		http.Error(w, `{"error": "failed to find team"}`, http.StatusInternalServerError)
+		return
+	}
+	if team == nil {
+		http.Error(w, `{"error": "team not found"}`, http.StatusNotFound)
+		return
+	}
+
+	if err := h.repos.TeamMembership.Delete(ctx, team.ID, vars["userId"]); err != nil {
+		http.Error(w, `{"error": "failed to remove team member"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateProjectTeam assigns a project to a team, or makes it visible to everyone
+func (h *AdminHandler) UpdateProjectTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+
+	var req UpdateProjectTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "invalid json"}`, http.StatusBadRequest)
+		return
+	}
+
+	if id == domain.DefaultProjectID {
+		http.Error(w, `{"error": "the default project cannot belong to a team"}`, http.StatusBadRequest)
+		return
+	}
+	project, err := h.repos.Project.FindByID(ctx, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch project"}`, http.StatusInternalServerError)
+		return
+	}
+	if project == nil {
+		http.Error(w, `{"error": "project not found"}`, http.StatusNotFound)
+		return
+	}
+
+	var teamID *string
+	if req.TeamID != nil && *req.TeamID != "" {
+		team, err := h.repos.Team.FindByID(ctx, *req.TeamID)
+		if err != nil {
+			http.Error(w, `{"error": "failed to find team"}`, http.StatusInternalServerError)
+			return
+		}
+		if team == nil {
+			http.Error(w, `{"error": "team not found"}`, http.StatusNotFound)
+			return
+		}
+		teamID = &team.ID
+	}
+
+	if err := h.repos.Project.UpdateTeamID(ctx, id, teamID); err != nil {
+		http.Error(w, `{"error": "failed to update project"}`, http.StatusInternalServerError)
+		return
+	}
+
+	resp := ProjectResponse{
+		ID:                     project.ID,
+		CanonicalGitRepository: project.CanonicalGitRepository,
+		TeamID:                 teamID,
+		CreatedAt:              project.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// DeleteSession deletes a session with its events and the file activities and
+// tool calls derived from them, in one transaction
+func (h *AdminHandler) DeleteSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+
+	session, err := h.repos.Session.FindByID(ctx, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch session"}`, http.StatusInternalServerError)
+		return
+	}
+	if session == nil {
+		http.Error(w, `{"error": "session not found"}`, http.StatusNotFound)
+		return
+	}
+
+	err = h.repos.Tx.RunInTx(ctx, func(ctx context.Context) error {
+		if err := h.repos.ToolCall.DeleteBySessionID(ctx, id); err != nil {
+			return err
+		}
+		if err := h.repos.FileActivity.DeleteBySessionID(ctx, id); err != nil {
+			return err
+		}
+		if err := h.repos.Event.DeleteBySessionID(ctx, id); err != nil {
+			return err
+		}
+		return h.repos.Session.Delete(ctx, id)
+	})
+	if err != nil {
+		http.Error(w, `{"error": "failed to delete session"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+func TestAdminDeleteSession(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	_, adminKey := s.createUser("admin@example.com", domain.UserRoleAdmin)
+	_, memberKey := s.createUser("member@example.com", domain.UserRoleMember)
+
+	rec := s.do(http.MethodPost, "/api/ingest", memberKey, map[string]interface{}{
+		"session_id": "claude-delete",
+		"cwd":        "/work/repo",
+		"transcript_lines": []map[string]interface{}{
+			{"uuid": "u1", "type": "assistant", "message": map[string]interface{}{"role": "assistant", "content": []map[string]interface{}{
+				{"type": "tool_use", "id": "toolu_1", "name": "Write", "input": map[string]interface{}{"file_path": "/work/repo/main.go", "content": "package main"}},
+			}}},
+		},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("ingest: status %d: %s", rec.Code, rec.Body.String())
+	}
+	session, err := s.repos.Session.FindByClaudeSessionID(ctx, "claude-delete")
+	if err != nil || session == nil {
+		t.Fatalf("find session: %v", err)
+	}
+	counts := func() (events, activities, calls int) {
+		t.Helper()
+		e, err := s.repos.Event.FindBySessionID(ctx, session.ID)
+		if err != nil {
+			t.Fatalf("find events: %v", err)
+		}
+		a, err := s.repos.FileActivity.FindBySessionID(ctx, session.ID)
+		if err != nil {
+			t.Fatalf("find file activities: %v", err)
+		}
+		c, err := s.repos.ToolCall.FindBySessionID(ctx, session.ID)
+		if err != nil {
+			t.Fatalf("find tool calls: %v", err)
+		}
+		return len(e), len(a), len(c)
+	}
+	if events, activities, calls := counts(); events != 1 || activities != 1 || calls != 1 {
+		t.Fatalf("before delete: %d events, %d file activities, %d tool calls, want 1 of each", events, activities, calls)
+	}
+
+	path := "/api/admin/sessions/" + session.ID
+	if rec := s.do(http.MethodDelete, path, memberKey, nil); rec.Code != http.StatusForbidden {
+		t.Errorf("member: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if rec := s.do(http.MethodDelete, path, adminKey, nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("admin: status %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if found, err := s.repos.Session.FindByID(ctx, session.ID); err != nil || found != nil {
+		t.Errorf("session after delete = %v, %v; want nil", found, err)
+	}
+	if events, activities, calls := counts(); events != 0 || activities != 0 || calls != 0 {
+		t.Errorf("after delete: %d events, %d file activities, %d tool calls, want none", events, activities, calls)
+	}
+	if rec := s.do(http.MethodDelete, path, adminKey, nil); rec.Code != http.StatusNotFound {
+		t.Errorf("second delete: status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
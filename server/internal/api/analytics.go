@@ -0,0 +1,138 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+	"github.com/satetsu888/agentrace/server/internal/repository"
+)
+
+// maxAnalyticsBuckets caps the length of a series (a little over a year of days)
+const maxAnalyticsBuckets = 400
+
+type AnalyticsHandler struct {
+	repos *repository.Repositories
+}
+
+func NewAnalyticsHandler(repos *repository.Repositories) *AnalyticsHandler {
+	return &AnalyticsHandler{repos: repos}
+}
+
+// Response types
+
+type AnalyticsBucketResponse struct {
+	Start             string `json:"start"`
+	SessionsStarted   int    `json:"sessions_started"`
+	EventsIngested    int    `json:"events_ingested"`
+	ActiveUsers       int    `json:"active_users"`
+	ToolInvocations   int    `json:"tool_invocations"`
+	PlanStatusChanges int    `json:"plan_status_changes"`
+}
+
+type AnalyticsResponse struct {
+	Bucket string                     `json:"bucket"`
+	From   string                     `json:"from"`
+	To     string                     `json:"to"`
+	Series []*AnalyticsBucketResponse `json:"series"`
+}
+
+// Get returns activity counts bucketed by day or week.
+// from and to are inclusive UTC dates (YYYY-MM-DD); project_id and user_id narrow the scope.
+// Projects hidden from the reader are left out.
+func (h *AnalyticsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	params := r.URL.Query()
+
+	bucket := domain.AnalyticsBucketDay
+	if b := params.Get("bucket"); b != "" {
+		bucket = domain.AnalyticsBucketSize(b)
+		if !bucket.IsValid() {
+			http.Error(w, `{"error": "invalid bucket: must be day or week"}`, http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Default range: the last 30 days, or the last 12 weeks
+	to := domain.AnalyticsBucketDay.Truncate(time.Now())
+	if s := params.Get("to"); s != "" {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			http.Error(w, `{"error": "invalid to: must be YYYY-MM-DD"}`, http.StatusBadRequest)
+			return
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -29)
+	if bucket == domain.AnalyticsBucketWeek {
+		from = bucket.Truncate(to).AddDate(0, 0, -7*11)
+	}
+	if s := params.Get("from"); s != "" {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			http.Error(w, `{"error": "invalid from: must be YYYY-MM-DD"}`, http.StatusBadRequest)
+			return
+		}
+		from = t
+	}
+	if from.After(to) {
+		http.Error(w, `{"error": "from must not be after to"}`, http.StatusBadRequest)
+		return
+	}
+
+	viewer, err := sessionViewer(ctx, h.repos)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch analytics"}`, http.StatusInternalServerError)
+		return
+	}
+	projectID := params.Get("project_id")
+	if projectID != "" && !viewer.CanViewProject(projectID) {
+		http.Error(w, `{"error": "project not found"}`, http.StatusNotFound)
+		return
+	}
+
+	query := domain.AnalyticsQuery{
+		Bucket:            bucket,
+		From:              from,
+		To:                to.AddDate(0, 0, 1), // to is inclusive
+		ProjectID:         projectID,
+		UserID:            params.Get("user_id"),
+		ExcludeProjectIDs: viewer.HiddenProjectIDs,
+	}
+	buckets := 0
+	for start := bucket.Truncate(query.From); start.Before(query.To); start = bucket.Next(start) {
+		if buckets++; buckets > maxAnalyticsBuckets {
+			http.Error(w, `{"error": "date range too large"}`, http.StatusBadRequest)
+			return
+		}
+	}
+
+	series, err := h.repos.Analytics.GetSeries(ctx, query)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch analytics"}`, http.StatusInternalServerError)
+		return
+	}
+
+	responses := make([]*AnalyticsBucketResponse, len(series))
+	for i, b := range series {
+		responses[i] = &AnalyticsBucketResponse{
+			Start:             b.Start.Format("2006-01-02"),
+			SessionsStarted:   b.SessionsStarted,
+			EventsIngested:    b.EventsIngested,
+			ActiveUsers:       b.ActiveUsers,
+			ToolInvocations:   b.ToolInvocations,
+			PlanStatusChanges: b.PlanStatusChanges,
+		}
+	}
+
+	response := AnalyticsResponse{
+		Bucket: string(bucket),
+		From:   from.Format("2006-01-02"),
+		To:     to.Format("2006-01-02"),
+		Series: responses,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/satetsu888/agentrace/server/internal/config"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+	"github.com/satetsu888/agentrace/server/internal/repository"
+	"github.com/satetsu888/agentrace/server/internal/repository/memory"
+)
+
+// testServer is the router over in-memory repositories
+type testServer struct {
+	t       *testing.T
+	cfg     *config.Config
+	repos   *repository.Repositories
+	handler http.Handler
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	cfg := &config.Config{MaxIngestBodySize: 64 << 20}
+	repos := memory.NewRepositories()
+	return &testServer{t: t, cfg: cfg, repos: repos, handler: NewRouter(cfg, repos, nil, nil)}
+}
+
+// createUser stores a user with the role and returns it with an API key of the scopes
+func (s *testServer) createUser(email string, role domain.UserRole, scopes ...domain.APIKeyScope) (*domain.User, string) {
+	s.t.Helper()
+	ctx := context.Background()
+	user := &domain.User{Email: email, Role: role}
+	if err := s.repos.User.Create(ctx, user); err != nil {
+		s.t.Fatalf("create user: %v", err)
+	}
+
+	rawKey, lookupID, err := generateAPIKey()
+	if err != nil {
+		s.t.Fatalf("generate api key: %v", err)
+	}
+	key := &domain.APIKey{
+		UserID:    user.ID,
+		Name:      "test",
+		LookupID:  lookupID,
+		KeyHash:   hashAPIKey(rawKey),
+		KeyPrefix: rawKey[:12],
+		Scopes:    scopes,
+	}
+	if err := s.repos.APIKey.Create(ctx, key); err != nil {
+		s.t.Fatalf("create api key: %v", err)
+	}
+	return user, rawKey
+}
+
+// createTeamProject stores a project owned by a new team without members
+func (s *testServer) createTeamProject(repo string) *domain.Project {
+	s.t.Helper()
+	ctx := context.Background()
+	team := &domain.Team{Name: repo}
+	if err := s.repos.Team.Create(ctx, team); err != nil {
+		s.t.Fatalf("create team: %v", err)
+	}
+	project := &domain.Project{CanonicalGitRepository: repo, TeamID: &team.ID}
+	if err := s.repos.Project.Create(ctx, project); err != nil {
+		s.t.Fatalf("create project: %v", err)
+	}
+	return project
+}
+
+// createSession stores a session of owner in the project
+func (s *testServer) createSession(owner *domain.User, projectID string, visibility domain.SessionVisibility) *domain.Session {
+	s.t.Helper()
+	session := &domain.Session{
+		ClaudeSessionID: "claude-" + owner.Email + "-" + projectID,
+		ProjectID:       projectID,
+		UserID:          &owner.ID,
+		Visibility:      visibility,
+	}
+	if err := s.repos.Session.Create(context.Background(), session); err != nil {
+		s.t.Fatalf("create session: %v", err)
+	}
+	return session
+}
+
+// do sends a request with the API key (none if empty) and a JSON body (none if nil)
+func (s *testServer) do(method, path, apiKey string, body interface{}) *httptest.ResponseRecorder {
+	s.t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			s.t.Fatalf("encode body: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// decode decodes a JSON response body into v
+func (s *testServer) decode(rec *httptest.ResponseRecorder, v interface{}) {
+	s.t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		s.t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+	}
+}
@@ -0,0 +1,243 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+	"github.com/satetsu888/agentrace/server/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// API key format: agtr_<lookup ID>_<secret>
+//   - lookup ID: apiKeyLookupIDLength random bytes, hex-encoded (indexed, not secret)
+//   - secret:    apiKeyLength random bytes, base64url-encoded
+//
+// The stored KeyHash is a SHA-256 verifier of the whole key. Because the secret is
+// high-entropy, a fast hash is sufficient and authentication needs a single indexed read.
+//
+// Legacy keys (agtr_<secret>, bcrypt-hashed) have no lookup ID. They are found once by
+// their display prefix, then upgraded in place with a lookup ID derived from the key
+// itself and a SHA-256 verifier, so subsequent requests take the fast path.
+const apiKeyLookupIDLength = 8
+
+// generateAPIKey generates a new API key and returns it with its lookup ID
+func generateAPIKey() (string, string, error) {
+	lookupBytes := make([]byte, apiKeyLookupIDLength)
+	if _, err := rand.Read(lookupBytes); err != nil {
+		return "", "", err
+	}
+	secretBytes := make([]byte, apiKeyLength)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", err
+	}
+	lookupID := hex.EncodeToString(lookupBytes)
+	return apiKeyPrefix + lookupID + "_" + base64.RawURLEncoding.EncodeToString(secretBytes), lookupID, nil
+}
+
+// hashAPIKey returns the SHA-256 verifier of an API key
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// parseAPIKeyLookupID extracts the lookup ID embedded in an API key.
+// Returns false for legacy keys that do not embed one.
+func parseAPIKeyLookupID(rawKey string) (string, bool) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return "", false
+	}
+	rest := strings.TrimPrefix(rawKey, apiKeyPrefix)
+	idLen := apiKeyLookupIDLength * 2
+	if len(rest) <= idLen+1 || rest[idLen] != '_' {
+		return "", false
+	}
+	lookupID := rest[:idLen]
+	if _, err := hex.DecodeString(lookupID); err != nil {
+		return "", false
+	}
+	return lookupID, true
+}
+
+// isLegacyAPIKey reports whether a token has the exact shape of a legacy key
+// (agtr_ followed by apiKeyLength base64url-encoded bytes)
+func isLegacyAPIKey(rawKey string) bool {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return false
+	}
+	secret := strings.TrimPrefix(rawKey, apiKeyPrefix)
+	if len(secret) != base64.RawURLEncoding.EncodedLen(apiKeyLength) {
+		return false
+	}
+	_, err := base64.RawURLEncoding.DecodeString(secret)
+	return err == nil
+}
+
+// legacyAPIKeyLookupID derives the lookup ID assigned to a legacy key when it is upgraded
+func legacyAPIKeyLookupID(rawKey string) string {
+	return hashAPIKey(rawKey)[:apiKeyLookupIDLength*2]
+}
+
+// verifyAPIKey checks a raw key against the stored verifier (SHA-256, or bcrypt for legacy keys)
+func verifyAPIKey(key *domain.APIKey, rawKey string) bool {
+	if strings.HasPrefix(key.KeyHash, "$2") {
+		return bcrypt.CompareHashAndPassword([]byte(key.KeyHash), []byte(rawKey)) == nil
+	}
+	return subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashAPIKey(rawKey))) == 1
+}
+
+// apiKeyAllows reports whether the key's scopes cover the request.
+// Keys without scopes can do everything their user can; no scope covers the admin API.
+func apiKeyAllows(key *domain.APIKey, r *http.Request) bool {
+	if !key.IsScoped() {
+		return true
+	}
+	path := r.URL.Path
+	if path == "/api/admin" || strings.HasPrefix(path, "/api/admin/") {
+		return false
+	}
+	for _, scope := range key.Scopes {
+		switch scope {
+		case domain.APIKeyScopeRead:
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				return true
+			}
+		case domain.APIKeyScopeIngest:
+			if path == "/api/ingest" || path == "/api/import" {
+				return true
+			}
+		case domain.APIKeyScopePlans:
+			if path == "/api/plans" || strings.HasPrefix(path, "/api/plans/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// legacyKeyPrefix returns the display prefix stored with a legacy key
+func legacyKeyPrefix(rawKey string) string {
+	return rawKey[:12] + "..."
+}
+
+// APIKeyFinder resolves raw API keys to their stored records. It keeps the
+// legacy keys indexed by display prefix, loaded once: keys are never created
+// without a lookup ID, so that set only shrinks as keys are upgraded, and a
+// token matching no legacy key is rejected without a query or a bcrypt compare.
+type APIKeyFinder struct {
+	repos *repository.Repositories
+
+	mu           sync.Mutex
+	legacyLoaded bool
+	legacy       map[string][]string // display prefix -> IDs of legacy keys not yet upgraded
+}
+
+func NewAPIKeyFinder(repos *repository.Repositories) *APIKeyFinder {
+	return &APIKeyFinder{repos: repos}
+}
+
+// Find resolves a raw API key to its stored record.
+// Returns nil if the key is unknown or does not verify.
+func (f *APIKeyFinder) Find(ctx context.Context, rawKey string) (*domain.APIKey, error) {
+	lookupID, ok := parseAPIKeyLookupID(rawKey)
+	if !ok {
+		// Anything else is rejected without touching the legacy keys
+		if !isLegacyAPIKey(rawKey) {
+			return nil, nil
+		}
+		lookupID = legacyAPIKeyLookupID(rawKey)
+	}
+
+	key, err := f.repos.APIKey.FindByLookupID(ctx, lookupID)
+	if err != nil {
+		return nil, err
+	}
+	if key != nil {
+		if !verifyAPIKey(key, rawKey) {
+			return nil, nil
+		}
+		return key, nil
+	}
+
+	// Keys with an embedded lookup ID are never stored without one
+	if ok {
+		return nil, nil
+	}
+
+	return f.findLegacy(ctx, rawKey)
+}
+
+// findLegacy checks the legacy keys with the token's display prefix (almost always
+// at most one) and upgrades the matching key
+func (f *APIKeyFinder) findLegacy(ctx context.Context, rawKey string) (*domain.APIKey, error) {
+	prefix := legacyKeyPrefix(rawKey)
+	ids, err := f.legacyKeyIDs(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		// Read the key again: it may have been deleted or upgraded since the index was loaded
+		key, err := f.repos.APIKey.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if key == nil || key.LookupID != "" {
+			f.forgetLegacy(prefix, id)
+			continue
+		}
+		if !verifyAPIKey(key, rawKey) {
+			continue
+		}
+
+		// Upgrade so the next request is a single indexed read
+		lookupID := legacyAPIKeyLookupID(rawKey)
+		keyHash := hashAPIKey(rawKey)
+		if err := f.repos.APIKey.UpdateLookupID(ctx, key.ID, lookupID, keyHash); err == nil {
+			key.LookupID = lookupID
+			key.KeyHash = keyHash
+			f.forgetLegacy(prefix, id)
+		}
+		return key, nil
+	}
+	return nil, nil
+}
+
+// legacyKeyIDs returns the IDs of the legacy keys with the display prefix,
+// loading the legacy keys on first use
+func (f *APIKeyFinder) legacyKeyIDs(ctx context.Context, prefix string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.legacyLoaded {
+		keys, err := f.repos.APIKey.FindLegacy(ctx)
+		if err != nil {
+			return nil, err
+		}
+		f.legacy = make(map[string][]string)
+		for _, key := range keys {
+			f.legacy[key.KeyPrefix] = append(f.legacy[key.KeyPrefix], key.ID)
+		}
+		f.legacyLoaded = true
+	}
+	return slices.Clone(f.legacy[prefix]), nil
+}
+
+// forgetLegacy removes a key that is no longer a legacy key from the index
+func (f *APIKeyFinder) forgetLegacy(prefix, id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.legacy[prefix] = slices.DeleteFunc(f.legacy[prefix], func(legacyID string) bool { return legacyID == id })
+	if len(f.legacy[prefix]) == 0 {
+		delete(f.legacy, prefix)
+	}
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+	"github.com/satetsu888/agentrace/server/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAPIKeyScopes(t *testing.T) {
+	s := newTestServer(t)
+	_, readKey := s.createUser("admin@example.com", domain.UserRoleAdmin, domain.APIKeyScopeRead)
+	_, fullKey := s.createUser("other-admin@example.com", domain.UserRoleAdmin)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		key    string
+		want   int
+	}{
+		{name: "read key lists sessions", method: http.MethodGet, path: "/api/sessions", key: readKey, want: http.StatusOK},
+		{name: "read key cannot list users as admin", method: http.MethodGet, path: "/api/admin/users", key: readKey, want: http.StatusForbidden},
+		{name: "read key cannot list teams as admin", method: http.MethodGet, path: "/api/admin/teams", key: readKey, want: http.StatusForbidden},
+		{name: "read key cannot create plans", method: http.MethodPost, path: "/api/plans", key: readKey, want: http.StatusForbidden},
+		{name: "unscoped key lists users as admin", method: http.MethodGet, path: "/api/admin/users", key: fullKey, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.do(tt.method, tt.path, tt.key, nil)
+			if rec.Code != tt.want {
+				t.Errorf("%s %s status = %d, want %d: %s", tt.method, tt.path, rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+// countingAPIKeyRepository counts the loads of the legacy keys
+type countingAPIKeyRepository struct {
+	repository.APIKeyRepository
+	findLegacyCalls int
+}
+
+func (r *countingAPIKeyRepository) FindLegacy(ctx context.Context) ([]*domain.APIKey, error) {
+	r.findLegacyCalls++
+	return r.APIKeyRepository.FindLegacy(ctx)
+}
+
+// legacyAPIKey returns a key in the format used before keys embedded a lookup ID
+func legacyAPIKey(t *testing.T) string {
+	t.Helper()
+	secret := make([]byte, apiKeyLength)
+	if _, err := rand.Read(secret); err != nil {
+		t.Fatalf("rand: %v", err)
+	}
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+}
+
+func TestAPIKeyFinder_Legacy(t *testing.T) {
+	s := newTestServer(t)
+	keys := &countingAPIKeyRepository{APIKeyRepository: s.repos.APIKey}
+	s.repos.APIKey = keys
+	user, _ := s.createUser("member@example.com", domain.UserRoleMember)
+
+	rawKey := legacyAPIKey(t)
+	hash, err := bcrypt.GenerateFromPassword([]byte(rawKey), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt: %v", err)
+	}
+	legacy := &domain.APIKey{UserID: user.ID, Name: "legacy", KeyHash: string(hash), KeyPrefix: legacyKeyPrefix(rawKey)}
+	if err := keys.Create(context.Background(), legacy); err != nil {
+		t.Fatalf("create api key: %v", err)
+	}
+
+	// Unknown legacy-shaped tokens are rejected from the index loaded once
+	for i := 0; i < 3; i++ {
+		if rec := s.do(http.MethodPost, "/api/plans", legacyAPIKey(t), map[string]string{}); rec.Code != http.StatusUnauthorized {
+			t.Errorf("unknown key status = %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+	}
+	if keys.findLegacyCalls != 1 {
+		t.Errorf("FindLegacy() called %d times, want 1", keys.findLegacyCalls)
+	}
+
+	// The legacy key is accepted and upgraded to an indexed lookup
+	for i := 0; i < 2; i++ {
+		if rec := s.do(http.MethodGet, "/api/sessions", rawKey, nil); rec.Code != http.StatusOK {
+			t.Errorf("legacy key status = %d, want %d", rec.Code, http.StatusOK)
+		}
+	}
+	upgraded, err := keys.FindByID(context.Background(), legacy.ID)
+	if err != nil {
+		t.Fatalf("find api key: %v", err)
+	}
+	if upgraded.LookupID != legacyAPIKeyLookupID(rawKey) {
+		t.Errorf("LookupID = %q, want %q", upgraded.LookupID, legacyAPIKeyLookupID(rawKey))
+	}
+	if keys.findLegacyCalls != 1 {
+		t.Errorf("FindLegacy() called %d times, want 1", keys.findLegacyCalls)
+	}
+}
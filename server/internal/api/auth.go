@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -20,17 +21,18 @@ const (
 	apiKeyPrefix       = "agtr_"
 	apiKeyLength       = 32
 	sessionTokenLength = 32
-	sessionDuration    = 7 * 24 * time.Hour   // 7 days
-	webSessionDuration = 10 * time.Minute     // 10 minutes for CLI login
+	sessionDuration    = 7 * 24 * time.Hour // 7 days
+	webSessionDuration = 10 * time.Minute   // 10 minutes for CLI login
 )
 
 type AuthHandler struct {
-	cfg   *config.Config
-	repos *repository.Repositories
+	cfg     *config.Config
+	repos   *repository.Repositories
+	apiKeys *APIKeyFinder
 }
 
-func NewAuthHandler(cfg *config.Config, repos *repository.Repositories) *AuthHandler {
-	return &AuthHandler{cfg: cfg, repos: repos}
+func NewAuthHandler(cfg *config.Config, repos *repository.Repositories, apiKeys *APIKeyFinder) *AuthHandler {
+	return &AuthHandler{cfg: cfg, repos: repos, apiKeys: apiKeys}
 }
 
 // RegisterRequest is the request body for user registration
@@ -66,9 +68,12 @@ type WebSessionResponse struct {
 	ExpiresAt time.Time `json:"expires_at"`
 }
 
-// CreateKeyRequest is the request body for creating an API key
+// CreateKeyRequest is the request body for creating an API key.
+// Without scopes the key can do everything its user can; without expires_at it never expires.
 type CreateKeyRequest struct {
-	Name string `json:"name"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`     // ingest, plans and/or read
+	ExpiresAt *time.Time `json:"expires_at"` // RFC 3339
 }
 
 // CreateKeyResponse is the response for creating an API key
@@ -82,10 +87,30 @@ type APIKeyInfo struct {
 	ID         string     `json:"id"`
 	Name       string     `json:"name"`
 	KeyPrefix  string     `json:"key_prefix"`
+	Scopes     []string   `json:"scopes"` // empty = full access
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	Expired    bool       `json:"expired"`
 	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
 	CreatedAt  time.Time  `json:"created_at"`
 }
 
+func apiKeyToInfo(k *domain.APIKey) *APIKeyInfo {
+	scopes := make([]string, len(k.Scopes))
+	for i, s := range k.Scopes {
+		scopes[i] = string(s)
+	}
+	return &APIKeyInfo{
+		ID:         k.ID,
+		Name:       k.Name,
+		KeyPrefix:  k.KeyPrefix,
+		Scopes:     scopes,
+		ExpiresAt:  k.ExpiresAt,
+		Expired:    k.IsExpired(),
+		LastUsedAt: k.LastUsedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
 // ListKeysResponse is the response for listing API keys
 type ListKeysResponse struct {
 	Keys []*APIKeyInfo `json:"keys"`
@@ -101,13 +126,17 @@ type UsersResponse struct {
 	Users []*domain.User `json:"users"`
 }
 
-// generateAPIKey generates a new API key
-func generateAPIKey() (string, error) {
-	bytes := make([]byte, apiKeyLength)
-	if _, err := rand.Read(bytes); err != nil {
-		return "", err
-	}
-	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(bytes), nil
+// MemberResponse is a user as listed to every reader (without role, disabled state or preferences)
+type MemberResponse struct {
+	ID          string    `json:"id"`
+	Email       string    `json:"email"`
+	DisplayName string    `json:"display_name"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// MembersResponse is the response for listing users publicly
+type MembersResponse struct {
+	Users []*MemberResponse `json:"users"`
 }
 
 // generateToken generates a random token
@@ -119,15 +148,6 @@ func generateToken() (string, error) {
 	return base64.RawURLEncoding.EncodeToString(bytes), nil
 }
 
-// hashAPIKey hashes an API key using bcrypt
-func hashAPIKey(key string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(key), bcrypt.DefaultCost)
-	if err != nil {
-		return "", err
-	}
-	return string(hash), nil
-}
-
 // hashPassword hashes a password using bcrypt
 func hashPassword(password string) (string, error) {
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -142,6 +162,22 @@ func checkPassword(password, hash string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
 }
 
+// newUserRole returns the role of a new account: admin for ADMIN_EMAILS and for the
+// first user of the installation, member otherwise
+func (h *AuthHandler) newUserRole(ctx context.Context, email string) (domain.UserRole, error) {
+	if h.cfg.IsAdminEmail(email) {
+		return domain.UserRoleAdmin, nil
+	}
+	users, err := h.repos.User.FindAll(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(users) == 0 {
+		return domain.UserRoleAdmin, nil
+	}
+	return domain.DefaultUserRole, nil
+}
+
 // Register handles user registration
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	var req RegisterRequest
@@ -165,6 +201,11 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.cfg.IsEmailAllowed(req.Email) {
+		http.Error(w, `{"error": "email domain is not allowed"}`, http.StatusForbidden)
+		return
+	}
+
 	ctx := r.Context()
 
 	// Check if email is already registered
@@ -185,9 +226,16 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	role, err := h.newUserRole(ctx, req.Email)
+	if err != nil {
+		http.Error(w, `{"error": "failed to check users"}`, http.StatusInternalServerError)
+		return
+	}
+
 	// Create user (DisplayName is empty, will show email)
 	user := &domain.User{
 		Email: req.Email,
+		Role:  role,
 	}
 	if err := h.repos.User.Create(ctx, user); err != nil {
 		http.Error(w, `{"error": "failed to create user"}`, http.StatusInternalServerError)
@@ -287,6 +335,11 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if user.Disabled {
+		http.Error(w, `{"error": "user is disabled"}`, http.StatusForbidden)
+		return
+	}
+
 	// Create web session
 	sessionToken, err := generateToken()
 	if err != nil {
@@ -344,6 +397,20 @@ func (h *AuthHandler) LoginWithAPIKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if user.Disabled {
+		http.Error(w, `{"error": "user is disabled"}`, http.StatusForbidden)
+		return
+	}
+	if apiKey.IsExpired() {
+		http.Error(w, `{"error": "api key expired"}`, http.StatusUnauthorized)
+		return
+	}
+	// A web session can do everything the user can, so scoped keys cannot open one
+	if apiKey.IsScoped() {
+		http.Error(w, `{"error": "api key is limited to its scopes"}`, http.StatusForbidden)
+		return
+	}
+
 	// Update last used at
 	_ = h.repos.APIKey.UpdateLastUsedAt(ctx, apiKey.ID)
 
@@ -384,23 +451,16 @@ func (h *AuthHandler) LoginWithAPIKey(w http.ResponseWriter, r *http.Request) {
 
 // findAPIKeyAndUser finds an API key and its associated user
 func (h *AuthHandler) findAPIKeyAndUser(ctx context.Context, rawKey string) (*domain.APIKey, *domain.User, error) {
-	users, err := h.repos.User.FindAll(ctx)
-	if err != nil {
+	key, err := h.apiKeys.Find(ctx, rawKey)
+	if err != nil || key == nil {
 		return nil, nil, err
 	}
 
-	for _, user := range users {
-		keys, err := h.repos.APIKey.FindByUserID(ctx, user.ID)
-		if err != nil {
-			continue
-		}
-		for _, key := range keys {
-			if err := bcrypt.CompareHashAndPassword([]byte(key.KeyHash), []byte(rawKey)); err == nil {
-				return key, user, nil
-			}
-		}
+	user, err := h.repos.User.FindByID(ctx, key.UserID)
+	if err != nil {
+		return nil, nil, err
 	}
-	return nil, nil, nil
+	return key, user, nil
 }
 
 // Session handles login via token (from CLI)
@@ -562,18 +622,32 @@ func (h *AuthHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Fields left out of the request are not changed
 	var req struct {
-		DisplayName string `json:"display_name"`
+		DisplayName              *string `json:"display_name"`
+		DefaultSessionVisibility *string `json:"default_session_visibility"` // "private", "team", "public" or "" for the server default
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, `{"error": "invalid request body"}`, http.StatusBadRequest)
 		return
 	}
+	if req.DefaultSessionVisibility != nil && *req.DefaultSessionVisibility != "" && !domain.SessionVisibility(*req.DefaultSessionVisibility).IsValid() {
+		http.Error(w, `{"error": "invalid default_session_visibility: must be private, team or public"}`, http.StatusBadRequest)
+		return
+	}
 
 	ctx := r.Context()
-	if err := h.repos.User.UpdateDisplayName(ctx, user.ID, req.DisplayName); err != nil {
-		http.Error(w, `{"error": "failed to update user"}`, http.StatusInternalServerError)
-		return
+	if req.DisplayName != nil {
+		if err := h.repos.User.UpdateDisplayName(ctx, user.ID, *req.DisplayName); err != nil {
+			http.Error(w, `{"error": "failed to update user"}`, http.StatusInternalServerError)
+			return
+		}
+	}
+	if req.DefaultSessionVisibility != nil {
+		if err := h.repos.User.UpdateDefaultSessionVisibility(ctx, user.ID, domain.SessionVisibility(*req.DefaultSessionVisibility)); err != nil {
+			http.Error(w, `{"error": "failed to update user"}`, http.StatusInternalServerError)
+			return
+		}
 	}
 
 	// Fetch updated user
@@ -588,7 +662,7 @@ func (h *AuthHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(resp)
 }
 
-// ListUsers returns all users
+// ListUsers returns all users, without their roles, disabled state or preferences
 func (h *AuthHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -598,7 +672,17 @@ func (h *AuthHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := UsersResponse{Users: users}
+	members := make([]*MemberResponse, len(users))
+	for i, u := range users {
+		members[i] = &MemberResponse{
+			ID:          u.ID,
+			Email:       u.Email,
+			DisplayName: u.DisplayName,
+			CreatedAt:   u.CreatedAt,
+		}
+	}
+
+	resp := MembersResponse{Users: members}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
@@ -621,13 +705,7 @@ func (h *AuthHandler) ListKeys(w http.ResponseWriter, r *http.Request) {
 
 	keyInfos := make([]*APIKeyInfo, len(keys))
 	for i, k := range keys {
-		keyInfos[i] = &APIKeyInfo{
-			ID:         k.ID,
-			Name:       k.Name,
-			KeyPrefix:  k.KeyPrefix,
-			LastUsedAt: k.LastUsedAt,
-			CreatedAt:  k.CreatedAt,
-		}
+		keyInfos[i] = apiKeyToInfo(k)
 	}
 
 	resp := ListKeysResponse{Keys: keyInfos}
@@ -654,26 +732,39 @@ func (h *AuthHandler) CreateKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var scopes []domain.APIKeyScope
+	for _, s := range req.Scopes {
+		scope := domain.APIKeyScope(s)
+		if !scope.IsValid() {
+			http.Error(w, `{"error": "invalid scope: must be ingest, plans or read"}`, http.StatusBadRequest)
+			return
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		http.Error(w, `{"error": "expires_at must be in the future"}`, http.StatusBadRequest)
+		return
+	}
+
 	ctx := r.Context()
 
 	// Generate API key
-	rawKey, err := generateAPIKey()
+	rawKey, lookupID, err := generateAPIKey()
 	if err != nil {
 		http.Error(w, `{"error": "failed to generate api key"}`, http.StatusInternalServerError)
 		return
 	}
 
-	keyHash, err := hashAPIKey(rawKey)
-	if err != nil {
-		http.Error(w, `{"error": "failed to hash api key"}`, http.StatusInternalServerError)
-		return
-	}
-
 	apiKey := &domain.APIKey{
 		UserID:    user.ID,
 		Name:      req.Name,
-		KeyHash:   keyHash,
+		LookupID:  lookupID,
+		KeyHash:   hashAPIKey(rawKey),
 		KeyPrefix: rawKey[:12] + "...",
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
 	}
 	if err := h.repos.APIKey.Create(ctx, apiKey); err != nil {
 		http.Error(w, `{"error": "failed to create api key"}`, http.StatusInternalServerError)
@@ -681,12 +772,7 @@ func (h *AuthHandler) CreateKey(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := CreateKeyResponse{
-		Key: &APIKeyInfo{
-			ID:        apiKey.ID,
-			Name:      apiKey.Name,
-			KeyPrefix: apiKey.KeyPrefix,
-			CreatedAt: apiKey.CreatedAt,
-		},
+		Key:    apiKeyToInfo(apiKey),
 		APIKey: rawKey,
 	}
 
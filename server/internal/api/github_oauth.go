@@ -115,16 +115,28 @@ func (h *AuthHandler) GitHubCallback(w http.ResponseWriter, r *http.Request) {
 			// Link to existing user
 			user = existingUser
 		} else {
+			if !h.cfg.IsEmailAllowed(email) {
+				http.Error(w, `{"error": "email domain is not allowed"}`, http.StatusForbidden)
+				return
+			}
+
 			// Create new user
 			displayName := githubUser.Name
 			if displayName == "" {
 				displayName = githubUser.Login
 			}
 
+			role, err := h.newUserRole(ctx, email)
+			if err != nil {
+				http.Error(w, `{"error": "database error"}`, http.StatusInternalServerError)
+				return
+			}
+
 			user = &domain.User{
 				ID:          uuid.New().String(),
 				Email:       email,
 				DisplayName: displayName,
+				Role:        role,
 				CreatedAt:   time.Now(),
 			}
 
@@ -149,6 +161,11 @@ func (h *AuthHandler) GitHubCallback(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if user.Disabled {
+		http.Error(w, `{"error": "user is disabled"}`, http.StatusForbidden)
+		return
+	}
+
 	// Create web session
 	if err := h.createSessionAndRedirect(ctx, w, r, user, state); err != nil {
 		http.Error(w, `{"error": "failed to create session"}`, http.StatusInternalServerError)
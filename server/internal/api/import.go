@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/satetsu888/agentrace/server/internal/repository"
+	"github.com/satetsu888/agentrace/server/internal/transcript"
+)
+
+// maxImportMemory is the part of an upload kept in memory; the rest is buffered on disk
+const maxImportMemory = 32 << 20
+
+// ImportHandler imports historical Claude Code transcripts through the ingest logic
+type ImportHandler struct {
+	repos       *repository.Repositories
+	ingest      *IngestHandler
+	maxBodySize int64 // 0 disables the limit
+}
+
+func NewImportHandler(repos *repository.Repositories, ingest *IngestHandler, maxBodySize int64) *ImportHandler {
+	return &ImportHandler{repos: repos, ingest: ingest, maxBodySize: maxBodySize}
+}
+
+// Response types
+
+type ImportSessionResponse struct {
+	File            string `json:"file"`
+	ClaudeSessionID string `json:"claude_session_id,omitempty"`
+	SessionID       string `json:"session_id,omitempty"`
+	EventsCreated   int    `json:"events_created"`
+	EventsSkipped   int    `json:"events_skipped"` // already imported (same uuid) or malformed
+	Error           string `json:"error,omitempty"`
+}
+
+type ImportResponse struct {
+	Sessions []*ImportSessionResponse `json:"sessions"`
+}
+
+// Import stores a parsed transcript on behalf of userID. Sessions created by
+// the import take their last activity and end times from the transcript;
+// importing the same transcript again only adds lines that were not stored yet.
+func (h *ImportHandler) Import(ctx context.Context, t *transcript.Transcript, userID *string) (*ImportSessionResponse, error) {
+	result, err := h.ingest.Ingest(ctx, &IngestRequest{
+		SessionID:       t.ClaudeSessionID,
+		TranscriptLines: t.Lines,
+		Cwd:             t.Cwd,
+		GitBranch:       t.GitBranch,
+	}, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ingest stamps the activity with the current time; a historical session ended with its last line
+	if result.IsNewSession && !t.EndedAt.IsZero() {
+		if err := h.repos.Session.UpdateUpdatedAt(ctx, result.Session.ID, t.EndedAt); err != nil {
+			return nil, err
+		}
+		if err := h.repos.Session.MarkEnded(ctx, result.Session.ID, t.EndedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ImportSessionResponse{
+		ClaudeSessionID: t.ClaudeSessionID,
+		SessionID:       result.Session.ID,
+		EventsCreated:   result.EventsCreated,
+		EventsSkipped:   len(t.Lines) - result.EventsCreated + t.SkippedLines,
+	}, nil
+}
+
+// Upload imports the transcript files of a multipart upload (field "files")
+// as sessions of the authenticated user
+func (h *ImportHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var userID *string
+	if uid := GetUserIDFromContext(ctx); uid != "" {
+		userID = &uid
+	}
+
+	// The upload shares the ingest body limit; parts beyond maxImportMemory are written to disk
+	if h.maxBodySize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+	}
+	if err := r.ParseMultipartForm(maxImportMemory); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, `{"error": "request body too large"}`, http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, `{"error": "invalid multipart form"}`, http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	files := r.MultipartForm.File["files"]
+	if len(files) == 0 {
+		http.Error(w, `{"error": "files are required"}`, http.StatusBadRequest)
+		return
+	}
+
+	response := ImportResponse{Sessions: make([]*ImportSessionResponse, 0, len(files))}
+	for _, fh := range files {
+		f, err := fh.Open()
+		if err != nil {
+			http.Error(w, `{"error": "failed to read upload"}`, http.StatusInternalServerError)
+			return
+		}
+		t, err := transcript.Parse(f, fh.Filename)
+		f.Close()
+		if err != nil {
+			// A file that is not a transcript does not fail the others
+			response.Sessions = append(response.Sessions, &ImportSessionResponse{File: fh.Filename, Error: err.Error()})
+			continue
+		}
+
+		result, err := h.Import(ctx, t, userID)
+		if err != nil {
+			var ingestErr *IngestError
+			if errors.As(err, &ingestErr) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(map[string]string{"error": ingestErr.Message})
+				return
+			}
+			http.Error(w, `{"error": "failed to import session"}`, http.StatusInternalServerError)
+			return
+		}
+		result.File = fh.Filename
+		response.Sessions = append(response.Sessions, result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
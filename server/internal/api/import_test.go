@@ -0,0 +1,46 @@
+package api
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+func TestImportUpload_BodyLimit(t *testing.T) {
+	s := newTestServer(t)
+	s.cfg.MaxIngestBodySize = 1 << 10
+	s.handler = NewRouter(s.cfg, s.repos, nil, nil)
+	_, key := s.createUser("member@example.com", domain.UserRoleMember)
+
+	upload := func(content string) *httptest.ResponseRecorder {
+		t.Helper()
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		part, err := mw.CreateFormFile("files", "session.jsonl")
+		if err != nil {
+			t.Fatalf("create form file: %v", err)
+		}
+		part.Write([]byte(content))
+		mw.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/import", &buf)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+key)
+		rec := httptest.NewRecorder()
+		s.handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	line := `{"uuid":"u1","sessionId":"claude-import","type":"user","message":{"role":"user","content":"hello"}}` + "\n"
+	if rec := upload(line); rec.Code != http.StatusOK {
+		t.Fatalf("small upload: status %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := upload(strings.Repeat(line, 20)); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("large upload: status = %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+}
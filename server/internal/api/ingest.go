@@ -1,30 +1,45 @@
 package api
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
+	"mime"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/satetsu888/agentrace/server/internal/domain"
+	"github.com/satetsu888/agentrace/server/internal/eventbus"
+	"github.com/satetsu888/agentrace/server/internal/pricing"
+	"github.com/satetsu888/agentrace/server/internal/redact"
 	"github.com/satetsu888/agentrace/server/internal/repository"
 )
 
 type IngestHandler struct {
-	repos *repository.Repositories
+	repos    *repository.Repositories
+	redactor *redact.Redactor // nil disables redaction
+	prices   *pricing.Table   // nil disables cost estimation
+	bus      *eventbus.Bus
+
+	maxBodySize int64 // maximum decompressed request body in bytes; 0 disables the limit
 }
 
-func NewIngestHandler(repos *repository.Repositories) *IngestHandler {
-	return &IngestHandler{repos: repos}
+func NewIngestHandler(repos *repository.Repositories, redactor *redact.Redactor, prices *pricing.Table, bus *eventbus.Bus, maxBodySize int64) *IngestHandler {
+	return &IngestHandler{repos: repos, redactor: redactor, prices: prices, bus: bus, maxBodySize: maxBodySize}
 }
 
 type IngestRequest struct {
-	SessionID       string                   `json:"session_id"`
-	TranscriptLines []map[string]interface{} `json:"transcript_lines"`
-	Cwd             string                   `json:"cwd"`
-	GitRemoteURL    string                   `json:"git_remote_url"`
-	GitBranch       string                   `json:"git_branch"`
+	SessionID       string            `json:"session_id"`
+	TranscriptLines []json.RawMessage `json:"transcript_lines"` // kept as sent so that exports can reproduce them
+	Cwd             string            `json:"cwd"`
+	GitRemoteURL    string            `json:"git_remote_url"`
+	GitBranch       string            `json:"git_branch"`
+	HookEventName   string            `json:"hook_event_name"` // Claude Code hook that triggered this request (e.g. Stop, SessionEnd)
+	Visibility      string            `json:"visibility"`      // optional: private, team or public; new sessions otherwise take the owner's default
 }
 
 type IngestResponse struct {
@@ -32,10 +47,74 @@ type IngestResponse struct {
 	EventsCreated int  `json:"events_created"`
 }
 
+// Handle ingests a JSON IngestRequest. With Content-Type application/x-ndjson the body is
+// instead a header line carrying the request's session metadata followed by one transcript
+// line per line, each processed as it is read. Either body may be gzip-compressed.
 func (h *IngestHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	body := r.Body
+	switch strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding"))) {
+	case "", "identity":
+	case "gzip":
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, `{"error": "invalid gzip body"}`, http.StatusBadRequest)
+			return
+		}
+		defer gz.Close()
+		body = gz
+	default:
+		http.Error(w, `{"error": "unsupported content encoding"}`, http.StatusUnsupportedMediaType)
+		return
+	}
+	// Limit the decompressed size so that a small gzip body cannot expand without bound
+	if h.maxBodySize > 0 {
+		body = http.MaxBytesReader(w, body, h.maxBodySize)
+	}
+
 	var req IngestRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"error": "invalid json"}`, http.StatusBadRequest)
+	var next nextLineFunc
+	var readErr error // failure to read a streamed line, reported as the client's fault
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/x-ndjson" {
+		dec := json.NewDecoder(body)
+		if err := dec.Decode(&req); err != nil {
+			writeIngestBodyError(w, err)
+			return
+		}
+		// Lines in the header come before the streamed ones
+		headerLines := linesOf(req.TranscriptLines)
+		next = func() (json.RawMessage, error) {
+			if line, err := headerLines(); err != io.EOF {
+				return line, err
+			}
+			var line json.RawMessage
+			if err := dec.Decode(&line); err != nil {
+				if err != io.EOF {
+					readErr = err
+				}
+				return nil, err
+			}
+			if !isJSONObject(line) {
+				readErr = errors.New("transcript line is not an object")
+				return nil, readErr
+			}
+			return line, nil
+		}
+	} else {
+		if err := json.NewDecoder(body).Decode(&req); err != nil {
+			writeIngestBodyError(w, err)
+			return
+		}
+		next = linesOf(req.TranscriptLines)
+	}
+	for _, line := range req.TranscriptLines {
+		if !isJSONObject(line) {
+			http.Error(w, `{"error": "invalid json"}`, http.StatusBadRequest)
+			return
+		}
+	}
+	if req.Visibility != "" && !domain.SessionVisibility(req.Visibility).IsValid() {
+		http.Error(w, `{"error": "invalid visibility: must be private, team or public"}`, http.StatusBadRequest)
 		return
 	}
 
@@ -47,18 +126,127 @@ func (h *IngestHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		userID = &uid
 	}
 
+	result, err := h.ingestLines(ctx, &req, next, userID)
+	if err != nil {
+		// Lines stored before a bad line are kept; sending them again skips them as duplicates
+		if readErr != nil {
+			writeIngestBodyError(w, readErr)
+			return
+		}
+		var ingestErr *IngestError
+		if errors.As(err, &ingestErr) {
+			http.Error(w, `{"error": "`+ingestErr.Message+`"}`, http.StatusInternalServerError)
+			return
+		}
+		http.Error(w, `{"error": "failed to ingest"}`, http.StatusInternalServerError)
+		return
+	}
+
+	resp := IngestResponse{
+		OK:            true,
+		EventsCreated: result.EventsCreated,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// writeIngestBodyError reports a request body that could not be read or decoded
+func writeIngestBodyError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		http.Error(w, `{"error": "request body too large"}`, http.StatusRequestEntityTooLarge)
+		return
+	}
+	http.Error(w, `{"error": "invalid json"}`, http.StatusBadRequest)
+}
+
+// isJSONObject reports whether the transcript line is a JSON object; lines are decoded later
+func isJSONObject(line json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(line)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+// nextLineFunc returns the next transcript line to ingest, or io.EOF after the last one
+type nextLineFunc func() (json.RawMessage, error)
+
+// linesOf returns a nextLineFunc over lines
+func linesOf(lines []json.RawMessage) nextLineFunc {
+	return func() (json.RawMessage, error) {
+		if len(lines) == 0 {
+			return nil, io.EOF
+		}
+		line := lines[0]
+		lines = lines[1:]
+		return line, nil
+	}
+}
+
+// IngestError is a failed ingest step; Message is safe to return to clients
+type IngestError struct {
+	Message string
+	Err     error
+}
+
+func (e *IngestError) Error() string {
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *IngestError) Unwrap() error {
+	return e.Err
+}
+
+// IngestResult describes what an ingest request changed
+type IngestResult struct {
+	Session       *domain.Session
+	IsNewSession  bool
+	EventsCreated int
+}
+
+// Ingest stores the transcript lines of req in the session it names, creating
+// the session and project as needed. Lines already stored (same uuid) are skipped.
+func (h *IngestHandler) Ingest(ctx context.Context, req *IngestRequest, userID *string) (*IngestResult, error) {
+	return h.ingestLines(ctx, req, linesOf(req.TranscriptLines), userID)
+}
+
+// ingestLines is Ingest with the transcript lines read one at a time from next
+// instead of req. If next fails, the lines read so far are still recorded.
+func (h *IngestHandler) ingestLines(ctx context.Context, req *IngestRequest, next nextLineFunc, userID *string) (*IngestResult, error) {
+	// Check whether this is a new session (for live stream notifications)
+	existing, err := h.repos.Session.FindByClaudeSessionID(ctx, req.SessionID)
+	if err != nil {
+		return nil, &IngestError{Message: "failed to find session", Err: err}
+	}
+	isNewSession := existing == nil
+
 	// Find or create session
 	session, err := h.repos.Session.FindOrCreateByClaudeSessionID(ctx, req.SessionID, userID)
 	if err != nil {
-		http.Error(w, `{"error": "failed to create session"}`, http.StatusInternalServerError)
-		return
+		return nil, &IngestError{Message: "failed to create session", Err: err}
+	}
+
+	// Apply the requested visibility; a new session otherwise takes its owner's default
+	visibility := domain.SessionVisibility(req.Visibility)
+	if !visibility.IsValid() && isNewSession && userID != nil {
+		user, err := h.repos.User.FindByID(ctx, *userID)
+		if err != nil {
+			return nil, &IngestError{Message: "failed to find user", Err: err}
+		}
+		if user != nil {
+			visibility = user.NewSessionVisibility()
+		}
+	}
+	if visibility.IsValid() && visibility != session.Visibility {
+		if err := h.repos.Session.UpdateVisibility(ctx, session.ID, visibility); err != nil {
+			return nil, &IngestError{Message: "failed to update visibility", Err: err}
+		}
+		session.Visibility = visibility
 	}
 
 	// Update project path if provided and not already set
 	if req.Cwd != "" && session.ProjectPath == "" {
 		if err := h.repos.Session.UpdateProjectPath(ctx, session.ID, req.Cwd); err != nil {
-			http.Error(w, `{"error": "failed to update project path"}`, http.StatusInternalServerError)
-			return
+			return nil, &IngestError{Message: "failed to update project path", Err: err}
 		}
 		session.ProjectPath = req.Cwd
 	}
@@ -69,14 +257,18 @@ func (h *IngestHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		canonicalURL := domain.NormalizeGitURL(req.GitRemoteURL)
 		project, err := h.repos.Project.FindOrCreateByCanonicalGitRepository(ctx, canonicalURL)
 		if err != nil {
-			http.Error(w, `{"error": "failed to create project"}`, http.StatusInternalServerError)
-			return
+			return nil, &IngestError{Message: "failed to create project", Err: err}
 		}
 
 		// Update session's project ID
 		if err := h.repos.Session.UpdateProjectID(ctx, session.ID, project.ID); err != nil {
-			http.Error(w, `{"error": "failed to update project"}`, http.StatusInternalServerError)
-			return
+			return nil, &IngestError{Message: "failed to update project", Err: err}
+		}
+		if err := h.repos.FileActivity.UpdateProjectIDBySessionID(ctx, session.ID, project.ID); err != nil {
+			return nil, &IngestError{Message: "failed to update project", Err: err}
+		}
+		if err := h.repos.ToolCall.UpdateProjectIDBySessionID(ctx, session.ID, project.ID); err != nil {
+			return nil, &IngestError{Message: "failed to update project", Err: err}
 		}
 		session.ProjectID = project.ID
 	}
@@ -84,18 +276,130 @@ func (h *IngestHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	// Update git branch if provided and not already set
 	if req.GitBranch != "" && session.GitBranch == "" {
 		if err := h.repos.Session.UpdateGitBranch(ctx, session.ID, req.GitBranch); err != nil {
-			http.Error(w, `{"error": "failed to update git branch"}`, http.StatusInternalServerError)
-			return
+			return nil, &IngestError{Message: "failed to update git branch", Err: err}
 		}
 		session.GitBranch = req.GitBranch
 	}
 
-	// Create events from transcript lines
-	eventsCreated := 0
-	for _, line := range req.TranscriptLines {
+	// Store the transcript lines in batches. Each batch is written in one transaction
+	// with the rows derived from it; the last one also updates the session.
+	st := &ingestState{
+		session:         session,
+		isNewSession:    isNewSession,
+		hookEventName:   req.HookEventName,
+		dailyCounts:     make(map[time.Time]*domain.AnalyticsCounts),
+		countedMessages: make(map[string]bool),
+		sidechainLines:  make(map[string]bool),
+		requestUUIDs:    make(map[string]bool),
+	}
+	var readErr error
+	for done := false; !done; {
+		lines := make([]json.RawMessage, 0, ingestBatchSize)
+		for len(lines) < ingestBatchSize {
+			line, err := next()
+			if err != nil {
+				if err != io.EOF {
+					readErr = err
+				}
+				done = true
+				break
+			}
+			lines = append(lines, line)
+		}
+
+		st.messages = nil
+		err := h.repos.Tx.RunInTx(ctx, func(ctx context.Context) error {
+			if err := h.storeLines(ctx, st, lines); err != nil {
+				return err
+			}
+			if done {
+				return h.updateSession(ctx, st)
+			}
+			return nil
+		})
+		if err != nil {
+			var ingestErr *IngestError
+			if errors.As(err, &ingestErr) {
+				return nil, err
+			}
+			return nil, &IngestError{Message: "failed to store events", Err: err}
+		}
+
+		// Notify live subscribers of the session once its events are committed
+		for _, msg := range st.messages {
+			h.bus.Publish(msg)
+		}
+	}
+
+	// Notify global subscribers of new sessions and session activity
+	if isNewSession {
+		h.bus.Publish(eventbus.Message{
+			Type:   eventbus.TypeSessionCreated,
+			Global: true,
+			Data:   SessionStreamData{SessionID: session.ID, ProjectID: session.ProjectID, EventsCreated: st.eventsCreated},
+		})
+	} else if st.eventsCreated > 0 {
+		h.bus.Publish(eventbus.Message{
+			Type:   eventbus.TypeSessionUpdated,
+			Global: true,
+			Data:   SessionStreamData{SessionID: session.ID, ProjectID: session.ProjectID, EventsCreated: st.eventsCreated},
+		})
+	}
+
+	if readErr != nil {
+		return nil, &IngestError{Message: "invalid transcript line", Err: readErr}
+	}
+
+	return &IngestResult{Session: session, IsNewSession: isNewSession, EventsCreated: st.eventsCreated}, nil
+}
+
+// ingestBatchSize is the number of transcript lines stored per transaction
+const ingestBatchSize = 500
+
+// ingestState accumulates what the batches of an ingest request change in its session
+type ingestState struct {
+	session       *domain.Session
+	isNewSession  bool
+	hookEventName string
+
+	eventsCreated   int
+	redactionCount  int
+	earliest        time.Time
+	dailyCounts     map[time.Time]*domain.AnalyticsCounts // analytics counts by event day
+	usage           domain.TokenUsage
+	countedMessages map[string]bool
+	subagentCount   int
+	sidechainLines  map[string]bool // uuid -> isSidechain for the lines of this request
+	requestUUIDs    map[string]bool
+	linkedUUIDs     []string           // lines of other sessions this session may continue
+	messages        []eventbus.Message // live notifications of the current batch, sent after commit
+}
+
+// storeLines stores a batch of transcript lines as events of the session, skipping
+// lines already stored, and records the files and tool calls of the new ones
+func (h *IngestHandler) storeLines(ctx context.Context, st *ingestState, lines []json.RawMessage) error {
+	session := st.session
+
+	events := make([]*domain.Event, 0, len(lines))
+	redactions := make(map[*domain.Event]int)
+	for _, raw := range lines {
+		// Strip secrets before anything is derived from or stored with the line
+		raw, redacted := h.redactor.RedactJSON(bytes.TrimSpace(raw))
+		var line map[string]interface{}
+		if err := json.Unmarshal(raw, &line); err != nil {
+			return &IngestError{Message: "invalid transcript line", Err: err}
+		}
+
 		event := &domain.Event{
 			SessionID: session.ID,
 			Payload:   line,
+			Raw:       raw,
+		}
+		redactions[event] = redacted
+
+		// Use the time recorded in the transcript so that batched lines keep their own times
+		if t, ok := event.PayloadTimestamp(); ok {
+			event.CreatedAt = t
 		}
 
 		// Extract uuid from transcript line (Claude Code's unique identifier)
@@ -103,47 +407,283 @@ func (h *IngestHandler) Handle(w http.ResponseWriter, r *http.Request) {
 			event.UUID = uuid
 		}
 
+		// Link to the previous line; subagents spawned by the Task tool write sidechain lines
+		if parentUUID, ok := line["parentUuid"].(string); ok {
+			event.ParentUUID = parentUUID
+		}
+		if isSidechain, ok := line["isSidechain"].(bool); ok {
+			event.IsSidechain = isSidechain
+		}
+
 		// Extract type if present
-		eventType := ""
 		if et, ok := line["type"].(string); ok {
-			eventType = et
 			event.EventType = et
 		}
 
 		// Auto-generate title from first user message if not set
 		// Skip meta messages, command messages, and tool results
-		if eventType == "user" && session.Title == nil && !isMetaMessage(line) {
+		if event.EventType == "user" && session.Title == nil && !isMetaMessage(line) {
 			if text := extractUserMessageText(line); text != "" && isValidUserInput(text) {
 				title := truncateString(text, 45)
-				if err := h.repos.Session.UpdateTitle(ctx, session.ID, title); err == nil {
-					session.Title = &title
+				if err := h.repos.Session.UpdateTitle(ctx, session.ID, title); err != nil {
+					return &IngestError{Message: "failed to update title", Err: err}
 				}
+				session.Title = &title
 			}
 		}
 
-		if err := h.repos.Event.Create(ctx, event); err != nil {
-			// Skip duplicate events (same uuid within session)
-			if errors.Is(err, repository.ErrDuplicateEvent) {
-				continue
+		if event.UUID != "" {
+			st.requestUUIDs[event.UUID] = true
+		}
+
+		events = append(events, event)
+	}
+
+	// Lines already stored (same uuid within session) are skipped
+	result, err := h.repos.Event.CreateBatch(ctx, events)
+	if err != nil {
+		return &IngestError{Message: "failed to create event", Err: err}
+	}
+
+	for _, event := range result.Created {
+		line := event.Payload
+		st.eventsCreated++
+		st.redactionCount += redactions[event]
+
+		// Index the files modified by the line's tool calls
+		for _, activity := range event.FileActivities(session.ProjectPath) {
+			activity.ProjectID = session.ProjectID
+			if err := h.repos.FileActivity.Create(ctx, activity); err != nil {
+				return &IngestError{Message: "failed to record file activity", Err: err}
 			}
-			http.Error(w, `{"error": "failed to create event"}`, http.StatusInternalServerError)
-			return
 		}
-		eventsCreated++
+
+		// Record tool calls as pending until their results arrive, possibly in a later request
+		for _, call := range event.ToolCalls() {
+			call.ProjectID = session.ProjectID
+			if err := h.repos.ToolCall.Create(ctx, call); err != nil {
+				return &IngestError{Message: "failed to record tool call", Err: err}
+			}
+		}
+		for _, result := range event.ToolResults() {
+			if err := h.repos.ToolCall.Complete(ctx, session.ID, result.ToolUseID, result.IsError, event.Time()); err != nil {
+				return &IngestError{Message: "failed to record tool result", Err: err}
+			}
+		}
+
+		// A resumed or forked session continues from a line of the earlier session,
+		// and its summaries point at the last line (leaf) they summarize
+		if event.ParentUUID != "" && !st.requestUUIDs[event.ParentUUID] {
+			st.linkedUUIDs = append(st.linkedUUIDs, event.ParentUUID)
+		}
+		if leafUUID, ok := line["leafUuid"].(string); ok && event.EventType == "summary" {
+			st.linkedUUIDs = append(st.linkedUUIDs, leafUUID)
+		}
+		if event.UUID != "" {
+			st.sidechainLines[event.UUID] = event.IsSidechain
+		}
+		// A subagent thread starts with an unlinked sidechain line or one following the main thread
+		if event.IsSidechain {
+			parentIsSidechain, linked := st.sidechainLines[event.ParentUUID]
+			if event.ParentUUID == "" || (linked && !parentIsSidechain) {
+				st.subagentCount++
+			}
+		}
+		if st.earliest.IsZero() || event.CreatedAt.Before(st.earliest) {
+			st.earliest = event.CreatedAt
+		}
+
+		day := domain.AnalyticsBucketDay.Truncate(event.CreatedAt)
+		if st.dailyCounts[day] == nil {
+			st.dailyCounts[day] = &domain.AnalyticsCounts{}
+		}
+		st.dailyCounts[day].EventsIngested++
+		st.dailyCounts[day].ToolInvocations += event.ToolUseCount()
+
+		// Usage is repeated on every content block line of an API message; count it once per request
+		if messageID, model, u, ok := event.MessageUsage(); ok && !st.countedMessages[messageID] {
+			if messageID != "" {
+				st.countedMessages[messageID] = true
+			}
+			u.EstimatedCostUSD = h.prices.Cost(model, u)
+			st.usage.Add(u)
+		}
+
+		if !shouldFilterEvent(event) {
+			st.messages = append(st.messages, eventbus.Message{
+				Type:      eventbus.TypeEventCreated,
+				SessionID: session.ID,
+				Data:      eventToResponse(event),
+			})
+		}
+	}
+	return nil
+}
+
+// updateSession records what the request's events changed in the session: counts,
+// usage, start and end times, its parent session and the analytics counters
+func (h *IngestHandler) updateSession(ctx context.Context, st *ingestState) error {
+	session := st.session
+
+	// Errors are returned rather than ignored: a failed statement aborts the
+	// transaction on PostgreSQL, and the counters must commit with the events
+	if st.redactionCount > 0 {
+		if err := h.repos.Session.AddRedactionCount(ctx, session.ID, st.redactionCount); err != nil {
+			return &IngestError{Message: "failed to update redaction count", Err: err}
+		}
+	}
+
+	if st.subagentCount > 0 {
+		if err := h.repos.Session.AddSubagentCount(ctx, session.ID, st.subagentCount); err != nil {
+			return &IngestError{Message: "failed to update subagent count", Err: err}
+		}
+	}
+
+	if !st.usage.IsZero() {
+		if err := h.repos.Session.AddUsage(ctx, session.ID, st.usage); err != nil {
+			return &IngestError{Message: "failed to update usage", Err: err}
+		}
+	}
+
+	// A session starts with its earliest event, which may be older than the first upload
+	if !st.earliest.IsZero() && st.earliest.Before(session.StartedAt) {
+		if err := h.repos.Session.UpdateStartedAt(ctx, session.ID, st.earliest); err != nil {
+			return &IngestError{Message: "failed to update started_at", Err: err}
+		}
+		session.StartedAt = st.earliest
+	}
+
+	// Link a resumed or forked session to the session it continues
+	if session.ParentSessionID == nil && len(st.linkedUUIDs) > 0 {
+		parent, err := h.findParentSession(ctx, session, st.linkedUUIDs)
+		if err != nil {
+			return &IngestError{Message: "failed to find parent session", Err: err}
+		}
+		if parent != nil {
+			if err := h.repos.Session.UpdateParentSessionID(ctx, session.ID, parent.ID); err != nil {
+				return &IngestError{Message: "failed to link parent session", Err: err}
+			}
+			session.ParentSessionID = &parent.ID
+		}
 	}
 
 	// Update session's updated_at timestamp if events were created
-	if eventsCreated > 0 {
-		_ = h.repos.Session.UpdateUpdatedAt(ctx, session.ID, time.Now())
+	if st.eventsCreated > 0 {
+		if err := h.repos.Session.UpdateUpdatedAt(ctx, session.ID, time.Now()); err != nil {
+			return &IngestError{Message: "failed to update session", Err: err}
+		}
 	}
 
-	resp := IngestResponse{
-		OK:            true,
-		EventsCreated: eventsCreated,
+	// Record session end when triggered by an end-of-session hook
+	if isSessionEndHook(st.hookEventName) {
+		if err := h.repos.Session.MarkEnded(ctx, session.ID, time.Now()); err != nil {
+			return &IngestError{Message: "failed to mark session ended", Err: err}
+		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
+	// Count activity for analytics at the time it happened, attributed to the session's owner
+	var ownerID string
+	if session.UserID != nil {
+		ownerID = *session.UserID
+	}
+	if st.isNewSession {
+		err := h.repos.Analytics.RecordActivity(ctx, domain.AnalyticsActivity{
+			Time:            session.StartedAt,
+			ProjectID:       session.ProjectID,
+			UserID:          ownerID,
+			AnalyticsCounts: domain.AnalyticsCounts{SessionsStarted: 1},
+		})
+		if err != nil {
+			return &IngestError{Message: "failed to record analytics", Err: err}
+		}
+	}
+	for day, counts := range st.dailyCounts {
+		err := h.repos.Analytics.RecordActivity(ctx, domain.AnalyticsActivity{
+			Time:            day,
+			ProjectID:       session.ProjectID,
+			UserID:          ownerID,
+			AnalyticsCounts: *counts,
+		})
+		if err != nil {
+			return &IngestError{Message: "failed to record analytics", Err: err}
+		}
+	}
+	return nil
+}
+
+// maxLinkedUUIDLookups bounds the lookups made per request to find a parent session
+const maxLinkedUUIDLookups = 10
+
+// findParentSession returns the session that session continues: of the other
+// sessions holding a line it links to, the latest one started before it.
+// Sessions that already descend from session are skipped so chains never loop.
+func (h *IngestHandler) findParentSession(ctx context.Context, session *domain.Session, linkedUUIDs []string) (*domain.Session, error) {
+	var parent *domain.Session
+	looked := make(map[string]bool)
+	for _, linkedUUID := range linkedUUIDs {
+		if looked[linkedUUID] {
+			continue
+		}
+		if len(looked) == maxLinkedUUIDLookups {
+			break
+		}
+		looked[linkedUUID] = true
+
+		sessionIDs, err := h.repos.Event.FindSessionIDsByUUID(ctx, linkedUUID)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range sessionIDs {
+			if id == session.ID || (parent != nil && id == parent.ID) {
+				continue
+			}
+			candidate, err := h.repos.Session.FindByID(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if candidate == nil || !candidate.StartedAt.Before(session.StartedAt) {
+				continue
+			}
+			if parent != nil && !candidate.StartedAt.After(parent.StartedAt) {
+				continue
+			}
+			descends, err := h.descendsFrom(ctx, candidate, session.ID)
+			if err != nil {
+				return nil, err
+			}
+			if !descends {
+				parent = candidate
+			}
+		}
+	}
+	return parent, nil
+}
+
+// descendsFrom reports whether ancestorID is among the ancestors of session
+func (h *IngestHandler) descendsFrom(ctx context.Context, session *domain.Session, ancestorID string) (bool, error) {
+	seen := make(map[string]bool)
+	for session.ParentSessionID != nil && !seen[*session.ParentSessionID] {
+		if *session.ParentSessionID == ancestorID {
+			return true, nil
+		}
+		seen[*session.ParentSessionID] = true
+
+		parent, err := h.repos.Session.FindByID(ctx, *session.ParentSessionID)
+		if err != nil {
+			return false, err
+		}
+		if parent == nil {
+			return false, nil
+		}
+		session = parent
+	}
+	return false, nil
+}
+
+// isSessionEndHook checks if the hook event marks the end of a session.
+// Stop fires at the end of every turn, so it does not end the session.
+func isSessionEndHook(hookEventName string) bool {
+	return hookEventName == "SessionEnd"
 }
 
 // isMetaMessage checks if the payload is a meta message (e.g., Caveat messages)
@@ -11,7 +11,6 @@ import (
 	"github.com/satetsu888/agentrace/server/internal/config"
 	"github.com/satetsu888/agentrace/server/internal/domain"
 	"github.com/satetsu888/agentrace/server/internal/repository"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type contextKey string
@@ -22,12 +21,13 @@ const (
 )
 
 type Middleware struct {
-	cfg   *config.Config
-	repos *repository.Repositories
+	cfg     *config.Config
+	repos   *repository.Repositories
+	apiKeys *APIKeyFinder
 }
 
-func NewMiddleware(cfg *config.Config, repos *repository.Repositories) *Middleware {
-	return &Middleware{cfg: cfg, repos: repos}
+func NewMiddleware(cfg *config.Config, repos *repository.Repositories, apiKeys *APIKeyFinder) *Middleware {
+	return &Middleware{cfg: cfg, repos: repos, apiKeys: apiKeys}
 }
 
 // GetUserFromContext returns the authenticated user from context
@@ -76,13 +76,20 @@ func (m *Middleware) AuthenticateBearer(next http.Handler) http.Handler {
 		// Validate API key from database
 		ctx := r.Context()
 
-		// Find API key by iterating (bcrypt comparison is slow, so we'll use prefix matching first)
-		// For production, we'd want to store a hash that can be looked up directly
+		// Find API key by its embedded lookup ID (single indexed read)
 		keys, err := m.findAPIKeyByToken(ctx, token)
 		if err != nil || keys == nil {
 			http.Error(w, `{"error": "invalid api key"}`, http.StatusUnauthorized)
 			return
 		}
+		if keys.IsExpired() {
+			http.Error(w, `{"error": "api key expired"}`, http.StatusUnauthorized)
+			return
+		}
+		if !apiKeyAllows(keys, r) {
+			http.Error(w, `{"error": "api key scope does not allow this request"}`, http.StatusForbidden)
+			return
+		}
 
 		// Update last used at
 		_ = m.repos.APIKey.UpdateLastUsedAt(ctx, keys.ID)
@@ -93,6 +100,10 @@ func (m *Middleware) AuthenticateBearer(next http.Handler) http.Handler {
 			http.Error(w, `{"error": "user not found"}`, http.StatusUnauthorized)
 			return
 		}
+		if user.Disabled {
+			http.Error(w, `{"error": "user is disabled"}`, http.StatusUnauthorized)
+			return
+		}
 
 		// Set user in context
 		r = setUserContext(r, user)
@@ -100,28 +111,9 @@ func (m *Middleware) AuthenticateBearer(next http.Handler) http.Handler {
 	})
 }
 
-// findAPIKeyByToken finds an API key by comparing bcrypt hash
+// findAPIKeyByToken finds an API key by its lookup ID and verifies it
 func (m *Middleware) findAPIKeyByToken(ctx context.Context, token string) (*domain.APIKey, error) {
-	// Get all API keys and compare
-	// This is not efficient, but works for memory storage
-	// For production with PostgreSQL, we'd use a different approach
-	users, err := m.repos.User.FindAll(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, user := range users {
-		keys, err := m.repos.APIKey.FindByUserID(ctx, user.ID)
-		if err != nil {
-			continue
-		}
-		for _, key := range keys {
-			if err := bcrypt.CompareHashAndPassword([]byte(key.KeyHash), []byte(token)); err == nil {
-				return key, nil
-			}
-		}
-	}
-	return nil, nil
+	return m.apiKeys.Find(ctx, token)
 }
 
 // AuthenticateSession validates session cookie authentication
@@ -155,6 +147,10 @@ func (m *Middleware) AuthenticateSession(next http.Handler) http.Handler {
 			http.Error(w, `{"error": "user not found"}`, http.StatusUnauthorized)
 			return
 		}
+		if user.Disabled {
+			http.Error(w, `{"error": "user is disabled"}`, http.StatusUnauthorized)
+			return
+		}
 
 		// Set user in context
 		r = setUserContext(r, user)
@@ -205,6 +201,32 @@ func (m *Middleware) OptionalBearerOrSession(next http.Handler) http.Handler {
 	})
 }
 
+// RequireRole rejects requests whose user does not have at least the given role.
+// It must run after one of the authentication middlewares. Requests made with the
+// fixed dev API key carry no user and are let through, except for admin routes.
+func (m *Middleware) RequireRole(role domain.UserRole) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := GetUserFromContext(r.Context())
+			if user == nil {
+				if m.cfg.APIKeyFixed != "" && role != domain.UserRoleAdmin {
+					next.ServeHTTP(w, r)
+					return
+				}
+				http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+				return
+			}
+
+			if !user.Role.Allows(role) {
+				http.Error(w, `{"error": "insufficient role"}`, http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // RequestLogger logs requests in dev mode
 func (m *Middleware) RequestLogger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -254,7 +276,8 @@ func (m *Middleware) CORS(next http.Handler) http.Handler {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match")
+			w.Header().Set("Access-Control-Expose-Headers", "ETag")
 
 			// Handle preflight requests
 			if r.Method == "OPTIONS" {
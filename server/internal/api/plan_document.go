@@ -3,21 +3,35 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/satetsu888/agentrace/server/internal/domain"
+	"github.com/satetsu888/agentrace/server/internal/eventbus"
+	"github.com/satetsu888/agentrace/server/internal/planpatch"
 	"github.com/satetsu888/agentrace/server/internal/repository"
 )
 
 type PlanDocumentHandler struct {
 	repos *repository.Repositories
+	bus   *eventbus.Bus
 }
 
-func NewPlanDocumentHandler(repos *repository.Repositories) *PlanDocumentHandler {
-	return &PlanDocumentHandler{repos: repos}
+func NewPlanDocumentHandler(repos *repository.Repositories, bus *eventbus.Bus) *PlanDocumentHandler {
+	return &PlanDocumentHandler{repos: repos, bus: bus}
+}
+
+// publishPlanChange notifies global stream subscribers of a plan change
+func (h *PlanDocumentHandler) publishPlanChange(msgType string, doc *domain.PlanDocument) {
+	h.bus.Publish(eventbus.Message{
+		Type:   msgType,
+		Global: true,
+		Data:   PlanStreamData{PlanID: doc.ID, ProjectID: doc.ProjectID, Status: string(doc.Status)},
+	})
 }
 
 // Response types
@@ -42,6 +56,7 @@ type PlanDocumentResponse struct {
 	CreatedAt     string                       `json:"created_at"`
 	UpdatedAt     string                       `json:"updated_at"`
 	IsFavorited   bool                         `json:"is_favorited"`
+	Revision      int                          `json:"revision"`
 }
 
 type PlanDocumentListResponse struct {
@@ -66,6 +81,19 @@ type PlanDocumentEventsResponse struct {
 	Events []*PlanDocumentEventResponse `json:"events"`
 }
 
+type PlanDocumentRevisionResponse struct {
+	EventID        string `json:"event_id"`
+	PlanDocumentID string `json:"plan_document_id"`
+	Body           string `json:"body"`
+	CreatedAt      string `json:"created_at"`
+}
+
+type PlanDocumentDiffResponse struct {
+	From  string `json:"from"`
+	To    string `json:"to"` // event ID, or "current" for the current body
+	Patch string `json:"patch"`
+}
+
 // Request types
 
 type CreatePlanDocumentRequest struct {
@@ -92,8 +120,37 @@ type SetPlanDocumentStatusRequest struct {
 	Message *string `json:"message"`
 }
 
+type RestorePlanDocumentRequest struct {
+	EventID         string  `json:"event_id"`
+	Message         *string `json:"message"`
+	ClaudeSessionID *string `json:"claude_session_id"`
+	ToolUseID       *string `json:"tool_use_id"`
+}
+
 // Helper functions
 
+var errRevisionNotFound = errors.New("revision not found")
+
+// bodyAtEvent reconstructs the plan body as of the given event by replaying the events up to it
+func bodyAtEvent(events []*domain.PlanDocumentEvent, eventID string) (string, *domain.PlanDocumentEvent, error) {
+	for i, event := range events {
+		if event.ID == eventID {
+			body, err := planpatch.Replay(events[:i+1])
+			return body, event, err
+		}
+	}
+	return "", nil, errRevisionNotFound
+}
+
+// writeRevisionError responds to a failed bodyAtEvent
+func writeRevisionError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errRevisionNotFound) {
+		http.Error(w, `{"error": "revision not found"}`, http.StatusNotFound)
+		return
+	}
+	http.Error(w, `{"error": "failed to reconstruct revision"}`, http.StatusInternalServerError)
+}
+
 func (h *PlanDocumentHandler) planDocumentToResponse(ctx context.Context, doc *domain.PlanDocument, isFavorited bool) (*PlanDocumentResponse, error) {
 	// Get collaborator user IDs from events
 	userIDs, err := h.repos.PlanDocumentEvent.GetCollaboratorUserIDs(ctx, doc.ID)
@@ -135,9 +192,74 @@ func (h *PlanDocumentHandler) planDocumentToResponse(ctx context.Context, doc *d
 		CreatedAt:     doc.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt:     doc.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		IsFavorited:   isFavorited,
+		Revision:      doc.Revision,
 	}, nil
 }
 
+// planETag formats a plan document revision as a strong entity tag
+func planETag(revision int) string {
+	return `"` + strconv.Itoa(revision) + `"`
+}
+
+// parseIfMatch returns the revision required by the If-Match header.
+// ok is false when the header is absent or "*" (no precondition).
+func parseIfMatch(r *http.Request) (revision int, ok bool, err error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return 0, false, nil
+	}
+	value = strings.TrimPrefix(value, "W/")
+	value = strings.Trim(value, `"`)
+	revision, err = strconv.Atoi(value)
+	if err != nil {
+		return 0, false, err
+	}
+	return revision, true, nil
+}
+
+// writeRevisionConflict responds 409 with the current revision so the client can rebase
+func writeRevisionConflict(w http.ResponseWriter, currentRevision int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", planETag(currentRevision))
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":            "revision conflict",
+		"current_revision": currentRevision,
+	})
+}
+
+// findVisiblePlan returns the plan document if the request's user can read its project.
+// Plans of other teams' projects are reported as not found (nil).
+func (h *PlanDocumentHandler) findVisiblePlan(ctx context.Context, id string) (*domain.PlanDocument, error) {
+	viewer, err := sessionViewer(ctx, h.repos)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := h.repos.PlanDocument.FindByID(ctx, id)
+	if err != nil || doc == nil {
+		return nil, err
+	}
+	if !viewer.CanViewProject(doc.ProjectID) {
+		return nil, nil
+	}
+	return doc, nil
+}
+
+// handleRevisionConflict re-reads the plan document after a failed compare-and-swap
+// and responds with its current revision
+func (h *PlanDocumentHandler) handleRevisionConflict(ctx context.Context, w http.ResponseWriter, id string) {
+	doc, err := h.repos.PlanDocument.FindByID(ctx, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch plan document"}`, http.StatusInternalServerError)
+		return
+	}
+	if doc == nil {
+		http.Error(w, `{"error": "plan document not found"}`, http.StatusNotFound)
+		return
+	}
+	writeRevisionConflict(w, doc.Revision)
+}
+
 func (h *PlanDocumentHandler) eventToResponse(ctx context.Context, event *domain.PlanDocumentEvent) *PlanDocumentEventResponse {
 	var userName *string
 	if event.UserID != nil {
@@ -249,12 +371,19 @@ func (h *PlanDocumentHandler) List(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	viewer, err := sessionViewer(ctx, h.repos)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch plan documents"}`, http.StatusInternalServerError)
+		return
+	}
+
 	// Use unified Find method with query object
 	query := domain.PlanDocumentQuery{
 		ProjectID:           projectID,
 		Statuses:            statuses,
 		DescriptionContains: descriptionParam,
 		PlanDocumentIDs:     planDocumentIDs,
+		ExcludeProjectIDs:   viewer.HiddenProjectIDs,
 		Limit:               limit,
 		Cursor:              cursor,
 		SortBy:              sortBy,
@@ -321,7 +450,7 @@ func (h *PlanDocumentHandler) Get(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	doc, err := h.repos.PlanDocument.FindByID(ctx, id)
+	doc, err := h.findVisiblePlan(ctx, id)
 	if err != nil {
 		http.Error(w, `{"error": "failed to fetch plan document"}`, http.StatusInternalServerError)
 		return
@@ -347,6 +476,7 @@ func (h *PlanDocumentHandler) Get(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", planETag(doc.Revision))
 	json.NewEncoder(w).Encode(resp)
 }
 
@@ -357,7 +487,7 @@ func (h *PlanDocumentHandler) GetEvents(w http.ResponseWriter, r *http.Request)
 	id := vars["id"]
 
 	// First check if the plan document exists
-	doc, err := h.repos.PlanDocument.FindByID(ctx, id)
+	doc, err := h.findVisiblePlan(ctx, id)
 	if err != nil {
 		http.Error(w, `{"error": "failed to fetch plan document"}`, http.StatusInternalServerError)
 		return
@@ -384,6 +514,103 @@ func (h *PlanDocumentHandler) GetEvents(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(response)
 }
 
+// GetRevision returns the body of a plan document as it was at the given event
+func (h *PlanDocumentHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	id := vars["id"]
+	eventID := vars["eventId"]
+
+	doc, err := h.findVisiblePlan(ctx, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch plan document"}`, http.StatusInternalServerError)
+		return
+	}
+	if doc == nil {
+		http.Error(w, `{"error": "plan document not found"}`, http.StatusNotFound)
+		return
+	}
+
+	events, err := h.repos.PlanDocumentEvent.FindByPlanDocumentID(ctx, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch events"}`, http.StatusInternalServerError)
+		return
+	}
+
+	body, event, err := bodyAtEvent(events, eventID)
+	if err != nil {
+		writeRevisionError(w, err)
+		return
+	}
+
+	response := PlanDocumentRevisionResponse{
+		EventID:        event.ID,
+		PlanDocumentID: doc.ID,
+		Body:           body,
+		CreatedAt:      event.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetDiff returns the patch between two revisions of a plan document.
+// Query parameters: from (event ID, required), to (event ID, defaults to the current body)
+func (h *PlanDocumentHandler) GetDiff(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	fromID := r.URL.Query().Get("from")
+	if fromID == "" {
+		http.Error(w, `{"error": "from is required"}`, http.StatusBadRequest)
+		return
+	}
+	toID := r.URL.Query().Get("to")
+
+	doc, err := h.findVisiblePlan(ctx, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch plan document"}`, http.StatusInternalServerError)
+		return
+	}
+	if doc == nil {
+		http.Error(w, `{"error": "plan document not found"}`, http.StatusNotFound)
+		return
+	}
+
+	events, err := h.repos.PlanDocumentEvent.FindByPlanDocumentID(ctx, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch events"}`, http.StatusInternalServerError)
+		return
+	}
+
+	fromBody, _, err := bodyAtEvent(events, fromID)
+	if err != nil {
+		writeRevisionError(w, err)
+		return
+	}
+
+	toBody := doc.Body
+	if toID == "" {
+		toID = "current"
+	} else {
+		toBody, _, err = bodyAtEvent(events, toID)
+		if err != nil {
+			writeRevisionError(w, err)
+			return
+		}
+	}
+
+	response := PlanDocumentDiffResponse{
+		From:  fromID,
+		To:    toID,
+		Patch: planpatch.Make(fromBody, toBody),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
 // Create creates a new plan document
 func (h *PlanDocumentHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -419,6 +646,16 @@ func (h *PlanDocumentHandler) Create(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	viewer, err := sessionViewer(ctx, h.repos)
+	if err != nil {
+		http.Error(w, `{"error": "failed to create plan document"}`, http.StatusInternalServerError)
+		return
+	}
+	if !viewer.CanViewProject(projectID) {
+		http.Error(w, `{"error": "project not found"}`, http.StatusNotFound)
+		return
+	}
+
 	// Determine initial status (default: planning)
 	status := domain.PlanDocumentStatusPlanning
 	if req.Status != nil && *req.Status != "" {
@@ -435,26 +672,29 @@ func (h *PlanDocumentHandler) Create(w http.ResponseWriter, r *http.Request) {
 		Status:      status,
 	}
 
-	if err := h.repos.PlanDocument.Create(ctx, doc); err != nil {
+	// The document and its initial event (body as "all additions" diff) are stored
+	// together so that replaying the events always reproduces the stored body
+	err = h.repos.Tx.RunInTx(ctx, func(ctx context.Context) error {
+		if err := h.repos.PlanDocument.Create(ctx, doc); err != nil {
+			return err
+		}
+		event := &domain.PlanDocumentEvent{
+			PlanDocumentID:  doc.ID,
+			ClaudeSessionID: req.ClaudeSessionID,
+			ToolUseID:       req.ToolUseID,
+			Patch:           planpatch.InitialPatch(req.Body),
+		}
+		if userID != "" {
+			event.UserID = &userID
+		}
+		return h.repos.PlanDocumentEvent.Create(ctx, event)
+	})
+	if err != nil {
 		http.Error(w, `{"error": "failed to create plan document"}`, http.StatusInternalServerError)
 		return
 	}
 
-	// Create initial event with body as "all additions" diff
-	event := &domain.PlanDocumentEvent{
-		PlanDocumentID:  doc.ID,
-		ClaudeSessionID: req.ClaudeSessionID,
-		ToolUseID:       req.ToolUseID,
-		Patch:           bodyToInitialPatch(req.Body),
-	}
-	if userID != "" {
-		event.UserID = &userID
-	}
-
-	if err := h.repos.PlanDocumentEvent.Create(ctx, event); err != nil {
-		// Log error but don't fail the request
-		// The document was created successfully
-	}
+	h.publishPlanChange(eventbus.TypePlanCreated, doc)
 
 	resp, err := h.planDocumentToResponse(ctx, doc, false)
 	if err != nil {
@@ -463,6 +703,7 @@ func (h *PlanDocumentHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", planETag(doc.Revision))
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(resp)
 }
@@ -474,15 +715,30 @@ func (h *PlanDocumentHandler) Update(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
+	expectedRevision, hasIfMatch, err := parseIfMatch(r)
+	if err != nil {
+		http.Error(w, `{"error": "invalid If-Match header"}`, http.StatusBadRequest)
+		return
+	}
+
+	viewer, err := sessionViewer(ctx, h.repos)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch plan document"}`, http.StatusInternalServerError)
+		return
+	}
 	doc, err := h.repos.PlanDocument.FindByID(ctx, id)
 	if err != nil {
 		http.Error(w, `{"error": "failed to fetch plan document"}`, http.StatusInternalServerError)
 		return
 	}
-	if doc == nil {
+	if doc == nil || !viewer.CanViewProject(doc.ProjectID) {
 		http.Error(w, `{"error": "plan document not found"}`, http.StatusNotFound)
 		return
 	}
+	if hasIfMatch && doc.Revision != expectedRevision {
+		writeRevisionConflict(w, doc.Revision)
+		return
+	}
 
 	var req UpdatePlanDocumentRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -493,29 +749,44 @@ func (h *PlanDocumentHandler) Update(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context (set by auth middleware)
 	userID := GetUserIDFromContext(ctx)
 
+	// Resolve the new body: a supplied body wins, and the stored patch is always
+	// computed here; a patch alone is applied to the stored body
+	oldBody := doc.Body
+	newBody := oldBody
+	if req.Body != nil {
+		newBody = *req.Body
+	} else if req.Patch != nil {
+		patched, err := planpatch.Apply(oldBody, *req.Patch)
+		if err != nil {
+			http.Error(w, `{"error": "patch does not apply to the current body"}`, http.StatusUnprocessableEntity)
+			return
+		}
+		newBody = patched
+	}
+
 	// Update fields if provided
 	if req.Description != nil {
 		doc.Description = *req.Description
 	}
-	if req.Body != nil {
-		doc.Body = *req.Body
-	}
+	doc.Body = newBody
 	if req.ProjectID != nil {
+		// Plans cannot be moved into another team's project
+		if !viewer.CanViewProject(*req.ProjectID) {
+			http.Error(w, `{"error": "project not found"}`, http.StatusNotFound)
+			return
+		}
 		doc.ProjectID = *req.ProjectID
 	}
 
-	if err := h.repos.PlanDocument.Update(ctx, doc); err != nil {
-		http.Error(w, `{"error": "failed to update plan document"}`, http.StatusInternalServerError)
-		return
-	}
-
-	// Create event with patch if provided
-	if req.Patch != nil {
-		event := &domain.PlanDocumentEvent{
+	// Record the body change with a server-computed patch, in the same transaction,
+	// so that replaying the events always reproduces the stored body
+	var event *domain.PlanDocumentEvent
+	if newBody != oldBody {
+		event = &domain.PlanDocumentEvent{
 			PlanDocumentID:  doc.ID,
 			ClaudeSessionID: req.ClaudeSessionID,
 			ToolUseID:       req.ToolUseID,
-			Patch:           *req.Patch,
+			Patch:           planpatch.Make(oldBody, newBody),
 		}
 		if req.Message != nil {
 			event.Message = *req.Message
@@ -523,13 +794,28 @@ func (h *PlanDocumentHandler) Update(w http.ResponseWriter, r *http.Request) {
 		if userID != "" {
 			event.UserID = &userID
 		}
+	}
 
-		if err := h.repos.PlanDocumentEvent.Create(ctx, event); err != nil {
-			// Log error but don't fail the request
-			// The document was updated successfully
+	err = h.repos.Tx.RunInTx(ctx, func(ctx context.Context) error {
+		if err := h.repos.PlanDocument.Update(ctx, doc); err != nil {
+			return err
 		}
+		if event == nil {
+			return nil
+		}
+		return h.repos.PlanDocumentEvent.Create(ctx, event)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrRevisionConflict) {
+			h.handleRevisionConflict(ctx, w, id)
+			return
+		}
+		http.Error(w, `{"error": "failed to update plan document"}`, http.StatusInternalServerError)
+		return
 	}
 
+	h.publishPlanChange(eventbus.TypePlanUpdated, doc)
+
 	// Check if favorited
 	var isFavorited bool
 	if currentUserID != "" {
@@ -546,6 +832,7 @@ func (h *PlanDocumentHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", planETag(doc.Revision))
 	json.NewEncoder(w).Encode(resp)
 }
 
@@ -555,7 +842,7 @@ func (h *PlanDocumentHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	doc, err := h.repos.PlanDocument.FindByID(ctx, id)
+	doc, err := h.findVisiblePlan(ctx, id)
 	if err != nil {
 		http.Error(w, `{"error": "failed to fetch plan document"}`, http.StatusInternalServerError)
 		return
@@ -570,6 +857,8 @@ func (h *PlanDocumentHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.publishPlanChange(eventbus.TypePlanDeleted, doc)
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -580,7 +869,13 @@ func (h *PlanDocumentHandler) SetStatus(w http.ResponseWriter, r *http.Request)
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	doc, err := h.repos.PlanDocument.FindByID(ctx, id)
+	expectedRevision, hasIfMatch, err := parseIfMatch(r)
+	if err != nil {
+		http.Error(w, `{"error": "invalid If-Match header"}`, http.StatusBadRequest)
+		return
+	}
+
+	doc, err := h.findVisiblePlan(ctx, id)
 	if err != nil {
 		http.Error(w, `{"error": "failed to fetch plan document"}`, http.StatusInternalServerError)
 		return
@@ -589,6 +884,10 @@ func (h *PlanDocumentHandler) SetStatus(w http.ResponseWriter, r *http.Request)
 		http.Error(w, `{"error": "plan document not found"}`, http.StatusNotFound)
 		return
 	}
+	if hasIfMatch && doc.Revision != expectedRevision {
+		writeRevisionConflict(w, doc.Revision)
+		return
+	}
 
 	var req SetPlanDocumentStatusRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -605,11 +904,6 @@ func (h *PlanDocumentHandler) SetStatus(w http.ResponseWriter, r *http.Request)
 	// Store old status for event
 	oldStatus := doc.Status
 
-	if err := h.repos.PlanDocument.SetStatus(ctx, id, status); err != nil {
-		http.Error(w, `{"error": "failed to update status"}`, http.StatusInternalServerError)
-		return
-	}
-
 	// Get user ID from context (set by auth middleware)
 	userID := GetUserIDFromContext(ctx)
 
@@ -625,8 +919,27 @@ func (h *PlanDocumentHandler) SetStatus(w http.ResponseWriter, r *http.Request)
 	if userID != "" {
 		event.UserID = &userID
 	}
-	// Ignore error - status was updated successfully
-	h.repos.PlanDocumentEvent.Create(ctx, event)
+
+	err = h.repos.Tx.RunInTx(ctx, func(ctx context.Context) error {
+		if _, err := h.repos.PlanDocument.SetStatus(ctx, id, status, doc.Revision); err != nil {
+			return err
+		}
+		return h.repos.PlanDocumentEvent.Create(ctx, event)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrRevisionConflict) {
+			h.handleRevisionConflict(ctx, w, id)
+			return
+		}
+		http.Error(w, `{"error": "failed to update status"}`, http.StatusInternalServerError)
+		return
+	}
+	_ = h.repos.Analytics.RecordActivity(ctx, domain.AnalyticsActivity{
+		Time:            time.Now(),
+		ProjectID:       doc.ProjectID,
+		UserID:          userID,
+		AnalyticsCounts: domain.AnalyticsCounts{PlanStatusChanges: 1},
+	})
 
 	// Fetch updated document
 	doc, err = h.repos.PlanDocument.FindByID(ctx, id)
@@ -635,6 +948,8 @@ func (h *PlanDocumentHandler) SetStatus(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	h.publishPlanChange(eventbus.TypePlanUpdated, doc)
+
 	// Check if favorited
 	var isFavorited bool
 	if currentUserID != "" {
@@ -651,19 +966,113 @@ func (h *PlanDocumentHandler) SetStatus(w http.ResponseWriter, r *http.Request)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", planETag(doc.Revision))
 	json.NewEncoder(w).Encode(resp)
 }
 
-// bodyToInitialPatch converts body content to an "all additions" diff format.
-// Each line is prefixed with "+" to indicate it was added.
-func bodyToInitialPatch(body string) string {
-	if body == "" {
-		return ""
+// Restore reverts the body of a plan document to the revision at the given event,
+// recording the change as a new event
+func (h *PlanDocumentHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	currentUserID := GetUserIDFromContext(ctx)
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	expectedRevision, hasIfMatch, err := parseIfMatch(r)
+	if err != nil {
+		http.Error(w, `{"error": "invalid If-Match header"}`, http.StatusBadRequest)
+		return
+	}
+
+	doc, err := h.findVisiblePlan(ctx, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch plan document"}`, http.StatusInternalServerError)
+		return
+	}
+	if doc == nil {
+		http.Error(w, `{"error": "plan document not found"}`, http.StatusNotFound)
+		return
+	}
+	if hasIfMatch && doc.Revision != expectedRevision {
+		writeRevisionConflict(w, doc.Revision)
+		return
+	}
+
+	var req RestorePlanDocumentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+	if req.EventID == "" {
+		http.Error(w, `{"error": "event_id is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	events, err := h.repos.PlanDocumentEvent.FindByPlanDocumentID(ctx, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch events"}`, http.StatusInternalServerError)
+		return
+	}
+
+	restoredBody, restoredEvent, err := bodyAtEvent(events, req.EventID)
+	if err != nil {
+		writeRevisionError(w, err)
+		return
+	}
+
+	// Restoring the current body is a no-op
+	if restoredBody != doc.Body {
+		oldBody := doc.Body
+		doc.Body = restoredBody
+
+		event := &domain.PlanDocumentEvent{
+			PlanDocumentID:  doc.ID,
+			ClaudeSessionID: req.ClaudeSessionID,
+			ToolUseID:       req.ToolUseID,
+			Patch:           planpatch.Make(oldBody, restoredBody),
+			Message:         "Restored revision from " + restoredEvent.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+		if req.Message != nil && *req.Message != "" {
+			event.Message = *req.Message
+		}
+		if currentUserID != "" {
+			event.UserID = &currentUserID
+		}
+
+		err := h.repos.Tx.RunInTx(ctx, func(ctx context.Context) error {
+			if err := h.repos.PlanDocument.Update(ctx, doc); err != nil {
+				return err
+			}
+			return h.repos.PlanDocumentEvent.Create(ctx, event)
+		})
+		if err != nil {
+			if errors.Is(err, repository.ErrRevisionConflict) {
+				h.handleRevisionConflict(ctx, w, id)
+				return
+			}
+			http.Error(w, `{"error": "failed to restore plan document"}`, http.StatusInternalServerError)
+			return
+		}
+
+		h.publishPlanChange(eventbus.TypePlanUpdated, doc)
 	}
-	lines := strings.Split(body, "\n")
-	result := make([]string, len(lines))
-	for i, line := range lines {
-		result[i] = "+" + line
+
+	// Check if favorited
+	var isFavorited bool
+	if currentUserID != "" {
+		fav, err := h.repos.UserFavorite.FindByUserAndTarget(ctx, currentUserID, domain.UserFavoriteTargetTypePlan, id)
+		if err == nil && fav != nil {
+			isFavorited = true
+		}
 	}
-	return strings.Join(result, "\n")
+
+	resp, err := h.planDocumentToResponse(ctx, doc, isFavorited)
+	if err != nil {
+		http.Error(w, `{"error": "failed to build response"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", planETag(doc.Revision))
+	json.NewEncoder(w).Encode(resp)
 }
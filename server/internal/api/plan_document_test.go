@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+	"github.com/satetsu888/agentrace/server/internal/planpatch"
+)
+
+// cliPatch is the patch the CLI's diff-match-patch writes for appending line after
+// the last line of body: offsets in UTF-16 code units, four characters of context
+func cliPatch(body, lastLine, line string) string {
+	start := len([]rune(strings.TrimSuffix(body, lastLine))) + 1
+	context := []rune(lastLine)
+	context = context[len(context)-4:]
+	return fmt.Sprintf("@@ -%d,4 +%d,%d @@\n %s\n+%s\n", start, start, 4+len([]rune(line)), url.PathEscape(string(context)), url.PathEscape(line))
+}
+
+func TestPlanDocumentUpdate_NonASCII(t *testing.T) {
+	s := newTestServer(t)
+	_, key := s.createUser("member@example.com", domain.UserRoleMember)
+
+	body := "# 計画\n\n" + strings.Repeat("計画の手順です。", 37) + "\n- 手順1\n"
+	rec := s.do(http.MethodPost, "/api/plans", key, map[string]string{"description": "計画", "body": body})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create plan: status %d: %s", rec.Code, rec.Body.String())
+	}
+	var plan PlanDocumentResponse
+	s.decode(rec, &plan)
+
+	// Body and patch, as update_plan sends them
+	withStep2 := body + "- 手順2\n"
+	rec = s.do(http.MethodPatch, "/api/plans/"+plan.ID, key, map[string]string{
+		"body":  withStep2,
+		"patch": cliPatch(body, "- 手順1\n", "- 手順2\n"),
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update with body and patch: status %d: %s", rec.Code, rec.Body.String())
+	}
+	s.decode(rec, &plan)
+	if plan.Body != withStep2 {
+		t.Errorf("body = %q, want %q", plan.Body, withStep2)
+	}
+
+	// A patch alone is applied to the stored body
+	withStep3 := withStep2 + "- 手順3\n"
+	rec = s.do(http.MethodPatch, "/api/plans/"+plan.ID, key, map[string]string{
+		"patch": cliPatch(withStep2, "- 手順2\n", "- 手順3\n"),
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update with patch: status %d: %s", rec.Code, rec.Body.String())
+	}
+	s.decode(rec, &plan)
+	if plan.Body != withStep3 {
+		t.Errorf("body = %q, want %q", plan.Body, withStep3)
+	}
+
+	// The stored events replay to the stored body
+	events, err := s.repos.PlanDocumentEvent.FindByPlanDocumentID(context.Background(), plan.ID)
+	if err != nil {
+		t.Fatalf("find events: %v", err)
+	}
+	replayed, err := planpatch.Replay(events)
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if replayed != withStep3 {
+		t.Errorf("Replay() = %q, want %q", replayed, withStep3)
+	}
+}
@@ -20,9 +20,10 @@ func NewProjectHandler(repos *repository.Repositories) *ProjectHandler {
 // Response types
 
 type ProjectListItemResponse struct {
-	ID                     string `json:"id"`
-	CanonicalGitRepository string `json:"canonical_git_repository"`
-	CreatedAt              string `json:"created_at"`
+	ID                     string  `json:"id"`
+	CanonicalGitRepository string  `json:"canonical_git_repository"`
+	TeamID                 *string `json:"team_id"` // the team owning the project; null = visible to everyone
+	CreatedAt              string  `json:"created_at"`
 }
 
 type ProjectListResponse struct {
@@ -31,9 +32,10 @@ type ProjectListResponse struct {
 }
 
 type ProjectResponse struct {
-	ID                     string `json:"id"`
-	CanonicalGitRepository string `json:"canonical_git_repository"`
-	CreatedAt              string `json:"created_at"`
+	ID                     string  `json:"id"`
+	CanonicalGitRepository string  `json:"canonical_git_repository"`
+	TeamID                 *string `json:"team_id"` // the team owning the project; null = visible to everyone
+	CreatedAt              string  `json:"created_at"`
 }
 
 // List returns all projects
@@ -49,19 +51,30 @@ func (h *ProjectHandler) List(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	viewer, err := sessionViewer(ctx, h.repos)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch projects"}`, http.StatusInternalServerError)
+		return
+	}
 	projects, nextCursor, err := h.repos.Project.FindAll(ctx, limit, cursor)
 	if err != nil {
 		http.Error(w, `{"error": "failed to fetch projects"}`, http.StatusInternalServerError)
 		return
 	}
 
-	responses := make([]*ProjectListItemResponse, len(projects))
-	for i, p := range projects {
-		responses[i] = &ProjectListItemResponse{
+	// Projects of other teams are dropped within the page (the page may be
+	// shorter than limit; next_cursor still continues from the last fetched project)
+	responses := make([]*ProjectListItemResponse, 0, len(projects))
+	for _, p := range projects {
+		if !viewer.CanViewProject(p.ID) {
+			continue
+		}
+		responses = append(responses, &ProjectListItemResponse{
 			ID:                     p.ID,
 			CanonicalGitRepository: p.CanonicalGitRepository,
+			TeamID:                 p.TeamID,
 			CreatedAt:              p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		}
+		})
 	}
 
 	response := ProjectListResponse{
@@ -79,12 +92,17 @@ func (h *ProjectHandler) Get(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
+	viewer, err := sessionViewer(ctx, h.repos)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch project"}`, http.StatusInternalServerError)
+		return
+	}
 	project, err := h.repos.Project.FindByID(ctx, id)
 	if err != nil {
 		http.Error(w, `{"error": "failed to fetch project"}`, http.StatusInternalServerError)
 		return
 	}
-	if project == nil {
+	if project == nil || !viewer.CanViewProject(project.ID) {
 		http.Error(w, `{"error": "project not found"}`, http.StatusNotFound)
 		return
 	}
@@ -92,6 +110,7 @@ func (h *ProjectHandler) Get(w http.ResponseWriter, r *http.Request) {
 	response := ProjectResponse{
 		ID:                     project.ID,
 		CanonicalGitRepository: project.CanonicalGitRepository,
+		TeamID:                 project.TeamID,
 		CreatedAt:              project.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 
@@ -6,14 +6,19 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/satetsu888/agentrace/server/internal/config"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+	"github.com/satetsu888/agentrace/server/internal/eventbus"
+	"github.com/satetsu888/agentrace/server/internal/pricing"
+	"github.com/satetsu888/agentrace/server/internal/redact"
 	"github.com/satetsu888/agentrace/server/internal/repository"
 )
 
-func NewRouter(cfg *config.Config, repos *repository.Repositories) http.Handler {
+func NewRouter(cfg *config.Config, repos *repository.Repositories, redactor *redact.Redactor, prices *pricing.Table) http.Handler {
 	r := mux.NewRouter()
 
 	// Middleware
-	mw := NewMiddleware(cfg, repos)
+	apiKeys := NewAPIKeyFinder(repos)
+	mw := NewMiddleware(cfg, repos, apiKeys)
 
 	// Apply CORS and request logger to all routes
 	r.Use(mw.CORS)
@@ -25,13 +30,20 @@ func NewRouter(cfg *config.Config, repos *repository.Repositories) http.Handler
 		w.WriteHeader(http.StatusNoContent)
 	})
 
+	// In-process event bus for live streams
+	bus := eventbus.New(eventbus.DefaultHistorySize)
+
 	// Handlers
-	ingestHandler := NewIngestHandler(repos)
+	ingestHandler := NewIngestHandler(repos, redactor, prices, bus, cfg.MaxIngestBodySize)
 	sessionHandler := NewSessionHandler(repos)
-	authHandler := NewAuthHandler(cfg, repos)
-	planDocumentHandler := NewPlanDocumentHandler(repos)
+	authHandler := NewAuthHandler(cfg, repos, apiKeys)
+	planDocumentHandler := NewPlanDocumentHandler(repos, bus)
 	projectHandler := NewProjectHandler(repos)
 	userFavoriteHandler := NewUserFavoriteHandler(repos)
+	streamHandler := NewStreamHandler(repos, bus)
+	analyticsHandler := NewAnalyticsHandler(repos)
+	importHandler := NewImportHandler(repos, ingestHandler, cfg.MaxIngestBodySize)
+	adminHandler := NewAdminHandler(repos)
 
 	// Auth routes (no auth required)
 	r.HandleFunc("/auth/register", authHandler.Register).Methods("POST")
@@ -44,17 +56,19 @@ func NewRouter(cfg *config.Config, repos *repository.Repositories) http.Handler
 	// API routes (Bearer auth - for CLI)
 	apiBearer := r.PathPrefix("/api").Subrouter()
 	apiBearer.Use(mw.AuthenticateBearer)
-	apiBearer.HandleFunc("/ingest", ingestHandler.Handle).Methods("POST")
+	apiBearer.Handle("/ingest", mw.RequireRole(domain.UserRoleMember)(http.HandlerFunc(ingestHandler.Handle))).Methods("POST")
 	apiBearer.HandleFunc("/auth/web-session", authHandler.CreateWebSession).Methods("POST")
 
-	// API routes (Bearer or Session auth - for CLI and Web)
+	// API routes (Bearer or Session auth - for CLI and Web, writes need the member role)
 	apiBearerOrSession := r.PathPrefix("/api").Subrouter()
-	apiBearerOrSession.Use(mw.AuthenticateBearerOrSession)
+	apiBearerOrSession.Use(mw.AuthenticateBearerOrSession, mw.RequireRole(domain.UserRoleMember))
+	apiBearerOrSession.HandleFunc("/import", importHandler.Upload).Methods("POST")
 	apiBearerOrSession.HandleFunc("/sessions/{id}", sessionHandler.Update).Methods("PATCH")
 	apiBearerOrSession.HandleFunc("/plans", planDocumentHandler.Create).Methods("POST")
 	apiBearerOrSession.HandleFunc("/plans/{id}", planDocumentHandler.Update).Methods("PATCH")
 	apiBearerOrSession.HandleFunc("/plans/{id}", planDocumentHandler.Delete).Methods("DELETE")
 	apiBearerOrSession.HandleFunc("/plans/{id}/status", planDocumentHandler.SetStatus).Methods("PATCH")
+	apiBearerOrSession.HandleFunc("/plans/{id}/restore", planDocumentHandler.Restore).Methods("POST")
 
 	// API routes (Session auth - for Web)
 	apiSession := r.PathPrefix("/api").Subrouter()
@@ -69,17 +83,49 @@ func NewRouter(cfg *config.Config, repos *repository.Repositories) http.Handler
 	apiSession.HandleFunc("/user-favorites", userFavoriteHandler.Create).Methods("POST")
 	apiSession.HandleFunc("/user-favorites", userFavoriteHandler.Delete).Methods("DELETE")
 
-	// API routes (Optional auth - public read access)
+	// API routes (admin only)
+	apiAdmin := r.PathPrefix("/api/admin").Subrouter()
+	apiAdmin.Use(mw.AuthenticateBearerOrSession, mw.RequireRole(domain.UserRoleAdmin))
+	apiAdmin.HandleFunc("/users", adminHandler.ListUsers).Methods("GET")
+	apiAdmin.HandleFunc("/users/{id}", adminHandler.UpdateUser).Methods("PATCH")
+	apiAdmin.HandleFunc("/users/{id}/revoke", adminHandler.RevokeUser).Methods("POST")
+	apiAdmin.HandleFunc("/teams", adminHandler.ListTeams).Methods("GET")
+	apiAdmin.HandleFunc("/teams", adminHandler.CreateTeam).Methods("POST")
+	apiAdmin.HandleFunc("/teams/{id}", adminHandler.DeleteTeam).Methods("DELETE")
+	apiAdmin.HandleFunc("/teams/{id}/members", adminHandler.AddTeamMember).Methods("POST")
+	apiAdmin.HandleFunc("/teams/{id}/members/{userId}", adminHandler.RemoveTeamMember).Methods("DELETE")
+	apiAdmin.HandleFunc("/projects/{id}", adminHandler.UpdateProjectTeam).Methods("PATCH")
+	apiAdmin.HandleFunc("/sessions/{id}", adminHandler.DeleteSession).Methods("DELETE")
+
+	// API routes (Optional auth - public read access, unless REQUIRE_AUTH is set)
 	apiOptional := r.PathPrefix("/api").Subrouter()
-	apiOptional.Use(mw.OptionalBearerOrSession)
+	if cfg.RequireAuth {
+		apiOptional.Use(mw.AuthenticateBearerOrSession)
+	} else {
+		apiOptional.Use(mw.OptionalBearerOrSession)
+	}
 	apiOptional.HandleFunc("/sessions", sessionHandler.List).Methods("GET")
+	apiOptional.HandleFunc("/sessions/search", sessionHandler.Search).Methods("GET")
 	apiOptional.HandleFunc("/sessions/{id}", sessionHandler.Get).Methods("GET")
+	apiOptional.HandleFunc("/sessions/{id}/export", sessionHandler.Export).Methods("GET")
+	apiOptional.HandleFunc("/sessions/{id}/tree", sessionHandler.Tree).Methods("GET")
+	apiOptional.HandleFunc("/sessions/{id}/chain", sessionHandler.Chain).Methods("GET")
+	apiOptional.HandleFunc("/sessions/{id}/files", sessionHandler.Files).Methods("GET")
+	apiOptional.HandleFunc("/sessions/{id}/diff", sessionHandler.Diff).Methods("GET")
+	apiOptional.HandleFunc("/sessions/{id}/tools", sessionHandler.Tools).Methods("GET")
+	apiOptional.HandleFunc("/sessions/{id}/stream", streamHandler.SessionStream).Methods("GET")
+	apiOptional.HandleFunc("/stream", streamHandler.GlobalStream).Methods("GET")
 	apiOptional.HandleFunc("/plans", planDocumentHandler.List).Methods("GET")
 	apiOptional.HandleFunc("/plans/{id}", planDocumentHandler.Get).Methods("GET")
 	apiOptional.HandleFunc("/plans/{id}/events", planDocumentHandler.GetEvents).Methods("GET")
+	apiOptional.HandleFunc("/plans/{id}/revisions/{eventId}", planDocumentHandler.GetRevision).Methods("GET")
+	apiOptional.HandleFunc("/plans/{id}/diff", planDocumentHandler.GetDiff).Methods("GET")
 	apiOptional.HandleFunc("/projects", projectHandler.List).Methods("GET")
 	apiOptional.HandleFunc("/projects/{id}", projectHandler.Get).Methods("GET")
+	apiOptional.HandleFunc("/projects/{id}/files", sessionHandler.ProjectFiles).Methods("GET")
+	apiOptional.HandleFunc("/projects/{id}/tools", sessionHandler.ProjectTools).Methods("GET")
 	apiOptional.HandleFunc("/users", authHandler.ListUsers).Methods("GET")
+	apiOptional.HandleFunc("/analytics", analyticsHandler.Get).Methods("GET")
 
 	// Health check (no auth)
 	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -95,8 +141,10 @@ func NewRouter(cfg *config.Config, repos *repository.Repositories) http.Handler
 		w.Header().Set("Content-Type", "application/json")
 		response := struct {
 			GitHubEnabled bool `json:"github_enabled"`
+			RequireAuth   bool `json:"require_auth"`
 		}{
 			GitHubEnabled: cfg.IsGitHubOAuthEnabled(),
+			RequireAuth:   cfg.RequireAuth,
 		}
 		json.NewEncoder(w).Encode(response)
 	}).Methods("GET")
@@ -19,23 +19,103 @@ func NewSessionHandler(repos *repository.Repositories) *SessionHandler {
 	return &SessionHandler{repos: repos}
 }
 
+// sessionViewer returns the reader of the request, anonymous when not signed in
+func sessionViewer(ctx context.Context, repos *repository.Repositories) (domain.SessionViewer, error) {
+	hidden, err := hiddenProjectIDs(ctx, repos)
+	if err != nil {
+		return domain.SessionViewer{}, err
+	}
+	return domain.SessionViewer{UserID: GetUserIDFromContext(ctx), HiddenProjectIDs: hidden}, nil
+}
+
+// hiddenProjectIDs returns the projects owned by teams the request's user is not
+// a member of. Admins see every project; anonymous readers see no team's projects.
+func hiddenProjectIDs(ctx context.Context, repos *repository.Repositories) ([]string, error) {
+	user := GetUserFromContext(ctx)
+	if user != nil && user.Role == domain.UserRoleAdmin {
+		return nil, nil
+	}
+
+	owned, err := repos.Project.FindTeamOwned(ctx)
+	if err != nil || len(owned) == 0 {
+		return nil, err
+	}
+
+	memberOf := make(map[string]bool)
+	if user != nil {
+		memberships, err := repos.TeamMembership.FindByUserID(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range memberships {
+			memberOf[m.TeamID] = true
+		}
+	}
+
+	var hidden []string
+	for _, p := range owned {
+		if p.TeamID != nil && !memberOf[*p.TeamID] {
+			hidden = append(hidden, p.ID)
+		}
+	}
+	return hidden, nil
+}
+
+// findVisibleSession returns the session if the request's user can read it.
+// Sessions hidden from the user are reported as not found (nil).
+func findVisibleSession(ctx context.Context, repos *repository.Repositories, id string) (*domain.Session, error) {
+	viewer, err := sessionViewer(ctx, repos)
+	if err != nil {
+		return nil, err
+	}
+	return findSessionFor(ctx, repos, viewer, id)
+}
+
+// findSessionFor is findVisibleSession for a viewer already looked up
+func findSessionFor(ctx context.Context, repos *repository.Repositories, viewer domain.SessionViewer, id string) (*domain.Session, error) {
+	session, err := repos.Session.FindByID(ctx, id)
+	if err != nil || session == nil {
+		return nil, err
+	}
+	if !viewer.CanView(session) {
+		return nil, nil
+	}
+	return session, nil
+}
+
 type SessionResponse struct {
 	ID              string           `json:"id"`
 	UserID          *string          `json:"user_id"`
 	UserName        *string          `json:"user_name"`
 	Project         *ProjectResponse `json:"project"`
 	ClaudeSessionID string           `json:"claude_session_id"`
+	ParentSessionID *string          `json:"parent_session_id"` // the session this one resumes or forks
 	ProjectPath     string           `json:"project_path"`
 	GitBranch       string           `json:"git_branch"`
 	Title           *string          `json:"title"`
 	StartedAt       string           `json:"started_at"`
 	EndedAt         *string          `json:"ended_at"`
+	DurationSeconds int64            `json:"duration_seconds"`
+	State           string           `json:"state"` // "active" or "ended"
 	UpdatedAt       string           `json:"updated_at"`
 	EventCount      int              `json:"event_count"`
+	RedactionCount  int              `json:"redaction_count"`
+	SubagentCount   int              `json:"subagent_count"`
+	Usage           *UsageResponse   `json:"usage"`
+	Visibility      string           `json:"visibility"` // "private", "team" or "public"
 	CreatedAt       string           `json:"created_at"`
 	IsFavorited     bool             `json:"is_favorited"`
 }
 
+type UsageResponse struct {
+	InputTokens         int64   `json:"input_tokens"`
+	OutputTokens        int64   `json:"output_tokens"`
+	CacheReadTokens     int64   `json:"cache_read_tokens"`
+	CacheCreationTokens int64   `json:"cache_creation_tokens"`
+	TotalTokens         int64   `json:"total_tokens"`
+	EstimatedCostUSD    float64 `json:"estimated_cost_usd"`
+}
+
 type SessionDetailResponse struct {
 	SessionResponse
 	Events []*EventResponse `json:"events"`
@@ -63,6 +143,7 @@ func (h *SessionHandler) sessionToResponse(ctx context.Context, s *domain.Sessio
 			projectResp = &ProjectResponse{
 				ID:                     project.ID,
 				CanonicalGitRepository: project.CanonicalGitRepository,
+				TeamID:                 project.TeamID,
 			}
 		}
 	}
@@ -73,15 +154,29 @@ func (h *SessionHandler) sessionToResponse(ctx context.Context, s *domain.Sessio
 		UserName:        userName,
 		Project:         projectResp,
 		ClaudeSessionID: s.ClaudeSessionID,
+		ParentSessionID: s.ParentSessionID,
 		ProjectPath:     s.ProjectPath,
 		GitBranch:       s.GitBranch,
 		Title:           s.Title,
 		StartedAt:       s.StartedAt.Format("2006-01-02T15:04:05Z07:00"),
 		EndedAt:         endedAt,
+		DurationSeconds: int64(s.Duration().Seconds()),
+		State:           string(s.State()),
 		UpdatedAt:       s.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		EventCount:      eventCount,
-		CreatedAt:       s.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		IsFavorited:     isFavorited,
+		RedactionCount:  s.RedactionCount,
+		SubagentCount:   s.SubagentCount,
+		Usage: &UsageResponse{
+			InputTokens:         s.Usage.InputTokens,
+			OutputTokens:        s.Usage.OutputTokens,
+			CacheReadTokens:     s.Usage.CacheReadTokens,
+			CacheCreationTokens: s.Usage.CacheCreationTokens,
+			TotalTokens:         s.Usage.TotalTokens(),
+			EstimatedCostUSD:    s.Usage.EstimatedCostUSD,
+		},
+		Visibility:  string(s.Visibility),
+		CreatedAt:   s.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		IsFavorited: isFavorited,
 	}
 }
 
@@ -145,19 +240,30 @@ func (h *SessionHandler) List(w http.ResponseWriter, r *http.Request) {
 	if sortBy != "created_at" {
 		sortBy = "updated_at"
 	}
+	// Optional state filter (active or ended)
+	state := domain.SessionState(r.URL.Query().Get("state"))
+	if state != "" && !state.IsValid() {
+		http.Error(w, `{"error": "invalid state"}`, http.StatusBadRequest)
+		return
+	}
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
 		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
 			limit = l
 		}
 	}
 
+	viewer, err := sessionViewer(ctx, h.repos)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch sessions"}`, http.StatusInternalServerError)
+		return
+	}
+
 	var sessions []*domain.Session
 	var nextCursor string
-	var err error
 	if projectID != "" {
-		sessions, nextCursor, err = h.repos.Session.FindByProjectID(ctx, projectID, limit, cursor, sortBy)
+		sessions, nextCursor, err = h.repos.Session.FindByProjectID(ctx, projectID, viewer, state, limit, cursor, sortBy)
 	} else {
-		sessions, nextCursor, err = h.repos.Session.FindAll(ctx, limit, cursor, sortBy)
+		sessions, nextCursor, err = h.repos.Session.FindAll(ctx, viewer, state, limit, cursor, sortBy)
 	}
 	if err != nil {
 		http.Error(w, `{"error": "failed to fetch sessions"}`, http.StatusInternalServerError)
@@ -229,7 +335,7 @@ func (h *SessionHandler) Get(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	session, err := h.repos.Session.FindByID(ctx, id)
+	session, err := findVisibleSession(ctx, h.repos, id)
 	if err != nil {
 		http.Error(w, `{"error": "failed to fetch session"}`, http.StatusInternalServerError)
 		return
@@ -282,8 +388,9 @@ func (h *SessionHandler) Get(w http.ResponseWriter, r *http.Request) {
 }
 
 type UpdateSessionRequest struct {
-	Title     *string `json:"title"`
-	ProjectID *string `json:"project_id"`
+	Title      *string `json:"title"`
+	ProjectID  *string `json:"project_id"`
+	Visibility *string `json:"visibility"` // only the session's owner can change it
 }
 
 func (h *SessionHandler) Update(w http.ResponseWriter, r *http.Request) {
@@ -298,7 +405,7 @@ func (h *SessionHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	session, err := h.repos.Session.FindByID(ctx, id)
+	session, err := findVisibleSession(ctx, h.repos, id)
 	if err != nil {
 		http.Error(w, `{"error": "failed to fetch session"}`, http.StatusInternalServerError)
 		return
@@ -308,6 +415,42 @@ func (h *SessionHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Sessions can only be moved into an existing project the user can see
+	if req.ProjectID != nil {
+		viewer, err := sessionViewer(ctx, h.repos)
+		if err != nil {
+			http.Error(w, `{"error": "failed to fetch project"}`, http.StatusInternalServerError)
+			return
+		}
+		project, err := h.repos.Project.FindByID(ctx, *req.ProjectID)
+		if err != nil {
+			http.Error(w, `{"error": "failed to fetch project"}`, http.StatusInternalServerError)
+			return
+		}
+		if project == nil || !viewer.CanViewProject(project.ID) {
+			http.Error(w, `{"error": "project not found"}`, http.StatusNotFound)
+			return
+		}
+	}
+
+	// Update visibility if provided
+	if req.Visibility != nil {
+		visibility := domain.SessionVisibility(*req.Visibility)
+		if !visibility.IsValid() {
+			http.Error(w, `{"error": "invalid visibility: must be private, team or public"}`, http.StatusBadRequest)
+			return
+		}
+		if session.UserID == nil || *session.UserID != userID {
+			http.Error(w, `{"error": "only the session owner can change its visibility"}`, http.StatusForbidden)
+			return
+		}
+		if err := h.repos.Session.UpdateVisibility(ctx, id, visibility); err != nil {
+			http.Error(w, `{"error": "failed to update visibility"}`, http.StatusInternalServerError)
+			return
+		}
+		session.Visibility = visibility
+	}
+
 	// Update title if provided
 	if req.Title != nil {
 		if err := h.repos.Session.UpdateTitle(ctx, id, *req.Title); err != nil {
@@ -323,6 +466,15 @@ func (h *SessionHandler) Update(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, `{"error": "failed to update project_id"}`, http.StatusInternalServerError)
 			return
 		}
+		// The files and tool calls of the session move with it
+		if err := h.repos.FileActivity.UpdateProjectIDBySessionID(ctx, id, *req.ProjectID); err != nil {
+			http.Error(w, `{"error": "failed to update project_id"}`, http.StatusInternalServerError)
+			return
+		}
+		if err := h.repos.ToolCall.UpdateProjectIDBySessionID(ctx, id, *req.ProjectID); err != nil {
+			http.Error(w, `{"error": "failed to update project_id"}`, http.StatusInternalServerError)
+			return
+		}
 		session.ProjectID = *req.ProjectID
 	}
 
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+// maxChainSessions bounds the number of sessions returned for a conversation chain
+const maxChainSessions = 200
+
+type SessionChainResponse struct {
+	SessionID     string             `json:"session_id"`
+	RootSessionID string             `json:"root_session_id"`
+	Sessions      []*SessionResponse `json:"sessions"` // every session of the chain, oldest first
+}
+
+// Chain returns the conversation chain of a session: the session it was
+// resumed or forked from up to the first one, and every session continuing them
+func (h *SessionHandler) Chain(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := GetUserIDFromContext(ctx)
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	viewer, err := sessionViewer(ctx, h.repos)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch session"}`, http.StatusInternalServerError)
+		return
+	}
+	session, err := findSessionFor(ctx, h.repos, viewer, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch session"}`, http.StatusInternalServerError)
+		return
+	}
+	if session == nil {
+		http.Error(w, `{"error": "session not found"}`, http.StatusNotFound)
+		return
+	}
+
+	// Walk up to the first session of the chain
+	root := session
+	seen := map[string]bool{root.ID: true}
+	for root.ParentSessionID != nil && !seen[*root.ParentSessionID] && len(seen) < maxChainSessions {
+		parent, err := findSessionFor(ctx, h.repos, viewer, *root.ParentSessionID)
+		if err != nil {
+			http.Error(w, `{"error": "failed to fetch session"}`, http.StatusInternalServerError)
+			return
+		}
+		if parent == nil {
+			break
+		}
+		seen[parent.ID] = true
+		root = parent
+	}
+
+	// Then collect every session continuing it, resumed or forked, that the user can read
+	chain := []*domain.Session{root}
+	included := map[string]bool{root.ID: true}
+	for i := 0; i < len(chain) && len(chain) < maxChainSessions; i++ {
+		children, err := h.repos.Session.FindByParentSessionID(ctx, chain[i].ID)
+		if err != nil {
+			http.Error(w, `{"error": "failed to fetch sessions"}`, http.StatusInternalServerError)
+			return
+		}
+		for _, child := range children {
+			if !included[child.ID] && viewer.CanView(child) && len(chain) < maxChainSessions {
+				included[child.ID] = true
+				chain = append(chain, child)
+			}
+		}
+	}
+	sort.SliceStable(chain, func(i, j int) bool {
+		return chain[i].StartedAt.Before(chain[j].StartedAt)
+	})
+
+	// Get favorited session IDs for the current user
+	favoritedIDs := make(map[string]bool)
+	if userID != "" {
+		targetIDs, err := h.repos.UserFavorite.GetTargetIDs(ctx, userID, domain.UserFavoriteTargetTypeSession)
+		if err == nil {
+			for _, id := range targetIDs {
+				favoritedIDs[id] = true
+			}
+		}
+	}
+
+	sessionResponses := make([]*SessionResponse, len(chain))
+	for i, s := range chain {
+		var userName *string
+		if s.UserID != nil {
+			user, err := h.repos.User.FindByID(ctx, *s.UserID)
+			if err == nil && user != nil {
+				displayName := user.GetDisplayName()
+				userName = &displayName
+			}
+		}
+
+		eventCount, err := h.repos.Event.CountBySessionID(ctx, s.ID)
+		if err != nil {
+			eventCount = 0
+		}
+
+		sessionResponses[i] = h.sessionToResponse(ctx, s, userName, eventCount, favoritedIDs[s.ID])
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&SessionChainResponse{
+		SessionID:     session.ID,
+		RootSessionID: root.ID,
+		Sessions:      sessionResponses,
+	})
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/satetsu888/agentrace/server/internal/codediff"
+)
+
+type DiffStepResponse struct {
+	EventID   string `json:"event_id"`
+	ToolUseID string `json:"tool_use_id"`
+	Tool      string `json:"tool"`
+	CreatedAt string `json:"created_at"`
+	Diff      string `json:"diff"`
+	Exact     bool   `json:"exact"` // false when the line numbers are relative to the edited snippets
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+type FileDiffResponse struct {
+	Path      string              `json:"path"`
+	Created   bool                `json:"created"`
+	Exact     bool                `json:"exact"` // false when the diff joins the steps' hunks
+	Diff      string              `json:"diff"`
+	Additions int                 `json:"additions"`
+	Deletions int                 `json:"deletions"`
+	Steps     []*DiffStepResponse `json:"steps"`
+}
+
+type SessionDiffResponse struct {
+	SessionID   string              `json:"session_id"`
+	ProjectPath string              `json:"project_path"`
+	Files       []*FileDiffResponse `json:"files"` // sorted by path
+}
+
+// Diff returns the changes of the session's Edit, MultiEdit and Write tool calls as
+// unified diffs per file, cumulative and per step. With format=patch it returns the
+// cumulative diffs as a single patch; path restricts it to one file.
+func (h *SessionHandler) Diff(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "patch" {
+		http.Error(w, `{"error": "invalid format: must be json or patch"}`, http.StatusBadRequest)
+		return
+	}
+	path := strings.TrimPrefix(strings.TrimSpace(r.URL.Query().Get("path")), "./")
+
+	session, err := findVisibleSession(ctx, h.repos, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch session"}`, http.StatusInternalServerError)
+		return
+	}
+	if session == nil {
+		http.Error(w, `{"error": "session not found"}`, http.StatusNotFound)
+		return
+	}
+
+	events, err := h.repos.Event.FindBySessionID(ctx, session.ID)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch events"}`, http.StatusInternalServerError)
+		return
+	}
+
+	files := make([]*FileDiffResponse, 0)
+	for _, d := range codediff.Build(events, session.ProjectPath) {
+		if path != "" && d.Path != path {
+			continue
+		}
+		steps := make([]*DiffStepResponse, len(d.Steps))
+		for i, step := range d.Steps {
+			steps[i] = &DiffStepResponse{
+				EventID:   step.EventID,
+				ToolUseID: step.ToolUseID,
+				Tool:      step.Tool,
+				CreatedAt: step.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+				Diff:      step.Diff,
+				Exact:     step.Exact,
+				Additions: step.Additions,
+				Deletions: step.Deletions,
+			}
+		}
+		files = append(files, &FileDiffResponse{
+			Path:      d.Path,
+			Created:   d.Created,
+			Exact:     d.Exact,
+			Diff:      d.Diff,
+			Additions: d.Additions,
+			Deletions: d.Deletions,
+			Steps:     steps,
+		})
+	}
+
+	if format == "patch" {
+		w.Header().Set("Content-Type", "text/x-diff; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="session-`+session.ID+`.patch"`)
+		for _, f := range files {
+			io.WriteString(w, f.Diff)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&SessionDiffResponse{
+		SessionID:   session.ID,
+		ProjectPath: session.ProjectPath,
+		Files:       files,
+	})
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/satetsu888/agentrace/server/internal/export"
+)
+
+// Export renders the session as a Markdown or HTML document, or as the original JSONL transcript
+func (h *SessionHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	format := export.FormatMarkdown
+	if f := r.URL.Query().Get("format"); f != "" {
+		format = export.Format(f)
+		if !format.IsValid() {
+			http.Error(w, `{"error": "invalid format: must be markdown, html or jsonl"}`, http.StatusBadRequest)
+			return
+		}
+	}
+
+	session, err := findVisibleSession(ctx, h.repos, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch session"}`, http.StatusInternalServerError)
+		return
+	}
+	if session == nil {
+		http.Error(w, `{"error": "session not found"}`, http.StatusNotFound)
+		return
+	}
+
+	events, err := h.repos.Event.FindBySessionID(ctx, session.ID)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch events"}`, http.StatusInternalServerError)
+		return
+	}
+
+	// The transcript keeps every line so that it can be re-imported
+	if format != export.FormatJSONL {
+		events = filterEvents(events)
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", `attachment; filename="session-`+session.ID+`.`+format.Extension()+`"`)
+	if err := export.Write(w, format, session, events); err != nil {
+		log.Printf("[SessionHandler.Export] failed to write session %s: %v", session.ID, err)
+	}
+}
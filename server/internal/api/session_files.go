@@ -0,0 +1,203 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+type SessionFileResponse struct {
+	Path           string   `json:"path"`       // relative to the session's project path when inside it
+	Operations     []string `json:"operations"` // distinct operations in order of first use: "edit", "write"
+	Count          int      `json:"count"`
+	FirstTouchedAt string   `json:"first_touched_at"`
+	LastTouchedAt  string   `json:"last_touched_at"`
+	EventIDs       []string `json:"event_ids"`
+}
+
+type SessionFilesResponse struct {
+	SessionID   string                 `json:"session_id"`
+	ProjectPath string                 `json:"project_path"`
+	Files       []*SessionFileResponse `json:"files"` // sorted by path
+}
+
+// Files returns the files modified by the session's Edit, MultiEdit, NotebookEdit and Write tool calls
+func (h *SessionHandler) Files(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	session, err := findVisibleSession(ctx, h.repos, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch session"}`, http.StatusInternalServerError)
+		return
+	}
+	if session == nil {
+		http.Error(w, `{"error": "session not found"}`, http.StatusNotFound)
+		return
+	}
+
+	activities, err := h.repos.FileActivity.FindBySessionID(ctx, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch files"}`, http.StatusInternalServerError)
+		return
+	}
+
+	byPath := make(map[string]*SessionFileResponse)
+	files := make([]*SessionFileResponse, 0)
+	for _, a := range activities {
+		touchedAt := a.CreatedAt.Format("2006-01-02T15:04:05Z07:00")
+		file, ok := byPath[a.Path]
+		if !ok {
+			file = &SessionFileResponse{
+				Path:           a.Path,
+				Operations:     []string{},
+				FirstTouchedAt: touchedAt,
+				EventIDs:       []string{},
+			}
+			byPath[a.Path] = file
+			files = append(files, file)
+		}
+		if !slices.Contains(file.Operations, string(a.Operation)) {
+			file.Operations = append(file.Operations, string(a.Operation))
+		}
+		// One tool call per event and file is the norm, but MultiEdit lines may repeat a path
+		if !slices.Contains(file.EventIDs, a.EventID) {
+			file.EventIDs = append(file.EventIDs, a.EventID)
+		}
+		file.Count++
+		file.LastTouchedAt = touchedAt
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&SessionFilesResponse{
+		SessionID:   session.ID,
+		ProjectPath: session.ProjectPath,
+		Files:       files,
+	})
+}
+
+type FileSessionResponse struct {
+	Session        *SessionResponse `json:"session"`
+	Operations     []string         `json:"operations"`
+	Count          int              `json:"count"`
+	FirstTouchedAt string           `json:"first_touched_at"`
+	LastTouchedAt  string           `json:"last_touched_at"`
+}
+
+type ProjectFileSessionsResponse struct {
+	ProjectID string                 `json:"project_id"`
+	Path      string                 `json:"path"`
+	Sessions  []*FileSessionResponse `json:"sessions"` // most recently touched first
+}
+
+// ProjectFiles returns every session of the project that modified the file given by the path query parameter
+func (h *SessionHandler) ProjectFiles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := GetUserIDFromContext(ctx)
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+
+	path := strings.TrimPrefix(strings.TrimSpace(r.URL.Query().Get("path")), "./")
+	if path == "" {
+		http.Error(w, `{"error": "path is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	viewer, err := sessionViewer(ctx, h.repos)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch project"}`, http.StatusInternalServerError)
+		return
+	}
+	project, err := h.repos.Project.FindByID(ctx, projectID)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch project"}`, http.StatusInternalServerError)
+		return
+	}
+	if project == nil || !viewer.CanViewProject(project.ID) {
+		http.Error(w, `{"error": "project not found"}`, http.StatusNotFound)
+		return
+	}
+
+	activities, err := h.repos.FileActivity.FindByProjectIDAndPath(ctx, projectID, path)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch files"}`, http.StatusInternalServerError)
+		return
+	}
+
+	// Get favorited session IDs for the current user
+	favoritedIDs := make(map[string]bool)
+	if userID != "" {
+		targetIDs, err := h.repos.UserFavorite.GetTargetIDs(ctx, userID, domain.UserFavoriteTargetTypeSession)
+		if err == nil {
+			for _, id := range targetIDs {
+				favoritedIDs[id] = true
+			}
+		}
+	}
+
+	// Activities are newest first, so sessions come out most recently touched first
+	bySession := make(map[string]*FileSessionResponse)
+	skipped := make(map[string]bool) // sessions missing or hidden from the user
+	sessionResponses := make([]*FileSessionResponse, 0)
+	for _, a := range activities {
+		touchedAt := a.CreatedAt.Format("2006-01-02T15:04:05Z07:00")
+		entry, ok := bySession[a.SessionID]
+		if !ok {
+			if skipped[a.SessionID] {
+				continue
+			}
+			s, err := findSessionFor(ctx, h.repos, viewer, a.SessionID)
+			if err != nil {
+				http.Error(w, `{"error": "failed to fetch session"}`, http.StatusInternalServerError)
+				return
+			}
+			if s == nil {
+				skipped[a.SessionID] = true
+				continue
+			}
+
+			var userName *string
+			if s.UserID != nil {
+				user, err := h.repos.User.FindByID(ctx, *s.UserID)
+				if err == nil && user != nil {
+					displayName := user.GetDisplayName()
+					userName = &displayName
+				}
+			}
+
+			eventCount, err := h.repos.Event.CountBySessionID(ctx, s.ID)
+			if err != nil {
+				eventCount = 0
+			}
+
+			entry = &FileSessionResponse{
+				Session:       h.sessionToResponse(ctx, s, userName, eventCount, favoritedIDs[s.ID]),
+				Operations:    []string{},
+				LastTouchedAt: touchedAt,
+			}
+			bySession[a.SessionID] = entry
+			sessionResponses = append(sessionResponses, entry)
+		}
+		if !slices.Contains(entry.Operations, string(a.Operation)) {
+			entry.Operations = append(entry.Operations, string(a.Operation))
+		}
+		entry.Count++
+		entry.FirstTouchedAt = touchedAt
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&ProjectFileSessionsResponse{
+		ProjectID: project.ID,
+		Path:      path,
+		Sessions:  sessionResponses,
+	})
+}
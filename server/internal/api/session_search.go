@@ -0,0 +1,166 @@
+package api
+
+import (
+	"encoding/json"
+	"html"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+type SessionSearchResult struct {
+	Session  *SessionResponse `json:"session"`
+	EventIDs []string         `json:"event_ids"`
+	Snippets []string         `json:"snippets"` // HTML-escaped text with matches wrapped in <mark>
+}
+
+type SessionSearchResponse struct {
+	Results []*SessionSearchResult `json:"results"`
+}
+
+const (
+	// searchEventLimit caps how many matching events are fetched before grouping by session
+	searchEventLimit = 500
+	// searchSnippetsPerSession caps how many snippets are returned per session
+	searchSnippetsPerSession = 3
+)
+
+// Search returns the sessions with events matching every term of q.
+// Ranking and tokenization depend on the backend's index (see "Searching Sessions" in the README):
+// only PostgreSQL orders by relevance, the others by recency.
+func (h *SessionHandler) Search(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := GetUserIDFromContext(ctx)
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	terms := domain.ParseSearchTerms(query)
+	if len(terms) == 0 {
+		http.Error(w, `{"error": "q is required"}`, http.StatusBadRequest)
+		return
+	}
+	limit := 20
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	viewer, err := sessionViewer(ctx, h.repos)
+	if err != nil {
+		http.Error(w, `{"error": "failed to search sessions"}`, http.StatusInternalServerError)
+		return
+	}
+	matches, err := h.repos.Event.Search(ctx, query, viewer, searchEventLimit)
+	if err != nil {
+		http.Error(w, `{"error": "failed to search sessions"}`, http.StatusInternalServerError)
+		return
+	}
+
+	// Group matches by session, keeping the order of the best match per session.
+	// Matches are of sessions the viewer can read; a session deleted since is skipped.
+	var sessionIDs []string
+	resultsBySession := make(map[string]*SessionSearchResult)
+	sessionsByID := make(map[string]*domain.Session)
+	skipped := make(map[string]bool)
+	for _, m := range matches {
+		result, ok := resultsBySession[m.SessionID]
+		if !ok {
+			if len(sessionIDs) >= limit || skipped[m.SessionID] {
+				continue
+			}
+			session, err := findSessionFor(ctx, h.repos, viewer, m.SessionID)
+			if err != nil || session == nil {
+				skipped[m.SessionID] = true
+				continue
+			}
+			sessionsByID[m.SessionID] = session
+			result = &SessionSearchResult{EventIDs: []string{}, Snippets: []string{}}
+			resultsBySession[m.SessionID] = result
+			sessionIDs = append(sessionIDs, m.SessionID)
+		}
+		result.EventIDs = append(result.EventIDs, m.EventID)
+		if len(result.Snippets) < searchSnippetsPerSession {
+			result.Snippets = append(result.Snippets, buildSearchSnippet(m.Text, terms))
+		}
+	}
+
+	results := make([]*SessionSearchResult, 0, len(sessionIDs))
+	for _, id := range sessionIDs {
+		session := sessionsByID[id]
+
+		var userName *string
+		if session.UserID != nil {
+			user, err := h.repos.User.FindByID(ctx, *session.UserID)
+			if err == nil && user != nil {
+				displayName := user.GetDisplayName()
+				userName = &displayName
+			}
+		}
+
+		var isFavorited bool
+		if userID != "" {
+			fav, err := h.repos.UserFavorite.FindByUserAndTarget(ctx, userID, domain.UserFavoriteTargetTypeSession, id)
+			if err == nil && fav != nil {
+				isFavorited = true
+			}
+		}
+
+		eventCount, err := h.repos.Event.CountBySessionID(ctx, session.ID)
+		if err != nil {
+			eventCount = 0
+		}
+
+		result := resultsBySession[id]
+		result.Session = h.sessionToResponse(ctx, session, userName, eventCount, isFavorited)
+		results = append(results, result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(SessionSearchResponse{Results: results})
+}
+
+// snippetContext is the number of characters shown around the first match
+const snippetContext = 80
+
+// buildSearchSnippet returns an excerpt of text around the first matching term,
+// HTML-escaped, with every match wrapped in <mark>
+func buildSearchSnippet(text string, terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		quoted[i] = regexp.QuoteMeta(t)
+	}
+	re := regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+
+	runes := []rune(text)
+	start, end := 0, len(runes)
+	if loc := re.FindStringIndex(text); loc != nil {
+		matchStart := utf8.RuneCountInString(text[:loc[0]])
+		start = max(0, matchStart-snippetContext/2)
+		end = min(len(runes), matchStart+snippetContext)
+	} else {
+		end = min(len(runes), snippetContext)
+	}
+	excerpt := strings.Join(strings.Fields(string(runes[start:end])), " ")
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	last := 0
+	for _, loc := range re.FindAllStringIndex(excerpt, -1) {
+		b.WriteString(html.EscapeString(excerpt[last:loc[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(excerpt[loc[0]:loc[1]]))
+		b.WriteString("</mark>")
+		last = loc[1]
+	}
+	b.WriteString(html.EscapeString(excerpt[last:]))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+func TestSessionUpdate_ProjectID(t *testing.T) {
+	s := newTestServer(t)
+	member, key := s.createUser("member@example.com", domain.UserRoleMember)
+	session := s.createSession(member, domain.DefaultProjectID, domain.SessionVisibilityPublic)
+	hidden := s.createTeamProject("https://github.com/example/hidden")
+	path := "/api/sessions/" + session.ID
+
+	tests := []struct {
+		name      string
+		projectID string
+		want      int
+	}{
+		{name: "nonexistent project", projectID: "no-such-project", want: http.StatusNotFound},
+		{name: "project of another team", projectID: hidden.ID, want: http.StatusNotFound},
+		{name: "visible project", projectID: domain.DefaultProjectID, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.do(http.MethodPatch, path, key, map[string]string{"project_id": tt.projectID})
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+
+	if stored, _ := s.repos.Session.FindByID(t.Context(), session.ID); stored.ProjectID != domain.DefaultProjectID {
+		t.Errorf("ProjectID = %q, want %q", stored.ProjectID, domain.DefaultProjectID)
+	}
+}
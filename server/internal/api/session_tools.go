@@ -0,0 +1,210 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+type ToolStatsResponse struct {
+	ToolName      string  `json:"tool_name,omitempty"` // empty for the total
+	Calls         int     `json:"calls"`
+	Errors        int     `json:"errors"`
+	Pending       int     `json:"pending"`    // calls without a result yet
+	ErrorRate     float64 `json:"error_rate"` // errors / completed calls
+	AvgDurationMs int64   `json:"avg_duration_ms"`
+	P50DurationMs int64   `json:"p50_duration_ms"`
+	P95DurationMs int64   `json:"p95_duration_ms"`
+	MaxDurationMs int64   `json:"max_duration_ms"`
+}
+
+type ToolCallResponse struct {
+	ID          string  `json:"id"`
+	EventID     string  `json:"event_id"`
+	ToolUseID   string  `json:"tool_use_id"`
+	ToolName    string  `json:"tool_name"`
+	IsError     bool    `json:"is_error"`
+	StartedAt   string  `json:"started_at"`
+	CompletedAt *string `json:"completed_at"`
+	DurationMs  *int64  `json:"duration_ms"`
+}
+
+type SessionToolsResponse struct {
+	SessionID string               `json:"session_id"`
+	Total     *ToolStatsResponse   `json:"total"`
+	Tools     []*ToolStatsResponse `json:"tools"` // most called first
+	Calls     []*ToolCallResponse  `json:"calls"` // oldest first
+}
+
+type ProjectToolsResponse struct {
+	ProjectID string               `json:"project_id"`
+	From      string               `json:"from,omitempty"`
+	To        string               `json:"to,omitempty"`
+	Total     *ToolStatsResponse   `json:"total"`
+	Tools     []*ToolStatsResponse `json:"tools"` // most called first
+}
+
+// Tools returns the session's tool calls with their outcome and latency, and statistics per tool
+func (h *SessionHandler) Tools(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	session, err := findVisibleSession(ctx, h.repos, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch session"}`, http.StatusInternalServerError)
+		return
+	}
+	if session == nil {
+		http.Error(w, `{"error": "session not found"}`, http.StatusNotFound)
+		return
+	}
+
+	calls, err := h.repos.ToolCall.FindBySessionID(ctx, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch tool calls"}`, http.StatusInternalServerError)
+		return
+	}
+
+	callResponses := make([]*ToolCallResponse, 0, len(calls))
+	for _, c := range calls {
+		callResponses = append(callResponses, toolCallToResponse(c))
+	}
+	stats, total := domain.SummarizeToolCalls(calls)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&SessionToolsResponse{
+		SessionID: session.ID,
+		Total:     toolStatsToResponse(total),
+		Tools:     toolStatsToResponses(stats),
+		Calls:     callResponses,
+	})
+}
+
+// ProjectTools returns statistics per tool over the project's sessions.
+// from and to are optional inclusive UTC dates (YYYY-MM-DD) of the calls' start.
+func (h *SessionHandler) ProjectTools(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+	params := r.URL.Query()
+
+	var from, to time.Time
+	if s := params.Get("from"); s != "" {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			http.Error(w, `{"error": "invalid from: must be YYYY-MM-DD"}`, http.StatusBadRequest)
+			return
+		}
+		from = t
+	}
+	if s := params.Get("to"); s != "" {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			http.Error(w, `{"error": "invalid to: must be YYYY-MM-DD"}`, http.StatusBadRequest)
+			return
+		}
+		to = t
+	}
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		http.Error(w, `{"error": "from must not be after to"}`, http.StatusBadRequest)
+		return
+	}
+
+	viewer, err := sessionViewer(ctx, h.repos)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch project"}`, http.StatusInternalServerError)
+		return
+	}
+	project, err := h.repos.Project.FindByID(ctx, projectID)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch project"}`, http.StatusInternalServerError)
+		return
+	}
+	if project == nil || !viewer.CanViewProject(project.ID) {
+		http.Error(w, `{"error": "project not found"}`, http.StatusNotFound)
+		return
+	}
+
+	end := to
+	if !end.IsZero() {
+		end = end.AddDate(0, 0, 1) // to is inclusive
+	}
+	calls, err := h.repos.ToolCall.FindByProjectID(ctx, projectID, from, end)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch tool calls"}`, http.StatusInternalServerError)
+		return
+	}
+
+	// Only count calls of sessions the viewer can read
+	visibleSessions := make(map[string]bool)
+	visibleCalls := make([]*domain.ToolCall, 0, len(calls))
+	for _, c := range calls {
+		visible, ok := visibleSessions[c.SessionID]
+		if !ok {
+			session, err := findSessionFor(ctx, h.repos, viewer, c.SessionID)
+			if err != nil {
+				http.Error(w, `{"error": "failed to fetch tool calls"}`, http.StatusInternalServerError)
+				return
+			}
+			visible = session != nil
+			visibleSessions[c.SessionID] = visible
+		}
+		if visible {
+			visibleCalls = append(visibleCalls, c)
+		}
+	}
+	stats, total := domain.SummarizeToolCalls(visibleCalls)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&ProjectToolsResponse{
+		ProjectID: project.ID,
+		From:      params.Get("from"),
+		To:        params.Get("to"),
+		Total:     toolStatsToResponse(total),
+		Tools:     toolStatsToResponses(stats),
+	})
+}
+
+func toolCallToResponse(c *domain.ToolCall) *ToolCallResponse {
+	resp := &ToolCallResponse{
+		ID:        c.ID,
+		EventID:   c.EventID,
+		ToolUseID: c.ToolUseID,
+		ToolName:  c.ToolName,
+		IsError:   c.IsError,
+		StartedAt: c.StartedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if d, ok := c.Duration(); ok {
+		completedAt := c.CompletedAt.Format("2006-01-02T15:04:05Z07:00")
+		durationMs := d.Milliseconds()
+		resp.CompletedAt = &completedAt
+		resp.DurationMs = &durationMs
+	}
+	return resp
+}
+
+func toolStatsToResponse(s *domain.ToolStats) *ToolStatsResponse {
+	return &ToolStatsResponse{
+		ToolName:      s.ToolName,
+		Calls:         s.Calls,
+		Errors:        s.Errors,
+		Pending:       s.Pending,
+		ErrorRate:     s.ErrorRate(),
+		AvgDurationMs: s.AvgDuration.Milliseconds(),
+		P50DurationMs: s.P50Duration.Milliseconds(),
+		P95DurationMs: s.P95Duration.Milliseconds(),
+		MaxDurationMs: s.MaxDuration.Milliseconds(),
+	}
+}
+
+func toolStatsToResponses(stats []*domain.ToolStats) []*ToolStatsResponse {
+	resp := make([]*ToolStatsResponse, 0, len(stats))
+	for _, s := range stats {
+		resp = append(resp, toolStatsToResponse(s))
+	}
+	return resp
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+type SessionTreeResponse struct {
+	SessionID     string                  `json:"session_id"`
+	SubagentCount int                     `json:"subagent_count"`
+	Turns         []*TreeTurnResponse     `json:"turns"`
+	Subagents     []*SubagentTreeResponse `json:"subagents"` // subagents not attached to any turn
+}
+
+type TreeTurnResponse struct {
+	Event     *EventResponse          `json:"event"`
+	Subagents []*SubagentTreeResponse `json:"subagents"`
+}
+
+type SubagentTreeResponse struct {
+	RootUUID string              `json:"root_uuid"`
+	Turns    []*TreeTurnResponse `json:"turns"`
+}
+
+func treeNodesToResponse(nodes []*domain.EventTreeNode) []*TreeTurnResponse {
+	turns := make([]*TreeTurnResponse, len(nodes))
+	for i, node := range nodes {
+		turns[i] = &TreeTurnResponse{
+			Event:     eventToResponse(node.Event),
+			Subagents: subagentThreadsToResponse(node.Subagents),
+		}
+	}
+	return turns
+}
+
+func subagentThreadsToResponse(threads []*domain.SubagentThread) []*SubagentTreeResponse {
+	subagents := make([]*SubagentTreeResponse, len(threads))
+	for i, thread := range threads {
+		subagents[i] = &SubagentTreeResponse{
+			RootUUID: thread.RootUUID,
+			Turns:    treeNodesToResponse(thread.Nodes),
+		}
+	}
+	return subagents
+}
+
+// Tree returns the session's events as its main thread with the subagent
+// (sidechain) threads nested under the turns that spawned them
+func (h *SessionHandler) Tree(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	session, err := findVisibleSession(ctx, h.repos, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch session"}`, http.StatusInternalServerError)
+		return
+	}
+	if session == nil {
+		http.Error(w, `{"error": "session not found"}`, http.StatusNotFound)
+		return
+	}
+
+	events, err := h.repos.Event.FindBySessionID(ctx, session.ID)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch events"}`, http.StatusInternalServerError)
+		return
+	}
+
+	// Filtered events are not shown but still link the lines around them
+	tree := domain.BuildEventTree(events, shouldFilterEvent)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&SessionTreeResponse{
+		SessionID:     session.ID,
+		SubagentCount: session.SubagentCount,
+		Turns:         treeNodesToResponse(tree.Nodes),
+		Subagents:     subagentThreadsToResponse(tree.Subagents),
+	})
+}
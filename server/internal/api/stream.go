@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+	"github.com/satetsu888/agentrace/server/internal/eventbus"
+	"github.com/satetsu888/agentrace/server/internal/repository"
+)
+
+// streamHeartbeatInterval keeps idle connections (and proxies) alive
+const streamHeartbeatInterval = 25 * time.Second
+
+// SessionStreamData is the payload of session.created / session.updated messages
+type SessionStreamData struct {
+	SessionID     string `json:"session_id"`
+	ProjectID     string `json:"project_id"`
+	EventsCreated int    `json:"events_created,omitempty"`
+}
+
+// PlanStreamData is the payload of plan.* messages
+type PlanStreamData struct {
+	PlanID    string `json:"plan_id"`
+	ProjectID string `json:"project_id,omitempty"`
+	Status    string `json:"status,omitempty"`
+}
+
+type StreamHandler struct {
+	repos *repository.Repositories
+	bus   *eventbus.Bus
+}
+
+func NewStreamHandler(repos *repository.Repositories, bus *eventbus.Bus) *StreamHandler {
+	return &StreamHandler{repos: repos, bus: bus}
+}
+
+// SessionStream streams new events of a session as Server-Sent Events
+func (h *StreamHandler) SessionStream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	session, err := findVisibleSession(ctx, h.repos, id)
+	if err != nil {
+		http.Error(w, `{"error": "failed to fetch session"}`, http.StatusInternalServerError)
+		return
+	}
+	if session == nil {
+		http.Error(w, `{"error": "session not found"}`, http.StatusNotFound)
+		return
+	}
+
+	// The session is looked up again for each message, so that it stops streaming
+	// as soon as it is made private or moved into a project the reader cannot see
+	h.serveStream(w, r, eventbus.SessionFilter(session.ID), func(eventbus.Message) bool {
+		visible, err := findVisibleSession(ctx, h.repos, session.ID)
+		return err == nil && visible != nil
+	})
+}
+
+// GlobalStream streams new sessions, session activity and plan changes as Server-Sent Events.
+// Messages about sessions and plans the reader cannot see are dropped.
+func (h *StreamHandler) GlobalStream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	viewer, err := sessionViewer(ctx, h.repos)
+	if err != nil {
+		http.Error(w, `{"error": "failed to open stream"}`, http.StatusInternalServerError)
+		return
+	}
+
+	h.serveStream(w, r, eventbus.GlobalFilter(), func(msg eventbus.Message) bool {
+		return h.canViewMessage(ctx, viewer, msg)
+	})
+}
+
+// canViewMessage reports whether the viewer may see a global stream message.
+// Sessions are looked up per message so visibility changes apply right away.
+func (h *StreamHandler) canViewMessage(ctx context.Context, viewer domain.SessionViewer, msg eventbus.Message) bool {
+	switch data := msg.Data.(type) {
+	case SessionStreamData:
+		session, err := h.repos.Session.FindByID(ctx, data.SessionID)
+		return err == nil && session != nil && viewer.CanView(session)
+	case PlanStreamData:
+		return viewer.CanViewProject(data.ProjectID)
+	}
+	return false
+}
+
+// serveStream writes the messages selected by filter as SSE. visible, when set,
+// additionally drops messages per reader; it runs outside the bus lock.
+func (h *StreamHandler) serveStream(w http.ResponseWriter, r *http.Request, filter eventbus.Filter, visible func(eventbus.Message) bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, `{"error": "streaming not supported"}`, http.StatusInternalServerError)
+		return
+	}
+
+	// EventSource sends Last-Event-ID on reconnect; the query parameter allows manual resume
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	sub := h.bus.Subscribe(filter, lastEventID)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Disable nginx response buffering
+	w.WriteHeader(http.StatusOK)
+
+	if sub.Reset {
+		// Missed messages cannot be replayed; the client should reload
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, msg := range sub.Replay {
+		if visible != nil && !visible(msg) {
+			continue
+		}
+		if err := writeStreamMessage(w, msg); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg, ok := <-sub.C:
+			if !ok {
+				// Dropped for falling behind; the client reconnects with Last-Event-ID
+				return
+			}
+			if visible != nil && !visible(msg) {
+				continue
+			}
+			if err := writeStreamMessage(w, msg); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeStreamMessage writes a message in SSE format
+func writeStreamMessage(w http.ResponseWriter, msg eventbus.Message) error {
+	data, err := json.Marshal(msg.Data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", msg.ID, msg.Type, data)
+	return err
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+func TestSessionStream_VisibilityChange(t *testing.T) {
+	s := newTestServer(t)
+	_, key := s.createUser("member@example.com", domain.UserRoleMember)
+	server := httptest.NewServer(s.handler)
+	defer server.Close()
+
+	ingest := func(uuid string, visibility string) {
+		t.Helper()
+		line := map[string]interface{}{"uuid": uuid, "type": "user", "message": map[string]interface{}{"role": "user", "content": "line " + uuid}}
+		rec := s.do(http.MethodPost, "/api/ingest", key, map[string]interface{}{
+			"session_id":       "claude-stream",
+			"transcript_lines": []interface{}{line},
+			"visibility":       visibility,
+		})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("ingest %s: status %d: %s", uuid, rec.Code, rec.Body.String())
+		}
+	}
+	setVisibility := func(id string, visibility domain.SessionVisibility) {
+		t.Helper()
+		if err := s.repos.Session.UpdateVisibility(context.Background(), id, visibility); err != nil {
+			t.Fatalf("update visibility: %v", err)
+		}
+	}
+
+	ingest("line-1", "public")
+	session, err := s.repos.Session.FindByClaudeSessionID(context.Background(), "claude-stream")
+	if err != nil || session == nil {
+		t.Fatalf("find session: %v", err)
+	}
+
+	// An anonymous reader follows the public session
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/sessions/"+session.ID+"/stream", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("stream status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	data := make(chan string)
+	go func() {
+		defer close(data)
+		lines := bufio.NewScanner(resp.Body)
+		for lines.Scan() {
+			if line, ok := strings.CutPrefix(lines.Text(), "data: "); ok {
+				data <- line
+			}
+		}
+	}()
+	nextData := func(wait time.Duration) (string, bool) {
+		select {
+		case line, ok := <-data:
+			return line, ok
+		case <-time.After(wait):
+			return "", false
+		}
+	}
+
+	ingest("line-2", "")
+	if line, ok := nextData(2 * time.Second); !ok || !strings.Contains(line, "line-2") {
+		t.Fatalf("message = %q, want line-2", line)
+	}
+
+	// Nothing is sent once the session is private
+	setVisibility(session.ID, domain.SessionVisibilityPrivate)
+	ingest("line-3", "")
+	if line, ok := nextData(200 * time.Millisecond); ok {
+		t.Errorf("message = %q, want none for a private session", line)
+	}
+}
@@ -0,0 +1,442 @@
+// Package codediff reconstructs the changes a session made to files from its
+// Edit, MultiEdit and Write tool calls, as unified diffs per file and per step.
+//
+// Tool calls carry only the replaced snippets, not the files themselves. A
+// file's content is known once it is written whole, or when Claude Code
+// recorded the original file in the tool result (toolUseResult.originalFile);
+// edits to a known file are applied to it and diffed with real line numbers.
+// Edits to a file whose content is unknown are diffed snippet against snippet,
+// with line numbers relative to the snippet, and are marked as not exact.
+package codediff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// contextLines is the number of unchanged lines shown around each change
+const contextLines = 3
+
+// Step is the change made by one tool call
+type Step struct {
+	EventID   string
+	ToolUseID string
+	Tool      string // Edit, MultiEdit or Write
+	CreatedAt time.Time
+	Diff      string // unified diff of the change
+	Exact     bool   // whether Diff's line numbers refer to the file; otherwise they are relative to the edited snippets
+	Additions int
+	Deletions int
+}
+
+// FileDiff is the reconstructed change to one file over a session
+type FileDiff struct {
+	Path      string // relative to the project path when inside it
+	Created   bool   // the file did not exist before its first step
+	Exact     bool   // whether Diff is the file before the session against after it; otherwise it joins the steps' hunks
+	Diff      string // cumulative unified diff
+	Additions int
+	Deletions int
+	Steps     []*Step
+}
+
+// toolResult is what the transcript recorded about the outcome of a tool call
+type toolResult struct {
+	isError      bool
+	created      bool    // Write reported creating the file
+	updated      bool    // Write reported overwriting an existing file
+	originalFile *string // the file before the tool call, if recorded
+}
+
+// fileState tracks a file while the steps are replayed
+type fileState struct {
+	diff        *FileDiff
+	before      string // content before the first step, valid if beforeKnown
+	beforeKnown bool
+	content     string // current content, valid if known
+	known       bool
+	tracked     bool // content has been known continuously since before the first step
+}
+
+// edit is one replacement of an Edit or MultiEdit call
+type edit struct {
+	oldString  string
+	newString  string
+	replaceAll bool
+}
+
+// Build reconstructs the file changes of chronologically sorted events.
+// Paths are made relative to projectPath; failed tool calls are skipped.
+func Build(events []*domain.Event, projectPath string) []*FileDiff {
+	results := collectResults(events)
+
+	files := make(map[string]*fileState)
+	for _, e := range events {
+		for _, block := range contentBlocks(e.Payload) {
+			if block["type"] != "tool_use" {
+				continue
+			}
+			tool, _ := block["name"].(string)
+			if tool != "Edit" && tool != "MultiEdit" && tool != "Write" {
+				continue
+			}
+			input, _ := block["input"].(map[string]interface{})
+			path, _ := input["file_path"].(string)
+			if path == "" {
+				continue
+			}
+			toolUseID, _ := block["id"].(string)
+			result := results[toolUseID]
+			if result.isError {
+				continue
+			}
+
+			path = domain.RelativeFilePath(projectPath, path)
+			state := files[path]
+			if state == nil {
+				state = &fileState{diff: &FileDiff{Path: path}, tracked: true}
+				files[path] = state
+			}
+
+			step := &Step{EventID: e.ID, ToolUseID: toolUseID, Tool: tool, CreatedAt: e.Time()}
+			state.apply(step, tool, input, result)
+			state.diff.Steps = append(state.diff.Steps, step)
+		}
+	}
+
+	diffs := make([]*FileDiff, 0, len(files))
+	for _, state := range files {
+		diffs = append(diffs, state.finish())
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Path < diffs[j].Path
+	})
+	return diffs
+}
+
+// apply replays a tool call on the file and records its diff in step
+func (s *fileState) apply(step *Step, tool string, input map[string]interface{}, result toolResult) {
+	first := len(s.diff.Steps) == 0
+
+	// The recorded original file is the ground truth; if it differs from the
+	// replayed content, the file was changed outside the session's tool calls
+	if result.originalFile != nil && (!s.known || s.content != *result.originalFile) {
+		if !first {
+			s.tracked = false
+		}
+		s.content, s.known = *result.originalFile, true
+	}
+	if first && s.known {
+		s.before, s.beforeKnown = s.content, true
+	}
+
+	if tool == "Write" {
+		content, _ := input["content"].(string)
+		exact := true
+		if !s.known {
+			// Without a recorded original, a first Write creates the file unless it reported an update
+			if first && !result.updated {
+				s.before, s.beforeKnown = "", true
+				s.diff.Created = true
+			} else {
+				exact = false
+				s.tracked = false
+			}
+			s.content = ""
+		}
+		if first && result.created {
+			s.diff.Created = true
+		}
+		s.setStepDiff(step, s.content, content, exact, first && s.diff.Created)
+		s.content, s.known = content, true
+		return
+	}
+
+	edits := toolEdits(tool, input)
+
+	// An empty old_string in the first edit creates the file
+	if len(edits) > 0 && edits[0].oldString == "" && (!s.known || s.content == "") {
+		if content, ok := applyEdits(edits[0].newString, edits[1:]); ok {
+			if !s.known {
+				if first {
+					s.before, s.beforeKnown = "", true
+					s.diff.Created = true
+				} else {
+					s.tracked = false
+				}
+			}
+			s.setStepDiff(step, "", content, true, first && s.diff.Created)
+			s.content, s.known = content, true
+			return
+		}
+	}
+
+	if s.known {
+		if content, ok := applyEdits(s.content, edits); ok {
+			s.setStepDiff(step, s.content, content, true, false)
+			s.content = content
+			return
+		}
+	}
+
+	// The file's content is unknown: diff the snippets
+	s.known = false
+	s.tracked = false
+	var hunks strings.Builder
+	for _, ed := range edits {
+		text, additions, deletions := unifiedHunks(snippet(ed.oldString), snippet(ed.newString))
+		hunks.WriteString(text)
+		step.Additions += additions
+		step.Deletions += deletions
+	}
+	step.Diff = s.header(false) + hunks.String()
+	step.Exact = false
+}
+
+// setStepDiff records the diff between before and after in step
+func (s *fileState) setStepDiff(step *Step, before, after string, exact, created bool) {
+	text, additions, deletions := unifiedHunks(before, after)
+	step.Diff = s.header(created) + text
+	step.Exact = exact
+	step.Additions = additions
+	step.Deletions = deletions
+}
+
+// finish computes the cumulative diff
+func (s *fileState) finish() *FileDiff {
+	d := s.diff
+	if s.tracked && s.beforeKnown && s.known {
+		text, additions, deletions := unifiedHunks(s.before, s.content)
+		if text != "" {
+			d.Diff = s.header(d.Created) + text
+		}
+		d.Exact = true
+		d.Additions = additions
+		d.Deletions = deletions
+		return d
+	}
+
+	var hunks strings.Builder
+	for _, step := range d.Steps {
+		hunks.WriteString(strings.SplitN(step.Diff, "\n", 3)[2])
+		d.Additions += step.Additions
+		d.Deletions += step.Deletions
+	}
+	if hunks.Len() > 0 {
+		d.Diff = s.header(d.Created) + hunks.String()
+	}
+	return d
+}
+
+// header returns the file header of a unified diff of the file
+func (s *fileState) header(created bool) string {
+	path := strings.TrimPrefix(s.diff.Path, "/")
+	if created {
+		return "--- /dev/null\n+++ b/" + path + "\n"
+	}
+	return "--- a/" + path + "\n+++ b/" + path + "\n"
+}
+
+// collectResults maps tool_use IDs to the recorded outcome of the call
+func collectResults(events []*domain.Event) map[string]toolResult {
+	results := make(map[string]toolResult)
+	for _, e := range events {
+		var ids []string
+		for _, block := range contentBlocks(e.Payload) {
+			if block["type"] != "tool_result" {
+				continue
+			}
+			id, _ := block["tool_use_id"].(string)
+			isError, _ := block["is_error"].(bool)
+			results[id] = toolResult{isError: isError}
+			ids = append(ids, id)
+		}
+
+		// Claude Code records the structured result of a line's single tool result beside it
+		toolUseResult, ok := e.Payload["toolUseResult"].(map[string]interface{})
+		if !ok || len(ids) != 1 {
+			continue
+		}
+		result := results[ids[0]]
+		switch toolUseResult["type"] {
+		case "create":
+			result.created = true
+		case "update":
+			result.updated = true
+		}
+		if original, ok := toolUseResult["originalFile"].(string); ok {
+			result.originalFile = &original
+		}
+		results[ids[0]] = result
+	}
+	return results
+}
+
+// contentBlocks returns the blocks of a transcript line's message content
+func contentBlocks(payload map[string]interface{}) []map[string]interface{} {
+	message, ok := payload["message"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	content, ok := message["content"].([]interface{})
+	if !ok {
+		return nil
+	}
+	blocks := make([]map[string]interface{}, 0, len(content))
+	for _, item := range content {
+		if block, ok := item.(map[string]interface{}); ok {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// toolEdits returns the replacements of an Edit or MultiEdit call
+func toolEdits(tool string, input map[string]interface{}) []edit {
+	toEdit := func(m map[string]interface{}) edit {
+		var ed edit
+		ed.oldString, _ = m["old_string"].(string)
+		ed.newString, _ = m["new_string"].(string)
+		ed.replaceAll, _ = m["replace_all"].(bool)
+		return ed
+	}
+	if tool == "Edit" {
+		return []edit{toEdit(input)}
+	}
+	items, _ := input["edits"].([]interface{})
+	edits := make([]edit, 0, len(items))
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			edits = append(edits, toEdit(m))
+		}
+	}
+	return edits
+}
+
+// applyEdits applies the replacements in order; it fails if a snippet is not in the content
+func applyEdits(content string, edits []edit) (string, bool) {
+	for _, ed := range edits {
+		if ed.oldString == "" || !strings.Contains(content, ed.oldString) {
+			return "", false
+		}
+		if ed.replaceAll {
+			content = strings.ReplaceAll(content, ed.oldString, ed.newString)
+		} else {
+			content = strings.Replace(content, ed.oldString, ed.newString, 1)
+		}
+	}
+	return content, true
+}
+
+// snippet terminates a snippet's last line so it is not reported as missing a newline
+func snippet(s string) string {
+	if s != "" && !strings.HasSuffix(s, "\n") {
+		return s + "\n"
+	}
+	return s
+}
+
+// diffLine is a line of a line-level diff; text keeps its newline, if any
+type diffLine struct {
+	op   byte // ' ', '-' or '+'
+	text string
+}
+
+// diffLines computes the line-level diff of a and b
+func diffLines(a, b string) []diffLine {
+	dmp := diffmatchpatch.New()
+	charsA, charsB, lines := dmp.DiffLinesToChars(a, b)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(charsA, charsB, false), lines)
+
+	var result []diffLine
+	for _, d := range diffs {
+		op := byte(' ')
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			op = '-'
+		case diffmatchpatch.DiffInsert:
+			op = '+'
+		}
+		for _, text := range strings.SplitAfter(d.Text, "\n") {
+			if text != "" {
+				result = append(result, diffLine{op: op, text: text})
+			}
+		}
+	}
+	return result
+}
+
+// unifiedHunks returns the hunks of the unified diff of a and b with the
+// number of added and deleted lines; it is empty if they are equal
+func unifiedHunks(a, b string) (string, int, int) {
+	lines := diffLines(a, b)
+
+	// Line numbers in a and b at each diff line
+	oldNumbers := make([]int, len(lines)+1)
+	newNumbers := make([]int, len(lines)+1)
+	oldNumbers[0], newNumbers[0] = 1, 1
+	for i, l := range lines {
+		oldNumbers[i+1], newNumbers[i+1] = oldNumbers[i], newNumbers[i]
+		if l.op != '+' {
+			oldNumbers[i+1]++
+		}
+		if l.op != '-' {
+			newNumbers[i+1]++
+		}
+	}
+
+	// Ranges of lines to show: the changes with their context, merged where they overlap
+	var ranges [][2]int
+	for i, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		lo, hi := max(i-contextLines, 0), min(i+contextLines+1, len(lines))
+		if n := len(ranges); n > 0 && lo <= ranges[n-1][1] {
+			ranges[n-1][1] = hi
+		} else {
+			ranges = append(ranges, [2]int{lo, hi})
+		}
+	}
+
+	var sb strings.Builder
+	additions, deletions := 0, 0
+	for _, r := range ranges {
+		oldCount := oldNumbers[r[1]] - oldNumbers[r[0]]
+		newCount := newNumbers[r[1]] - newNumbers[r[0]]
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(oldNumbers[r[0]], oldCount), hunkRange(newNumbers[r[0]], newCount))
+		for _, l := range lines[r[0]:r[1]] {
+			switch l.op {
+			case '-':
+				deletions++
+			case '+':
+				additions++
+			}
+			sb.WriteByte(l.op)
+			if text, ok := strings.CutSuffix(l.text, "\n"); ok {
+				sb.WriteString(text)
+				sb.WriteByte('\n')
+			} else {
+				sb.WriteString(text)
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+	}
+	return sb.String(), additions, deletions
+}
+
+// hunkRange formats the start and length of a hunk's side
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
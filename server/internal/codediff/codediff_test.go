@@ -0,0 +1,192 @@
+package codediff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+func toolUse(id, name string, input map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "assistant",
+		"message": map[string]interface{}{"content": []interface{}{
+			map[string]interface{}{"type": "tool_use", "id": id, "name": name, "input": input},
+		}},
+	}
+}
+
+func toolResultLine(id string, isError bool, toolUseResult map[string]interface{}) map[string]interface{} {
+	line := map[string]interface{}{
+		"type": "user",
+		"message": map[string]interface{}{"content": []interface{}{
+			map[string]interface{}{"type": "tool_result", "tool_use_id": id, "is_error": isError, "content": "ok"},
+		}},
+	}
+	if toolUseResult != nil {
+		line["toolUseResult"] = toolUseResult
+	}
+	return line
+}
+
+func events(lines ...map[string]interface{}) []*domain.Event {
+	result := make([]*domain.Event, len(lines))
+	for i, line := range lines {
+		result[i] = &domain.Event{
+			ID:        "event-" + string(rune('a'+i)),
+			Payload:   line,
+			CreatedAt: time.Date(2026, 3, 2, 9, 0, i, 0, time.UTC),
+		}
+	}
+	return result
+}
+
+func TestUnifiedHunks(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected string
+	}{
+		{
+			name:     "equal",
+			a:        "a\nb\n",
+			b:        "a\nb\n",
+			expected: "",
+		},
+		{
+			name:     "new file",
+			a:        "",
+			b:        "a\nb\n",
+			expected: "@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:     "change with context",
+			a:        "1\n2\n3\n4\n5\n6\n7\n8\n",
+			b:        "1\n2\n3\n4\nfive\n6\n7\n8\n",
+			expected: "@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name:     "separate hunks",
+			a:        "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			b:        "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			expected: "@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+ten\n",
+		},
+		{
+			name:     "missing newline",
+			a:        "a\nb",
+			b:        "a\nc",
+			expected: "@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, _ := unifiedHunks(tt.a, tt.b)
+			if got != tt.expected {
+				t.Errorf("unifiedHunks() =\n%s\nwant\n%s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuild_TrackedFile(t *testing.T) {
+	diffs := Build(events(
+		toolUse("t1", "Write", map[string]interface{}{"file_path": "/app/main.go", "content": "package main\n\nfunc main() {\n}\n"}),
+		toolResultLine("t1", false, map[string]interface{}{"type": "create"}),
+		toolUse("t2", "Edit", map[string]interface{}{"file_path": "/app/main.go", "old_string": "func main() {\n}", "new_string": "func main() {\n\tprintln(1)\n}"}),
+		toolUse("t3", "MultiEdit", map[string]interface{}{"file_path": "/app/main.go", "edits": []interface{}{
+			map[string]interface{}{"old_string": "println(1)", "new_string": "println(2)"},
+		}}),
+		// Failed calls did not change the file
+		toolUse("t4", "Edit", map[string]interface{}{"file_path": "/app/main.go", "old_string": "missing", "new_string": "x"}),
+		toolResultLine("t4", true, nil),
+	), "/app")
+
+	if len(diffs) != 1 {
+		t.Fatalf("len(diffs) = %d, want 1", len(diffs))
+	}
+	d := diffs[0]
+	if d.Path != "main.go" || !d.Created || !d.Exact {
+		t.Errorf("diff = %s created=%v exact=%v, want main.go created exact", d.Path, d.Created, d.Exact)
+	}
+	expected := "--- /dev/null\n+++ b/main.go\n@@ -0,0 +1,5 @@\n+package main\n+\n+func main() {\n+\tprintln(2)\n+}\n"
+	if d.Diff != expected {
+		t.Errorf("Diff =\n%s\nwant\n%s", d.Diff, expected)
+	}
+	if d.Additions != 5 || d.Deletions != 0 {
+		t.Errorf("+%d -%d, want +5 -0", d.Additions, d.Deletions)
+	}
+
+	if len(d.Steps) != 3 {
+		t.Fatalf("len(Steps) = %d, want 3", len(d.Steps))
+	}
+	step := d.Steps[2]
+	if step.Tool != "MultiEdit" || step.ToolUseID != "t3" || step.EventID != "event-d" || !step.Exact {
+		t.Errorf("step = %+v, want exact MultiEdit t3 of event-d", step)
+	}
+	expected = "--- a/main.go\n+++ b/main.go\n@@ -1,5 +1,5 @@\n package main\n \n func main() {\n-\tprintln(1)\n+\tprintln(2)\n }\n"
+	if step.Diff != expected {
+		t.Errorf("step Diff =\n%s\nwant\n%s", step.Diff, expected)
+	}
+}
+
+func TestBuild_UnknownContent(t *testing.T) {
+	diffs := Build(events(
+		toolUse("t1", "Edit", map[string]interface{}{"file_path": "/app/a.go", "old_string": "x := 1", "new_string": "x := 2"}),
+		toolUse("t2", "Edit", map[string]interface{}{"file_path": "/app/a.go", "old_string": "y := 1", "new_string": "y := 2"}),
+	), "/app")
+
+	if len(diffs) != 1 {
+		t.Fatalf("len(diffs) = %d, want 1", len(diffs))
+	}
+	d := diffs[0]
+	if d.Exact || d.Created {
+		t.Errorf("exact=%v created=%v, want neither", d.Exact, d.Created)
+	}
+	expected := "--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-x := 1\n+x := 2\n@@ -1 +1 @@\n-y := 1\n+y := 2\n"
+	if d.Diff != expected {
+		t.Errorf("Diff =\n%s\nwant\n%s", d.Diff, expected)
+	}
+	if d.Additions != 2 || d.Deletions != 2 {
+		t.Errorf("+%d -%d, want +2 -2", d.Additions, d.Deletions)
+	}
+	for _, step := range d.Steps {
+		if step.Exact {
+			t.Errorf("step %s is exact, want snippet diff", step.ToolUseID)
+		}
+	}
+}
+
+func TestBuild_OriginalFile(t *testing.T) {
+	diffs := Build(events(
+		toolUse("t1", "Edit", map[string]interface{}{"file_path": "/app/a.go", "old_string": "b", "new_string": "B"}),
+		toolResultLine("t1", false, map[string]interface{}{"originalFile": "a\nb\nc\n"}),
+		toolUse("t2", "Edit", map[string]interface{}{"file_path": "/app/a.go", "old_string": "c", "new_string": "C", "replace_all": true}),
+	), "/app")
+
+	d := diffs[0]
+	if !d.Exact || d.Created {
+		t.Errorf("exact=%v created=%v, want exact, not created", d.Exact, d.Created)
+	}
+	expected := "--- a/a.go\n+++ b/a.go\n@@ -1,3 +1,3 @@\n a\n-b\n-c\n+B\n+C\n"
+	if d.Diff != expected {
+		t.Errorf("Diff =\n%s\nwant\n%s", d.Diff, expected)
+	}
+}
+
+func TestBuild_ChangedOutsideSession(t *testing.T) {
+	diffs := Build(events(
+		toolUse("t1", "Write", map[string]interface{}{"file_path": "/app/a.go", "content": "a\n"}),
+		// The user edited the file before the next tool call
+		toolUse("t2", "Edit", map[string]interface{}{"file_path": "/app/a.go", "old_string": "b", "new_string": "c"}),
+		toolResultLine("t2", false, map[string]interface{}{"originalFile": "a\nb\n"}),
+	), "/app")
+
+	d := diffs[0]
+	if d.Exact {
+		t.Error("cumulative diff is exact, want the steps joined")
+	}
+	if !d.Steps[1].Exact {
+		t.Error("second step is not exact, want it diffed against the recorded original")
+	}
+}
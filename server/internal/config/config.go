@@ -1,16 +1,29 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"strings"
+)
 
 type Config struct {
 	Port               string
 	DBType             string
 	DatabaseURL        string
 	DevMode            bool
-	APIKeyFixed        string // For backward compatibility in dev mode
-	WebURL             string // Frontend URL for redirects (defaults to self)
-	GitHubClientID     string // GitHub OAuth Client ID
-	GitHubClientSecret string // GitHub OAuth Client Secret
+	APIKeyFixed        string   // For backward compatibility in dev mode
+	WebURL             string   // Frontend URL for redirects (defaults to self)
+	GitHubClientID     string   // GitHub OAuth Client ID
+	GitHubClientSecret string   // GitHub OAuth Client Secret
+	RedactionEnabled   bool     // Redact secrets from ingested transcripts (default true)
+	RedactionPatterns  []string // Additional redaction regexes (newline-separated REDACTION_PATTERNS)
+	RedactionKey       string   // HMAC key of the hashes in redaction placeholders; empty leaves the hashes out
+	ModelPrices        string   // JSON price table overrides for cost estimation (MODEL_PRICES)
+	MaxIngestBodySize  int64    // Maximum decompressed /api/ingest body in bytes; 0 disables the limit
+
+	RequireAuth         bool     // Require login for reads (sessions, plans, projects, users, analytics)
+	AllowedEmailDomains []string // Email domains allowed to sign up (comma-separated ALLOWED_EMAIL_DOMAINS); empty allows any
+	AdminEmails         []string // Emails allowed to sign up regardless of ALLOWED_EMAIL_DOMAINS (comma-separated ADMIN_EMAILS)
 }
 
 func Load() *Config {
@@ -24,6 +37,15 @@ func Load() *Config {
 		WebURL:             getEnv("WEB_URL", ""), // Empty means same origin
 		GitHubClientID:     getEnv("GITHUB_CLIENT_ID", ""),
 		GitHubClientSecret: getEnv("GITHUB_CLIENT_SECRET", ""),
+		RedactionEnabled:   getEnv("REDACTION_ENABLED", "true") != "false",
+		RedactionPatterns:  splitLines(getEnv("REDACTION_PATTERNS", "")),
+		RedactionKey:       getEnv("REDACTION_KEY", ""),
+		ModelPrices:        getEnv("MODEL_PRICES", ""),
+		MaxIngestBodySize:  getEnvInt64("MAX_INGEST_BODY_SIZE", 64<<20),
+
+		RequireAuth:         getEnv("REQUIRE_AUTH", "") == "true",
+		AllowedEmailDomains: splitList(getEnv("ALLOWED_EMAIL_DOMAINS", "")),
+		AdminEmails:         splitList(getEnv("ADMIN_EMAILS", "")),
 	}
 }
 
@@ -32,6 +54,38 @@ func (c *Config) IsGitHubOAuthEnabled() bool {
 	return c.GitHubClientID != "" && c.GitHubClientSecret != ""
 }
 
+// IsEmailAllowed reports whether a new account may be created for email: any email
+// when no domains are configured, otherwise emails of an allowed domain or an admin
+func (c *Config) IsEmailAllowed(email string) bool {
+	if len(c.AllowedEmailDomains) == 0 {
+		return true
+	}
+	if c.IsAdminEmail(email) {
+		return true
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := email[at+1:]
+	for _, allowed := range c.AllowedEmailDomains {
+		if strings.EqualFold(strings.TrimPrefix(allowed, "@"), domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdminEmail reports whether email is listed in ADMIN_EMAILS
+func (c *Config) IsAdminEmail(email string) bool {
+	for _, admin := range c.AdminEmails {
+		if strings.EqualFold(admin, email) {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Config) IsDevMode() bool {
 	return c.DevMode || c.APIKeyFixed != ""
 }
@@ -42,3 +96,33 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvInt64 returns the integer value of key, or defaultValue if it is unset or not an integer
+func getEnvInt64(key string, defaultValue int64) int64 {
+	if n, err := strconv.ParseInt(os.Getenv(key), 10, 64); err == nil {
+		return n
+	}
+	return defaultValue
+}
+
+// splitList splits a comma-separated value, dropping blank entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// splitLines splits a newline-separated value, dropping blank lines
+func splitLines(value string) []string {
+	var lines []string
+	for _, line := range strings.Split(value, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
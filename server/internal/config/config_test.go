@@ -0,0 +1,56 @@
+package config
+
+import "testing"
+
+func TestIsEmailAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		domains []string
+		admins  []string
+		email   string
+		want    bool
+	}{
+		{"no allowlist", nil, nil, "someone@anywhere.com", true},
+		{"allowed domain", []string{"example.com"}, nil, "alice@example.com", true},
+		{"domain is case-insensitive", []string{"Example.com"}, nil, "alice@EXAMPLE.COM", true},
+		{"domain with leading @", []string{"@example.com"}, nil, "alice@example.com", true},
+		{"other domain", []string{"example.com"}, nil, "alice@contractor.com", false},
+		{"subdomain is not matched", []string{"example.com"}, nil, "alice@dev.example.com", false},
+		{"suffix is not matched", []string{"example.com"}, nil, "alice@badexample.com", false},
+		{"second allowed domain", []string{"example.com", "example.org"}, nil, "bob@example.org", true},
+		{"no @", []string{"example.com"}, nil, "example.com", false},
+		{"admin outside allowlist", []string{"example.com"}, []string{"Owner@Contractor.com"}, "owner@contractor.com", true},
+		{"other user of admin's domain", []string{"example.com"}, []string{"owner@contractor.com"}, "other@contractor.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{AllowedEmailDomains: tt.domains, AdminEmails: tt.admins}
+			if got := cfg.IsEmailAllowed(tt.email); got != tt.want {
+				t.Errorf("IsEmailAllowed(%q) = %v, want %v", tt.email, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	got := splitList(" example.com, ,example.org ,")
+	if len(got) != 2 || got[0] != "example.com" || got[1] != "example.org" {
+		t.Errorf("splitList() = %q, want [example.com example.org]", got)
+	}
+	if got := splitList(""); got != nil {
+		t.Errorf("splitList(\"\") = %q, want nil", got)
+	}
+}
+
+func TestIsAdminEmail(t *testing.T) {
+	cfg := &Config{AdminEmails: []string{"Owner@Example.com"}}
+	if !cfg.IsAdminEmail("owner@example.com") {
+		t.Error("IsAdminEmail() should match case-insensitively")
+	}
+	if cfg.IsAdminEmail("other@example.com") {
+		t.Error("IsAdminEmail() should not match other emails")
+	}
+	if (&Config{}).IsAdminEmail("owner@example.com") {
+		t.Error("IsAdminEmail() should be false without ADMIN_EMAILS")
+	}
+}
@@ -0,0 +1,170 @@
+package domain
+
+import "time"
+
+// AnalyticsBucketSize is the time bucket size of an analytics series
+type AnalyticsBucketSize string
+
+const (
+	AnalyticsBucketDay  AnalyticsBucketSize = "day"
+	AnalyticsBucketWeek AnalyticsBucketSize = "week"
+)
+
+func (b AnalyticsBucketSize) IsValid() bool {
+	switch b {
+	case AnalyticsBucketDay, AnalyticsBucketWeek:
+		return true
+	}
+	return false
+}
+
+// Truncate returns the start of the bucket containing t.
+// Buckets are in UTC; weeks start on Monday.
+func (b AnalyticsBucketSize) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if b == AnalyticsBucketWeek {
+		offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+		day = day.AddDate(0, 0, -offset)
+	}
+	return day
+}
+
+// Next returns the start of the bucket following start
+func (b AnalyticsBucketSize) Next(start time.Time) time.Time {
+	if b == AnalyticsBucketWeek {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// AnalyticsCounts are activity counts aggregated by analytics
+type AnalyticsCounts struct {
+	SessionsStarted   int
+	EventsIngested    int
+	ToolInvocations   int // tool_use blocks in ingested events
+	PlanStatusChanges int
+}
+
+// Add adds other to c
+func (c *AnalyticsCounts) Add(other AnalyticsCounts) {
+	c.SessionsStarted += other.SessionsStarted
+	c.EventsIngested += other.EventsIngested
+	c.ToolInvocations += other.ToolInvocations
+	c.PlanStatusChanges += other.PlanStatusChanges
+}
+
+// AnalyticsActivity is activity reported to AnalyticsRepository.RecordActivity.
+// A user with EventsIngested > 0 counts as active.
+type AnalyticsActivity struct {
+	Time      time.Time
+	ProjectID string
+	UserID    string // empty for anonymous activity
+	AnalyticsCounts
+}
+
+// AnalyticsQuery selects an analytics series.
+// From and To are UTC day boundaries; From is widened to the start of its bucket.
+type AnalyticsQuery struct {
+	Bucket    AnalyticsBucketSize
+	From      time.Time // inclusive
+	To        time.Time // exclusive
+	ProjectID string    // optional filter
+	UserID    string    // optional filter
+	// ExcludeProjectIDs are left out of the series (projects the reader cannot see)
+	ExcludeProjectIDs []string
+}
+
+// Excludes reports whether the project is left out of the series
+func (q AnalyticsQuery) Excludes(projectID string) bool {
+	for _, id := range q.ExcludeProjectIDs {
+		if id == projectID {
+			return true
+		}
+	}
+	return false
+}
+
+// AnalyticsBucket is one point of an analytics series
+type AnalyticsBucket struct {
+	Start time.Time
+	AnalyticsCounts
+	ActiveUsers int // distinct users with ingested events
+}
+
+// AnalyticsSeries accumulates counts into the (zero-filled) buckets of a query.
+// Backends feed it daily or per-activity counts; it rolls them up into weeks as needed.
+type AnalyticsSeries struct {
+	query   AnalyticsQuery
+	buckets []*AnalyticsBucket
+	index   map[int64]int // bucket start (unix) -> position
+	users   []map[string]struct{}
+}
+
+// NewAnalyticsSeries creates an empty series covering the query range
+func NewAnalyticsSeries(query AnalyticsQuery) *AnalyticsSeries {
+	s := &AnalyticsSeries{query: query, index: make(map[int64]int)}
+	for start := query.Bucket.Truncate(query.From); start.Before(query.To); start = query.Bucket.Next(start) {
+		s.index[start.Unix()] = len(s.buckets)
+		s.buckets = append(s.buckets, &AnalyticsBucket{Start: start})
+		s.users = append(s.users, make(map[string]struct{}))
+	}
+	return s
+}
+
+// From returns the start of the first bucket
+func (s *AnalyticsSeries) From() time.Time {
+	return s.query.Bucket.Truncate(s.query.From)
+}
+
+func (s *AnalyticsSeries) position(t time.Time) (int, bool) {
+	if t.Before(s.From()) || !t.Before(s.query.To) {
+		return 0, false
+	}
+	i, ok := s.index[s.query.Bucket.Truncate(t).Unix()]
+	return i, ok
+}
+
+// Add adds counts to the bucket containing t; times outside the range are ignored
+func (s *AnalyticsSeries) Add(t time.Time, counts AnalyticsCounts) {
+	if i, ok := s.position(t); ok {
+		s.buckets[i].Add(counts)
+	}
+}
+
+// AddActiveUser marks userID as active in the bucket containing t
+func (s *AnalyticsSeries) AddActiveUser(t time.Time, userID string) {
+	if userID == "" {
+		return
+	}
+	if i, ok := s.position(t); ok {
+		s.users[i][userID] = struct{}{}
+	}
+}
+
+// Buckets returns the accumulated buckets in chronological order
+func (s *AnalyticsSeries) Buckets() []*AnalyticsBucket {
+	for i, b := range s.buckets {
+		b.ActiveUsers = len(s.users[i])
+	}
+	return s.buckets
+}
+
+// ToolUseCount returns the number of tool_use blocks in the event's message content
+func (e *Event) ToolUseCount() int {
+	message, ok := e.Payload["message"].(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	content, ok := message["content"].([]interface{})
+	if !ok {
+		return 0
+	}
+	count := 0
+	for _, item := range content {
+		if block, ok := item.(map[string]interface{}); ok && block["type"] == "tool_use" {
+			count++
+		}
+	}
+	return count
+}
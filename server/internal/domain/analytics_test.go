@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnalyticsBucketSizeTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		bucket   AnalyticsBucketSize
+		input    time.Time
+		expected time.Time
+	}{
+		{
+			name:     "day",
+			bucket:   AnalyticsBucketDay,
+			input:    time.Date(2026, 3, 4, 15, 30, 0, 0, time.UTC),
+			expected: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day converts to UTC",
+			bucket:   AnalyticsBucketDay,
+			input:    time.Date(2026, 3, 4, 2, 0, 0, 0, time.FixedZone("JST", 9*60*60)),
+			expected: time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "week starts on Monday",
+			bucket:   AnalyticsBucketWeek,
+			input:    time.Date(2026, 3, 4, 15, 30, 0, 0, time.UTC), // Wednesday
+			expected: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "sunday belongs to the previous week",
+			bucket:   AnalyticsBucketWeek,
+			input:    time.Date(2026, 3, 8, 23, 59, 0, 0, time.UTC),
+			expected: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.bucket.Truncate(tt.input); !got.Equal(tt.expected) {
+				t.Errorf("Truncate() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAnalyticsSeries(t *testing.T) {
+	from := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC) // Wednesday
+	series := NewAnalyticsSeries(AnalyticsQuery{
+		Bucket: AnalyticsBucketWeek,
+		From:   from,
+		To:     from.AddDate(0, 0, 10),
+	})
+
+	series.Add(time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC), AnalyticsCounts{EventsIngested: 1}) // same week, before From
+	series.Add(time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC), AnalyticsCounts{EventsIngested: 2})
+	series.Add(time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC), AnalyticsCounts{EventsIngested: 4}) // after To
+	series.AddActiveUser(time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), "u1")
+	series.AddActiveUser(time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC), "u1")
+	series.AddActiveUser(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), "u2")
+
+	buckets := series.Buckets()
+	if len(buckets) != 2 {
+		t.Fatalf("len(Buckets()) = %d, want 2", len(buckets))
+	}
+	if !buckets[0].Start.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("buckets[0].Start = %v", buckets[0].Start)
+	}
+	if buckets[0].EventsIngested != 3 || buckets[0].ActiveUsers != 1 {
+		t.Errorf("buckets[0] = %+v, want 3 events and 1 active user", buckets[0])
+	}
+	if buckets[1].EventsIngested != 0 || buckets[1].ActiveUsers != 1 {
+		t.Errorf("buckets[1] = %+v, want 0 events and 1 active user", buckets[1])
+	}
+}
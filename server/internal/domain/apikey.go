@@ -1,13 +1,77 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+// APIKeyScope limits what an API key can be used for
+type APIKeyScope string
+
+const (
+	APIKeyScopeIngest APIKeyScope = "ingest" // send sessions
+	APIKeyScopePlans  APIKeyScope = "plans"  // read and write plans
+	APIKeyScopeRead   APIKeyScope = "read"   // read only
+)
+
+// IsValid checks if the scope is a known value
+func (s APIKeyScope) IsValid() bool {
+	switch s {
+	case APIKeyScopeIngest, APIKeyScopePlans, APIKeyScopeRead:
+		return true
+	}
+	return false
+}
 
 type APIKey struct {
 	ID         string
 	UserID     string
-	Name       string     // Key name (e.g., "MacBook Pro", "Work PC")
-	KeyHash    string     // bcrypt hash
-	KeyPrefix  string     // "agtr_xxxx..." (for display)
+	Name       string        // Key name (e.g., "MacBook Pro", "Work PC")
+	LookupID   string        // Public identifier embedded in the key, used for indexed lookup (empty for legacy keys not yet upgraded)
+	KeyHash    string        // SHA-256 verifier (bcrypt hash for legacy keys)
+	KeyPrefix  string        // "agtr_xxxx..." (for display)
+	Scopes     []APIKeyScope // empty = everything the user can do
+	ExpiresAt  *time.Time    // nil = never expires
 	LastUsedAt *time.Time
 	CreatedAt  time.Time
 }
+
+// IsScoped reports whether the key is limited to its scopes
+func (k *APIKey) IsScoped() bool {
+	return len(k.Scopes) > 0
+}
+
+// HasScope reports whether the key carries the scope
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (k *APIKey) IsExpired() bool {
+	return k.ExpiresAt != nil && time.Now().After(*k.ExpiresAt)
+}
+
+// FormatAPIKeyScopes joins scopes into the comma-separated form they are stored in
+func FormatAPIKeyScopes(scopes []APIKeyScope) string {
+	parts := make([]string, len(scopes))
+	for i, s := range scopes {
+		parts[i] = string(s)
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseAPIKeyScopes parses stored scopes; an empty string means no scopes
+func ParseAPIKeyScopes(s string) []APIKeyScope {
+	if s == "" {
+		return nil
+	}
+	var scopes []APIKeyScope
+	for _, part := range strings.Split(s, ",") {
+		scopes = append(scopes, APIKeyScope(part))
+	}
+	return scopes
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAPIKeyScopesRoundTrip(t *testing.T) {
+	scopes := []APIKeyScope{APIKeyScopeIngest, APIKeyScopePlans}
+	stored := FormatAPIKeyScopes(scopes)
+	if stored != "ingest,plans" {
+		t.Errorf("FormatAPIKeyScopes() = %q, want %q", stored, "ingest,plans")
+	}
+	parsed := ParseAPIKeyScopes(stored)
+	if len(parsed) != 2 || parsed[0] != APIKeyScopeIngest || parsed[1] != APIKeyScopePlans {
+		t.Errorf("ParseAPIKeyScopes(%q) = %v", stored, parsed)
+	}
+	if got := ParseAPIKeyScopes(""); got != nil {
+		t.Errorf("ParseAPIKeyScopes(\"\") = %v, want nil", got)
+	}
+	if got := FormatAPIKeyScopes(nil); got != "" {
+		t.Errorf("FormatAPIKeyScopes(nil) = %q, want empty", got)
+	}
+}
+
+func TestAPIKeyIsExpired(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	if (&APIKey{}).IsExpired() {
+		t.Error("a key without ExpiresAt should never expire")
+	}
+	if !(&APIKey{ExpiresAt: &past}).IsExpired() {
+		t.Error("a key past ExpiresAt should be expired")
+	}
+	if (&APIKey{ExpiresAt: &future}).IsExpired() {
+		t.Error("a key before ExpiresAt should not be expired")
+	}
+}
+
+func TestAPIKeyHasScope(t *testing.T) {
+	key := &APIKey{Scopes: []APIKeyScope{APIKeyScopeRead}}
+	if !key.IsScoped() || !key.HasScope(APIKeyScopeRead) || key.HasScope(APIKeyScopePlans) {
+		t.Errorf("unexpected scopes for %v", key.Scopes)
+	}
+	if (&APIKey{}).IsScoped() {
+		t.Error("a key without scopes should not be scoped")
+	}
+	if APIKeyScope("admin").IsValid() {
+		t.Error("unknown scope should be invalid")
+	}
+}
@@ -1,12 +1,47 @@
 package domain
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
 type Event struct {
-	ID        string
-	SessionID string
-	UUID      string // Claude Code transcript line UUID (unique per session)
-	EventType string
-	Payload   map[string]interface{}
-	CreatedAt time.Time
+	ID          string
+	SessionID   string
+	UUID        string // Claude Code transcript line UUID (unique per session)
+	ParentUUID  string // UUID of the transcript line this one follows (empty for the first line of a thread)
+	IsSidechain bool   // true for lines of a subagent spawned by the Task tool
+	EventType   string
+	Payload     map[string]interface{}
+	Raw         []byte    // the transcript line as received, after redaction (nil for events stored before lines were kept)
+	CreatedAt   time.Time // when the event happened (the line's timestamp), or when it was ingested if the line has none
+}
+
+// PayloadTimestamp returns the timestamp Claude Code recorded on the transcript line
+func (e *Event) PayloadTimestamp() (time.Time, bool) {
+	ts, ok := e.Payload["timestamp"].(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// Time returns when the event happened. Events ingested before CreatedAt
+// carried the line's timestamp are placed by their payload timestamp.
+func (e *Event) Time() time.Time {
+	if t, ok := e.PayloadTimestamp(); ok {
+		return t
+	}
+	return e.CreatedAt
+}
+
+// SortEventsByTime sorts events chronologically, keeping the order of events with equal times
+func SortEventsByTime(events []*Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time().Before(events[j].Time())
+	})
 }
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventTime(t *testing.T) {
+	ingested := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		payload  map[string]interface{}
+		expected time.Time
+	}{
+		{
+			name:     "payload timestamp",
+			payload:  map[string]interface{}{"timestamp": "2026-03-02T09:00:01.500Z"},
+			expected: time.Date(2026, 3, 2, 9, 0, 1, 500000000, time.UTC),
+		},
+		{
+			name:     "payload timestamp with offset",
+			payload:  map[string]interface{}{"timestamp": "2026-03-02T18:00:00+09:00"},
+			expected: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "malformed timestamp falls back to CreatedAt",
+			payload:  map[string]interface{}{"timestamp": "yesterday"},
+			expected: ingested,
+		},
+		{
+			name:     "no timestamp falls back to CreatedAt",
+			payload:  map[string]interface{}{"type": "summary"},
+			expected: ingested,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Event{Payload: tt.payload, CreatedAt: ingested}
+			if got := e.Time(); !got.Equal(tt.expected) {
+				t.Errorf("Time() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSortEventsByTime(t *testing.T) {
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	events := []*Event{
+		{ID: "c", Payload: map[string]interface{}{"timestamp": "2026-03-02T09:00:01Z"}},
+		{ID: "a", Payload: map[string]interface{}{"timestamp": "2026-03-02T09:00:00.5Z"}},
+		{ID: "b1", CreatedAt: base.Add(time.Second)},
+		{ID: "b2", CreatedAt: base.Add(time.Second)},
+	}
+
+	SortEventsByTime(events)
+
+	var got string
+	for _, e := range events {
+		got += e.ID + " "
+	}
+	// Equal times keep their original order
+	if expected := "a c b1 b2 "; got != expected {
+		t.Errorf("SortEventsByTime() order = %q, want %q", got, expected)
+	}
+}
@@ -0,0 +1,183 @@
+package domain
+
+import "strings"
+
+// TaskToolName is the Claude Code tool that spawns a subagent
+const TaskToolName = "Task"
+
+// EventTree is a session's events arranged as its main thread, with the
+// threads of the subagents (sidechains) branching off the events that spawned them
+type EventTree struct {
+	Nodes     []*EventTreeNode
+	Subagents []*SubagentThread // threads that could not be attached to any main thread event
+}
+
+// EventTreeNode is an event with the subagent threads it spawned
+type EventTreeNode struct {
+	Event     *Event
+	Subagents []*SubagentThread
+}
+
+// SubagentThread is the thread of one subagent invocation
+type SubagentThread struct {
+	RootUUID string // UUID of the first line of the thread
+	Nodes    []*EventTreeNode
+}
+
+// TaskPrompts returns the prompts of the Task tool_use blocks in the event's message content
+func (e *Event) TaskPrompts() []string {
+	message, ok := e.Payload["message"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	content, ok := message["content"].([]interface{})
+	if !ok {
+		return nil
+	}
+	var prompts []string
+	for _, item := range content {
+		block, ok := item.(map[string]interface{})
+		if !ok || block["type"] != "tool_use" || block["name"] != TaskToolName {
+			continue
+		}
+		prompt := ""
+		if input, ok := block["input"].(map[string]interface{}); ok {
+			prompt, _ = input["prompt"].(string)
+		}
+		prompts = append(prompts, prompt)
+	}
+	return prompts
+}
+
+// messageText returns the text of the event's message content
+func (e *Event) messageText() string {
+	message, ok := e.Payload["message"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	switch content := message["content"].(type) {
+	case string:
+		return content
+	case []interface{}:
+		var parts []string
+		for _, item := range content {
+			if block, ok := item.(map[string]interface{}); ok && block["type"] == "text" {
+				if text, ok := block["text"].(string); ok {
+					parts = append(parts, text)
+				}
+			}
+		}
+		return strings.Join(parts, "\n")
+	}
+	return ""
+}
+
+// BuildEventTree arranges chronologically sorted events into a tree. Sidechain
+// lines are grouped into threads by following their parent links; a thread
+// branches off the event its first line follows, or, when the transcript does
+// not link it, off the main thread event with the matching Task call.
+// Events for which hidden returns true are left out of the tree but still link threads.
+func BuildEventTree(events []*Event, hidden func(*Event) bool) *EventTree {
+	byUUID := make(map[string]*Event, len(events))
+	for _, e := range events {
+		if e.UUID != "" {
+			byUUID[e.UUID] = e
+		}
+	}
+
+	// The first line of the sidechain thread each sidechain line belongs to
+	roots := make(map[*Event]*Event)
+	var rootOf func(e *Event, depth int) *Event
+	rootOf = func(e *Event, depth int) *Event {
+		if root, ok := roots[e]; ok {
+			return root
+		}
+		root := e
+		if parent, ok := byUUID[e.ParentUUID]; ok && parent.IsSidechain && parent != e && depth < len(events) {
+			root = rootOf(parent, depth+1)
+		}
+		roots[e] = root
+		return root
+	}
+
+	tree := &EventTree{}
+	nodes := make(map[*Event]*EventTreeNode)
+	threads := make(map[*Event]*SubagentThread)
+	var threadRoots []*Event
+	var main []*Event
+	for _, e := range events {
+		var root *Event
+		if e.IsSidechain {
+			root = rootOf(e, 0)
+			if threads[root] == nil {
+				threads[root] = &SubagentThread{RootUUID: root.UUID}
+				threadRoots = append(threadRoots, root)
+			}
+		}
+		if hidden != nil && hidden(e) {
+			continue
+		}
+		node := &EventTreeNode{Event: e}
+		nodes[e] = node
+		if root != nil {
+			threads[root].Nodes = append(threads[root].Nodes, node)
+		} else {
+			tree.Nodes = append(tree.Nodes, node)
+			main = append(main, e)
+		}
+	}
+
+	for _, root := range threadRoots {
+		thread := threads[root]
+		if len(thread.Nodes) == 0 {
+			continue
+		}
+		if anchor := findThreadAnchor(root, byUUID, nodes, main); anchor != nil {
+			anchor.Subagents = append(anchor.Subagents, thread)
+		} else {
+			tree.Subagents = append(tree.Subagents, thread)
+		}
+	}
+
+	return tree
+}
+
+// findThreadAnchor returns the node a subagent thread branches off
+func findThreadAnchor(root *Event, byUUID map[string]*Event, nodes map[*Event]*EventTreeNode, main []*Event) *EventTreeNode {
+	// Linked by the transcript: the nearest shown line the thread follows
+	seen := make(map[*Event]bool)
+	for parent := byUUID[root.ParentUUID]; parent != nil && !seen[parent]; parent = byUUID[parent.ParentUUID] {
+		if node, ok := nodes[parent]; ok {
+			return node
+		}
+		seen[parent] = true
+	}
+
+	// Otherwise the Task call that spawned it: the latest one before the thread
+	// with the same prompt, else the latest one, else the latest main thread event
+	started := root.Time()
+	prompt := root.messageText()
+	var matched, latestTask, latest *Event
+	for _, e := range main {
+		if e.Time().After(started) {
+			break
+		}
+		latest = e
+		for _, p := range e.TaskPrompts() {
+			latestTask = e
+			if prompt != "" && p == prompt {
+				matched = e
+			}
+		}
+	}
+	if matched != nil {
+		return nodes[matched]
+	}
+	if latestTask != nil {
+		return nodes[latestTask]
+	}
+	if latest != nil {
+		return nodes[latest]
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func treeEvent(uuid, parent string, sidechain bool, offset time.Duration, payload map[string]interface{}) *Event {
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+	return &Event{
+		UUID:        uuid,
+		ParentUUID:  parent,
+		IsSidechain: sidechain,
+		Payload:     payload,
+		CreatedAt:   time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC).Add(offset),
+	}
+}
+
+func taskCall(prompt string) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "assistant",
+		"message": map[string]interface{}{
+			"content": []interface{}{
+				map[string]interface{}{"type": "tool_use", "name": "Task", "input": map[string]interface{}{"prompt": prompt}},
+			},
+		},
+	}
+}
+
+func subagentPrompt(prompt string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":    "user",
+		"message": map[string]interface{}{"content": prompt},
+	}
+}
+
+func threadUUIDs(thread *SubagentThread) string {
+	var got string
+	for _, node := range thread.Nodes {
+		got += node.Event.UUID + " "
+	}
+	return got
+}
+
+func TestBuildEventTree(t *testing.T) {
+	events := []*Event{
+		treeEvent("m1", "", false, 0, nil),
+		treeEvent("m2", "m1", false, 1*time.Second, taskCall("Find the tests")),
+		treeEvent("m3", "m2", false, 2*time.Second, taskCall("Read the README")),
+		// Two subagents running in parallel, neither linked to the main thread
+		treeEvent("a1", "", true, 3*time.Second, subagentPrompt("Find the tests")),
+		treeEvent("b1", "", true, 3*time.Second, subagentPrompt("Read the README")),
+		treeEvent("a2", "a1", true, 4*time.Second, nil),
+		treeEvent("b2", "b1", true, 5*time.Second, map[string]interface{}{"type": "system"}),
+		treeEvent("b3", "b2", true, 6*time.Second, nil),
+		treeEvent("m4", "m3", false, 7*time.Second, nil),
+	}
+
+	tree := BuildEventTree(events, func(e *Event) bool { return e.Payload["type"] == "system" })
+
+	if len(tree.Nodes) != 4 {
+		t.Fatalf("main thread has %d nodes, want 4", len(tree.Nodes))
+	}
+	if len(tree.Subagents) != 0 {
+		t.Errorf("unattached subagents = %d, want 0", len(tree.Subagents))
+	}
+
+	// Each thread branches off the Task call with its prompt
+	m2, m3 := tree.Nodes[1], tree.Nodes[2]
+	if len(m2.Subagents) != 1 || threadUUIDs(m2.Subagents[0]) != "a1 a2 " {
+		t.Errorf("m2 subagents = %+v, want thread a1 a2", m2.Subagents)
+	}
+	// Hidden lines are left out but keep the thread together
+	if len(m3.Subagents) != 1 || threadUUIDs(m3.Subagents[0]) != "b1 b3 " {
+		t.Errorf("m3 subagents = %+v, want thread b1 b3", m3.Subagents)
+	}
+	if m3.Subagents[0].RootUUID != "b1" {
+		t.Errorf("RootUUID = %q, want b1", m3.Subagents[0].RootUUID)
+	}
+}
+
+func TestBuildEventTree_Fallbacks(t *testing.T) {
+	events := []*Event{
+		treeEvent("m1", "", false, 0, taskCall("Explore")),
+		treeEvent("m2", "m1", false, 1*time.Second, nil),
+		// Linked to the main thread by the transcript
+		treeEvent("a1", "m1", true, 2*time.Second, nil),
+		// Unlinked with an unknown prompt: the latest Task call
+		treeEvent("b1", "", true, 3*time.Second, subagentPrompt("Something else")),
+		// Continues the linked thread
+		treeEvent("a2", "a1", true, 4*time.Second, nil),
+	}
+
+	tree := BuildEventTree(events, nil)
+
+	if len(tree.Nodes) != 2 {
+		t.Fatalf("main thread has %d nodes, want 2", len(tree.Nodes))
+	}
+	m1 := tree.Nodes[0]
+	if len(m1.Subagents) != 2 {
+		t.Fatalf("m1 has %d subagents, want 2", len(m1.Subagents))
+	}
+	if got := threadUUIDs(m1.Subagents[0]); got != "a1 a2 " {
+		t.Errorf("first thread = %q, want a1 a2", got)
+	}
+	if got := threadUUIDs(m1.Subagents[1]); got != "b1 " {
+		t.Errorf("second thread = %q, want b1", got)
+	}
+}
+
+func TestBuildEventTree_Unattached(t *testing.T) {
+	events := []*Event{
+		treeEvent("a1", "", true, 0, nil),
+		treeEvent("m1", "", false, time.Second, nil),
+	}
+
+	tree := BuildEventTree(events, nil)
+
+	if len(tree.Nodes) != 1 || len(tree.Nodes[0].Subagents) != 0 {
+		t.Fatalf("main thread = %+v, want m1 without subagents", tree.Nodes)
+	}
+	if len(tree.Subagents) != 1 || threadUUIDs(tree.Subagents[0]) != "a1 " {
+		t.Errorf("unattached subagents = %+v, want thread a1", tree.Subagents)
+	}
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// FileOperation is how a tool call changed a file
+type FileOperation string
+
+const (
+	FileOperationEdit  FileOperation = "edit"  // Edit, MultiEdit and NotebookEdit: changes to an existing file
+	FileOperationWrite FileOperation = "write" // Write: creates or overwrites the whole file
+)
+
+// fileTools maps the Claude Code tools that modify files to their operation and file path input
+var fileTools = map[string]struct {
+	operation FileOperation
+	pathInput string
+}{
+	"Edit":         {FileOperationEdit, "file_path"},
+	"MultiEdit":    {FileOperationEdit, "file_path"},
+	"NotebookEdit": {FileOperationEdit, "notebook_path"},
+	"Write":        {FileOperationWrite, "file_path"},
+}
+
+// FileActivity records a file modified by a tool call of a session
+type FileActivity struct {
+	ID        string
+	SessionID string
+	ProjectID string // the session's project, for looking up the sessions that touched a file
+	EventID   string
+	Path      string // relative to the session's project path, or absolute if outside it
+	Operation FileOperation
+	CreatedAt time.Time // when the tool was called
+}
+
+// RelativeFilePath returns path relative to projectPath if it is inside it
+func RelativeFilePath(projectPath, path string) string {
+	if projectPath == "" {
+		return path
+	}
+	if rel, ok := strings.CutPrefix(path, strings.TrimSuffix(projectPath, "/")+"/"); ok && rel != "" {
+		return rel
+	}
+	return path
+}
+
+// FileActivities returns the files modified by the tool_use blocks in the event's
+// message content, with paths relative to projectPath
+func (e *Event) FileActivities(projectPath string) []*FileActivity {
+	var activities []*FileActivity
+	for _, block := range e.contentBlocks() {
+		if block["type"] != "tool_use" {
+			continue
+		}
+		name, _ := block["name"].(string)
+		tool, ok := fileTools[name]
+		if !ok {
+			continue
+		}
+		input, _ := block["input"].(map[string]interface{})
+		path, _ := input[tool.pathInput].(string)
+		if path == "" {
+			continue
+		}
+		activities = append(activities, &FileActivity{
+			SessionID: e.SessionID,
+			EventID:   e.ID,
+			Path:      RelativeFilePath(projectPath, path),
+			Operation: tool.operation,
+			CreatedAt: e.Time(),
+		})
+	}
+	return activities
+}
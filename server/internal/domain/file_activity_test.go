@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRelativeFilePath(t *testing.T) {
+	tests := []struct {
+		name        string
+		projectPath string
+		path        string
+		expected    string
+	}{
+		{"inside project", "/home/u/app", "/home/u/app/server/main.go", "server/main.go"},
+		{"project path with trailing slash", "/home/u/app/", "/home/u/app/README.md", "README.md"},
+		{"sibling with same prefix", "/home/u/app", "/home/u/app2/main.go", "/home/u/app2/main.go"},
+		{"outside project", "/home/u/app", "/tmp/notes.md", "/tmp/notes.md"},
+		{"no project path", "", "/home/u/app/main.go", "/home/u/app/main.go"},
+		{"already relative", "/home/u/app", "main.go", "main.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RelativeFilePath(tt.projectPath, tt.path); got != tt.expected {
+				t.Errorf("RelativeFilePath(%q, %q) = %q, want %q", tt.projectPath, tt.path, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEventFileActivities(t *testing.T) {
+	e := &Event{
+		ID:        "event-1",
+		SessionID: "session-1",
+		Payload: map[string]interface{}{
+			"type":      "assistant",
+			"timestamp": "2026-03-02T09:00:00Z",
+			"message": map[string]interface{}{
+				"content": []interface{}{
+					map[string]interface{}{"type": "text", "text": "Updating files"},
+					map[string]interface{}{"type": "tool_use", "name": "Edit", "input": map[string]interface{}{"file_path": "/home/u/app/main.go"}},
+					map[string]interface{}{"type": "tool_use", "name": "MultiEdit", "input": map[string]interface{}{"file_path": "/home/u/app/go.mod"}},
+					map[string]interface{}{"type": "tool_use", "name": "Write", "input": map[string]interface{}{"file_path": "/home/u/app/docs/new.md"}},
+					map[string]interface{}{"type": "tool_use", "name": "NotebookEdit", "input": map[string]interface{}{"notebook_path": "/home/u/app/nb.ipynb"}},
+					// Not modifying files, or without a path
+					map[string]interface{}{"type": "tool_use", "name": "Read", "input": map[string]interface{}{"file_path": "/home/u/app/main.go"}},
+					map[string]interface{}{"type": "tool_use", "name": "Write", "input": map[string]interface{}{}},
+				},
+			},
+		},
+	}
+
+	activities := e.FileActivities("/home/u/app")
+
+	expected := []struct {
+		path      string
+		operation FileOperation
+	}{
+		{"main.go", FileOperationEdit},
+		{"go.mod", FileOperationEdit},
+		{"docs/new.md", FileOperationWrite},
+		{"nb.ipynb", FileOperationEdit},
+	}
+	if len(activities) != len(expected) {
+		t.Fatalf("FileActivities() returned %d activities, want %d", len(activities), len(expected))
+	}
+	for i, want := range expected {
+		got := activities[i]
+		if got.Path != want.path || got.Operation != want.operation {
+			t.Errorf("activity[%d] = %s %s, want %s %s", i, got.Operation, got.Path, want.operation, want.path)
+		}
+		if got.SessionID != "session-1" || got.EventID != "event-1" {
+			t.Errorf("activity[%d] session/event = %s/%s, want session-1/event-1", i, got.SessionID, got.EventID)
+		}
+		if !got.CreatedAt.Equal(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)) {
+			t.Errorf("activity[%d].CreatedAt = %v, want the event time", i, got.CreatedAt)
+		}
+	}
+
+	if got := (&Event{Payload: map[string]interface{}{"type": "user"}}).FileActivities(""); len(got) != 0 {
+		t.Errorf("FileActivities() without tool calls = %d activities, want 0", len(got))
+	}
+}
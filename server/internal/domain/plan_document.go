@@ -28,6 +28,7 @@ type PlanDocument struct {
 	Description string
 	Body        string
 	Status      PlanDocumentStatus
+	Revision    int // incremented on every change; used for optimistic concurrency
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -35,6 +36,7 @@ type PlanDocument struct {
 // PlanDocumentQuery represents search criteria for plan documents
 type PlanDocumentQuery struct {
 	ProjectID           string               // Filter by project ID (empty = all projects)
+	ExcludeProjectIDs   []string             // Skip plans of these projects
 	Statuses            []PlanDocumentStatus // Filter by statuses (empty = all statuses)
 	DescriptionContains string               // Filter by description containing this text (case-insensitive)
 	PlanDocumentIDs     []string             // Filter by specific plan document IDs (empty = all)
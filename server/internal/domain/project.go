@@ -7,7 +7,8 @@ const DefaultProjectID = "00000000-0000-0000-0000-000000000000"
 
 type Project struct {
 	ID                     string
-	CanonicalGitRepository string // Normalized HTTP-style git URL (empty = no project)
+	CanonicalGitRepository string  // Normalized HTTP-style git URL (empty = no project)
+	TeamID                 *string // nullable - the team that owns the project; nil = visible to everyone
 	CreatedAt              time.Time
 }
 
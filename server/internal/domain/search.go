@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// EventSearchMatch is an event matching a full-text search
+type EventSearchMatch struct {
+	EventID   string
+	SessionID string
+	Text      string // searchable text of the event (see Event.SearchText)
+	CreatedAt time.Time
+}
+
+// ParseSearchTerms splits a search query into lowercase terms.
+// All terms must match for an event to be returned.
+func ParseSearchTerms(query string) []string {
+	fields := strings.Fields(strings.ToLower(query))
+	terms := make([]string, 0, len(fields))
+	for _, f := range fields {
+		f = strings.Trim(f, `"'`)
+		if f != "" {
+			terms = append(terms, f)
+		}
+	}
+	return terms
+}
+
+// MatchesSearchTerms reports whether every term occurs in text as a word prefix
+// (case-insensitive), mirroring the token prefix matching of the full-text indexes.
+// Used by backends without a full-text index.
+func MatchesSearchTerms(text string, terms []string) bool {
+	if len(terms) == 0 || text == "" {
+		return false
+	}
+	lower := strings.ToLower(text)
+	for _, t := range terms {
+		if !containsWordPrefix(lower, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsWordPrefix reports whether term occurs in s at the start of a word
+func containsWordPrefix(s, term string) bool {
+	for offset := 0; offset < len(s); {
+		i := strings.Index(s[offset:], term)
+		if i < 0 {
+			return false
+		}
+		pos := offset + i
+		prev, _ := utf8.DecodeLastRuneInString(s[:pos])
+		if pos == 0 || !(unicode.IsLetter(prev) || unicode.IsDigit(prev)) {
+			return true
+		}
+		offset = pos + 1
+	}
+	return false
+}
+
+// SearchText returns the human-readable text of the event used for full-text search:
+// message text, tool inputs and tool results. Metadata such as IDs is excluded.
+func (e *Event) SearchText() string {
+	var parts []string
+
+	if summary, ok := e.Payload["summary"].(string); ok {
+		parts = append(parts, summary)
+	}
+
+	if message, ok := e.Payload["message"].(map[string]interface{}); ok {
+		switch content := message["content"].(type) {
+		case string:
+			parts = append(parts, content)
+		case []interface{}:
+			for _, item := range content {
+				block, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				switch block["type"] {
+				case "text":
+					if text, ok := block["text"].(string); ok {
+						parts = append(parts, text)
+					}
+				case "tool_use":
+					collectStrings(block["input"], &parts)
+				case "tool_result":
+					collectStrings(block["content"], &parts)
+				}
+			}
+		}
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// collectStrings appends all string values (and text blocks) found in v
+func collectStrings(v interface{}, parts *[]string) {
+	switch val := v.(type) {
+	case string:
+		if val != "" {
+			*parts = append(*parts, val)
+		}
+	case []interface{}:
+		for _, child := range val {
+			collectStrings(child, parts)
+		}
+	case map[string]interface{}:
+		// Content blocks: only their text, not type tags
+		if text, ok := val["text"].(string); ok {
+			*parts = append(*parts, text)
+			return
+		}
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			if key != "type" {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			collectStrings(val[key], parts)
+		}
+	}
+}
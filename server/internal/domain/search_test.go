@@ -0,0 +1,82 @@
+package domain
+
+import "testing"
+
+func TestMatchesSearchTerms(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		query    string
+		expected bool
+	}{
+		{
+			name:     "all terms present",
+			text:     "Fix the flaky migration test",
+			query:    "flaky migration",
+			expected: true,
+		},
+		{
+			name:     "case insensitive",
+			text:     "Fix the Flaky Migration test",
+			query:    "FLAKY",
+			expected: true,
+		},
+		{
+			name:     "word prefix",
+			text:     "run migrations",
+			query:    "migration",
+			expected: true,
+		},
+		{
+			name:     "not at word start",
+			text:     "TestFlakyMigration",
+			query:    "flaky",
+			expected: false,
+		},
+		{
+			name:     "one term missing",
+			text:     "Fix the flaky migration test",
+			query:    "flaky readme",
+			expected: false,
+		},
+		{
+			name:     "empty query",
+			text:     "anything",
+			query:    "",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MatchesSearchTerms(tt.text, ParseSearchTerms(tt.query))
+			if result != tt.expected {
+				t.Errorf("MatchesSearchTerms(%q, %q) = %v, want %v", tt.text, tt.query, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEventSearchText(t *testing.T) {
+	event := &Event{
+		Payload: map[string]interface{}{
+			"type": "assistant",
+			"uuid": "4f9c2b1e",
+			"message": map[string]interface{}{
+				"content": []interface{}{
+					map[string]interface{}{"type": "text", "text": "Running the tests"},
+					map[string]interface{}{
+						"type":  "tool_use",
+						"id":    "toolu_01",
+						"input": map[string]interface{}{"command": "go test ./..."},
+					},
+				},
+			},
+		},
+	}
+
+	expected := "Running the tests\ngo test ./..."
+	if text := event.SearchText(); text != expected {
+		t.Errorf("SearchText() = %q, want %q", text, expected)
+	}
+}
@@ -2,16 +2,115 @@ package domain
 
 import "time"
 
+// SessionState represents whether a session is still running
+type SessionState string
+
+const (
+	SessionStateActive SessionState = "active"
+	SessionStateEnded  SessionState = "ended"
+)
+
+// IsValid checks if the state is a valid value
+func (s SessionState) IsValid() bool {
+	switch s {
+	case SessionStateActive, SessionStateEnded:
+		return true
+	}
+	return false
+}
+
+// SessionVisibility controls who can read a session
+type SessionVisibility string
+
+const (
+	SessionVisibilityPrivate SessionVisibility = "private" // only the session's owner
+	SessionVisibilityTeam    SessionVisibility = "team"    // any signed-in user
+	SessionVisibilityPublic  SessionVisibility = "public"  // anyone, including anonymous readers
+)
+
+// DefaultSessionVisibility applies to sessions of users who have not chosen a default
+const DefaultSessionVisibility = SessionVisibilityPublic
+
+// IsValid checks if the visibility is a valid value
+func (v SessionVisibility) IsValid() bool {
+	switch v {
+	case SessionVisibilityPrivate, SessionVisibilityTeam, SessionVisibilityPublic:
+		return true
+	}
+	return false
+}
+
+// SessionViewer is the reader a session, plan or project query is made for
+type SessionViewer struct {
+	UserID           string   // empty for anonymous readers
+	HiddenProjectIDs []string // projects owned by teams the reader is not a member of
+}
+
+// CanViewProject reports whether the viewer may read the project and its sessions and plans
+func (v SessionViewer) CanViewProject(projectID string) bool {
+	for _, id := range v.HiddenProjectIDs {
+		if id == projectID {
+			return false
+		}
+	}
+	return true
+}
+
+// CanView reports whether the viewer may read the session.
+// Owners always can, even after its project was given to a team they are not in.
+func (v SessionViewer) CanView(s *Session) bool {
+	if v.UserID != "" && s.UserID != nil && *s.UserID == v.UserID {
+		return true
+	}
+	if !v.CanViewProject(s.ProjectID) {
+		return false
+	}
+	switch s.Visibility {
+	case SessionVisibilityPublic:
+		return true
+	case SessionVisibilityTeam:
+		return v.UserID != ""
+	}
+	return false
+}
+
 type Session struct {
 	ID              string
 	UserID          *string // nullable - set when user is authenticated
 	ProjectID       string  // reference to Project
 	ClaudeSessionID string
+	ParentSessionID *string // nullable - the session this one resumes or forks
 	ProjectPath     string
 	GitBranch       string  // git current branch
 	Title           *string // nullable - auto-generated from first user message or manually set
 	StartedAt       time.Time
-	EndedAt         *time.Time
-	UpdatedAt       time.Time // last activity time (updated when events are added)
+	EndedAt         *time.Time // nullable - set by SessionEnd hook
+	UpdatedAt       time.Time  // last activity time (updated when events are added)
 	CreatedAt       time.Time
+	RedactionCount  int        // number of secrets redacted from ingested events
+	SubagentCount   int        // number of subagents (sidechains) spawned during the session
+	Usage           TokenUsage // token usage aggregated from ingested events
+	Visibility      SessionVisibility
+}
+
+// State returns ended if the session has been marked ended and no activity
+// has been recorded since (a session resumed after SessionEnd is active again).
+func (s *Session) State() SessionState {
+	if s.EndedAt != nil && !s.UpdatedAt.After(*s.EndedAt) {
+		return SessionStateEnded
+	}
+	return SessionStateActive
+}
+
+// Duration returns the elapsed time from start until the session ended,
+// or until the last activity if it is still active
+func (s *Session) Duration() time.Duration {
+	end := s.UpdatedAt
+	if s.State() == SessionStateEnded {
+		end = *s.EndedAt
+	}
+	if end.Before(s.StartedAt) {
+		return 0
+	}
+	return end.Sub(s.StartedAt)
 }
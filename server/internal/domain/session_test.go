@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionStateAndDuration(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		ts := start.Add(d)
+		return &ts
+	}
+
+	tests := []struct {
+		name             string
+		updatedAt        time.Time
+		endedAt          *time.Time
+		expectedState    SessionState
+		expectedDuration time.Duration
+	}{
+		{
+			name:             "never ended",
+			updatedAt:        start.Add(5 * time.Minute),
+			endedAt:          nil,
+			expectedState:    SessionStateActive,
+			expectedDuration: 5 * time.Minute,
+		},
+		{
+			name:             "ended after last activity",
+			updatedAt:        start.Add(5 * time.Minute),
+			endedAt:          at(6 * time.Minute),
+			expectedState:    SessionStateEnded,
+			expectedDuration: 6 * time.Minute,
+		},
+		{
+			name:             "ended at last activity",
+			updatedAt:        start.Add(5 * time.Minute),
+			endedAt:          at(5 * time.Minute),
+			expectedState:    SessionStateEnded,
+			expectedDuration: 5 * time.Minute,
+		},
+		{
+			name:             "resumed after end",
+			updatedAt:        start.Add(10 * time.Minute),
+			endedAt:          at(6 * time.Minute),
+			expectedState:    SessionStateActive,
+			expectedDuration: 10 * time.Minute,
+		},
+		{
+			name:             "updated before start",
+			updatedAt:        start.Add(-1 * time.Minute),
+			endedAt:          nil,
+			expectedState:    SessionStateActive,
+			expectedDuration: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Session{StartedAt: start, UpdatedAt: tt.updatedAt, EndedAt: tt.endedAt}
+			if state := s.State(); state != tt.expectedState {
+				t.Errorf("State() = %q, want %q", state, tt.expectedState)
+			}
+			if d := s.Duration(); d != tt.expectedDuration {
+				t.Errorf("Duration() = %v, want %v", d, tt.expectedDuration)
+			}
+		})
+	}
+}
+
+func TestSessionViewerCanView(t *testing.T) {
+	owner := "user-1"
+
+	tests := []struct {
+		name       string
+		visibility SessionVisibility
+		ownerID    *string
+		viewerID   string
+		expected   bool
+	}{
+		{name: "public to anonymous", visibility: SessionVisibilityPublic, ownerID: &owner, viewerID: "", expected: true},
+		{name: "team to anonymous", visibility: SessionVisibilityTeam, ownerID: &owner, viewerID: "", expected: false},
+		{name: "team to other user", visibility: SessionVisibilityTeam, ownerID: &owner, viewerID: "user-2", expected: true},
+		{name: "private to anonymous", visibility: SessionVisibilityPrivate, ownerID: &owner, viewerID: "", expected: false},
+		{name: "private to other user", visibility: SessionVisibilityPrivate, ownerID: &owner, viewerID: "user-2", expected: false},
+		{name: "private to owner", visibility: SessionVisibilityPrivate, ownerID: &owner, viewerID: owner, expected: true},
+		{name: "private without owner", visibility: SessionVisibilityPrivate, ownerID: nil, viewerID: owner, expected: false},
+		{name: "unknown visibility to owner", visibility: "", ownerID: &owner, viewerID: owner, expected: true},
+		{name: "unknown visibility to other user", visibility: "", ownerID: &owner, viewerID: "user-2", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &Session{UserID: tt.ownerID, Visibility: tt.visibility}
+			viewer := SessionViewer{UserID: tt.viewerID}
+			if got := viewer.CanView(session); got != tt.expected {
+				t.Errorf("CanView() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSessionViewerHiddenProjects(t *testing.T) {
+	viewer := SessionViewer{UserID: "user-1", HiddenProjectIDs: []string{"project-2"}}
+
+	if !viewer.CanViewProject("project-1") {
+		t.Error("CanViewProject(project-1) = false, want true")
+	}
+	if viewer.CanViewProject("project-2") {
+		t.Error("CanViewProject(project-2) = true, want false")
+	}
+
+	session := &Session{ProjectID: "project-2", Visibility: SessionVisibilityPublic}
+	if viewer.CanView(session) {
+		t.Error("CanView() of a public session in a hidden project = true, want false")
+	}
+	session.ProjectID = "project-1"
+	if !viewer.CanView(session) {
+		t.Error("CanView() of a public session in a visible project = false, want true")
+	}
+
+	// Owners keep their sessions when the project is given to another team
+	owner := "user-1"
+	own := &Session{ProjectID: "project-2", UserID: &owner, Visibility: SessionVisibilityPrivate}
+	if !viewer.CanView(own) {
+		t.Error("CanView() of the viewer's own session in a hidden project = false, want true")
+	}
+	other := SessionViewer{UserID: "user-2", HiddenProjectIDs: []string{"project-2"}}
+	if other.CanView(own) {
+		t.Error("CanView() of another user's session in a hidden project = true, want false")
+	}
+}
+
+func TestUserNewSessionVisibility(t *testing.T) {
+	user := &User{}
+	if got := user.NewSessionVisibility(); got != DefaultSessionVisibility {
+		t.Errorf("NewSessionVisibility() = %q, want %q", got, DefaultSessionVisibility)
+	}
+
+	user.DefaultSessionVisibility = SessionVisibilityPrivate
+	if got := user.NewSessionVisibility(); got != SessionVisibilityPrivate {
+		t.Errorf("NewSessionVisibility() = %q, want %q", got, SessionVisibilityPrivate)
+	}
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// Team groups users; projects owned by a team are only visible to its members
+type Team struct {
+	ID        string
+	Name      string
+	CreatedAt time.Time
+}
+
+// TeamMembership links a user to a team
+type TeamMembership struct {
+	TeamID    string
+	UserID    string
+	CreatedAt time.Time
+}
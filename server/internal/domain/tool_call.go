@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// ToolCall is a tool_use block of a session paired with its tool_result
+type ToolCall struct {
+	ID          string
+	SessionID   string
+	ProjectID   string // the session's project, for per-project statistics
+	EventID     string // the line with the tool_use block
+	ToolUseID   string
+	ToolName    string
+	IsError     bool
+	StartedAt   time.Time  // time of the tool_use line
+	CompletedAt *time.Time // time of the tool_result line; nil while the result is pending
+}
+
+// Duration returns the time between the tool_use and tool_result lines
+func (c *ToolCall) Duration() (time.Duration, bool) {
+	if c.CompletedAt == nil {
+		return 0, false
+	}
+	return max(c.CompletedAt.Sub(c.StartedAt), 0), true
+}
+
+// ToolResult is the outcome of a tool call recorded in a tool_result block
+type ToolResult struct {
+	ToolUseID string
+	IsError   bool
+}
+
+// ToolCalls returns the calls of the tool_use blocks in the event's message content
+func (e *Event) ToolCalls() []*ToolCall {
+	var calls []*ToolCall
+	for _, block := range e.contentBlocks() {
+		if block["type"] != "tool_use" {
+			continue
+		}
+		id, _ := block["id"].(string)
+		name, _ := block["name"].(string)
+		if id == "" {
+			continue
+		}
+		calls = append(calls, &ToolCall{
+			SessionID: e.SessionID,
+			EventID:   e.ID,
+			ToolUseID: id,
+			ToolName:  name,
+			StartedAt: e.Time(),
+		})
+	}
+	return calls
+}
+
+// ToolResults returns the tool_result blocks in the event's message content
+func (e *Event) ToolResults() []ToolResult {
+	var results []ToolResult
+	for _, block := range e.contentBlocks() {
+		if block["type"] != "tool_result" {
+			continue
+		}
+		id, _ := block["tool_use_id"].(string)
+		if id == "" {
+			continue
+		}
+		isError, _ := block["is_error"].(bool)
+		results = append(results, ToolResult{ToolUseID: id, IsError: isError})
+	}
+	return results
+}
+
+// contentBlocks returns the blocks of the event's message content
+func (e *Event) contentBlocks() []map[string]interface{} {
+	message, ok := e.Payload["message"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	content, ok := message["content"].([]interface{})
+	if !ok {
+		return nil
+	}
+	blocks := make([]map[string]interface{}, 0, len(content))
+	for _, item := range content {
+		if block, ok := item.(map[string]interface{}); ok {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// ToolStats summarizes the calls of one tool
+type ToolStats struct {
+	ToolName    string
+	Calls       int
+	Errors      int
+	Pending     int // calls without a result yet
+	AvgDuration time.Duration
+	P50Duration time.Duration
+	P95Duration time.Duration
+	MaxDuration time.Duration
+}
+
+// ErrorRate returns the share of completed calls that failed
+func (s *ToolStats) ErrorRate() float64 {
+	completed := s.Calls - s.Pending
+	if completed == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(completed)
+}
+
+// SummarizeToolCalls returns the statistics of each tool, most called first,
+// and the statistics of all calls together
+func SummarizeToolCalls(calls []*ToolCall) ([]*ToolStats, *ToolStats) {
+	byTool := make(map[string][]*ToolCall)
+	for _, c := range calls {
+		byTool[c.ToolName] = append(byTool[c.ToolName], c)
+	}
+
+	stats := make([]*ToolStats, 0, len(byTool))
+	for name, toolCalls := range byTool {
+		stats = append(stats, summarize(name, toolCalls))
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Calls != stats[j].Calls {
+			return stats[i].Calls > stats[j].Calls
+		}
+		return stats[i].ToolName < stats[j].ToolName
+	})
+	return stats, summarize("", calls)
+}
+
+func summarize(name string, calls []*ToolCall) *ToolStats {
+	s := &ToolStats{ToolName: name, Calls: len(calls)}
+	var durations []time.Duration
+	var total time.Duration
+	for _, c := range calls {
+		d, ok := c.Duration()
+		if !ok {
+			s.Pending++
+			continue
+		}
+		if c.IsError {
+			s.Errors++
+		}
+		durations = append(durations, d)
+		total += d
+	}
+	if len(durations) == 0 {
+		return s
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	s.AvgDuration = total / time.Duration(len(durations))
+	s.P50Duration = percentile(durations, 50)
+	s.P95Duration = percentile(durations, 95)
+	s.MaxDuration = durations[len(durations)-1]
+	return s
+}
+
+// percentile returns the nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventToolCallsAndResults(t *testing.T) {
+	use := &Event{
+		ID:        "event-1",
+		SessionID: "session-1",
+		Payload: map[string]interface{}{
+			"timestamp": "2026-03-02T09:00:00Z",
+			"message": map[string]interface{}{"content": []interface{}{
+				map[string]interface{}{"type": "text", "text": "Checking"},
+				map[string]interface{}{"type": "tool_use", "id": "toolu_1", "name": "Bash"},
+				map[string]interface{}{"type": "tool_use", "id": "toolu_2", "name": "Read"},
+			}},
+		},
+	}
+
+	calls := use.ToolCalls()
+	if len(calls) != 2 {
+		t.Fatalf("ToolCalls() returned %d calls, want 2", len(calls))
+	}
+	if calls[0].ToolUseID != "toolu_1" || calls[0].ToolName != "Bash" || calls[0].EventID != "event-1" || calls[0].SessionID != "session-1" {
+		t.Errorf("calls[0] = %+v", calls[0])
+	}
+	if !calls[1].StartedAt.Equal(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("StartedAt = %v, want the line time", calls[1].StartedAt)
+	}
+
+	result := &Event{Payload: map[string]interface{}{
+		"message": map[string]interface{}{"content": []interface{}{
+			map[string]interface{}{"type": "tool_result", "tool_use_id": "toolu_1", "is_error": true},
+			map[string]interface{}{"type": "tool_result", "tool_use_id": "toolu_2"},
+		}},
+	}}
+	results := result.ToolResults()
+	if len(results) != 2 || !results[0].IsError || results[1].IsError || results[1].ToolUseID != "toolu_2" {
+		t.Errorf("ToolResults() = %+v", results)
+	}
+
+	if got := (&Event{Payload: map[string]interface{}{"message": map[string]interface{}{"content": "text"}}}).ToolCalls(); len(got) != 0 {
+		t.Errorf("ToolCalls() for text content = %d calls, want 0", len(got))
+	}
+}
+
+func TestSummarizeToolCalls(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	call := func(name string, seconds int, isError bool) *ToolCall {
+		c := &ToolCall{ToolName: name, StartedAt: start, IsError: isError}
+		if seconds >= 0 {
+			completed := start.Add(time.Duration(seconds) * time.Second)
+			c.CompletedAt = &completed
+		}
+		return c
+	}
+
+	stats, total := SummarizeToolCalls([]*ToolCall{
+		call("Bash", 1, false),
+		call("Bash", 3, true),
+		call("Bash", 2, false),
+		call("Bash", -1, false), // pending
+		call("Read", 1, false),
+	})
+
+	if len(stats) != 2 {
+		t.Fatalf("len(stats) = %d, want 2", len(stats))
+	}
+	bash := stats[0]
+	if bash.ToolName != "Bash" || bash.Calls != 4 || bash.Errors != 1 || bash.Pending != 1 {
+		t.Errorf("Bash stats = %+v", bash)
+	}
+	if bash.AvgDuration != 2*time.Second || bash.P50Duration != 2*time.Second || bash.P95Duration != 3*time.Second || bash.MaxDuration != 3*time.Second {
+		t.Errorf("Bash durations = avg %v p50 %v p95 %v max %v", bash.AvgDuration, bash.P50Duration, bash.P95Duration, bash.MaxDuration)
+	}
+	if rate := bash.ErrorRate(); rate < 0.33 || rate > 0.34 {
+		t.Errorf("Bash ErrorRate() = %v, want 1/3", rate)
+	}
+
+	if total.Calls != 5 || total.Errors != 1 || total.Pending != 1 || total.ToolName != "" {
+		t.Errorf("total = %+v", total)
+	}
+
+	_, empty := SummarizeToolCalls(nil)
+	if empty.Calls != 0 || empty.ErrorRate() != 0 {
+		t.Errorf("empty total = %+v", empty)
+	}
+}
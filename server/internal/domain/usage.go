@@ -0,0 +1,64 @@
+package domain
+
+// TokenUsage is token usage reported in message.usage of assistant transcript lines,
+// together with its estimated cost
+type TokenUsage struct {
+	InputTokens         int64
+	OutputTokens        int64
+	CacheReadTokens     int64   // cache_read_input_tokens
+	CacheCreationTokens int64   // cache_creation_input_tokens
+	EstimatedCostUSD    float64 // estimated from the model price table at ingest time
+}
+
+// TotalTokens returns the sum of all token counts
+func (u TokenUsage) TotalTokens() int64 {
+	return u.InputTokens + u.OutputTokens + u.CacheReadTokens + u.CacheCreationTokens
+}
+
+// IsZero reports whether no tokens and no cost are recorded
+func (u TokenUsage) IsZero() bool {
+	return u.TotalTokens() == 0 && u.EstimatedCostUSD == 0
+}
+
+// Add adds other to u
+func (u *TokenUsage) Add(other TokenUsage) {
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.CacheReadTokens += other.CacheReadTokens
+	u.CacheCreationTokens += other.CacheCreationTokens
+	u.EstimatedCostUSD += other.EstimatedCostUSD
+}
+
+// MessageUsage extracts the API message ID, model and token usage of an assistant transcript line.
+// ok is false if the line carries no usage.
+//
+// Claude Code writes one line per content block of an assistant message, each repeating
+// the same message ID and usage, so callers must count a message ID only once.
+func (e *Event) MessageUsage() (messageID string, model string, usage TokenUsage, ok bool) {
+	message, isMap := e.Payload["message"].(map[string]interface{})
+	if !isMap {
+		return "", "", TokenUsage{}, false
+	}
+	raw, isMap := message["usage"].(map[string]interface{})
+	if !isMap {
+		return "", "", TokenUsage{}, false
+	}
+
+	messageID, _ = message["id"].(string)
+	model, _ = message["model"].(string)
+	usage = TokenUsage{
+		InputTokens:         usageCount(raw, "input_tokens"),
+		OutputTokens:        usageCount(raw, "output_tokens"),
+		CacheReadTokens:     usageCount(raw, "cache_read_input_tokens"),
+		CacheCreationTokens: usageCount(raw, "cache_creation_input_tokens"),
+	}
+	return messageID, model, usage, true
+}
+
+// usageCount reads a token count (JSON numbers decode as float64)
+func usageCount(usage map[string]interface{}, key string) int64 {
+	if v, ok := usage[key].(float64); ok && v > 0 {
+		return int64(v)
+	}
+	return 0
+}
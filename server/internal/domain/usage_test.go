@@ -0,0 +1,62 @@
+package domain
+
+import "testing"
+
+func TestEventMessageUsage(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       map[string]interface{}
+		expectedOK    bool
+		expectedID    string
+		expectedModel string
+		expected      TokenUsage
+	}{
+		{
+			name: "assistant message with usage",
+			payload: map[string]interface{}{
+				"type": "assistant",
+				"message": map[string]interface{}{
+					"id":    "msg_01",
+					"model": "claude-sonnet-4-5-20250929",
+					"usage": map[string]interface{}{
+						"input_tokens":                float64(12),
+						"output_tokens":               float64(345),
+						"cache_read_input_tokens":     float64(6789),
+						"cache_creation_input_tokens": float64(1011),
+						"service_tier":                "standard",
+					},
+				},
+			},
+			expectedOK:    true,
+			expectedID:    "msg_01",
+			expectedModel: "claude-sonnet-4-5-20250929",
+			expected:      TokenUsage{InputTokens: 12, OutputTokens: 345, CacheReadTokens: 6789, CacheCreationTokens: 1011},
+		},
+		{
+			name: "user message without usage",
+			payload: map[string]interface{}{
+				"type":    "user",
+				"message": map[string]interface{}{"role": "user", "content": "hello"},
+			},
+			expectedOK: false,
+		},
+		{
+			name:       "no message",
+			payload:    map[string]interface{}{"type": "summary"},
+			expectedOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &Event{Payload: tt.payload}
+			id, model, usage, ok := event.MessageUsage()
+			if ok != tt.expectedOK {
+				t.Fatalf("MessageUsage() ok = %v, want %v", ok, tt.expectedOK)
+			}
+			if id != tt.expectedID || model != tt.expectedModel || usage != tt.expected {
+				t.Errorf("MessageUsage() = (%q, %q, %+v), want (%q, %q, %+v)", id, model, usage, tt.expectedID, tt.expectedModel, tt.expected)
+			}
+		})
+	}
+}
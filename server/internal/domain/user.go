@@ -2,11 +2,53 @@ package domain
 
 import "time"
 
+// UserRole is what a user is allowed to do
+type UserRole string
+
+const (
+	UserRoleAdmin  UserRole = "admin"  // manages users, in addition to what members do
+	UserRoleMember UserRole = "member" // reads, ingests sessions and edits plans
+	UserRoleViewer UserRole = "viewer" // reads only
+)
+
+// DefaultUserRole is the role of new users other than the first one
+const DefaultUserRole = UserRoleMember
+
+// IsValid checks if the role is a valid value
+func (r UserRole) IsValid() bool {
+	switch r {
+	case UserRoleAdmin, UserRoleMember, UserRoleViewer:
+		return true
+	}
+	return false
+}
+
+// Allows reports whether the role grants everything the required role does
+func (r UserRole) Allows(required UserRole) bool {
+	return r.rank() >= required.rank() && r.rank() > 0
+}
+
+func (r UserRole) rank() int {
+	switch r {
+	case UserRoleViewer:
+		return 1
+	case UserRoleMember:
+		return 2
+	case UserRoleAdmin:
+		return 3
+	}
+	return 0
+}
+
 type User struct {
 	ID          string    `json:"id"`
 	Email       string    `json:"email"`
 	DisplayName string    `json:"display_name"`
 	CreatedAt   time.Time `json:"created_at"`
+
+	DefaultSessionVisibility SessionVisibility `json:"default_session_visibility"` // visibility of the user's new sessions; empty means DefaultSessionVisibility
+	Role                     UserRole          `json:"role"`
+	Disabled                 bool              `json:"disabled"` // disabled users cannot sign in or use their API keys
 }
 
 // GetDisplayName returns DisplayName if set, otherwise Email
@@ -16,3 +58,11 @@ func (u *User) GetDisplayName() string {
 	}
 	return u.Email
 }
+
+// NewSessionVisibility returns the visibility of the user's new sessions
+func (u *User) NewSessionVisibility() SessionVisibility {
+	if u.DefaultSessionVisibility.IsValid() {
+		return u.DefaultSessionVisibility
+	}
+	return DefaultSessionVisibility
+}
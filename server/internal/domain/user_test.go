@@ -0,0 +1,27 @@
+package domain
+
+import "testing"
+
+func TestUserRoleAllows(t *testing.T) {
+	tests := []struct {
+		role     UserRole
+		required UserRole
+		want     bool
+	}{
+		{UserRoleAdmin, UserRoleAdmin, true},
+		{UserRoleAdmin, UserRoleMember, true},
+		{UserRoleAdmin, UserRoleViewer, true},
+		{UserRoleMember, UserRoleAdmin, false},
+		{UserRoleMember, UserRoleMember, true},
+		{UserRoleMember, UserRoleViewer, true},
+		{UserRoleViewer, UserRoleMember, false},
+		{UserRoleViewer, UserRoleViewer, true},
+		{UserRole(""), UserRoleViewer, false},
+		{UserRole("owner"), UserRoleViewer, false},
+	}
+	for _, tt := range tests {
+		if got := tt.role.Allows(tt.required); got != tt.want {
+			t.Errorf("%q.Allows(%q) = %v, want %v", tt.role, tt.required, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Package eventbus is an in-process publish/subscribe bus used to push
+// ingest and plan changes to live (SSE) subscribers.
+//
+// Every message gets an ID of the form "<epoch>-<seq>". The bus keeps a
+// bounded history so a reconnecting subscriber can resume after the last ID
+// it saw; if that ID is from another process (server restart) or has fallen
+// out of the history, the subscriber is told to reset instead.
+package eventbus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message types
+const (
+	TypeEventCreated   = "event.created"
+	TypeSessionCreated = "session.created"
+	TypeSessionUpdated = "session.updated"
+	TypePlanCreated    = "plan.created"
+	TypePlanUpdated    = "plan.updated"
+	TypePlanDeleted    = "plan.deleted"
+)
+
+// DefaultHistorySize is the number of recent messages kept for resume
+const DefaultHistorySize = 1000
+
+// subscriberBuffer is the channel size per subscriber; a subscriber that
+// falls further behind is disconnected and must resume with its last ID
+const subscriberBuffer = 64
+
+// Message is a single published change
+type Message struct {
+	ID        string      // assigned by Publish
+	Type      string      // one of the Type* constants
+	SessionID string      // set for session-scoped messages (event.created)
+	Global    bool        // delivered to the global stream
+	Data      interface{} // JSON-serializable payload
+	seq       uint64
+}
+
+// Filter selects which messages a subscriber receives
+type Filter func(Message) bool
+
+// SessionFilter selects messages scoped to the given session
+func SessionFilter(sessionID string) Filter {
+	return func(m Message) bool { return m.SessionID == sessionID }
+}
+
+// GlobalFilter selects messages published to the global stream
+func GlobalFilter() Filter {
+	return func(m Message) bool { return m.Global }
+}
+
+type subscriber struct {
+	ch     chan Message
+	filter Filter
+}
+
+// Bus fans out published messages to subscribers
+type Bus struct {
+	mu          sync.Mutex
+	epoch       string
+	seq         uint64
+	history     []Message
+	historySize int
+	subscribers map[*subscriber]struct{}
+}
+
+// New creates a Bus that keeps historySize messages for resume
+func New(historySize int) *Bus {
+	return &Bus{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		historySize: historySize,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Publish assigns an ID to msg and delivers it to matching subscribers.
+// A nil Bus does nothing.
+func (b *Bus) Publish(msg Message) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	msg.seq = b.seq
+	msg.ID = fmt.Sprintf("%s-%d", b.epoch, b.seq)
+
+	b.history = append(b.history, msg)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for sub := range b.subscribers {
+		if !sub.filter(msg) {
+			continue
+		}
+		select {
+		case sub.ch <- msg:
+		default:
+			// Too slow: disconnect so the client resumes from history
+			close(sub.ch)
+			delete(b.subscribers, sub)
+		}
+	}
+}
+
+// Subscription is a live feed of messages
+type Subscription struct {
+	// Replay holds messages published after the requested last ID
+	Replay []Message
+	// Reset is true when the requested last ID could not be resumed from
+	// (unknown epoch or outside the history); the client should reload state
+	Reset bool
+	// C delivers new messages; it is closed if the subscriber falls behind
+	C <-chan Message
+
+	bus *Bus
+	sub *subscriber
+}
+
+// Subscribe starts a subscription for messages matching filter.
+// If lastID is non-empty, messages after it are returned in Replay.
+func (b *Bus) Subscribe(filter Filter, lastID string) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &subscriber{ch: make(chan Message, subscriberBuffer), filter: filter}
+	b.subscribers[sub] = struct{}{}
+	s := &Subscription{C: sub.ch, bus: b, sub: sub}
+
+	if lastID == "" {
+		return s
+	}
+
+	lastSeq, ok := b.parseID(lastID)
+	if !ok || lastSeq > b.seq {
+		s.Reset = true
+		return s
+	}
+	if lastSeq == b.seq {
+		return s
+	}
+	// History must still contain the message right after lastSeq
+	if len(b.history) == 0 || b.history[0].seq > lastSeq+1 {
+		s.Reset = true
+		return s
+	}
+	for _, m := range b.history {
+		if m.seq > lastSeq && filter(m) {
+			s.Replay = append(s.Replay, m)
+		}
+	}
+	return s
+}
+
+// Close stops the subscription
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	if _, ok := s.bus.subscribers[s.sub]; ok {
+		close(s.sub.ch)
+		delete(s.bus.subscribers, s.sub)
+	}
+}
+
+// parseID returns the sequence number of an ID issued by this bus
+func (b *Bus) parseID(id string) (uint64, bool) {
+	epoch, seqStr, ok := strings.Cut(id, "-")
+	if !ok || epoch != b.epoch {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return seq, true
+}
@@ -0,0 +1,123 @@
+package eventbus
+
+import (
+	"testing"
+)
+
+func TestPublishSubscribe(t *testing.T) {
+	bus := New(DefaultHistorySize)
+
+	sub := bus.Subscribe(SessionFilter("session-1"), "")
+	defer sub.Close()
+
+	bus.Publish(Message{Type: TypeEventCreated, SessionID: "session-2"})
+	bus.Publish(Message{Type: TypeEventCreated, SessionID: "session-1", Data: "hello"})
+
+	select {
+	case msg := <-sub.C:
+		if msg.SessionID != "session-1" || msg.Data != "hello" || msg.ID == "" {
+			t.Errorf("unexpected message: %+v", msg)
+		}
+	default:
+		t.Fatal("expected a message for session-1")
+	}
+
+	select {
+	case msg := <-sub.C:
+		t.Errorf("unexpected extra message: %+v", msg)
+	default:
+	}
+}
+
+func TestSubscribe_Resume(t *testing.T) {
+	bus := New(DefaultHistorySize)
+
+	first := bus.Subscribe(GlobalFilter(), "")
+	bus.Publish(Message{Type: TypeSessionCreated, Global: true, Data: 1})
+	lastSeen := (<-first.C).ID
+	first.Close()
+
+	bus.Publish(Message{Type: TypeSessionUpdated, Global: true, Data: 2})
+	bus.Publish(Message{Type: TypeEventCreated, SessionID: "session-1", Data: 3})
+	bus.Publish(Message{Type: TypePlanUpdated, Global: true, Data: 4})
+
+	sub := bus.Subscribe(GlobalFilter(), lastSeen)
+	defer sub.Close()
+
+	if sub.Reset {
+		t.Fatal("expected resume, got reset")
+	}
+	if len(sub.Replay) != 2 || sub.Replay[0].Data != 2 || sub.Replay[1].Data != 4 {
+		t.Errorf("unexpected replay: %+v", sub.Replay)
+	}
+}
+
+func TestSubscribe_Reset(t *testing.T) {
+	tests := []struct {
+		name   string
+		lastID func(bus *Bus) string
+	}{
+		{
+			name:   "ID from another process",
+			lastID: func(bus *Bus) string { return "otherepoch-1" },
+		},
+		{
+			name:   "malformed ID",
+			lastID: func(bus *Bus) string { return "garbage" },
+		},
+		{
+			name: "ID outside history",
+			lastID: func(bus *Bus) string {
+				sub := bus.Subscribe(GlobalFilter(), "")
+				defer sub.Close()
+				bus.Publish(Message{Global: true})
+				id := (<-sub.C).ID
+				for i := 0; i < 5; i++ {
+					bus.Publish(Message{Global: true})
+				}
+				return id
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := New(3)
+			lastID := tt.lastID(bus)
+
+			sub := bus.Subscribe(GlobalFilter(), lastID)
+			defer sub.Close()
+			if !sub.Reset {
+				t.Errorf("Subscribe(%q) Reset = false, want true", lastID)
+			}
+			if len(sub.Replay) != 0 {
+				t.Errorf("Subscribe(%q) Replay = %d messages, want none", lastID, len(sub.Replay))
+			}
+		})
+	}
+}
+
+func TestPublish_SlowSubscriberDisconnected(t *testing.T) {
+	bus := New(DefaultHistorySize)
+
+	sub := bus.Subscribe(GlobalFilter(), "")
+	for i := 0; i < subscriberBuffer+1; i++ {
+		bus.Publish(Message{Global: true})
+	}
+
+	received := 0
+	for range sub.C {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Errorf("received %d messages before close, want %d", received, subscriberBuffer)
+	}
+
+	// Closing an already dropped subscription is a no-op
+	sub.Close()
+}
+
+func TestPublish_NilBus(t *testing.T) {
+	var bus *Bus
+	bus.Publish(Message{Global: true})
+}
@@ -0,0 +1,257 @@
+// Package export renders a session transcript as a standalone document
+// (Markdown or HTML) or as Claude Code's original JSONL transcript.
+//
+// The document formats group consecutive transcript lines into turns: the
+// lines Claude Code writes for each content block of an assistant message
+// are merged, and tool results are attached to the turn that requested them.
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+// Format is an export format
+type Format string
+
+const (
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+	FormatJSONL    Format = "jsonl"
+)
+
+func (f Format) IsValid() bool {
+	switch f {
+	case FormatMarkdown, FormatHTML, FormatJSONL:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	}
+	return "application/x-ndjson"
+}
+
+// Extension returns the file extension of the format
+func (f Format) Extension() string {
+	switch f {
+	case FormatMarkdown:
+		return "md"
+	case FormatHTML:
+		return "html"
+	}
+	return "jsonl"
+}
+
+// Write renders events in the given format
+func Write(w io.Writer, format Format, session *domain.Session, events []*domain.Event) error {
+	switch format {
+	case FormatMarkdown:
+		return Markdown(w, session, events)
+	case FormatHTML:
+		return HTML(w, session, events)
+	case FormatJSONL:
+		return JSONL(w, events)
+	}
+	return fmt.Errorf("unknown export format %q", format)
+}
+
+// JSONL writes one transcript line per event, in order, so that the output can
+// be re-imported as a Claude Code transcript. Lines are written as they were
+// received. Events stored before lines were kept are re-encoded from their
+// payload, with HTML characters not escaped (matching JSON.stringify) but with
+// object keys in sorted order.
+func JSONL(w io.Writer, events []*domain.Event) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, e := range events {
+		if e.Raw != nil {
+			if _, err := w.Write(e.Raw); err != nil {
+				return err
+			}
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+			continue
+		}
+		// Encode appends the newline
+		if err := enc.Encode(e.Payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Block kinds
+const (
+	blockText       = "text"
+	blockThinking   = "thinking"
+	blockToolUse    = "tool_use"
+	blockToolResult = "tool_result"
+)
+
+// block is one renderable piece of a turn
+type block struct {
+	Kind    string
+	Text    string // text, thinking or tool result content
+	Name    string // tool name
+	Input   string // tool input as indented JSON
+	IsError bool   // tool result reported an error
+}
+
+// turn is a run of consecutive transcript lines from the same speaker
+type turn struct {
+	Role   string // "user" or "assistant"
+	Time   time.Time
+	Blocks []block
+}
+
+// buildTurns groups user and assistant lines into turns; other line types are skipped
+func buildTurns(events []*domain.Event) []*turn {
+	var turns []*turn
+	for _, e := range events {
+		role, _ := e.Payload["type"].(string)
+		if role != "user" && role != "assistant" {
+			continue
+		}
+		blocks := messageBlocks(e.Payload)
+		if len(blocks) == 0 {
+			continue
+		}
+
+		var current *turn
+		if len(turns) > 0 {
+			current = turns[len(turns)-1]
+		}
+		switch {
+		case current != nil && current.Role == role && role == "assistant":
+			current.Blocks = append(current.Blocks, blocks...)
+		case current != nil && role == "user" && onlyToolResults(blocks):
+			current.Blocks = append(current.Blocks, blocks...)
+		default:
+			turns = append(turns, &turn{Role: role, Time: e.Time(), Blocks: blocks})
+		}
+	}
+	return turns
+}
+
+func onlyToolResults(blocks []block) bool {
+	for _, b := range blocks {
+		if b.Kind != blockToolResult {
+			return false
+		}
+	}
+	return true
+}
+
+// messageBlocks extracts the content blocks of a transcript line's message
+func messageBlocks(payload map[string]interface{}) []block {
+	message, ok := payload["message"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	// Claude Code's typical user message format
+	if text, ok := message["content"].(string); ok {
+		if strings.TrimSpace(text) == "" {
+			return nil
+		}
+		return []block{{Kind: blockText, Text: text}}
+	}
+
+	items, ok := message["content"].([]interface{})
+	if !ok {
+		return nil
+	}
+	var blocks []block
+	for _, item := range items {
+		b, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch b["type"] {
+		case "text":
+			if text, _ := b["text"].(string); strings.TrimSpace(text) != "" {
+				blocks = append(blocks, block{Kind: blockText, Text: text})
+			}
+		case "thinking":
+			if text, _ := b["thinking"].(string); strings.TrimSpace(text) != "" {
+				blocks = append(blocks, block{Kind: blockThinking, Text: text})
+			}
+		case "tool_use":
+			name, _ := b["name"].(string)
+			blocks = append(blocks, block{Kind: blockToolUse, Name: name, Input: indentJSON(b["input"])})
+		case "tool_result":
+			isError, _ := b["is_error"].(bool)
+			blocks = append(blocks, block{Kind: blockToolResult, Text: toolResultText(b["content"]), IsError: isError})
+		}
+	}
+	return blocks
+}
+
+// toolResultText flattens tool result content, which is either a string or a list of blocks
+func toolResultText(content interface{}) string {
+	if text, ok := content.(string); ok {
+		return text
+	}
+	items, ok := content.([]interface{})
+	if !ok {
+		return ""
+	}
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		b, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch b["type"] {
+		case "text":
+			if text, ok := b["text"].(string); ok {
+				parts = append(parts, text)
+			}
+		case "image":
+			parts = append(parts, "[image]")
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func indentJSON(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// title returns the session title, or a placeholder for untitled sessions
+func title(session *domain.Session) string {
+	if session.Title != nil && *session.Title != "" {
+		return *session.Title
+	}
+	return "Session " + session.ID
+}
+
+// formatTime formats times in documents
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05 UTC")
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+func testSession() *domain.Session {
+	title := "Fix the <build>"
+	return &domain.Session{
+		ID:          "session-1",
+		Title:       &title,
+		ProjectPath: "/home/user/project",
+		GitBranch:   "main",
+		StartedAt:   time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func testEvents() []*domain.Event {
+	lines := []map[string]interface{}{
+		{"type": "user", "timestamp": "2026-03-02T09:00:01.000Z", "message": map[string]interface{}{"role": "user", "content": "Run the tests"}},
+		{"type": "assistant", "message": map[string]interface{}{"id": "msg_1", "content": []interface{}{
+			map[string]interface{}{"type": "text", "text": "Running them now."},
+		}}},
+		{"type": "assistant", "message": map[string]interface{}{"id": "msg_1", "content": []interface{}{
+			map[string]interface{}{"type": "tool_use", "id": "toolu_1", "name": "Bash", "input": map[string]interface{}{"command": "go test ./..."}},
+		}}},
+		{"type": "user", "message": map[string]interface{}{"role": "user", "content": []interface{}{
+			map[string]interface{}{"type": "tool_result", "tool_use_id": "toolu_1", "is_error": true, "content": "FAIL\n```\nx <y>\n```"},
+		}}},
+		{"type": "summary", "summary": "Tests"},
+		{"type": "assistant", "message": map[string]interface{}{"id": "msg_2", "content": []interface{}{
+			map[string]interface{}{"type": "text", "text": "One test fails."},
+		}}},
+	}
+	events := make([]*domain.Event, len(lines))
+	for i, line := range lines {
+		events[i] = &domain.Event{Payload: line, CreatedAt: time.Date(2026, 3, 2, 9, 0, i, 0, time.UTC)}
+	}
+	return events
+}
+
+func TestBuildTurns(t *testing.T) {
+	turns := buildTurns(testEvents())
+	if len(turns) != 2 {
+		t.Fatalf("len(turns) = %d, want 2", len(turns))
+	}
+
+	if turns[0].Role != "user" || len(turns[0].Blocks) != 1 {
+		t.Errorf("turns[0] = %+v", turns[0])
+	}
+	if !turns[0].Time.Equal(time.Date(2026, 3, 2, 9, 0, 1, 0, time.UTC)) {
+		t.Errorf("turns[0].Time = %v, want the transcript timestamp", turns[0].Time)
+	}
+
+	// Assistant blocks of the same turn are merged, tool results attached
+	kinds := make([]string, len(turns[1].Blocks))
+	for i, b := range turns[1].Blocks {
+		kinds[i] = b.Kind
+	}
+	expected := []string{blockText, blockToolUse, blockToolResult, blockText}
+	if strings.Join(kinds, ",") != strings.Join(expected, ",") {
+		t.Errorf("turns[1] kinds = %v, want %v", kinds, expected)
+	}
+	if !turns[1].Blocks[2].IsError {
+		t.Error("tool result IsError = false, want true")
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Markdown(&buf, testSession(), testEvents()); err != nil {
+		t.Fatalf("Markdown() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# Fix the <build>\n",
+		"- Branch: `main`\n",
+		"## User (2026-03-02 09:00:01 UTC)\n\nRun the tests\n",
+		"**Tool: Bash**\n\n```json\n{\n  \"command\": \"go test ./...\"\n}\n```\n",
+		// The result contains a fence, so a longer one is used
+		"**Tool result (error)**\n\n````\nFAIL\n```\nx <y>\n```\n````\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Markdown() missing %q\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "## Assistant") != 1 {
+		t.Errorf("Markdown() should have one assistant turn\n%s", out)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := HTML(&buf, testSession(), testEvents()); err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"<title>Fix the &lt;build&gt;</title>",
+		`<section class="assistant">`,
+		`<div class="tool-result error"><strong>Tool result (error)</strong><pre>FAIL`,
+		"x &lt;y&gt;",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML() missing %q\n%s", want, out)
+		}
+	}
+}
+
+func TestJSONL(t *testing.T) {
+	events := []*domain.Event{
+		{Payload: map[string]interface{}{"type": "user", "message": map[string]interface{}{"content": "a < b && c"}}},
+		{Payload: map[string]interface{}{"type": "file-history-snapshot"}},
+		{Payload: map[string]interface{}{"type": "summary", "cost": 1.5}, Raw: []byte(`{"type":"summary", "cost":1.50}`)},
+	}
+
+	var buf bytes.Buffer
+	if err := JSONL(&buf, events); err != nil {
+		t.Fatalf("JSONL() error = %v", err)
+	}
+
+	expected := `{"message":{"content":"a < b && c"},"type":"user"}` + "\n" +
+		`{"type":"file-history-snapshot"}` + "\n" +
+		`{"type":"summary", "cost":1.50}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("JSONL() = %q, want %q", buf.String(), expected)
+	}
+}
@@ -0,0 +1,86 @@
+package export
+
+import (
+	"html/template"
+	"io"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+var htmlTemplate = template.Must(template.New("session").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 960px; margin: 2rem auto; padding: 0 1rem; color: #1f2328; }
+dl { display: grid; grid-template-columns: max-content auto; gap: 0.25rem 1rem; color: #59636e; }
+dd { margin: 0; }
+section { border-top: 1px solid #d1d9e0; padding: 0.5rem 0; }
+h2 { font-size: 1rem; }
+h2 time { font-weight: normal; color: #59636e; margin-left: 0.5rem; }
+.user h2 { color: #0969da; }
+.assistant h2 { color: #8250df; }
+.text { white-space: pre-wrap; }
+pre { background: #f6f8fa; padding: 0.75rem; overflow-x: auto; white-space: pre-wrap; }
+.error pre { background: #ffebe9; }
+details { color: #59636e; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<dl>
+<dt>Session</dt><dd><code>{{.Session.ID}}</code></dd>
+<dt>Started</dt><dd>{{.Started}}</dd>
+{{- if .Session.ProjectPath}}
+<dt>Project</dt><dd><code>{{.Session.ProjectPath}}</code></dd>
+{{- end}}
+{{- if .Session.GitBranch}}
+<dt>Branch</dt><dd><code>{{.Session.GitBranch}}</code></dd>
+{{- end}}
+</dl>
+{{- range .Turns}}
+<section class="{{.Role}}">
+<h2>{{if eq .Role "assistant"}}Assistant{{else}}User{{end}}<time>{{.Time}}</time></h2>
+{{- range .Blocks}}
+{{- if eq .Kind "text"}}
+<div class="text">{{.Text}}</div>
+{{- else if eq .Kind "thinking"}}
+<details><summary>Thinking</summary><div class="text">{{.Text}}</div></details>
+{{- else if eq .Kind "tool_use"}}
+<div class="tool-use"><strong>Tool: {{.Name}}</strong>{{if .Input}}<pre>{{.Input}}</pre>{{end}}</div>
+{{- else if eq .Kind "tool_result"}}
+<div class="tool-result{{if .IsError}} error{{end}}"><strong>Tool result{{if .IsError}} (error){{end}}</strong><pre>{{.Text}}</pre></div>
+{{- end}}
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+type htmlTurn struct {
+	Role   string
+	Time   string
+	Blocks []block
+}
+
+// HTML renders the session as a self-contained HTML page
+func HTML(w io.Writer, session *domain.Session, events []*domain.Event) error {
+	turns := buildTurns(events)
+	data := struct {
+		Title   string
+		Session *domain.Session
+		Started string
+		Turns   []htmlTurn
+	}{
+		Title:   title(session),
+		Session: session,
+		Started: formatTime(session.StartedAt),
+		Turns:   make([]htmlTurn, len(turns)),
+	}
+	for i, t := range turns {
+		data.Turns[i] = htmlTurn{Role: t.Role, Time: formatTime(t.Time), Blocks: t.Blocks}
+	}
+	return htmlTemplate.Execute(w, data)
+}
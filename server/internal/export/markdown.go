@@ -0,0 +1,71 @@
+package export
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+// Markdown renders the session as a Markdown document
+func Markdown(w io.Writer, session *domain.Session, events []*domain.Event) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString("# " + strings.ReplaceAll(title(session), "\n", " ") + "\n\n")
+	bw.WriteString("- Session: `" + session.ID + "`\n")
+	bw.WriteString("- Started: " + formatTime(session.StartedAt) + "\n")
+	if session.ProjectPath != "" {
+		bw.WriteString("- Project: `" + session.ProjectPath + "`\n")
+	}
+	if session.GitBranch != "" {
+		bw.WriteString("- Branch: `" + session.GitBranch + "`\n")
+	}
+
+	for _, t := range buildTurns(events) {
+		heading := "User"
+		if t.Role == "assistant" {
+			heading = "Assistant"
+		}
+		bw.WriteString("\n## " + heading + " (" + formatTime(t.Time) + ")\n")
+
+		for _, b := range t.Blocks {
+			bw.WriteString("\n")
+			switch b.Kind {
+			case blockText:
+				bw.WriteString(b.Text + "\n")
+			case blockThinking:
+				bw.WriteString("<details>\n<summary>Thinking</summary>\n\n" + b.Text + "\n\n</details>\n")
+			case blockToolUse:
+				bw.WriteString("**Tool: " + b.Name + "**\n")
+				if b.Input != "" {
+					bw.WriteString("\n" + codeBlock("json", b.Input))
+				}
+			case blockToolResult:
+				if b.IsError {
+					bw.WriteString("**Tool result (error)**\n")
+				} else {
+					bw.WriteString("**Tool result**\n")
+				}
+				bw.WriteString("\n" + codeBlock("", b.Text))
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// codeBlock fences text with a backtick run longer than any inside it
+func codeBlock(lang, text string) string {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	return fence + lang + "\n" + text + "\n" + fence + "\n"
+}
@@ -0,0 +1,171 @@
+// Package planpatch computes, applies and replays the patches stored in
+// plan document events.
+//
+// The first body_change event of a plan stores the initial body in an
+// "all additions" format (each line prefixed with "+"); every later
+// body_change event stores a diff-match-patch patch against the previous
+// body. Replaying the events in order reproduces the current body.
+package planpatch
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// ErrPatchMismatch is returned when a patch does not apply cleanly to the body it is based on
+var ErrPatchMismatch = errors.New("patch does not apply to the current body")
+
+// InitialPatch converts body content to an "all additions" diff format.
+// Each line is prefixed with "+" to indicate it was added.
+func InitialPatch(body string) string {
+	if body == "" {
+		return ""
+	}
+	lines := strings.Split(body, "\n")
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		result[i] = "+" + line
+	}
+	return strings.Join(result, "\n")
+}
+
+// initialBody is the inverse of InitialPatch
+func initialBody(patch string) (string, error) {
+	if patch == "" {
+		return "", nil
+	}
+	lines := strings.Split(patch, "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "+") {
+			return "", ErrPatchMismatch
+		}
+		lines[i] = line[1:]
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// Patch offsets and lengths are counted in UTF-16 code units, as written by the
+// JavaScript diff-match-patch the CLI uses; go-diff counts bytes, so they are
+// converted when patches are made and applied.
+
+// Make returns the diff-match-patch patch text turning oldBody into newBody
+func Make(oldBody, newBody string) string {
+	dmp := diffmatchpatch.New()
+	patches := dmp.PatchMake(oldBody, newBody)
+	for i, p := range patches {
+		// A patch covers oldBody[Start1:Start1+Length1] and newBody[Start2:Start2+Length2]
+		patches[i].Start1 = utf16Len(oldBody[:p.Start1])
+		patches[i].Length1 = utf16Len(oldBody[p.Start1 : p.Start1+p.Length1])
+		patches[i].Start2 = utf16Len(newBody[:p.Start2])
+		patches[i].Length2 = utf16Len(newBody[p.Start2 : p.Start2+p.Length2])
+	}
+	return dmp.PatchToText(patches)
+}
+
+// Apply applies diff-match-patch patch text to body.
+// It returns ErrPatchMismatch if the text is malformed or any hunk fails to apply.
+func Apply(body, patch string) (string, error) {
+	if patch == "" {
+		return body, nil
+	}
+	dmp := diffmatchpatch.New()
+	patches, err := dmp.PatchFromText(patch)
+	if err != nil {
+		return "", ErrPatchMismatch
+	}
+	// Hunks are applied one at a time: Start2 is an offset into the text with the
+	// previous hunks applied, which is needed to convert it to bytes
+	result := body
+	for _, p := range patches {
+		text1, text2, err := hunkTexts(p)
+		if err != nil {
+			return "", ErrPatchMismatch
+		}
+		p.Start1 = byteOffset(body, p.Start1)
+		p.Start2 = byteOffset(result, p.Start2)
+		p.Length1 = len(text1)
+		p.Length2 = len(text2)
+
+		var applied []bool
+		result, applied = dmp.PatchApply([]diffmatchpatch.Patch{p}, result)
+		if !applied[0] {
+			return "", ErrPatchMismatch
+		}
+	}
+	return result, nil
+}
+
+// hunkTexts returns the text a patch expects (context and deletions) and the text it leaves
+func hunkTexts(p diffmatchpatch.Patch) (string, string, error) {
+	var text1, text2 strings.Builder
+	lines := strings.Split(p.String(), "\n")
+	for _, line := range lines[1:] { // skip the @@ header
+		if line == "" {
+			continue
+		}
+		// Decoded the same way as PatchFromText
+		text, err := url.QueryUnescape(strings.ReplaceAll(line[1:], "+", "%2b"))
+		if err != nil {
+			return "", "", err
+		}
+		switch line[0] {
+		case ' ':
+			text1.WriteString(text)
+			text2.WriteString(text)
+		case '-':
+			text1.WriteString(text)
+		case '+':
+			text2.WriteString(text)
+		}
+	}
+	return text1.String(), text2.String(), nil
+}
+
+// utf16Len returns the length of s in UTF-16 code units
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// byteOffset converts an offset into s in UTF-16 code units to bytes
+func byteOffset(s string, units int) int {
+	n := 0
+	for i, r := range s {
+		if n >= units {
+			return i
+		}
+		n += utf16.RuneLen(r)
+	}
+	return len(s)
+}
+
+// Replay reconstructs a plan body from its events (oldest first).
+// Status change events are skipped.
+func Replay(events []*domain.PlanDocumentEvent) (string, error) {
+	var body string
+	initialized := false
+	for _, event := range events {
+		if event.EventType != domain.PlanDocumentEventTypeBodyChange {
+			continue
+		}
+		var err error
+		if !initialized {
+			body, err = initialBody(event.Patch)
+			initialized = true
+		} else {
+			body, err = Apply(body, event.Patch)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return body, nil
+}
@@ -0,0 +1,126 @@
+package planpatch
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+func TestMakeApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldBody string
+		newBody string
+	}{
+		{
+			name:    "append line",
+			oldBody: "# Plan\n\n- step 1\n",
+			newBody: "# Plan\n\n- step 1\n- step 2\n",
+		},
+		{
+			name:    "remove everything",
+			oldBody: "# Plan\n\n- step 1\n",
+			newBody: "",
+		},
+		{
+			name:    "multibyte and special characters",
+			oldBody: "# 計画\n\n- 手順 1: a+b=c\n",
+			newBody: "# 計画\n\n- 手順 1: a+b=c 100%\n- 手順 2 ✅\n",
+		},
+		{
+			name:    "no change",
+			oldBody: "same",
+			newBody: "same",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Apply(tt.oldBody, Make(tt.oldBody, tt.newBody))
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if result != tt.newBody {
+				t.Errorf("Apply(Make()) = %q, want %q", result, tt.newBody)
+			}
+		})
+	}
+}
+
+func TestApply_UTF16Offsets(t *testing.T) {
+	// A plan with 300 multibyte characters before the edit, so that offsets in
+	// bytes and in UTF-16 code units are far apart
+	prefix := strings.Repeat("計画の手順です。", 37) + "\n- "
+	body := prefix + "手順1\n"
+	want := body + "- 手順2\n"
+
+	// The patch the CLI's diff-match-patch writes for the edit: offsets in UTF-16
+	// code units, four characters of context, text URI-encoded
+	start := len([]rune(prefix)) + 1
+	patch := fmt.Sprintf("@@ -%d,4 +%d,10 @@\n %s\n+- %s\n", start, start, url.PathEscape("手順1\n"), url.PathEscape("手順2\n"))
+	if patch != Make(body, want) {
+		t.Errorf("Make() = %q, want %q", Make(body, want), patch)
+	}
+
+	got, err := Apply(body, patch)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("Apply() = %q, want %q", got, want)
+	}
+}
+
+func TestApply_Mismatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		patch string
+	}{
+		{
+			name:  "patch for a different body",
+			body:  "completely unrelated text that shares nothing",
+			patch: Make("# Plan\n\n- step 1\n", "# Plan\n\n- step 1 (done)\n"),
+		},
+		{
+			name:  "malformed patch",
+			body:  "# Plan",
+			patch: "not a patch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Apply(tt.body, tt.patch); !errors.Is(err, ErrPatchMismatch) {
+				t.Errorf("Apply() error = %v, want ErrPatchMismatch", err)
+			}
+		})
+	}
+}
+
+func TestReplay(t *testing.T) {
+	bodies := []string{
+		"# Plan\n\n- step 1\n",
+		"# Plan\n\n- step 1\n- step 2\n",
+		"# Plan (revised)\n\n- step 2\n",
+	}
+
+	events := []*domain.PlanDocumentEvent{
+		{EventType: domain.PlanDocumentEventTypeBodyChange, Patch: InitialPatch(bodies[0])},
+		{EventType: domain.PlanDocumentEventTypeStatusChange, Patch: "planning -> implementation"},
+		{EventType: domain.PlanDocumentEventTypeBodyChange, Patch: Make(bodies[0], bodies[1])},
+		{EventType: domain.PlanDocumentEventTypeBodyChange, Patch: Make(bodies[1], bodies[2])},
+	}
+
+	body, err := Replay(events)
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if body != bodies[2] {
+		t.Errorf("Replay() = %q, want %q", body, bodies[2])
+	}
+}
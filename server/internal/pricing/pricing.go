@@ -0,0 +1,94 @@
+// Package pricing estimates the cost of model token usage from a price table.
+//
+// The built-in table covers Claude models; it can be extended or overridden
+// with a JSON object keyed by model name prefix, e.g.
+//
+//	{"claude-sonnet-4": {"input": 3, "output": 15, "cache_read": 0.3, "cache_write": 3.75}}
+//
+// Prices are in USD per million tokens. A model matches the longest prefix
+// in the table, so "claude-opus-4-5-20251101" uses the "claude-opus-4-5" entry.
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+// Price is the USD price per million tokens
+type Price struct {
+	Input         float64 `json:"input"`
+	Output        float64 `json:"output"`
+	CacheRead     float64 `json:"cache_read"`
+	CacheCreation float64 `json:"cache_write"`
+}
+
+// defaultPrices are the published Claude API prices
+var defaultPrices = map[string]Price{
+	"claude-opus-4-5":   {Input: 5, Output: 25, CacheRead: 0.5, CacheCreation: 6.25},
+	"claude-opus-4":     {Input: 15, Output: 75, CacheRead: 1.5, CacheCreation: 18.75},
+	"claude-sonnet-4":   {Input: 3, Output: 15, CacheRead: 0.3, CacheCreation: 3.75},
+	"claude-3-7-sonnet": {Input: 3, Output: 15, CacheRead: 0.3, CacheCreation: 3.75},
+	"claude-3-5-sonnet": {Input: 3, Output: 15, CacheRead: 0.3, CacheCreation: 3.75},
+	"claude-haiku-4-5":  {Input: 1, Output: 5, CacheRead: 0.1, CacheCreation: 1.25},
+	"claude-3-5-haiku":  {Input: 0.8, Output: 4, CacheRead: 0.08, CacheCreation: 1},
+	"claude-3-opus":     {Input: 15, Output: 75, CacheRead: 1.5, CacheCreation: 18.75},
+	"claude-3-haiku":    {Input: 0.25, Output: 1.25, CacheRead: 0.03, CacheCreation: 0.3},
+}
+
+// Table maps model name prefixes to prices
+type Table struct {
+	prices map[string]Price
+}
+
+// New creates a Table from the built-in prices and optional JSON overrides
+func New(overridesJSON string) (*Table, error) {
+	prices := make(map[string]Price, len(defaultPrices))
+	for model, price := range defaultPrices {
+		prices[model] = price
+	}
+
+	if strings.TrimSpace(overridesJSON) != "" {
+		var overrides map[string]Price
+		if err := json.Unmarshal([]byte(overridesJSON), &overrides); err != nil {
+			return nil, fmt.Errorf("invalid model price table: %w", err)
+		}
+		for model, price := range overrides {
+			prices[model] = price
+		}
+	}
+
+	return &Table{prices: prices}, nil
+}
+
+// Lookup returns the price of the longest model prefix matching model
+func (t *Table) Lookup(model string) (Price, bool) {
+	if t == nil {
+		return Price{}, false
+	}
+	var best string
+	for prefix := range t.prices {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return Price{}, false
+	}
+	return t.prices[best], true
+}
+
+// Cost returns the estimated USD cost of usage for model.
+// Unknown models (and a nil Table) cost 0.
+func (t *Table) Cost(model string, usage domain.TokenUsage) float64 {
+	price, ok := t.Lookup(model)
+	if !ok {
+		return 0
+	}
+	return (float64(usage.InputTokens)*price.Input +
+		float64(usage.OutputTokens)*price.Output +
+		float64(usage.CacheReadTokens)*price.CacheRead +
+		float64(usage.CacheCreationTokens)*price.CacheCreation) / 1_000_000
+}
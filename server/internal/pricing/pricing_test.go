@@ -0,0 +1,76 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+func TestCost(t *testing.T) {
+	table, err := New(`{"my-model": {"input": 2, "output": 4}, "claude-sonnet-4": {"input": 1, "output": 1}}`)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	usage := domain.TokenUsage{
+		InputTokens:         1_000_000,
+		OutputTokens:        100_000,
+		CacheReadTokens:     2_000_000,
+		CacheCreationTokens: 400_000,
+	}
+
+	tests := []struct {
+		name     string
+		model    string
+		expected float64
+	}{
+		{
+			name:     "longest prefix wins",
+			model:    "claude-opus-4-5-20251101",
+			expected: 5 + 2.5 + 1 + 2.5,
+		},
+		{
+			name:     "shorter prefix",
+			model:    "claude-opus-4-1-20250805",
+			expected: 15 + 7.5 + 3 + 7.5,
+		},
+		{
+			name:     "override replaces built-in price",
+			model:    "claude-sonnet-4-5-20250929",
+			expected: 1 + 0.1,
+		},
+		{
+			name:     "custom model",
+			model:    "my-model-v2",
+			expected: 2 + 0.4,
+		},
+		{
+			name:     "unknown model",
+			model:    "<synthetic>",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost := table.Cost(tt.model, usage)
+			if math.Abs(cost-tt.expected) > 1e-9 {
+				t.Errorf("Cost(%q) = %v, want %v", tt.model, cost, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNew_InvalidJSON(t *testing.T) {
+	if _, err := New(`{"claude": 3}`); err == nil {
+		t.Error("New() error = nil, want error for invalid price table")
+	}
+}
+
+func TestCost_NilTable(t *testing.T) {
+	var table *Table
+	if cost := table.Cost("claude-opus-4-5", domain.TokenUsage{InputTokens: 1000}); cost != 0 {
+		t.Errorf("Cost() = %v, want 0", cost)
+	}
+}
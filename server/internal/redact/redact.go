@@ -0,0 +1,236 @@
+// Package redact removes secrets from ingested transcript payloads before
+// they are persisted.
+//
+// Each match is replaced with a placeholder of the form
+//
+//	[REDACTED:<detector>:<hash>]
+//
+// where <hash> is an HMAC of the secret keyed by a server secret, so the same
+// secret always yields the same placeholder and occurrences can still be
+// correlated across events without storing the value. Without a key the
+// placeholder is [REDACTED:<detector>], since an unkeyed hash would let
+// readers test guesses of low-entropy secrets offline.
+package redact
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// placeholderPrefix marks text that has already been redacted
+const placeholderPrefix = "[REDACTED:"
+
+// Detector finds one kind of secret.
+// If Pattern has a capture group, only the first group is replaced
+// (e.g. the value in KEY=value); otherwise the whole match is replaced.
+type Detector struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// builtinDetectors covers common credential formats
+var builtinDetectors = []Detector{
+	{Name: "private_key", Pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+	{Name: "aws_access_key_id", Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{Name: "aws_secret_access_key", Pattern: regexp.MustCompile(`(?i)aws_secret_access_key["']?\s*[=:]\s*["']?([A-Za-z0-9/+=]{40})`)},
+	{Name: "github_token", Pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`)},
+	{Name: "slack_token", Pattern: regexp.MustCompile(`\bxox[abeprs]-[A-Za-z0-9-]{10,}`)},
+	{Name: "anthropic_api_key", Pattern: regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_-]{20,}`)},
+	{Name: "openai_api_key", Pattern: regexp.MustCompile(`\bsk-(?:proj-)?[A-Za-z0-9_-]{20,}`)},
+	{Name: "stripe_key", Pattern: regexp.MustCompile(`\b[rs]k_(?:live|test)_[A-Za-z0-9]{16,}`)},
+	{Name: "google_api_key", Pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`)},
+	{Name: "agentrace_api_key", Pattern: regexp.MustCompile(`\bagtr_[A-Za-z0-9_-]{20,}`)},
+	{Name: "jwt", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)},
+	{Name: "url_password", Pattern: regexp.MustCompile(`\b[a-zA-Z][a-zA-Z0-9+.-]*://[^:/\s@]+:([^@\s/]+)@`)},
+	{Name: "env_secret", Pattern: regexp.MustCompile(`\b[A-Z0-9_]*(?:SECRET|TOKEN|PASSWORD|PASSWD|API_KEY|APIKEY|ACCESS_KEY|PRIVATE_KEY)[A-Z0-9_]*=["']?([^\s"'$]{8,})`)},
+}
+
+// Redactor replaces secrets in transcript payloads
+type Redactor struct {
+	detectors []Detector
+	key       []byte // HMAC key of placeholder hashes; nil leaves the hash out
+}
+
+// New creates a Redactor with the built-in detectors plus the given custom patterns.
+// Custom patterns follow the same capture group rule as Detector.
+// key keys the placeholder hashes; an empty key leaves them out.
+func New(key string, customPatterns []string) (*Redactor, error) {
+	detectors := make([]Detector, 0, len(builtinDetectors)+len(customPatterns))
+	detectors = append(detectors, builtinDetectors...)
+	for _, p := range customPatterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+		detectors = append(detectors, Detector{Name: "custom", Pattern: re})
+	}
+	r := &Redactor{detectors: detectors}
+	if key != "" {
+		r.key = []byte(key)
+	}
+	return r, nil
+}
+
+// RedactPayload replaces secrets in every string value of the payload in place
+// and returns the number of redactions made. A nil Redactor does nothing.
+func (r *Redactor) RedactPayload(payload map[string]interface{}) int {
+	if r == nil {
+		return 0
+	}
+	count := 0
+	for k, v := range payload {
+		payload[k] = r.redactValue(v, &count)
+	}
+	return count
+}
+
+// RedactString replaces secrets in s and returns the result with the number of redactions made
+func (r *Redactor) RedactString(s string) (string, int) {
+	if r == nil {
+		return s, 0
+	}
+	count := 0
+	for _, d := range r.detectors {
+		s = r.redactWith(d, s, &count)
+	}
+	return s, count
+}
+
+// RedactJSON replaces secrets in the string values of a JSON document and
+// returns the result with the number of redactions made. Only the redacted
+// string literals are re-encoded; keys and the rest of the document are kept
+// byte for byte, so that the original formatting and key order survive.
+func (r *Redactor) RedactJSON(raw []byte) ([]byte, int) {
+	if r == nil {
+		return raw, 0
+	}
+	var out []byte // nil until the first redaction
+	count := 0
+	last := 0
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '"' {
+			continue
+		}
+		end := stringLiteralEnd(raw, i)
+		if end < 0 {
+			break
+		}
+		if !isObjectKey(raw, end) {
+			var value string
+			if err := json.Unmarshal(raw[i:end], &value); err == nil {
+				if redacted, n := r.RedactString(value); n > 0 {
+					out = append(out, raw[last:i]...)
+					out = appendJSONString(out, redacted)
+					last = end
+					count += n
+				}
+			}
+		}
+		i = end - 1
+	}
+	if count == 0 {
+		return raw, 0
+	}
+	return append(out, raw[last:]...), count
+}
+
+// stringLiteralEnd returns the index just past the string literal starting at
+// raw[start] (a quote), or -1 if it is not terminated
+func stringLiteralEnd(raw []byte, start int) int {
+	for i := start + 1; i < len(raw); i++ {
+		switch raw[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// isObjectKey reports whether the string literal ending at raw[end] is followed by a colon
+func isObjectKey(raw []byte, end int) bool {
+	for i := end; i < len(raw); i++ {
+		switch raw[i] {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case ':':
+			return true
+		}
+		return false
+	}
+	return false
+}
+
+// appendJSONString appends s as a JSON string literal without HTML escaping, like JSON.stringify
+func appendJSONString(dst []byte, s string) []byte {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return append(dst, bytes.TrimSuffix(buf.Bytes(), []byte("\n"))...)
+}
+
+func (r *Redactor) redactValue(v interface{}, count *int) interface{} {
+	switch val := v.(type) {
+	case string:
+		s, n := r.RedactString(val)
+		*count += n
+		return s
+	case map[string]interface{}:
+		for k, child := range val {
+			val[k] = r.redactValue(child, count)
+		}
+		return val
+	case []interface{}:
+		for i, child := range val {
+			val[i] = r.redactValue(child, count)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+func (r *Redactor) redactWith(d Detector, s string, count *int) string {
+	matches := d.Pattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		// Replace only the first capture group when the pattern has one
+		if len(m) >= 4 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		secret := s[start:end]
+		if start < last || secret == "" || strings.HasPrefix(secret, placeholderPrefix) {
+			continue
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(r.placeholder(d.Name, secret))
+		last = end
+		*count++
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// placeholder returns a stable replacement for the secret
+func (r *Redactor) placeholder(name, secret string) string {
+	if r.key == nil {
+		return placeholderPrefix + name + "]"
+	}
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(secret))
+	return placeholderPrefix + name + ":" + hex.EncodeToString(mac.Sum(nil)[:4]) + "]"
+}
@@ -0,0 +1,238 @@
+package dynamodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+// AnalyticsRepository maintains precomputed daily counters, since DynamoDB
+// cannot aggregate the source tables. Each activity is added to the counter
+// items of every scope it can be filtered by.
+type AnalyticsRepository struct {
+	db *DB
+}
+
+func NewAnalyticsRepository(db *DB) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+type analyticsCounterItem struct {
+	Scope             string   `dynamodbav:"scope"`
+	Day               string   `dynamodbav:"day"`
+	SessionsStarted   int      `dynamodbav:"sessions_started,omitempty"`
+	EventsIngested    int      `dynamodbav:"events_ingested,omitempty"`
+	ToolInvocations   int      `dynamodbav:"tool_invocations,omitempty"`
+	PlanStatusChanges int      `dynamodbav:"plan_status_changes,omitempty"`
+	ActiveUsers       []string `dynamodbav:"active_users,stringset,omitempty"`
+}
+
+// analyticsScope returns the counter scope for a project/user filter (either may be empty)
+func analyticsScope(projectID, userID string) string {
+	switch {
+	case projectID != "" && userID != "":
+		return "project#" + projectID + "#user#" + userID
+	case projectID != "":
+		return "project#" + projectID
+	case userID != "":
+		return "user#" + userID
+	}
+	return "all"
+}
+
+func (r *AnalyticsRepository) RecordActivity(ctx context.Context, activity domain.AnalyticsActivity) error {
+	activeUser := activity.EventsIngested > 0 && activity.UserID != ""
+	if activity.AnalyticsCounts == (domain.AnalyticsCounts{}) && !activeUser {
+		return nil
+	}
+
+	update := expression.Add(expression.Name("sessions_started"), expression.Value(activity.SessionsStarted)).
+		Add(expression.Name("events_ingested"), expression.Value(activity.EventsIngested)).
+		Add(expression.Name("tool_invocations"), expression.Value(activity.ToolInvocations)).
+		Add(expression.Name("plan_status_changes"), expression.Value(activity.PlanStatusChanges))
+	if activeUser {
+		update = update.Add(expression.Name("active_users"), expression.Value(&types.AttributeValueMemberSS{Value: []string{activity.UserID}}))
+	}
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return err
+	}
+
+	scopes := []string{analyticsScope("", ""), analyticsScope(activity.ProjectID, "")}
+	if activity.UserID != "" {
+		scopes = append(scopes, analyticsScope("", activity.UserID), analyticsScope(activity.ProjectID, activity.UserID))
+	}
+
+	day := activity.Time.UTC().Format("2006-01-02")
+	for _, scope := range scopes {
+		_, err := r.db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName: aws.String(r.db.TableName("analytics_counters")),
+			Key: map[string]types.AttributeValue{
+				"scope": &types.AttributeValueMemberS{Value: scope},
+				"day":   &types.AttributeValueMemberS{Value: day},
+			},
+			UpdateExpression:          expr.Update(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *AnalyticsRepository) GetSeries(ctx context.Context, query domain.AnalyticsQuery) ([]*domain.AnalyticsBucket, error) {
+	series := domain.NewAnalyticsSeries(query)
+	from := series.From().Format("2006-01-02")
+	last := query.To.UTC().AddDate(0, 0, -1).Format("2006-01-02") // To is exclusive
+
+	if query.ProjectID != "" && query.Excludes(query.ProjectID) {
+		return series.Buckets(), nil
+	}
+
+	// Counters of excluded projects are subtracted from the unfiltered scopes.
+	// hiddenEvents counts the events each active user ingested into them per day.
+	hiddenEvents := make(map[string]map[string]int)
+	if query.ProjectID == "" {
+		for _, projectID := range query.ExcludeProjectIDs {
+			items, err := r.queryCounters(ctx, analyticsScope(projectID, query.UserID), from, last)
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				t, err := time.Parse("2006-01-02", item.Day)
+				if err != nil {
+					continue
+				}
+				counts := item.counts()
+				series.Add(t, domain.AnalyticsCounts{
+					SessionsStarted:   -counts.SessionsStarted,
+					EventsIngested:    -counts.EventsIngested,
+					ToolInvocations:   -counts.ToolInvocations,
+					PlanStatusChanges: -counts.PlanStatusChanges,
+				})
+				for _, userID := range item.ActiveUsers {
+					events := item.EventsIngested
+					if query.UserID == "" {
+						userItem, err := r.getCounter(ctx, analyticsScope(projectID, userID), item.Day)
+						if err != nil {
+							return nil, err
+						}
+						events = userItem.EventsIngested
+					}
+					if hiddenEvents[item.Day] == nil {
+						hiddenEvents[item.Day] = make(map[string]int)
+					}
+					hiddenEvents[item.Day][userID] += events
+				}
+			}
+		}
+	}
+
+	items, err := r.queryCounters(ctx, analyticsScope(query.ProjectID, query.UserID), from, last)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		t, err := time.Parse("2006-01-02", item.Day)
+		if err != nil {
+			continue
+		}
+		series.Add(t, item.counts())
+		for _, userID := range item.ActiveUsers {
+			// A user active in an excluded project stays active only if
+			// they ingested more events than that on the day
+			if hidden := hiddenEvents[item.Day][userID]; hidden > 0 {
+				events := item.EventsIngested
+				if query.UserID == "" {
+					userItem, err := r.getCounter(ctx, analyticsScope("", userID), item.Day)
+					if err != nil {
+						return nil, err
+					}
+					events = userItem.EventsIngested
+				}
+				if events <= hidden {
+					continue
+				}
+			}
+			series.AddActiveUser(t, userID)
+		}
+	}
+
+	return series.Buckets(), nil
+}
+
+func (item *analyticsCounterItem) counts() domain.AnalyticsCounts {
+	return domain.AnalyticsCounts{
+		SessionsStarted:   item.SessionsStarted,
+		EventsIngested:    item.EventsIngested,
+		ToolInvocations:   item.ToolInvocations,
+		PlanStatusChanges: item.PlanStatusChanges,
+	}
+}
+
+// queryCounters returns the counter items of a scope for the days from..last (inclusive)
+func (r *AnalyticsRepository) queryCounters(ctx context.Context, scope, from, last string) ([]analyticsCounterItem, error) {
+	keyCond := expression.Key("scope").Equal(expression.Value(scope)).
+		And(expression.Key("day").Between(expression.Value(from), expression.Value(last)))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	var items []analyticsCounterItem
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := r.db.Client.Query(ctx, &dynamodb.QueryInput{
+			TableName:                 aws.String(r.db.TableName("analytics_counters")),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			ExclusiveStartKey:         startKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var page []analyticsCounterItem
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &page); err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		startKey = result.LastEvaluatedKey
+	}
+	return items, nil
+}
+
+// getCounter returns the counter item of a scope for a day (zero if there is none)
+func (r *AnalyticsRepository) getCounter(ctx context.Context, scope, day string) (*analyticsCounterItem, error) {
+	result, err := r.db.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.db.TableName("analytics_counters")),
+		Key: map[string]types.AttributeValue{
+			"scope": &types.AttributeValueMemberS{Value: scope},
+			"day":   &types.AttributeValueMemberS{Value: day},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var item analyticsCounterItem
+	if result.Item != nil {
+		if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+			return nil, err
+		}
+	}
+	return &item, nil
+}
@@ -24,8 +24,12 @@ func NewAPIKeyRepository(db *DB) *APIKeyRepository {
 type apiKeyItem struct {
 	ID         string  `dynamodbav:"id"`
 	UserID     string  `dynamodbav:"user_id"`
+	LookupID   string  `dynamodbav:"lookup_id,omitempty"` // Sparse GSI key (omitted for legacy keys)
 	KeyHash    string  `dynamodbav:"key_hash"`
+	KeyPrefix  string  `dynamodbav:"key_prefix"`
 	Name       string  `dynamodbav:"name"`
+	Scopes     string  `dynamodbav:"scopes,omitempty"`
+	ExpiresAt  *string `dynamodbav:"expires_at,omitempty"`
 	LastUsedAt *string `dynamodbav:"last_used_at,omitempty"`
 	CreatedAt  string  `dynamodbav:"created_at"`
 }
@@ -44,11 +48,21 @@ func (r *APIKeyRepository) Create(ctx context.Context, apiKey *domain.APIKey) er
 		lastUsedAt = &s
 	}
 
+	var expiresAt *string
+	if apiKey.ExpiresAt != nil {
+		s := apiKey.ExpiresAt.Format(time.RFC3339Nano)
+		expiresAt = &s
+	}
+
 	item := apiKeyItem{
 		ID:         apiKey.ID,
 		UserID:     apiKey.UserID,
+		LookupID:   apiKey.LookupID,
 		KeyHash:    apiKey.KeyHash,
+		KeyPrefix:  apiKey.KeyPrefix,
 		Name:       apiKey.Name,
+		Scopes:     domain.FormatAPIKeyScopes(apiKey.Scopes),
+		ExpiresAt:  expiresAt,
 		LastUsedAt: lastUsedAt,
 		CreatedAt:  apiKey.CreatedAt.Format(time.RFC3339Nano),
 	}
@@ -118,6 +132,42 @@ func (r *APIKeyRepository) FindByUserID(ctx context.Context, userID string) ([]*
 	return apiKeys, nil
 }
 
+func (r *APIKeyRepository) FindLegacy(ctx context.Context) ([]*domain.APIKey, error) {
+	filterExpr := expression.AttributeNotExists(expression.Name("lookup_id"))
+	expr, err := expression.NewBuilder().WithFilter(filterExpr).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	var apiKeys []*domain.APIKey
+	var lastKey map[string]types.AttributeValue
+	for {
+		result, err := r.db.Client.Scan(ctx, &dynamodb.ScanInput{
+			TableName:                 aws.String(r.db.TableName("api_keys")),
+			FilterExpression:          expr.Filter(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			ExclusiveStartKey:         lastKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var items []apiKeyItem
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &items); err != nil {
+			return nil, err
+		}
+		for i := range items {
+			apiKeys = append(apiKeys, r.itemToAPIKey(&items[i]))
+		}
+
+		if result.LastEvaluatedKey == nil {
+			return apiKeys, nil
+		}
+		lastKey = result.LastEvaluatedKey
+	}
+}
+
 func (r *APIKeyRepository) FindByKeyHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
 	keyCond := expression.Key("key_hash").Equal(expression.Value(keyHash))
 	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
@@ -148,6 +198,40 @@ func (r *APIKeyRepository) FindByKeyHash(ctx context.Context, keyHash string) (*
 	return r.itemToAPIKey(&item), nil
 }
 
+func (r *APIKeyRepository) FindByLookupID(ctx context.Context, lookupID string) (*domain.APIKey, error) {
+	if lookupID == "" {
+		return nil, nil
+	}
+
+	keyCond := expression.Key("lookup_id").Equal(expression.Value(lookupID))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.db.Client.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.db.TableName("api_keys")),
+		IndexName:                 aws.String("lookup_id-index"),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		Limit:                     aws.Int32(1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Items) == 0 {
+		return nil, nil
+	}
+
+	var item apiKeyItem
+	if err := attributevalue.UnmarshalMap(result.Items[0], &item); err != nil {
+		return nil, err
+	}
+
+	return r.itemToAPIKey(&item), nil
+}
+
 func (r *APIKeyRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.db.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
 		TableName: aws.String(r.db.TableName("api_keys")),
@@ -177,9 +261,35 @@ func (r *APIKeyRepository) UpdateLastUsedAt(ctx context.Context, id string) erro
 	return err
 }
 
+func (r *APIKeyRepository) UpdateLookupID(ctx context.Context, id string, lookupID string, keyHash string) error {
+	update := expression.Set(expression.Name("lookup_id"), expression.Value(lookupID)).
+		Set(expression.Name("key_hash"), expression.Value(keyHash))
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.db.TableName("api_keys")),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	return err
+}
+
 func (r *APIKeyRepository) itemToAPIKey(item *apiKeyItem) *domain.APIKey {
 	createdAt, _ := time.Parse(time.RFC3339Nano, item.CreatedAt)
 
+	var expiresAt *time.Time
+	if item.ExpiresAt != nil {
+		t, _ := time.Parse(time.RFC3339Nano, *item.ExpiresAt)
+		expiresAt = &t
+	}
+
 	var lastUsedAt *time.Time
 	if item.LastUsedAt != nil {
 		t, _ := time.Parse(time.RFC3339Nano, *item.LastUsedAt)
@@ -189,8 +299,12 @@ func (r *APIKeyRepository) itemToAPIKey(item *apiKeyItem) *domain.APIKey {
 	return &domain.APIKey{
 		ID:         item.ID,
 		UserID:     item.UserID,
+		LookupID:   item.LookupID,
 		KeyHash:    item.KeyHash,
+		KeyPrefix:  item.KeyPrefix,
 		Name:       item.Name,
+		Scopes:     domain.ParseAPIKeyScopes(item.Scopes),
+		ExpiresAt:  expiresAt,
 		LastUsedAt: lastUsedAt,
 		CreatedAt:  createdAt,
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
@@ -127,12 +128,128 @@ func (db *DB) TableName(name string) string {
 	return db.TablePrefix + name
 }
 
+// batchWriteSize is the most requests BatchWriteItem accepts per call
+const batchWriteSize = 25
+
+// maxBatchWriteAttempts bounds the calls made to write one batch when items are left unprocessed
+const maxBatchWriteAttempts = 8
+
+// BatchWrite sends the write requests to the table in batches, resending the
+// items DynamoDB leaves unprocessed (throttling) with a growing delay
+func (db *DB) BatchWrite(ctx context.Context, table string, requests []types.WriteRequest) error {
+	tableName := db.TableName(table)
+	for start := 0; start < len(requests); start += batchWriteSize {
+		pending := map[string][]types.WriteRequest{
+			tableName: requests[start:min(start+batchWriteSize, len(requests))],
+		}
+		for attempt := 1; len(pending) > 0; attempt++ {
+			if attempt > maxBatchWriteAttempts {
+				return fmt.Errorf("batch write to %s: %d items left unprocessed", tableName, len(pending[tableName]))
+			}
+			if attempt > 1 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(time.Duration(attempt*attempt) * 25 * time.Millisecond):
+				}
+			}
+
+			result, err := db.Client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+				RequestItems: pending,
+			})
+			if err != nil {
+				return err
+			}
+			pending = result.UnprocessedItems
+		}
+	}
+	return nil
+}
+
+// DeleteSessionItems deletes every item of the session from a table whose
+// partition key is session_id and whose sort key is rangeKey
+func (db *DB) DeleteSessionItems(ctx context.Context, table, rangeKey, sessionID string) error {
+	keyCond := expression.Key("session_id").Equal(expression.Value(sessionID))
+	projection := expression.NamesList(expression.Name("session_id"), expression.Name(rangeKey))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).WithProjection(projection).Build()
+	if err != nil {
+		return err
+	}
+
+	var requests []types.WriteRequest
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := db.Client.Query(ctx, &dynamodb.QueryInput{
+			TableName:                 aws.String(db.TableName(table)),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ProjectionExpression:      expr.Projection(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			ExclusiveStartKey:         startKey,
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range result.Items {
+			requests = append(requests, types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: key}})
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		startKey = result.LastEvaluatedKey
+	}
+	return db.BatchWrite(ctx, table, requests)
+}
+
+// batchGetSize is the most keys BatchGetItem accepts per call
+const batchGetSize = 100
+
+// maxBatchGetAttempts bounds the calls made to read one batch when keys are left unprocessed
+const maxBatchGetAttempts = 8
+
+// BatchGet reads the items with the given keys from the table in batches,
+// requesting the keys DynamoDB leaves unprocessed again with a growing delay.
+// Keys without an item are left out of the result.
+func (db *DB) BatchGet(ctx context.Context, table string, keys []map[string]types.AttributeValue) ([]map[string]types.AttributeValue, error) {
+	tableName := db.TableName(table)
+	var items []map[string]types.AttributeValue
+	for start := 0; start < len(keys); start += batchGetSize {
+		pending := map[string]types.KeysAndAttributes{
+			tableName: {Keys: keys[start:min(start+batchGetSize, len(keys))]},
+		}
+		for attempt := 1; len(pending) > 0; attempt++ {
+			if attempt > maxBatchGetAttempts {
+				return nil, fmt.Errorf("batch get from %s: %d keys left unprocessed", tableName, len(pending[tableName].Keys))
+			}
+			if attempt > 1 {
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(time.Duration(attempt*attempt) * 25 * time.Millisecond):
+				}
+			}
+
+			result, err := db.Client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+				RequestItems: pending,
+			})
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, result.Responses[tableName]...)
+			pending = result.UnprocessedKeys
+		}
+	}
+	return items, nil
+}
+
 // ensureTables creates all required tables if they don't exist
 func (db *DB) ensureTables(ctx context.Context) error {
 	tables := []tableDefinition{
 		db.projectsTable(),
 		db.sessionsTable(),
 		db.eventsTable(),
+		db.eventUUIDsTable(),
 		db.usersTable(),
 		db.apiKeysTable(),
 		db.webSessionsTable(),
@@ -141,6 +258,11 @@ func (db *DB) ensureTables(ctx context.Context) error {
 		db.planDocumentsTable(),
 		db.planDocumentEventsTable(),
 		db.userFavoritesTable(),
+		db.analyticsCountersTable(),
+		db.fileActivitiesTable(),
+		db.toolCallsTable(),
+		db.teamsTable(),
+		db.teamMembershipsTable(),
 	}
 
 	for _, table := range tables {
@@ -344,6 +466,22 @@ func (db *DB) eventsTable() tableDefinition {
 	}
 }
 
+// eventUUIDsTable has one item per stored transcript line uuid of a session, so
+// that duplicate lines can be found by key (the events table is keyed by time)
+func (db *DB) eventUUIDsTable() tableDefinition {
+	return tableDefinition{
+		name: "event_uuids",
+		keySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("session_id"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("uuid"), KeyType: types.KeyTypeRange},
+		},
+		attributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("session_id"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("uuid"), AttributeType: types.ScalarAttributeTypeS},
+		},
+	}
+}
+
 func (db *DB) usersTable() tableDefinition {
 	return tableDefinition{
 		name: "users",
@@ -582,6 +720,107 @@ func (db *DB) userFavoritesTable() tableDefinition {
 	}
 }
 
+func (db *DB) analyticsCountersTable() tableDefinition {
+	return tableDefinition{
+		name: "analytics_counters",
+		keySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("scope"), KeyType: types.KeyTypeHash}, // all, project#<id>, user#<id>, project#<id>#user#<id>
+			{AttributeName: aws.String("day"), KeyType: types.KeyTypeRange},  // YYYY-MM-DD (UTC)
+		},
+		attributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("scope"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("day"), AttributeType: types.ScalarAttributeTypeS},
+		},
+	}
+}
+
+func (db *DB) fileActivitiesTable() tableDefinition {
+	return tableDefinition{
+		name: "file_activities",
+		keySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("session_id"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("sort_key"), KeyType: types.KeyTypeRange}, // created_at#id for chronological ordering
+		},
+		attributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("session_id"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("sort_key"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("project_path"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		globalSecondaryIndexes: []types.GlobalSecondaryIndex{
+			{
+				IndexName: aws.String("project_path-index"),
+				KeySchema: []types.KeySchemaElement{
+					{AttributeName: aws.String("project_path"), KeyType: types.KeyTypeHash}, // project_id#path
+					{AttributeName: aws.String("sort_key"), KeyType: types.KeyTypeRange},
+				},
+				Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+			},
+		},
+	}
+}
+
+func (db *DB) toolCallsTable() tableDefinition {
+	return tableDefinition{
+		name: "tool_calls",
+		keySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("session_id"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("tool_use_id"), KeyType: types.KeyTypeRange},
+		},
+		attributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("session_id"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("tool_use_id"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("project_id"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("started_at"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		globalSecondaryIndexes: []types.GlobalSecondaryIndex{
+			{
+				IndexName: aws.String("project_id-index"),
+				KeySchema: []types.KeySchemaElement{
+					{AttributeName: aws.String("project_id"), KeyType: types.KeyTypeHash},
+					{AttributeName: aws.String("started_at"), KeyType: types.KeyTypeRange},
+				},
+				Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+			},
+		},
+	}
+}
+
+func (db *DB) teamsTable() tableDefinition {
+	return tableDefinition{
+		name: "teams",
+		keySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: types.KeyTypeHash},
+		},
+		attributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("id"), AttributeType: types.ScalarAttributeTypeS},
+		},
+	}
+}
+
+func (db *DB) teamMembershipsTable() tableDefinition {
+	return tableDefinition{
+		name: "team_memberships",
+		keySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("team_id"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("user_id"), KeyType: types.KeyTypeRange},
+		},
+		attributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("team_id"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("user_id"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		globalSecondaryIndexes: []types.GlobalSecondaryIndex{
+			{
+				IndexName: aws.String("user_id-index"),
+				KeySchema: []types.KeySchemaElement{
+					{AttributeName: aws.String("user_id"), KeyType: types.KeyTypeHash},
+					{AttributeName: aws.String("team_id"), KeyType: types.KeyTypeRange},
+				},
+				Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+			},
+		},
+	}
+}
+
 // WaitForGSIActive waits for a GSI to become ACTIVE.
 // This is exported for use in migrations when adding new GSIs.
 func (db *DB) WaitForGSIActive(ctx context.Context, tableName, indexName string) error {
@@ -3,6 +3,7 @@ package dynamodb
 import (
 	"context"
 	"encoding/json"
+	"sort"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -24,13 +25,22 @@ func NewEventRepository(db *DB) *EventRepository {
 }
 
 type eventItem struct {
+	SessionID   string `dynamodbav:"session_id"`
+	SortKey     string `dynamodbav:"sort_key"` // created_at#id for chronological ordering
+	ID          string `dynamodbav:"id"`
+	EventType   string `dynamodbav:"event_type"`
+	Payload     string `dynamodbav:"payload,omitempty"` // JSON string; omitted when raw is set, since items are limited to 400 KB
+	Raw         string `dynamodbav:"raw,omitempty"`     // the transcript line as received, after redaction
+	UUID        string `dynamodbav:"uuid,omitempty"`    // omitted when empty: it is the uuid-index key
+	ParentUUID  string `dynamodbav:"parent_uuid,omitempty"`
+	IsSidechain bool   `dynamodbav:"is_sidechain,omitempty"`
+	CreatedAt   string `dynamodbav:"created_at"`
+}
+
+// eventUUIDItem marks a transcript line uuid as stored in a session
+type eventUUIDItem struct {
 	SessionID string `dynamodbav:"session_id"`
-	SortKey   string `dynamodbav:"sort_key"` // created_at#id for chronological ordering
-	ID        string `dynamodbav:"id"`
-	EventType string `dynamodbav:"event_type"`
-	Payload   string `dynamodbav:"payload"` // JSON string
 	UUID      string `dynamodbav:"uuid"`
-	CreatedAt string `dynamodbav:"created_at"`
 }
 
 func (r *EventRepository) Create(ctx context.Context, event *domain.Event) error {
@@ -43,45 +53,161 @@ func (r *EventRepository) Create(ctx context.Context, event *domain.Event) error
 
 	// Check for duplicate UUID within session
 	if event.UUID != "" {
-		existing, err := r.findByUUID(ctx, event.SessionID, event.UUID)
+		stored, err := r.storedUUIDs(ctx, []eventUUIDItem{{SessionID: event.SessionID, UUID: event.UUID}})
 		if err != nil {
 			return err
 		}
-		if existing != nil {
+		if stored[eventUUIDItem{SessionID: event.SessionID, UUID: event.UUID}] {
 			return repository.ErrDuplicateEvent
 		}
 	}
 
-	payloadJSON, err := json.Marshal(event.Payload)
+	av, err := r.eventToItem(event)
 	if err != nil {
 		return err
 	}
 
-	createdAtStr := event.CreatedAt.Format(time.RFC3339Nano)
-	sortKey := createdAtStr + "#" + event.ID // Chronological ordering with ID as tiebreaker
-
-	item := eventItem{
-		SessionID: event.SessionID,
-		SortKey:   sortKey,
-		ID:        event.ID,
-		EventType: event.EventType,
-		Payload:   string(payloadJSON),
-		UUID:      event.UUID,
-		CreatedAt: createdAtStr,
+	_, err = r.db.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.db.TableName("events")),
+		Item:      av,
+	})
+	if err != nil || event.UUID == "" {
+		return err
 	}
 
-	av, err := attributevalue.MarshalMap(item)
+	marker, err := attributevalue.MarshalMap(eventUUIDItem{SessionID: event.SessionID, UUID: event.UUID})
 	if err != nil {
 		return err
 	}
-
 	_, err = r.db.Client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(r.db.TableName("events")),
-		Item:      av,
+		TableName: aws.String(r.db.TableName("event_uuids")),
+		Item:      marker,
 	})
 	return err
 }
 
+// CreateBatch writes the events with BatchWriteItem. It takes no conditions, so
+// the uuids already stored are read first from event_uuids with BatchGetItem.
+// Events are written before their uuids, so that a failed batch can be sent again.
+func (r *EventRepository) CreateBatch(ctx context.Context, events []*domain.Event) (*repository.EventBatchResult, error) {
+	result := &repository.EventBatchResult{Created: make([]*domain.Event, 0, len(events))}
+	seen := make(map[eventUUIDItem]bool) // uuids of the events in the batch
+	candidates := make([]*domain.Event, 0, len(events))
+	var keys []eventUUIDItem
+	for _, event := range events {
+		if event.ID == "" {
+			event.ID = uuid.New().String()
+		}
+		if event.CreatedAt.IsZero() {
+			event.CreatedAt = time.Now()
+		}
+
+		if event.UUID != "" {
+			key := eventUUIDItem{SessionID: event.SessionID, UUID: event.UUID}
+			if seen[key] {
+				result.Duplicates++
+				continue
+			}
+			seen[key] = true
+			keys = append(keys, key)
+		}
+		candidates = append(candidates, event)
+	}
+
+	stored, err := r.storedUUIDs(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]types.WriteRequest, 0, len(candidates))
+	markers := make([]types.WriteRequest, 0, len(keys))
+	for _, event := range candidates {
+		if event.UUID != "" {
+			key := eventUUIDItem{SessionID: event.SessionID, UUID: event.UUID}
+			if stored[key] {
+				result.Duplicates++
+				continue
+			}
+			marker, err := attributevalue.MarshalMap(key)
+			if err != nil {
+				return nil, err
+			}
+			markers = append(markers, types.WriteRequest{PutRequest: &types.PutRequest{Item: marker}})
+		}
+
+		av, err := r.eventToItem(event)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: av}})
+		result.Created = append(result.Created, event)
+	}
+
+	if err := r.db.BatchWrite(ctx, "events", requests); err != nil {
+		return nil, err
+	}
+	if err := r.db.BatchWrite(ctx, "event_uuids", markers); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// storedUUIDs returns which of the keys are uuids of stored events
+func (r *EventRepository) storedUUIDs(ctx context.Context, keys []eventUUIDItem) (map[eventUUIDItem]bool, error) {
+	avKeys := make([]map[string]types.AttributeValue, len(keys))
+	for i, key := range keys {
+		av, err := attributevalue.MarshalMap(key)
+		if err != nil {
+			return nil, err
+		}
+		avKeys[i] = av
+	}
+
+	found, err := r.db.BatchGet(ctx, "event_uuids", avKeys)
+	if err != nil {
+		return nil, err
+	}
+	var items []eventUUIDItem
+	if err := attributevalue.UnmarshalListOfMaps(found, &items); err != nil {
+		return nil, err
+	}
+
+	stored := make(map[eventUUIDItem]bool, len(items))
+	for _, item := range items {
+		stored[item] = true
+	}
+	return stored, nil
+}
+
+// eventToItem marshals an event into an events table item
+func (r *EventRepository) eventToItem(event *domain.Event) (map[string]types.AttributeValue, error) {
+	var payloadJSON []byte
+	if event.Raw == nil {
+		var err error
+		payloadJSON, err = json.Marshal(event.Payload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	createdAtStr := event.CreatedAt.Format(time.RFC3339Nano)
+	sortKey := createdAtStr + "#" + event.ID // Chronological ordering with ID as tiebreaker
+
+	item := eventItem{
+		SessionID:   event.SessionID,
+		SortKey:     sortKey,
+		ID:          event.ID,
+		EventType:   event.EventType,
+		Payload:     string(payloadJSON),
+		Raw:         string(event.Raw),
+		UUID:        event.UUID,
+		ParentUUID:  event.ParentUUID,
+		IsSidechain: event.IsSidechain,
+		CreatedAt:   createdAtStr,
+	}
+	return attributevalue.MarshalMap(item)
+}
+
 func (r *EventRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*domain.Event, error) {
 	keyCond := expression.Key("session_id").Equal(expression.Value(sessionID))
 	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
@@ -110,6 +236,9 @@ func (r *EventRepository) FindBySessionID(ctx context.Context, sessionID string)
 		events[i] = r.itemToEvent(&item)
 	}
 
+	// Sort keys do not sort chronologically across timestamp precisions and zones
+	domain.SortEventsByTime(events)
+
 	return events, nil
 }
 
@@ -134,52 +263,138 @@ func (r *EventRepository) CountBySessionID(ctx context.Context, sessionID string
 	return int(result.Count), nil
 }
 
-func (r *EventRepository) findByUUID(ctx context.Context, sessionID, eventUUID string) (*domain.Event, error) {
-	keyCond := expression.Key("session_id").Equal(expression.Value(sessionID))
-	filterExpr := expression.Name("uuid").Equal(expression.Value(eventUUID))
-	expr, err := expression.NewBuilder().
-		WithKeyCondition(keyCond).
-		WithFilter(filterExpr).
-		Build()
+// DeleteBySessionID deletes the session's events, then their uuid markers
+func (r *EventRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	if err := r.db.DeleteSessionItems(ctx, "events", "sort_key", sessionID); err != nil {
+		return err
+	}
+	return r.db.DeleteSessionItems(ctx, "event_uuids", "uuid", sessionID)
+}
+
+func (r *EventRepository) FindSessionIDsByUUID(ctx context.Context, eventUUID string) ([]string, error) {
+	keyCond := expression.Key("uuid").Equal(expression.Value(eventUUID))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
 	if err != nil {
 		return nil, err
 	}
 
 	result, err := r.db.Client.Query(ctx, &dynamodb.QueryInput{
 		TableName:                 aws.String(r.db.TableName("events")),
+		IndexName:                 aws.String("uuid-index"),
 		KeyConditionExpression:    expr.KeyCondition(),
-		FilterExpression:          expr.Filter(),
 		ExpressionAttributeNames:  expr.Names(),
 		ExpressionAttributeValues: expr.Values(),
-		Limit:                     aws.Int32(1),
 	})
 	if err != nil {
 		return nil, err
 	}
-	if len(result.Items) == 0 {
-		return nil, nil
-	}
 
-	var item eventItem
-	if err := attributevalue.UnmarshalMap(result.Items[0], &item); err != nil {
+	var items []eventItem
+	if err := attributevalue.UnmarshalListOfMaps(result.Items, &items); err != nil {
 		return nil, err
 	}
 
-	return r.itemToEvent(&item), nil
+	seen := make(map[string]bool)
+	var sessionIDs []string
+	for _, item := range items {
+		if !seen[item.SessionID] {
+			seen[item.SessionID] = true
+			sessionIDs = append(sessionIDs, item.SessionID)
+		}
+	}
+	return sessionIDs, nil
+}
+
+// Search scans the events table and matches terms in memory.
+// DynamoDB has no full-text index, so this reads every event; it stops once limit matches are found.
+// The session of each matching event is looked up once to check that the viewer can read it.
+func (r *EventRepository) Search(ctx context.Context, query string, viewer domain.SessionViewer, limit int) ([]*domain.EventSearchMatch, error) {
+	terms := domain.ParseSearchTerms(query)
+	if len(terms) == 0 {
+		return []*domain.EventSearchMatch{}, nil
+	}
+
+	sessions := NewSessionRepository(r.db)
+	visible := make(map[string]bool) // session ID -> readable by the viewer
+	matches := make([]*domain.EventSearchMatch, 0)
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := r.db.Client.Scan(ctx, &dynamodb.ScanInput{
+			TableName:         aws.String(r.db.TableName("events")),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var items []eventItem
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &items); err != nil {
+			return nil, err
+		}
+
+		for i := range items {
+			event := r.itemToEvent(&items[i])
+			text := event.SearchText()
+			if !domain.MatchesSearchTerms(text, terms) {
+				continue
+			}
+			canView, ok := visible[event.SessionID]
+			if !ok {
+				session, err := sessions.FindByID(ctx, event.SessionID)
+				if err != nil {
+					return nil, err
+				}
+				canView = session != nil && viewer.CanView(session)
+				visible[event.SessionID] = canView
+			}
+			if canView {
+				matches = append(matches, &domain.EventSearchMatch{
+					EventID:   event.ID,
+					SessionID: event.SessionID,
+					Text:      text,
+					CreatedAt: event.CreatedAt,
+				})
+			}
+		}
+
+		if len(result.LastEvaluatedKey) == 0 || (limit > 0 && len(matches) >= limit) {
+			break
+		}
+		startKey = result.LastEvaluatedKey
+	}
+
+	// Most recent first
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].CreatedAt.After(matches[j].CreatedAt)
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
 }
 
 func (r *EventRepository) itemToEvent(item *eventItem) *domain.Event {
 	createdAt, _ := time.Parse(time.RFC3339Nano, item.CreatedAt)
 
+	var raw []byte
+	payloadJSON := item.Payload
+	if item.Raw != "" {
+		raw = []byte(item.Raw)
+		payloadJSON = item.Raw
+	}
 	var payload map[string]interface{}
-	json.Unmarshal([]byte(item.Payload), &payload)
+	json.Unmarshal([]byte(payloadJSON), &payload)
 
 	return &domain.Event{
-		ID:        item.ID,
-		SessionID: item.SessionID,
-		EventType: item.EventType,
-		Payload:   payload,
-		UUID:      item.UUID,
-		CreatedAt: createdAt,
+		ID:          item.ID,
+		SessionID:   item.SessionID,
+		EventType:   item.EventType,
+		Payload:     payload,
+		Raw:         raw,
+		UUID:        item.UUID,
+		ParentUUID:  item.ParentUUID,
+		IsSidechain: item.IsSidechain,
+		CreatedAt:   createdAt,
 	}
 }
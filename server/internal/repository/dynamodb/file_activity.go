@@ -0,0 +1,200 @@
+package dynamodb
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+type FileActivityRepository struct {
+	db *DB
+}
+
+func NewFileActivityRepository(db *DB) *FileActivityRepository {
+	return &FileActivityRepository{db: db}
+}
+
+type fileActivityItem struct {
+	SessionID   string `dynamodbav:"session_id"`
+	SortKey     string `dynamodbav:"sort_key"` // created_at#id for chronological ordering
+	ID          string `dynamodbav:"id"`
+	ProjectID   string `dynamodbav:"project_id"`
+	ProjectPath string `dynamodbav:"project_path"` // project_id#path for project_path-index
+	EventID     string `dynamodbav:"event_id"`
+	Path        string `dynamodbav:"path"`
+	Operation   string `dynamodbav:"operation"`
+	CreatedAt   string `dynamodbav:"created_at"`
+}
+
+func fileActivityProjectPath(projectID, path string) string {
+	return projectID + "#" + path
+}
+
+func (r *FileActivityRepository) Create(ctx context.Context, activity *domain.FileActivity) error {
+	if activity.ID == "" {
+		activity.ID = uuid.New().String()
+	}
+	if activity.CreatedAt.IsZero() {
+		activity.CreatedAt = time.Now()
+	}
+
+	createdAtStr := activity.CreatedAt.Format(time.RFC3339Nano)
+	item := fileActivityItem{
+		SessionID:   activity.SessionID,
+		SortKey:     createdAtStr + "#" + activity.ID,
+		ID:          activity.ID,
+		ProjectID:   activity.ProjectID,
+		ProjectPath: fileActivityProjectPath(activity.ProjectID, activity.Path),
+		EventID:     activity.EventID,
+		Path:        activity.Path,
+		Operation:   string(activity.Operation),
+		CreatedAt:   createdAtStr,
+	}
+
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.db.TableName("file_activities")),
+		Item:      av,
+	})
+	return err
+}
+
+func (r *FileActivityRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*domain.FileActivity, error) {
+	items, err := r.findItemsBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	activities := make([]*domain.FileActivity, len(items))
+	for i := range items {
+		activities[i] = r.itemToFileActivity(&items[i])
+	}
+
+	// Sort keys do not sort chronologically across timestamp precisions and zones
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].CreatedAt.Before(activities[j].CreatedAt)
+	})
+
+	return activities, nil
+}
+
+func (r *FileActivityRepository) FindByProjectIDAndPath(ctx context.Context, projectID, path string) ([]*domain.FileActivity, error) {
+	keyCond := expression.Key("project_path").Equal(expression.Value(fileActivityProjectPath(projectID, path)))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.db.Client.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.db.TableName("file_activities")),
+		IndexName:                 aws.String("project_path-index"),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(false), // Newest first
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var items []fileActivityItem
+	if err := attributevalue.UnmarshalListOfMaps(result.Items, &items); err != nil {
+		return nil, err
+	}
+
+	activities := make([]*domain.FileActivity, len(items))
+	for i := range items {
+		activities[i] = r.itemToFileActivity(&items[i])
+	}
+
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].CreatedAt.After(activities[j].CreatedAt)
+	})
+
+	return activities, nil
+}
+
+func (r *FileActivityRepository) UpdateProjectIDBySessionID(ctx context.Context, sessionID string, projectID string) error {
+	items, err := r.findItemsBySessionID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		update := expression.Set(expression.Name("project_id"), expression.Value(projectID)).
+			Set(expression.Name("project_path"), expression.Value(fileActivityProjectPath(projectID, item.Path)))
+		expr, err := expression.NewBuilder().WithUpdate(update).Build()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName: aws.String(r.db.TableName("file_activities")),
+			Key: map[string]types.AttributeValue{
+				"session_id": &types.AttributeValueMemberS{Value: item.SessionID},
+				"sort_key":   &types.AttributeValueMemberS{Value: item.SortKey},
+			},
+			UpdateExpression:          expr.Update(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *FileActivityRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	return r.db.DeleteSessionItems(ctx, "file_activities", "sort_key", sessionID)
+}
+
+func (r *FileActivityRepository) findItemsBySessionID(ctx context.Context, sessionID string) ([]fileActivityItem, error) {
+	keyCond := expression.Key("session_id").Equal(expression.Value(sessionID))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.db.Client.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.db.TableName("file_activities")),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(true), // Ascending order (chronological)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var items []fileActivityItem
+	if err := attributevalue.UnmarshalListOfMaps(result.Items, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *FileActivityRepository) itemToFileActivity(item *fileActivityItem) *domain.FileActivity {
+	createdAt, _ := time.Parse(time.RFC3339Nano, item.CreatedAt)
+	return &domain.FileActivity{
+		ID:        item.ID,
+		SessionID: item.SessionID,
+		ProjectID: item.ProjectID,
+		EventID:   item.EventID,
+		Path:      item.Path,
+		Operation: domain.FileOperation(item.Operation),
+		CreatedAt: createdAt,
+	}
+}
@@ -162,12 +162,25 @@ func (r *MigrationRunner) recordVersion(ctx context.Context, version string) err
 // Add new migrations here as they are created.
 func registeredMigrations() []Migration {
 	return []Migration{
-		// Migrations will be added here as they are created
-		// Example:
-		// {
-		//     Version:     "0.0.1",
-		//     Description: "Add parent_session_id GSI for subagent support",
-		//     Up:          migration_0_0_1_AddSubagentSupport,
-		// },
+		{
+			Version:     "0.0.1",
+			Description: "Add lookup_id GSI to api_keys for indexed API key authentication",
+			Up:          migration_0_0_1_AddAPIKeyLookupID,
+		},
+		{
+			Version:     "0.0.2",
+			Description: "Add uuid GSI to events and parent_session_id GSI to sessions for conversation chains",
+			Up:          migration_0_0_2_AddChainIndexes,
+		},
+		{
+			Version:     "0.0.3",
+			Description: "Promote the first registered user to admin",
+			Up:          migration_0_0_3_PromoteFirstUserToAdmin,
+		},
+		{
+			Version:     "0.0.4",
+			Description: "Backfill event_uuids with the uuids of stored events for batched duplicate checks",
+			Up:          migration_0_0_4_BackfillEventUUIDs,
+		},
 	}
 }
@@ -0,0 +1,20 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/satetsu888/agentrace/server/migrations"
+)
+
+func TestRegisteredMigrationsMatchRegistry(t *testing.T) {
+	registered := registeredMigrations()
+	registry := migrations.DynamoDBMigrations()
+	if len(registered) != len(registry) {
+		t.Fatalf("registered %d migrations, registry lists %d", len(registered), len(registry))
+	}
+	for i, m := range registered {
+		if m.Version != registry[i].Version || m.Description != registry[i].Description {
+			t.Errorf("migration %d = %s %q, registry lists %s %q", i, m.Version, m.Description, registry[i].Version, registry[i].Description)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package dynamodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+// migration_0_0_1_AddAPIKeyLookupID adds a sparse GSI on api_keys.lookup_id so that
+// API key authentication is a single indexed read
+func migration_0_0_1_AddAPIKeyLookupID(ctx context.Context, db *DB) error {
+	return db.addGSIIfNotExists(ctx, "api_keys",
+		[]types.AttributeDefinition{
+			{AttributeName: aws.String("lookup_id"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		types.GlobalSecondaryIndex{
+			IndexName: aws.String("lookup_id-index"),
+			KeySchema: []types.KeySchemaElement{
+				{AttributeName: aws.String("lookup_id"), KeyType: types.KeyTypeHash},
+			},
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		},
+	)
+}
+
+// migration_0_0_2_AddChainIndexes adds the GSIs used to link resumed and forked
+// sessions: events by transcript line uuid and sessions by parent session
+func migration_0_0_2_AddChainIndexes(ctx context.Context, db *DB) error {
+	err := db.addGSIIfNotExists(ctx, "events",
+		[]types.AttributeDefinition{
+			{AttributeName: aws.String("uuid"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		types.GlobalSecondaryIndex{
+			IndexName: aws.String("uuid-index"),
+			KeySchema: []types.KeySchemaElement{
+				{AttributeName: aws.String("uuid"), KeyType: types.KeyTypeHash},
+			},
+			// Table keys (session_id) are always projected
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeKeysOnly},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return db.addGSIIfNotExists(ctx, "sessions",
+		[]types.AttributeDefinition{
+			{AttributeName: aws.String("parent_session_id"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		types.GlobalSecondaryIndex{
+			IndexName: aws.String("parent_session_id-index"),
+			KeySchema: []types.KeySchemaElement{
+				{AttributeName: aws.String("parent_session_id"), KeyType: types.KeyTypeHash},
+			},
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		},
+	)
+}
+
+// migration_0_0_3_PromoteFirstUserToAdmin makes the earliest registered user an
+// admin so that existing installations keep someone who can manage users
+func migration_0_0_3_PromoteFirstUserToAdmin(ctx context.Context, db *DB) error {
+	var first *userItem
+	var firstCreatedAt time.Time
+	var lastKey map[string]types.AttributeValue
+	for {
+		result, err := db.Client.Scan(ctx, &dynamodb.ScanInput{
+			TableName:         aws.String(db.TableName("users")),
+			ExclusiveStartKey: lastKey,
+		})
+		if err != nil {
+			return err
+		}
+
+		var items []userItem
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &items); err != nil {
+			return err
+		}
+		for i := range items {
+			item := items[i]
+			if item.Role == string(domain.UserRoleAdmin) {
+				// An admin already exists
+				return nil
+			}
+			createdAt, _ := time.Parse(time.RFC3339Nano, item.CreatedAt)
+			if first == nil || createdAt.Before(firstCreatedAt) ||
+				(createdAt.Equal(firstCreatedAt) && item.ID < first.ID) {
+				first = &item
+				firstCreatedAt = createdAt
+			}
+		}
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastKey = result.LastEvaluatedKey
+	}
+	if first == nil {
+		return nil
+	}
+
+	return NewUserRepository(db).UpdateRole(ctx, first.ID, domain.UserRoleAdmin)
+}
+
+// migration_0_0_4_BackfillEventUUIDs records the uuids of the stored events in
+// event_uuids, which CreateBatch reads to skip lines that were already ingested
+func migration_0_0_4_BackfillEventUUIDs(ctx context.Context, db *DB) error {
+	proj := expression.NamesList(expression.Name("session_id"), expression.Name("uuid"))
+	filter := expression.AttributeExists(expression.Name("uuid"))
+	expr, err := expression.NewBuilder().WithProjection(proj).WithFilter(filter).Build()
+	if err != nil {
+		return err
+	}
+
+	var lastKey map[string]types.AttributeValue
+	for {
+		result, err := db.Client.Scan(ctx, &dynamodb.ScanInput{
+			TableName:                 aws.String(db.TableName("events")),
+			ProjectionExpression:      expr.Projection(),
+			FilterExpression:          expr.Filter(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			ExclusiveStartKey:         lastKey,
+		})
+		if err != nil {
+			return err
+		}
+
+		requests := make([]types.WriteRequest, len(result.Items))
+		for i, item := range result.Items {
+			requests[i] = types.WriteRequest{PutRequest: &types.PutRequest{Item: item}}
+		}
+		if err := db.BatchWrite(ctx, "event_uuids", requests); err != nil {
+			return err
+		}
+
+		if result.LastEvaluatedKey == nil {
+			return nil
+		}
+		lastKey = result.LastEvaluatedKey
+	}
+}
+
+// addGSIIfNotExists adds a GSI to an existing table and waits for it to become ACTIVE.
+// It is a no-op if the index already exists.
+func (db *DB) addGSIIfNotExists(ctx context.Context, table string, attrs []types.AttributeDefinition, gsi types.GlobalSecondaryIndex) error {
+	tableName := db.TableName(table)
+
+	desc, err := db.Client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return err
+	}
+	for _, existing := range desc.Table.GlobalSecondaryIndexes {
+		if *existing.IndexName == *gsi.IndexName {
+			return nil
+		}
+	}
+
+	_, err = db.Client.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:            aws.String(tableName),
+		AttributeDefinitions: attrs,
+		GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{
+			{
+				Create: &types.CreateGlobalSecondaryIndexAction{
+					IndexName:  gsi.IndexName,
+					KeySchema:  gsi.KeySchema,
+					Projection: gsi.Projection,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return db.WaitForGSIActive(ctx, tableName, *gsi.IndexName)
+}
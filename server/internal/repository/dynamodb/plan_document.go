@@ -2,6 +2,7 @@ package dynamodb
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"time"
 
@@ -29,6 +30,7 @@ type planDocumentItem struct {
 	Description string `dynamodbav:"description"`
 	Body        string `dynamodbav:"body"`
 	Status      string `dynamodbav:"status"`
+	Revision    int    `dynamodbav:"revision,omitempty"` // missing on items created before revisions were introduced (treated as 1)
 	CreatedAt   string `dynamodbav:"created_at"`
 	UpdatedAt   string `dynamodbav:"updated_at"`
 	GSIPK       string `dynamodbav:"_gsi_pk"` // Fixed value for global queries
@@ -53,6 +55,7 @@ func (r *PlanDocumentRepository) Create(ctx context.Context, doc *domain.PlanDoc
 	if doc.ProjectID == "" {
 		doc.ProjectID = domain.DefaultProjectID
 	}
+	doc.Revision = 1
 
 	item := planDocumentItem{
 		ID:          doc.ID,
@@ -60,6 +63,7 @@ func (r *PlanDocumentRepository) Create(ctx context.Context, doc *domain.PlanDoc
 		Description: doc.Description,
 		Body:        doc.Body,
 		Status:      string(doc.Status),
+		Revision:    doc.Revision,
 		CreatedAt:   doc.CreatedAt.Format(time.RFC3339Nano),
 		UpdatedAt:   doc.UpdatedAt.Format(time.RFC3339Nano),
 		GSIPK:       planDocumentGSIPK,
@@ -146,6 +150,14 @@ func (r *PlanDocumentRepository) findByProjectID(ctx context.Context, query doma
 		filterConditions = append(filterConditions, expression.Name("id").In(idValues[0], idValues[1:]...))
 	}
 
+	if len(query.ExcludeProjectIDs) > 0 {
+		projectValues := make([]expression.OperandBuilder, len(query.ExcludeProjectIDs))
+		for i, id := range query.ExcludeProjectIDs {
+			projectValues[i] = expression.Value(id)
+		}
+		filterConditions = append(filterConditions, expression.Not(expression.Name("project_id").In(projectValues[0], projectValues[1:]...)))
+	}
+
 	if len(filterConditions) > 0 {
 		var combinedFilter expression.ConditionBuilder
 		combinedFilter = filterConditions[0]
@@ -227,6 +239,14 @@ func (r *PlanDocumentRepository) findAll(ctx context.Context, query domain.PlanD
 		filterConditions = append(filterConditions, expression.Name("id").In(idValues[0], idValues[1:]...))
 	}
 
+	if len(query.ExcludeProjectIDs) > 0 {
+		projectValues := make([]expression.OperandBuilder, len(query.ExcludeProjectIDs))
+		for i, id := range query.ExcludeProjectIDs {
+			projectValues[i] = expression.Value(id)
+		}
+		filterConditions = append(filterConditions, expression.Not(expression.Name("project_id").In(projectValues[0], projectValues[1:]...)))
+	}
+
 	if len(filterConditions) > 0 {
 		var combinedFilter expression.ConditionBuilder
 		combinedFilter = filterConditions[0]
@@ -304,14 +324,15 @@ func (r *PlanDocumentRepository) processResults(items []map[string]types.Attribu
 }
 
 func (r *PlanDocumentRepository) Update(ctx context.Context, doc *domain.PlanDocument) error {
-	doc.UpdatedAt = time.Now()
+	updatedAt := time.Now()
 
 	update := expression.Set(expression.Name("project_id"), expression.Value(doc.ProjectID)).
 		Set(expression.Name("description"), expression.Value(doc.Description)).
 		Set(expression.Name("body"), expression.Value(doc.Body)).
 		Set(expression.Name("status"), expression.Value(string(doc.Status))).
-		Set(expression.Name("updated_at"), expression.Value(doc.UpdatedAt.Format(time.RFC3339Nano)))
-	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+		Set(expression.Name("revision"), expression.Value(doc.Revision+1)).
+		Set(expression.Name("updated_at"), expression.Value(updatedAt.Format(time.RFC3339Nano)))
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(revisionCondition(doc.Revision)).Build()
 	if err != nil {
 		return err
 	}
@@ -322,10 +343,21 @@ func (r *PlanDocumentRepository) Update(ctx context.Context, doc *domain.PlanDoc
 			"id": &types.AttributeValueMemberS{Value: doc.ID},
 		},
 		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
 		ExpressionAttributeNames:  expr.Names(),
 		ExpressionAttributeValues: expr.Values(),
 	})
-	return err
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return repository.ErrRevisionConflict
+		}
+		return err
+	}
+
+	doc.Revision++
+	doc.UpdatedAt = updatedAt
+	return nil
 }
 
 func (r *PlanDocumentRepository) Delete(ctx context.Context, id string) error {
@@ -338,12 +370,13 @@ func (r *PlanDocumentRepository) Delete(ctx context.Context, id string) error {
 	return err
 }
 
-func (r *PlanDocumentRepository) SetStatus(ctx context.Context, id string, status domain.PlanDocumentStatus) error {
+func (r *PlanDocumentRepository) SetStatus(ctx context.Context, id string, status domain.PlanDocumentStatus, expectedRevision int) (int, error) {
 	update := expression.Set(expression.Name("status"), expression.Value(string(status))).
+		Set(expression.Name("revision"), expression.Value(expectedRevision+1)).
 		Set(expression.Name("updated_at"), expression.Value(time.Now().Format(time.RFC3339Nano)))
-	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(revisionCondition(expectedRevision)).Build()
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	_, err = r.db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
@@ -352,10 +385,28 @@ func (r *PlanDocumentRepository) SetStatus(ctx context.Context, id string, statu
 			"id": &types.AttributeValueMemberS{Value: id},
 		},
 		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
 		ExpressionAttributeNames:  expr.Names(),
 		ExpressionAttributeValues: expr.Values(),
 	})
-	return err
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return 0, repository.ErrRevisionConflict
+		}
+		return 0, err
+	}
+	return expectedRevision + 1, nil
+}
+
+// revisionCondition matches an existing document at the expected revision.
+// Documents without a revision attribute are at revision 1.
+func revisionCondition(expectedRevision int) expression.ConditionBuilder {
+	cond := expression.Name("revision").Equal(expression.Value(expectedRevision))
+	if expectedRevision == 1 {
+		cond = cond.Or(expression.AttributeNotExists(expression.Name("revision")))
+	}
+	return expression.AttributeExists(expression.Name("id")).And(cond)
 }
 
 func (r *PlanDocumentRepository) itemToPlanDocument(item *planDocumentItem) *domain.PlanDocument {
@@ -368,6 +419,7 @@ func (r *PlanDocumentRepository) itemToPlanDocument(item *planDocumentItem) *dom
 		Description: item.Description,
 		Body:        item.Body,
 		Status:      domain.PlanDocumentStatus(item.Status),
+		Revision:    max(item.Revision, 1),
 		CreatedAt:   createdAt,
 		UpdatedAt:   updatedAt,
 	}
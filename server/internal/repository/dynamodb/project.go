@@ -25,6 +25,7 @@ func NewProjectRepository(db *DB) *ProjectRepository {
 type projectItem struct {
 	ID                     string `dynamodbav:"id"`
 	CanonicalGitRepository string `dynamodbav:"canonical_git_repository,omitempty"`
+	TeamID                 string `dynamodbav:"team_id,omitempty"`
 	CreatedAt              string `dynamodbav:"created_at"`
 	GSIPK                  string `dynamodbav:"_gsi_pk"`
 }
@@ -43,6 +44,9 @@ func (r *ProjectRepository) Create(ctx context.Context, project *domain.Project)
 		CreatedAt:              project.CreatedAt.Format(time.RFC3339Nano),
 		GSIPK:                  projectGSIPK,
 	}
+	if project.TeamID != nil {
+		item.TeamID = *project.TeamID
+	}
 
 	av, err := attributevalue.MarshalMap(item)
 	if err != nil {
@@ -199,12 +203,76 @@ func (r *ProjectRepository) GetDefaultProject(ctx context.Context) (*domain.Proj
 	return r.FindByID(ctx, domain.DefaultProjectID)
 }
 
+func (r *ProjectRepository) FindTeamOwned(ctx context.Context) ([]*domain.Project, error) {
+	filterExpr := expression.AttributeExists(expression.Name("team_id"))
+	expr, err := expression.NewBuilder().WithFilter(filterExpr).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []*domain.Project
+	var lastKey map[string]types.AttributeValue
+	for {
+		result, err := r.db.Client.Scan(ctx, &dynamodb.ScanInput{
+			TableName:                 aws.String(r.db.TableName("projects")),
+			FilterExpression:          expr.Filter(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			ExclusiveStartKey:         lastKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var items []projectItem
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &items); err != nil {
+			return nil, err
+		}
+		for i := range items {
+			projects = append(projects, r.itemToProject(&items[i]))
+		}
+
+		if result.LastEvaluatedKey == nil {
+			return projects, nil
+		}
+		lastKey = result.LastEvaluatedKey
+	}
+}
+
+func (r *ProjectRepository) UpdateTeamID(ctx context.Context, id string, teamID *string) error {
+	var update expression.UpdateBuilder
+	if teamID != nil {
+		update = expression.Set(expression.Name("team_id"), expression.Value(*teamID))
+	} else {
+		update = expression.Remove(expression.Name("team_id"))
+	}
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.db.TableName("projects")),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	return err
+}
+
 func (r *ProjectRepository) itemToProject(item *projectItem) *domain.Project {
 	createdAt, _ := time.Parse(time.RFC3339Nano, item.CreatedAt)
-	return &domain.Project{
+	project := &domain.Project{
 		ID:                     item.ID,
 		CanonicalGitRepository: item.CanonicalGitRepository,
 		CreatedAt:              createdAt,
 	}
+	if item.TeamID != "" {
+		project.TeamID = &item.TeamID
+	}
+	return project
 }
 
@@ -18,5 +18,11 @@ func NewRepositories(db *DB) *repository.Repositories {
 		PlanDocument:       NewPlanDocumentRepository(db),
 		PlanDocumentEvent:  NewPlanDocumentEventRepository(db),
 		UserFavorite:       NewUserFavoriteRepository(db),
+		Team:               NewTeamRepository(db),
+		TeamMembership:     NewTeamMembershipRepository(db),
+		Analytics:          NewAnalyticsRepository(db),
+		FileActivity:       NewFileActivityRepository(db),
+		ToolCall:           NewToolCallRepository(db),
+		Tx:                 repository.NoTransaction{},
 	}
 }
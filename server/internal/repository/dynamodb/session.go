@@ -2,6 +2,7 @@ package dynamodb
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -23,18 +24,27 @@ func NewSessionRepository(db *DB) *SessionRepository {
 }
 
 type sessionItem struct {
-	ID              string  `dynamodbav:"id"`
-	UserID          *string `dynamodbav:"user_id,omitempty"`
-	ProjectID       string  `dynamodbav:"project_id"`
-	ClaudeSessionID string  `dynamodbav:"claude_session_id"`
-	ProjectPath     string  `dynamodbav:"project_path,omitempty"`
-	GitBranch       string  `dynamodbav:"git_branch,omitempty"`
-	Title           *string `dynamodbav:"title,omitempty"`
-	StartedAt       string  `dynamodbav:"started_at"`
-	EndedAt         *string `dynamodbav:"ended_at,omitempty"`
-	UpdatedAt       string  `dynamodbav:"updated_at"`
-	CreatedAt       string  `dynamodbav:"created_at"`
-	GSIPK           string  `dynamodbav:"_gsi_pk"` // Fixed value for global queries
+	ID                  string  `dynamodbav:"id"`
+	UserID              *string `dynamodbav:"user_id,omitempty"`
+	ProjectID           string  `dynamodbav:"project_id"`
+	ClaudeSessionID     string  `dynamodbav:"claude_session_id"`
+	ParentSessionID     *string `dynamodbav:"parent_session_id,omitempty"`
+	ProjectPath         string  `dynamodbav:"project_path,omitempty"`
+	GitBranch           string  `dynamodbav:"git_branch,omitempty"`
+	Title               *string `dynamodbav:"title,omitempty"`
+	StartedAt           string  `dynamodbav:"started_at"`
+	EndedAt             *string `dynamodbav:"ended_at,omitempty"`
+	UpdatedAt           string  `dynamodbav:"updated_at"`
+	CreatedAt           string  `dynamodbav:"created_at"`
+	RedactionCount      int     `dynamodbav:"redaction_count,omitempty"`
+	SubagentCount       int     `dynamodbav:"subagent_count,omitempty"`
+	InputTokens         int64   `dynamodbav:"input_tokens,omitempty"`
+	OutputTokens        int64   `dynamodbav:"output_tokens,omitempty"`
+	CacheReadTokens     int64   `dynamodbav:"cache_read_tokens,omitempty"`
+	CacheCreationTokens int64   `dynamodbav:"cache_creation_tokens,omitempty"`
+	EstimatedCostUSD    float64 `dynamodbav:"estimated_cost_usd,omitempty"`
+	Visibility          string  `dynamodbav:"visibility,omitempty"`
+	GSIPK               string  `dynamodbav:"_gsi_pk"` // Fixed value for global queries
 }
 
 const sessionGSIPK = "SESSION"
@@ -55,6 +65,9 @@ func (r *SessionRepository) Create(ctx context.Context, session *domain.Session)
 	if session.ProjectID == "" {
 		session.ProjectID = domain.DefaultProjectID
 	}
+	if session.Visibility == "" {
+		session.Visibility = domain.DefaultSessionVisibility
+	}
 
 	var endedAt *string
 	if session.EndedAt != nil {
@@ -63,18 +76,27 @@ func (r *SessionRepository) Create(ctx context.Context, session *domain.Session)
 	}
 
 	item := sessionItem{
-		ID:              session.ID,
-		UserID:          session.UserID,
-		ProjectID:       session.ProjectID,
-		ClaudeSessionID: session.ClaudeSessionID,
-		ProjectPath:     session.ProjectPath,
-		GitBranch:       session.GitBranch,
-		Title:           session.Title,
-		StartedAt:       session.StartedAt.Format(time.RFC3339Nano),
-		EndedAt:         endedAt,
-		UpdatedAt:       session.UpdatedAt.Format(time.RFC3339Nano),
-		CreatedAt:       session.CreatedAt.Format(time.RFC3339Nano),
-		GSIPK:           sessionGSIPK,
+		ID:                  session.ID,
+		UserID:              session.UserID,
+		ProjectID:           session.ProjectID,
+		ClaudeSessionID:     session.ClaudeSessionID,
+		ParentSessionID:     session.ParentSessionID,
+		ProjectPath:         session.ProjectPath,
+		GitBranch:           session.GitBranch,
+		Title:               session.Title,
+		StartedAt:           session.StartedAt.Format(time.RFC3339Nano),
+		EndedAt:             endedAt,
+		UpdatedAt:           session.UpdatedAt.Format(time.RFC3339Nano),
+		CreatedAt:           session.CreatedAt.Format(time.RFC3339Nano),
+		RedactionCount:      session.RedactionCount,
+		SubagentCount:       session.SubagentCount,
+		InputTokens:         session.Usage.InputTokens,
+		OutputTokens:        session.Usage.OutputTokens,
+		CacheReadTokens:     session.Usage.CacheReadTokens,
+		CacheCreationTokens: session.Usage.CacheCreationTokens,
+		EstimatedCostUSD:    session.Usage.EstimatedCostUSD,
+		Visibility:          string(session.Visibility),
+		GSIPK:               sessionGSIPK,
 	}
 
 	av, err := attributevalue.MarshalMap(item)
@@ -141,7 +163,7 @@ func (r *SessionRepository) FindByClaudeSessionID(ctx context.Context, claudeSes
 	return r.itemToSession(&item), nil
 }
 
-func (r *SessionRepository) FindAll(ctx context.Context, limit int, cursor string, sortBy string) ([]*domain.Session, string, error) {
+func (r *SessionRepository) FindAll(ctx context.Context, viewer domain.SessionViewer, state domain.SessionState, limit int, cursor string, sortBy string) ([]*domain.Session, string, error) {
 	indexName := "gsi-updated_at-index"
 	if sortBy == "created_at" {
 		indexName = "gsi-created_at-index"
@@ -189,21 +211,11 @@ func (r *SessionRepository) FindAll(ctx context.Context, limit int, cursor strin
 		input.Limit = aws.Int32(int32(limit + 1))
 	}
 
-	result, err := r.db.Client.Query(ctx, input)
+	sessions, err := r.queryVisible(ctx, input, viewer, state, limit)
 	if err != nil {
 		return nil, "", err
 	}
 
-	var items []sessionItem
-	if err := attributevalue.UnmarshalListOfMaps(result.Items, &items); err != nil {
-		return nil, "", err
-	}
-
-	sessions := make([]*domain.Session, 0, len(items))
-	for _, item := range items {
-		sessions = append(sessions, r.itemToSession(&item))
-	}
-
 	// Generate cursor
 	var nextCursor string
 	if limit > 0 && len(sessions) > limit {
@@ -221,7 +233,7 @@ func (r *SessionRepository) FindAll(ctx context.Context, limit int, cursor strin
 	return sessions, nextCursor, nil
 }
 
-func (r *SessionRepository) FindByProjectID(ctx context.Context, projectID string, limit int, cursor string, sortBy string) ([]*domain.Session, string, error) {
+func (r *SessionRepository) FindByProjectID(ctx context.Context, projectID string, viewer domain.SessionViewer, state domain.SessionState, limit int, cursor string, sortBy string) ([]*domain.Session, string, error) {
 	indexName := "project_id-updated_at-index"
 	sortAttr := "updated_at"
 	if sortBy == "created_at" {
@@ -266,21 +278,11 @@ func (r *SessionRepository) FindByProjectID(ctx context.Context, projectID strin
 		input.Limit = aws.Int32(int32(limit + 1))
 	}
 
-	result, err := r.db.Client.Query(ctx, input)
+	sessions, err := r.queryVisible(ctx, input, viewer, state, limit)
 	if err != nil {
 		return nil, "", err
 	}
 
-	var items []sessionItem
-	if err := attributevalue.UnmarshalListOfMaps(result.Items, &items); err != nil {
-		return nil, "", err
-	}
-
-	sessions := make([]*domain.Session, 0, len(items))
-	for _, item := range items {
-		sessions = append(sessions, r.itemToSession(&item))
-	}
-
 	var nextCursor string
 	if limit > 0 && len(sessions) > limit {
 		sessions = sessions[:limit]
@@ -297,6 +299,40 @@ func (r *SessionRepository) FindByProjectID(ctx context.Context, projectID strin
 	return sessions, nextCursor, nil
 }
 
+// queryVisible runs a session query page by page, keeping the sessions the
+// viewer can read that are in the given state (any if empty) until limit+1
+// of them are found (all of them if limit is 0)
+func (r *SessionRepository) queryVisible(ctx context.Context, input *dynamodb.QueryInput, viewer domain.SessionViewer, state domain.SessionState, limit int) ([]*domain.Session, error) {
+	var sessions []*domain.Session
+	for {
+		result, err := r.db.Client.Query(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		var items []sessionItem
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &items); err != nil {
+			return nil, err
+		}
+
+		for i := range items {
+			session := r.itemToSession(&items[i])
+			if viewer.CanView(session) && (state == "" || session.State() == state) {
+				sessions = append(sessions, session)
+			}
+		}
+
+		if len(result.LastEvaluatedKey) == 0 || (limit > 0 && len(sessions) > limit) {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+	if limit > 0 && len(sessions) > limit+1 {
+		sessions = sessions[:limit+1]
+	}
+	return sessions, nil
+}
+
 func (r *SessionRepository) FindOrCreateByClaudeSessionID(ctx context.Context, claudeSessionID string, userID *string) (*domain.Session, error) {
 	session, err := r.FindByClaudeSessionID(ctx, claudeSessionID)
 	if err != nil {
@@ -329,6 +365,42 @@ func (r *SessionRepository) FindOrCreateByClaudeSessionID(ctx context.Context, c
 	return newSession, nil
 }
 
+func (r *SessionRepository) FindByParentSessionID(ctx context.Context, parentSessionID string) ([]*domain.Session, error) {
+	keyCond := expression.Key("parent_session_id").Equal(expression.Value(parentSessionID))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.db.Client.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.db.TableName("sessions")),
+		IndexName:                 aws.String("parent_session_id-index"),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var items []sessionItem
+	if err := attributevalue.UnmarshalListOfMaps(result.Items, &items); err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*domain.Session, len(items))
+	for i, item := range items {
+		sessions[i] = r.itemToSession(&item)
+	}
+
+	// Oldest first
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].StartedAt.Before(sessions[j].StartedAt)
+	})
+
+	return sessions, nil
+}
+
 func (r *SessionRepository) UpdateUserID(ctx context.Context, id string, userID string) error {
 	update := expression.Set(expression.Name("user_id"), expression.Value(userID))
 	expr, err := expression.NewBuilder().WithUpdate(update).Build()
@@ -443,6 +515,153 @@ func (r *SessionRepository) UpdateUpdatedAt(ctx context.Context, id string, upda
 	return err
 }
 
+func (r *SessionRepository) UpdateStartedAt(ctx context.Context, id string, startedAt time.Time) error {
+	update := expression.Set(expression.Name("started_at"), expression.Value(startedAt.Format(time.RFC3339Nano)))
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.db.TableName("sessions")),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	return err
+}
+
+func (r *SessionRepository) UpdateParentSessionID(ctx context.Context, id string, parentSessionID string) error {
+	update := expression.Set(expression.Name("parent_session_id"), expression.Value(parentSessionID))
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.db.TableName("sessions")),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	return err
+}
+
+func (r *SessionRepository) UpdateVisibility(ctx context.Context, id string, visibility domain.SessionVisibility) error {
+	update := expression.Set(expression.Name("visibility"), expression.Value(string(visibility)))
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.db.TableName("sessions")),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	return err
+}
+
+func (r *SessionRepository) MarkEnded(ctx context.Context, id string, endedAt time.Time) error {
+	update := expression.Set(expression.Name("ended_at"), expression.Value(endedAt.Format(time.RFC3339Nano)))
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.db.TableName("sessions")),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	return err
+}
+
+func (r *SessionRepository) AddRedactionCount(ctx context.Context, id string, count int) error {
+	update := expression.Add(expression.Name("redaction_count"), expression.Value(count))
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.db.TableName("sessions")),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	return err
+}
+
+func (r *SessionRepository) AddSubagentCount(ctx context.Context, id string, count int) error {
+	update := expression.Add(expression.Name("subagent_count"), expression.Value(count))
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.db.TableName("sessions")),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	return err
+}
+
+func (r *SessionRepository) AddUsage(ctx context.Context, id string, usage domain.TokenUsage) error {
+	update := expression.Add(expression.Name("input_tokens"), expression.Value(usage.InputTokens)).
+		Add(expression.Name("output_tokens"), expression.Value(usage.OutputTokens)).
+		Add(expression.Name("cache_read_tokens"), expression.Value(usage.CacheReadTokens)).
+		Add(expression.Name("cache_creation_tokens"), expression.Value(usage.CacheCreationTokens)).
+		Add(expression.Name("estimated_cost_usd"), expression.Value(usage.EstimatedCostUSD))
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.db.TableName("sessions")),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	return err
+}
+
+func (r *SessionRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.db.TableName("sessions")),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	return err
+}
+
 func (r *SessionRepository) itemToSession(item *sessionItem) *domain.Session {
 	startedAt, _ := time.Parse(time.RFC3339Nano, item.StartedAt)
 	updatedAt, _ := time.Parse(time.RFC3339Nano, item.UpdatedAt)
@@ -454,11 +673,17 @@ func (r *SessionRepository) itemToSession(item *sessionItem) *domain.Session {
 		endedAt = &t
 	}
 
+	visibility := domain.SessionVisibility(item.Visibility)
+	if visibility == "" {
+		visibility = domain.DefaultSessionVisibility
+	}
+
 	return &domain.Session{
 		ID:              item.ID,
 		UserID:          item.UserID,
 		ProjectID:       item.ProjectID,
 		ClaudeSessionID: item.ClaudeSessionID,
+		ParentSessionID: item.ParentSessionID,
 		ProjectPath:     item.ProjectPath,
 		GitBranch:       item.GitBranch,
 		Title:           item.Title,
@@ -466,5 +691,15 @@ func (r *SessionRepository) itemToSession(item *sessionItem) *domain.Session {
 		EndedAt:         endedAt,
 		UpdatedAt:       updatedAt,
 		CreatedAt:       createdAt,
+		RedactionCount:  item.RedactionCount,
+		SubagentCount:   item.SubagentCount,
+		Usage: domain.TokenUsage{
+			InputTokens:         item.InputTokens,
+			OutputTokens:        item.OutputTokens,
+			CacheReadTokens:     item.CacheReadTokens,
+			CacheCreationTokens: item.CacheCreationTokens,
+			EstimatedCostUSD:    item.EstimatedCostUSD,
+		},
+		Visibility: visibility,
 	}
 }
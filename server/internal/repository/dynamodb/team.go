@@ -0,0 +1,268 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+type TeamRepository struct {
+	db *DB
+}
+
+func NewTeamRepository(db *DB) *TeamRepository {
+	return &TeamRepository{db: db}
+}
+
+type teamItem struct {
+	ID        string `dynamodbav:"id"`
+	Name      string `dynamodbav:"name"`
+	CreatedAt string `dynamodbav:"created_at"`
+}
+
+func (r *TeamRepository) Create(ctx context.Context, team *domain.Team) error {
+	if team.ID == "" {
+		team.ID = uuid.New().String()
+	}
+	if team.CreatedAt.IsZero() {
+		team.CreatedAt = time.Now()
+	}
+
+	av, err := attributevalue.MarshalMap(teamItem{
+		ID:        team.ID,
+		Name:      team.Name,
+		CreatedAt: team.CreatedAt.Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.db.TableName("teams")),
+		Item:      av,
+	})
+	return err
+}
+
+func (r *TeamRepository) FindByID(ctx context.Context, id string) (*domain.Team, error) {
+	result, err := r.db.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.db.TableName("teams")),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var item teamItem
+	if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+		return nil, err
+	}
+
+	return r.itemToTeam(&item), nil
+}
+
+func (r *TeamRepository) FindAll(ctx context.Context) ([]*domain.Team, error) {
+	var teams []*domain.Team
+	var lastKey map[string]types.AttributeValue
+	for {
+		result, err := r.db.Client.Scan(ctx, &dynamodb.ScanInput{
+			TableName:         aws.String(r.db.TableName("teams")),
+			ExclusiveStartKey: lastKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var items []teamItem
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &items); err != nil {
+			return nil, err
+		}
+		for i := range items {
+			teams = append(teams, r.itemToTeam(&items[i]))
+		}
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastKey = result.LastEvaluatedKey
+	}
+
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].Name < teams[j].Name
+	})
+
+	return teams, nil
+}
+
+func (r *TeamRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.db.TableName("teams")),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	return err
+}
+
+func (r *TeamRepository) itemToTeam(item *teamItem) *domain.Team {
+	createdAt, _ := time.Parse(time.RFC3339Nano, item.CreatedAt)
+	return &domain.Team{
+		ID:        item.ID,
+		Name:      item.Name,
+		CreatedAt: createdAt,
+	}
+}
+
+type TeamMembershipRepository struct {
+	db *DB
+}
+
+func NewTeamMembershipRepository(db *DB) *TeamMembershipRepository {
+	return &TeamMembershipRepository{db: db}
+}
+
+type teamMembershipItem struct {
+	TeamID    string `dynamodbav:"team_id"`
+	UserID    string `dynamodbav:"user_id"`
+	CreatedAt string `dynamodbav:"created_at"`
+}
+
+func (r *TeamMembershipRepository) Create(ctx context.Context, membership *domain.TeamMembership) error {
+	if membership.CreatedAt.IsZero() {
+		membership.CreatedAt = time.Now()
+	}
+
+	av, err := attributevalue.MarshalMap(teamMembershipItem{
+		TeamID:    membership.TeamID,
+		UserID:    membership.UserID,
+		CreatedAt: membership.CreatedAt.Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return err
+	}
+
+	cond := expression.AttributeNotExists(expression.Name("user_id"))
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                aws.String(r.db.TableName("team_memberships")),
+		Item:                     av,
+		ConditionExpression:      expr.Condition(),
+		ExpressionAttributeNames: expr.Names(),
+	})
+	if err != nil {
+		// Already a member
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return nil
+		}
+	}
+	return err
+}
+
+func (r *TeamMembershipRepository) Delete(ctx context.Context, teamID string, userID string) error {
+	_, err := r.db.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.db.TableName("team_memberships")),
+		Key: map[string]types.AttributeValue{
+			"team_id": &types.AttributeValueMemberS{Value: teamID},
+			"user_id": &types.AttributeValueMemberS{Value: userID},
+		},
+	})
+	return err
+}
+
+func (r *TeamMembershipRepository) DeleteByTeamID(ctx context.Context, teamID string) error {
+	memberships, err := r.FindByTeamID(ctx, teamID)
+	if err != nil {
+		return err
+	}
+
+	requests := make([]types.WriteRequest, len(memberships))
+	for i, m := range memberships {
+		requests[i] = types.WriteRequest{
+			DeleteRequest: &types.DeleteRequest{
+				Key: map[string]types.AttributeValue{
+					"team_id": &types.AttributeValueMemberS{Value: m.TeamID},
+					"user_id": &types.AttributeValueMemberS{Value: m.UserID},
+				},
+			},
+		}
+	}
+
+	return r.db.BatchWrite(ctx, "team_memberships", requests)
+}
+
+func (r *TeamMembershipRepository) FindByTeamID(ctx context.Context, teamID string) ([]*domain.TeamMembership, error) {
+	return r.query(ctx, "", expression.Key("team_id").Equal(expression.Value(teamID)))
+}
+
+func (r *TeamMembershipRepository) FindByUserID(ctx context.Context, userID string) ([]*domain.TeamMembership, error) {
+	return r.query(ctx, "user_id-index", expression.Key("user_id").Equal(expression.Value(userID)))
+}
+
+// query returns every membership matching the key condition, ordered by creation
+func (r *TeamMembershipRepository) query(ctx context.Context, indexName string, keyCond expression.KeyConditionBuilder) ([]*domain.TeamMembership, error) {
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.db.TableName("team_memberships")),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}
+	if indexName != "" {
+		input.IndexName = aws.String(indexName)
+	}
+
+	var memberships []*domain.TeamMembership
+	for {
+		result, err := r.db.Client.Query(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		var items []teamMembershipItem
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			createdAt, _ := time.Parse(time.RFC3339Nano, item.CreatedAt)
+			memberships = append(memberships, &domain.TeamMembership{
+				TeamID:    item.TeamID,
+				UserID:    item.UserID,
+				CreatedAt: createdAt,
+			})
+		}
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	sort.SliceStable(memberships, func(i, j int) bool {
+		return memberships[i].CreatedAt.Before(memberships[j].CreatedAt)
+	})
+
+	return memberships, nil
+}
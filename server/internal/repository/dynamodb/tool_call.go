@@ -0,0 +1,228 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+type ToolCallRepository struct {
+	db *DB
+}
+
+func NewToolCallRepository(db *DB) *ToolCallRepository {
+	return &ToolCallRepository{db: db}
+}
+
+type toolCallItem struct {
+	SessionID   string `dynamodbav:"session_id"`
+	ToolUseID   string `dynamodbav:"tool_use_id"`
+	ID          string `dynamodbav:"id"`
+	ProjectID   string `dynamodbav:"project_id"`
+	EventID     string `dynamodbav:"event_id"`
+	ToolName    string `dynamodbav:"tool_name"`
+	IsError     bool   `dynamodbav:"is_error"`
+	StartedAt   string `dynamodbav:"started_at"`
+	CompletedAt string `dynamodbav:"completed_at,omitempty"`
+}
+
+func (r *ToolCallRepository) Create(ctx context.Context, call *domain.ToolCall) error {
+	if call.ID == "" {
+		call.ID = uuid.New().String()
+	}
+	if call.StartedAt.IsZero() {
+		call.StartedAt = time.Now()
+	}
+
+	item := toolCallItem{
+		SessionID: call.SessionID,
+		ToolUseID: call.ToolUseID,
+		ID:        call.ID,
+		ProjectID: call.ProjectID,
+		EventID:   call.EventID,
+		ToolName:  call.ToolName,
+		IsError:   call.IsError,
+		StartedAt: call.StartedAt.Format(time.RFC3339Nano),
+	}
+	if call.CompletedAt != nil {
+		item.CompletedAt = call.CompletedAt.Format(time.RFC3339Nano)
+	}
+
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	cond := expression.AttributeNotExists(expression.Name("tool_use_id"))
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                aws.String(r.db.TableName("tool_calls")),
+		Item:                     av,
+		ConditionExpression:      expr.Condition(),
+		ExpressionAttributeNames: expr.Names(),
+	})
+	if err != nil {
+		// Already recorded for the session
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return nil
+		}
+	}
+	return err
+}
+
+func (r *ToolCallRepository) Complete(ctx context.Context, sessionID, toolUseID string, isError bool, completedAt time.Time) error {
+	update := expression.Set(expression.Name("is_error"), expression.Value(isError)).
+		Set(expression.Name("completed_at"), expression.Value(completedAt.Format(time.RFC3339Nano)))
+	cond := expression.AttributeExists(expression.Name("tool_use_id")).
+		And(expression.AttributeNotExists(expression.Name("completed_at")))
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(cond).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.db.TableName("tool_calls")),
+		Key: map[string]types.AttributeValue{
+			"session_id":  &types.AttributeValueMemberS{Value: sessionID},
+			"tool_use_id": &types.AttributeValueMemberS{Value: toolUseID},
+		},
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		// Unknown or already completed call
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return nil
+		}
+	}
+	return err
+}
+
+func (r *ToolCallRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*domain.ToolCall, error) {
+	keyCond := expression.Key("session_id").Equal(expression.Value(sessionID))
+	return r.query(ctx, "", keyCond, time.Time{}, time.Time{})
+}
+
+func (r *ToolCallRepository) FindByProjectID(ctx context.Context, projectID string, from, to time.Time) ([]*domain.ToolCall, error) {
+	keyCond := expression.Key("project_id").Equal(expression.Value(projectID))
+	return r.query(ctx, "project_id-index", keyCond, from, to)
+}
+
+func (r *ToolCallRepository) UpdateProjectIDBySessionID(ctx context.Context, sessionID string, projectID string) error {
+	calls, err := r.FindBySessionID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	for _, call := range calls {
+		update := expression.Set(expression.Name("project_id"), expression.Value(projectID))
+		expr, err := expression.NewBuilder().WithUpdate(update).Build()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName: aws.String(r.db.TableName("tool_calls")),
+			Key: map[string]types.AttributeValue{
+				"session_id":  &types.AttributeValueMemberS{Value: call.SessionID},
+				"tool_use_id": &types.AttributeValueMemberS{Value: call.ToolUseID},
+			},
+			UpdateExpression:          expr.Update(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ToolCallRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	return r.db.DeleteSessionItems(ctx, "tool_calls", "tool_use_id", sessionID)
+}
+
+// query returns the tool calls matching the key condition and started in [from, to), oldest first.
+// Times are filtered here: started_at strings do not compare chronologically across precisions and zones
+func (r *ToolCallRepository) query(ctx context.Context, indexName string, keyCond expression.KeyConditionBuilder, from, to time.Time) ([]*domain.ToolCall, error) {
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.db.TableName("tool_calls")),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}
+	if indexName != "" {
+		input.IndexName = aws.String(indexName)
+	}
+
+	calls := make([]*domain.ToolCall, 0)
+	for {
+		result, err := r.db.Client.Query(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		var items []toolCallItem
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &items); err != nil {
+			return nil, err
+		}
+		for i := range items {
+			call := r.itemToToolCall(&items[i])
+			if (!from.IsZero() && call.StartedAt.Before(from)) || (!to.IsZero() && !call.StartedAt.Before(to)) {
+				continue
+			}
+			calls = append(calls, call)
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	sort.SliceStable(calls, func(i, j int) bool {
+		return calls[i].StartedAt.Before(calls[j].StartedAt)
+	})
+	return calls, nil
+}
+
+func (r *ToolCallRepository) itemToToolCall(item *toolCallItem) *domain.ToolCall {
+	startedAt, _ := time.Parse(time.RFC3339Nano, item.StartedAt)
+	call := &domain.ToolCall{
+		ID:        item.ID,
+		SessionID: item.SessionID,
+		ProjectID: item.ProjectID,
+		EventID:   item.EventID,
+		ToolUseID: item.ToolUseID,
+		ToolName:  item.ToolName,
+		IsError:   item.IsError,
+		StartedAt: startedAt,
+	}
+	if item.CompletedAt != "" {
+		completedAt, _ := time.Parse(time.RFC3339Nano, item.CompletedAt)
+		call.CompletedAt = &completedAt
+	}
+	return call
+}
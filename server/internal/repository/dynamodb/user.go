@@ -26,6 +26,10 @@ type userItem struct {
 	Email       string `dynamodbav:"email"`
 	DisplayName string `dynamodbav:"display_name,omitempty"`
 	CreatedAt   string `dynamodbav:"created_at"`
+
+	DefaultSessionVisibility string `dynamodbav:"default_session_visibility,omitempty"`
+	Role                     string `dynamodbav:"role,omitempty"`
+	Disabled                 bool   `dynamodbav:"disabled,omitempty"`
 }
 
 func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
@@ -35,12 +39,19 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	if user.CreatedAt.IsZero() {
 		user.CreatedAt = time.Now()
 	}
+	if user.Role == "" {
+		user.Role = domain.DefaultUserRole
+	}
 
 	item := userItem{
 		ID:          user.ID,
 		Email:       user.Email,
 		DisplayName: user.DisplayName,
 		CreatedAt:   user.CreatedAt.Format(time.RFC3339Nano),
+
+		DefaultSessionVisibility: string(user.DefaultSessionVisibility),
+		Role:                     string(user.Role),
+		Disabled:                 user.Disabled,
 	}
 
 	av, err := attributevalue.MarshalMap(item)
@@ -147,12 +158,78 @@ func (r *UserRepository) UpdateDisplayName(ctx context.Context, id string, displ
 	return err
 }
 
+func (r *UserRepository) UpdateDefaultSessionVisibility(ctx context.Context, id string, visibility domain.SessionVisibility) error {
+	update := expression.Set(expression.Name("default_session_visibility"), expression.Value(string(visibility)))
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.db.TableName("users")),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	return err
+}
+
+func (r *UserRepository) UpdateRole(ctx context.Context, id string, role domain.UserRole) error {
+	update := expression.Set(expression.Name("role"), expression.Value(string(role)))
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.db.TableName("users")),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	return err
+}
+
+func (r *UserRepository) UpdateDisabled(ctx context.Context, id string, disabled bool) error {
+	update := expression.Set(expression.Name("disabled"), expression.Value(disabled))
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.db.TableName("users")),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	return err
+}
+
 func (r *UserRepository) itemToUser(item *userItem) *domain.User {
 	createdAt, _ := time.Parse(time.RFC3339Nano, item.CreatedAt)
+	// Users stored before roles existed are members; migration 0.0.3 promotes the first one
+	role := domain.UserRole(item.Role)
+	if role == "" {
+		role = domain.DefaultUserRole
+	}
 	return &domain.User{
 		ID:          item.ID,
 		Email:       item.Email,
 		DisplayName: item.DisplayName,
 		CreatedAt:   createdAt,
+
+		DefaultSessionVisibility: domain.SessionVisibility(item.DefaultSessionVisibility),
+		Role:                     role,
+		Disabled:                 item.Disabled,
 	}
 }
@@ -128,6 +128,40 @@ func (r *WebSessionRepository) DeleteExpired(ctx context.Context) error {
 	return nil
 }
 
+func (r *WebSessionRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	filterExpr := expression.Name("user_id").Equal(expression.Value(userID))
+	expr, err := expression.NewBuilder().WithFilter(filterExpr).Build()
+	if err != nil {
+		return err
+	}
+
+	var lastKey map[string]types.AttributeValue
+	for {
+		result, err := r.db.Client.Scan(ctx, &dynamodb.ScanInput{
+			TableName:                 aws.String(r.db.TableName("web_sessions")),
+			FilterExpression:          expr.Filter(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			ExclusiveStartKey:         lastKey,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, item := range result.Items {
+			id := item["id"].(*types.AttributeValueMemberS).Value
+			if err := r.Delete(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		if result.LastEvaluatedKey == nil {
+			return nil
+		}
+		lastKey = result.LastEvaluatedKey
+	}
+}
+
 func (r *WebSessionRepository) itemToWebSession(item *webSessionItem) *domain.WebSession {
 	expiresAt, _ := time.Parse(time.RFC3339Nano, item.ExpiresAt)
 	createdAt, _ := time.Parse(time.RFC3339Nano, item.CreatedAt)
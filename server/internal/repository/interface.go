@@ -11,6 +11,9 @@ import (
 // ErrDuplicateEvent is returned when trying to create an event with a UUID that already exists
 var ErrDuplicateEvent = errors.New("duplicate event: UUID already exists for this session")
 
+// ErrRevisionConflict is returned when a plan document was modified since the expected revision was read
+var ErrRevisionConflict = errors.New("revision conflict: plan document was modified")
+
 // ProjectRepository はプロジェクトの永続化を担当する
 type ProjectRepository interface {
 	Create(ctx context.Context, project *domain.Project) error
@@ -19,6 +22,8 @@ type ProjectRepository interface {
 	FindOrCreateByCanonicalGitRepository(ctx context.Context, canonicalGitRepo string) (*domain.Project, error)
 	FindAll(ctx context.Context, limit int, cursor string) ([]*domain.Project, string, error) // Returns (projects, nextCursor, error)
 	GetDefaultProject(ctx context.Context) (*domain.Project, error)                           // CanonicalGitRepository が空のプロジェクト
+	FindTeamOwned(ctx context.Context) ([]*domain.Project, error)                             // Projects that belong to a team
+	UpdateTeamID(ctx context.Context, id string, teamID *string) error                        // nil makes the project visible to everyone
 }
 
 // SessionRepository はセッションの永続化を担当する
@@ -26,22 +31,41 @@ type SessionRepository interface {
 	Create(ctx context.Context, session *domain.Session) error
 	FindByID(ctx context.Context, id string) (*domain.Session, error)
 	FindByClaudeSessionID(ctx context.Context, claudeSessionID string) (*domain.Session, error)
-	FindAll(ctx context.Context, limit int, cursor string, sortBy string) ([]*domain.Session, string, error)                      // Returns (sessions, nextCursor, error)
-	FindByProjectID(ctx context.Context, projectID string, limit int, cursor string, sortBy string) ([]*domain.Session, string, error) // Returns (sessions, nextCursor, error)
+	FindAll(ctx context.Context, viewer domain.SessionViewer, state domain.SessionState, limit int, cursor string, sortBy string) ([]*domain.Session, string, error)                           // Sessions the viewer can read, in the given state (empty = any); returns (sessions, nextCursor, error)
+	FindByProjectID(ctx context.Context, projectID string, viewer domain.SessionViewer, state domain.SessionState, limit int, cursor string, sortBy string) ([]*domain.Session, string, error) // Sessions the viewer can read, in the given state (empty = any); returns (sessions, nextCursor, error)
 	FindOrCreateByClaudeSessionID(ctx context.Context, claudeSessionID string, userID *string) (*domain.Session, error)
+	FindByParentSessionID(ctx context.Context, parentSessionID string) ([]*domain.Session, error) // Sessions continuing the given one, oldest first
 	UpdateUserID(ctx context.Context, id string, userID string) error
 	UpdateProjectPath(ctx context.Context, id string, projectPath string) error
 	UpdateProjectID(ctx context.Context, id string, projectID string) error
 	UpdateGitBranch(ctx context.Context, id string, gitBranch string) error
 	UpdateTitle(ctx context.Context, id string, title string) error
 	UpdateUpdatedAt(ctx context.Context, id string, updatedAt time.Time) error
+	UpdateStartedAt(ctx context.Context, id string, startedAt time.Time) error // Backfills the start from the earliest event time
+	UpdateParentSessionID(ctx context.Context, id string, parentSessionID string) error
+	UpdateVisibility(ctx context.Context, id string, visibility domain.SessionVisibility) error
+	MarkEnded(ctx context.Context, id string, endedAt time.Time) error
+	AddRedactionCount(ctx context.Context, id string, count int) error
+	AddSubagentCount(ctx context.Context, id string, count int) error
+	AddUsage(ctx context.Context, id string, usage domain.TokenUsage) error // Adds to the session's aggregated token usage
+	Delete(ctx context.Context, id string) error                            // Its events and derived rows are deleted separately
 }
 
 // EventRepository はイベントの永続化を担当する
 type EventRepository interface {
 	Create(ctx context.Context, event *domain.Event) error
+	CreateBatch(ctx context.Context, events []*domain.Event) (*EventBatchResult, error) // Skips events whose uuid is already stored for the session
 	FindBySessionID(ctx context.Context, sessionID string) ([]*domain.Event, error)
 	CountBySessionID(ctx context.Context, sessionID string) (int, error)
+	FindSessionIDsByUUID(ctx context.Context, eventUUID string) ([]string, error)                                         // Sessions holding a transcript line with the uuid
+	Search(ctx context.Context, query string, viewer domain.SessionViewer, limit int) ([]*domain.EventSearchMatch, error) // Events of sessions the viewer can read; all query terms must match; ordered by relevance or recency
+	DeleteBySessionID(ctx context.Context, sessionID string) error                                                        // Also removes them from the search index
+}
+
+// EventBatchResult is the outcome of EventRepository.CreateBatch
+type EventBatchResult struct {
+	Created    []*domain.Event // stored events, in input order
+	Duplicates int             // events skipped because their uuid was already stored for the session
 }
 
 // UserRepository はユーザーの永続化を担当する
@@ -51,16 +75,22 @@ type UserRepository interface {
 	FindByEmail(ctx context.Context, email string) (*domain.User, error)
 	FindAll(ctx context.Context) ([]*domain.User, error)
 	UpdateDisplayName(ctx context.Context, id string, displayName string) error
+	UpdateDefaultSessionVisibility(ctx context.Context, id string, visibility domain.SessionVisibility) error
+	UpdateRole(ctx context.Context, id string, role domain.UserRole) error
+	UpdateDisabled(ctx context.Context, id string, disabled bool) error
 }
 
 // APIKeyRepository はAPIキーの永続化を担当する
 type APIKeyRepository interface {
 	Create(ctx context.Context, key *domain.APIKey) error
 	FindByKeyHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	FindByLookupID(ctx context.Context, lookupID string) (*domain.APIKey, error)
 	FindByUserID(ctx context.Context, userID string) ([]*domain.APIKey, error)
+	FindLegacy(ctx context.Context) ([]*domain.APIKey, error) // Keys without a lookup ID, not yet upgraded
 	FindByID(ctx context.Context, id string) (*domain.APIKey, error)
 	Delete(ctx context.Context, id string) error
 	UpdateLastUsedAt(ctx context.Context, id string) error
+	UpdateLookupID(ctx context.Context, id string, lookupID string, keyHash string) error // Upgrades a legacy key to indexed lookup
 }
 
 // WebSessionRepository はWebセッションの永続化を担当する
@@ -68,6 +98,7 @@ type WebSessionRepository interface {
 	Create(ctx context.Context, session *domain.WebSession) error
 	FindByToken(ctx context.Context, token string) (*domain.WebSession, error)
 	Delete(ctx context.Context, id string) error
+	DeleteByUserID(ctx context.Context, userID string) error // Signs the user out everywhere
 	DeleteExpired(ctx context.Context) error
 }
 
@@ -92,9 +123,9 @@ type PlanDocumentRepository interface {
 	Create(ctx context.Context, doc *domain.PlanDocument) error
 	FindByID(ctx context.Context, id string) (*domain.PlanDocument, error)
 	Find(ctx context.Context, query domain.PlanDocumentQuery) ([]*domain.PlanDocument, string, error) // Returns (docs, nextCursor, error)
-	Update(ctx context.Context, doc *domain.PlanDocument) error
+	Update(ctx context.Context, doc *domain.PlanDocument) error                                       // Compare-and-swap on doc.Revision; increments it or returns ErrRevisionConflict
 	Delete(ctx context.Context, id string) error
-	SetStatus(ctx context.Context, id string, status domain.PlanDocumentStatus) error
+	SetStatus(ctx context.Context, id string, status domain.PlanDocumentStatus, expectedRevision int) (int, error) // Returns the new revision or ErrRevisionConflict
 }
 
 // PlanDocumentEventRepository はPlanDocumentEventの永続化を担当する
@@ -117,6 +148,64 @@ type UserFavoriteRepository interface {
 	GetTargetIDs(ctx context.Context, userID string, targetType domain.UserFavoriteTargetType) ([]string, error)
 }
 
+// TeamRepository はチームの永続化を担当する
+type TeamRepository interface {
+	Create(ctx context.Context, team *domain.Team) error
+	FindByID(ctx context.Context, id string) (*domain.Team, error)
+	FindAll(ctx context.Context) ([]*domain.Team, error) // Sorted by name
+	Delete(ctx context.Context, id string) error
+}
+
+// TeamMembershipRepository はチームメンバーシップの永続化を担当する
+type TeamMembershipRepository interface {
+	Create(ctx context.Context, membership *domain.TeamMembership) error // Adding an existing member is a no-op
+	Delete(ctx context.Context, teamID string, userID string) error
+	DeleteByTeamID(ctx context.Context, teamID string) error
+	FindByTeamID(ctx context.Context, teamID string) ([]*domain.TeamMembership, error)
+	FindByUserID(ctx context.Context, userID string) ([]*domain.TeamMembership, error)
+}
+
+// FileActivityRepository はファイル操作履歴の永続化を担当する
+type FileActivityRepository interface {
+	Create(ctx context.Context, activity *domain.FileActivity) error
+	FindBySessionID(ctx context.Context, sessionID string) ([]*domain.FileActivity, error)              // Oldest first
+	FindByProjectIDAndPath(ctx context.Context, projectID, path string) ([]*domain.FileActivity, error) // Newest first
+	UpdateProjectIDBySessionID(ctx context.Context, sessionID string, projectID string) error           // Follows the session moving to another project
+	DeleteBySessionID(ctx context.Context, sessionID string) error
+}
+
+// ToolCallRepository はツール呼び出し履歴の永続化を担当する
+type ToolCallRepository interface {
+	Create(ctx context.Context, call *domain.ToolCall) error                                               // Ignores a call already recorded for the session
+	Complete(ctx context.Context, sessionID, toolUseID string, isError bool, completedAt time.Time) error  // Records the result of a pending call; does nothing if the call is unknown
+	FindBySessionID(ctx context.Context, sessionID string) ([]*domain.ToolCall, error)                     // Oldest first
+	FindByProjectID(ctx context.Context, projectID string, from, to time.Time) ([]*domain.ToolCall, error) // Started in [from, to); zero times leave the range open
+	UpdateProjectIDBySessionID(ctx context.Context, sessionID string, projectID string) error              // Follows the session moving to another project
+	DeleteBySessionID(ctx context.Context, sessionID string) error
+}
+
+// AnalyticsRepository は利用状況の集計を担当する
+type AnalyticsRepository interface {
+	// RecordActivity は集計カウンタを持つバックエンド（memory, dynamodb）向けにアクティビティを記録する。
+	// SQLバックエンドは元テーブルから集計するため何もしない
+	RecordActivity(ctx context.Context, activity domain.AnalyticsActivity) error
+	GetSeries(ctx context.Context, query domain.AnalyticsQuery) ([]*domain.AnalyticsBucket, error)
+}
+
+// Transactor はリポジトリ操作をトランザクションにまとめる
+type Transactor interface {
+	// RunInTx calls fn with a context whose repository calls share one transaction,
+	// committed if fn returns nil. Calls nested in fn join the outer transaction.
+	RunInTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// NoTransaction is the Transactor of backends without transactions: fn runs directly
+type NoTransaction struct{}
+
+func (NoTransaction) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
 // Repositories は全リポジトリをまとめる
 type Repositories struct {
 	Project            ProjectRepository
@@ -130,4 +219,10 @@ type Repositories struct {
 	PlanDocument       PlanDocumentRepository
 	PlanDocumentEvent  PlanDocumentEventRepository
 	UserFavorite       UserFavoriteRepository
+	Team               TeamRepository
+	TeamMembership     TeamMembershipRepository
+	Analytics          AnalyticsRepository
+	FileActivity       FileActivityRepository
+	ToolCall           ToolCallRepository
+	Tx                 Transactor
 }
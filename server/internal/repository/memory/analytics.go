@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+// AnalyticsRepository keeps recorded activities and aggregates them on read
+type AnalyticsRepository struct {
+	mu         sync.RWMutex
+	activities []domain.AnalyticsActivity
+}
+
+func NewAnalyticsRepository() *AnalyticsRepository {
+	return &AnalyticsRepository{}
+}
+
+func (r *AnalyticsRepository) RecordActivity(ctx context.Context, activity domain.AnalyticsActivity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.activities = append(r.activities, activity)
+	return nil
+}
+
+func (r *AnalyticsRepository) GetSeries(ctx context.Context, query domain.AnalyticsQuery) ([]*domain.AnalyticsBucket, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	series := domain.NewAnalyticsSeries(query)
+	for _, a := range r.activities {
+		if query.ProjectID != "" && a.ProjectID != query.ProjectID {
+			continue
+		}
+		if query.UserID != "" && a.UserID != query.UserID {
+			continue
+		}
+		if query.Excludes(a.ProjectID) {
+			continue
+		}
+		series.Add(a.Time, a.AnalyticsCounts)
+		if a.EventsIngested > 0 {
+			series.AddActiveUser(a.Time, a.UserID)
+		}
+	}
+	return series.Buckets(), nil
+}
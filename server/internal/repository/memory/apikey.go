@@ -47,6 +47,21 @@ func (r *APIKeyRepository) FindByKeyHash(ctx context.Context, keyHash string) (*
 	return nil, nil
 }
 
+func (r *APIKeyRepository) FindByLookupID(ctx context.Context, lookupID string) (*domain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if lookupID == "" {
+		return nil, nil
+	}
+	for _, k := range r.keys {
+		if k.LookupID == lookupID {
+			return k, nil
+		}
+	}
+	return nil, nil
+}
+
 func (r *APIKeyRepository) FindByUserID(ctx context.Context, userID string) ([]*domain.APIKey, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -60,6 +75,19 @@ func (r *APIKeyRepository) FindByUserID(ctx context.Context, userID string) ([]*
 	return keys, nil
 }
 
+func (r *APIKeyRepository) FindLegacy(ctx context.Context) ([]*domain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]*domain.APIKey, 0)
+	for _, k := range r.keys {
+		if k.LookupID == "" {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
 func (r *APIKeyRepository) FindByID(ctx context.Context, id string) (*domain.APIKey, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -91,3 +119,16 @@ func (r *APIKeyRepository) UpdateLastUsedAt(ctx context.Context, id string) erro
 	key.LastUsedAt = &now
 	return nil
 }
+
+func (r *APIKeyRepository) UpdateLookupID(ctx context.Context, id string, lookupID string, keyHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return nil
+	}
+	key.LookupID = lookupID
+	key.KeyHash = keyHash
+	return nil
+}
@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"errors"
 	"sort"
 	"sync"
 	"time"
@@ -16,12 +17,14 @@ type EventRepository struct {
 	events map[string]*domain.Event
 	// uuidIndex maps "session_id:uuid" -> event.ID to detect duplicates
 	uuidIndex map[string]string
+	sessions  *SessionRepository // for the visibility of search matches
 }
 
-func NewEventRepository() *EventRepository {
+func NewEventRepository(sessions *SessionRepository) *EventRepository {
 	return &EventRepository{
 		events:    make(map[string]*domain.Event),
 		uuidIndex: make(map[string]string),
+		sessions:  sessions,
 	}
 }
 
@@ -49,18 +52,19 @@ func (r *EventRepository) Create(ctx context.Context, event *domain.Event) error
 	return nil
 }
 
-// getTimestampFromPayload extracts timestamp from payload, falls back to CreatedAt
-func getTimestampFromPayload(e *domain.Event) time.Time {
-	if ts, ok := e.Payload["timestamp"].(string); ok {
-		if parsed, err := time.Parse(time.RFC3339, ts); err == nil {
-			return parsed
-		}
-		// Try parsing without timezone
-		if parsed, err := time.Parse("2006-01-02T15:04:05.000Z", ts); err == nil {
-			return parsed
+func (r *EventRepository) CreateBatch(ctx context.Context, events []*domain.Event) (*repository.EventBatchResult, error) {
+	result := &repository.EventBatchResult{Created: make([]*domain.Event, 0, len(events))}
+	for _, event := range events {
+		if err := r.Create(ctx, event); err != nil {
+			if errors.Is(err, repository.ErrDuplicateEvent) {
+				result.Duplicates++
+				continue
+			}
+			return nil, err
 		}
+		result.Created = append(result.Created, event)
 	}
-	return e.CreatedAt
+	return result, nil
 }
 
 func (r *EventRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*domain.Event, error) {
@@ -74,10 +78,7 @@ func (r *EventRepository) FindBySessionID(ctx context.Context, sessionID string)
 		}
 	}
 
-	// Sort by payload.timestamp ascending (oldest first)
-	sort.Slice(events, func(i, j int) bool {
-		return getTimestampFromPayload(events[i]).Before(getTimestampFromPayload(events[j]))
-	})
+	domain.SortEventsByTime(events)
 
 	return events, nil
 }
@@ -95,3 +96,72 @@ func (r *EventRepository) CountBySessionID(ctx context.Context, sessionID string
 
 	return count, nil
 }
+
+func (r *EventRepository) FindSessionIDsByUUID(ctx context.Context, eventUUID string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var sessionIDs []string
+	for _, e := range r.events {
+		if e.UUID == eventUUID && !seen[e.SessionID] {
+			seen[e.SessionID] = true
+			sessionIDs = append(sessionIDs, e.SessionID)
+		}
+	}
+
+	return sessionIDs, nil
+}
+
+func (r *EventRepository) Search(ctx context.Context, query string, viewer domain.SessionViewer, limit int) ([]*domain.EventSearchMatch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	terms := domain.ParseSearchTerms(query)
+	if len(terms) == 0 {
+		return []*domain.EventSearchMatch{}, nil
+	}
+
+	matches := make([]*domain.EventSearchMatch, 0)
+	for _, e := range r.events {
+		session, _ := r.sessions.FindByID(ctx, e.SessionID)
+		if session == nil || !viewer.CanView(session) {
+			continue
+		}
+		text := e.SearchText()
+		if domain.MatchesSearchTerms(text, terms) {
+			matches = append(matches, &domain.EventSearchMatch{
+				EventID:   e.ID,
+				SessionID: e.SessionID,
+				Text:      text,
+				CreatedAt: e.CreatedAt,
+			})
+		}
+	}
+
+	// Most recent first
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].CreatedAt.After(matches[j].CreatedAt)
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+func (r *EventRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, e := range r.events {
+		if e.SessionID != sessionID {
+			continue
+		}
+		if e.UUID != "" {
+			delete(r.uuidIndex, e.SessionID+":"+e.UUID)
+		}
+		delete(r.events, id)
+	}
+	return nil
+}
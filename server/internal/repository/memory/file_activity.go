@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+type FileActivityRepository struct {
+	mu         sync.RWMutex
+	activities []*domain.FileActivity // in insertion order, which breaks ties between equal times
+}
+
+func NewFileActivityRepository() *FileActivityRepository {
+	return &FileActivityRepository{}
+}
+
+func (r *FileActivityRepository) Create(ctx context.Context, activity *domain.FileActivity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if activity.ID == "" {
+		activity.ID = uuid.New().String()
+	}
+	if activity.CreatedAt.IsZero() {
+		activity.CreatedAt = time.Now()
+	}
+
+	r.activities = append(r.activities, activity)
+	return nil
+}
+
+func (r *FileActivityRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*domain.FileActivity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	activities := make([]*domain.FileActivity, 0)
+	for _, a := range r.activities {
+		if a.SessionID == sessionID {
+			activities = append(activities, a)
+		}
+	}
+
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].CreatedAt.Before(activities[j].CreatedAt)
+	})
+
+	return activities, nil
+}
+
+func (r *FileActivityRepository) FindByProjectIDAndPath(ctx context.Context, projectID, path string) ([]*domain.FileActivity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	activities := make([]*domain.FileActivity, 0)
+	for _, a := range r.activities {
+		if a.ProjectID == projectID && a.Path == path {
+			activities = append(activities, a)
+		}
+	}
+
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].CreatedAt.After(activities[j].CreatedAt)
+	})
+
+	return activities, nil
+}
+
+func (r *FileActivityRepository) UpdateProjectIDBySessionID(ctx context.Context, sessionID string, projectID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, a := range r.activities {
+		if a.SessionID == sessionID {
+			a.ProjectID = projectID
+		}
+	}
+	return nil
+}
+
+func (r *FileActivityRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.activities[:0]
+	for _, a := range r.activities {
+		if a.SessionID != sessionID {
+			kept = append(kept, a)
+		}
+	}
+	r.activities = kept
+	return nil
+}
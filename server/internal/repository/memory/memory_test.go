@@ -22,8 +22,10 @@ func TestSessionRepository(t *testing.T) {
 }
 
 func TestEventRepository(t *testing.T) {
+	sessions := NewSessionRepository()
 	s := &testsuite.EventRepositorySuite{
-		Repo: NewEventRepository(),
+		Repo:        NewEventRepository(sessions),
+		SessionRepo: sessions,
 	}
 	suite.Run(t, s)
 }
@@ -83,3 +85,38 @@ func TestUserFavoriteRepository(t *testing.T) {
 	}
 	suite.Run(t, s)
 }
+
+func TestTeamRepository(t *testing.T) {
+	s := &testsuite.TeamRepositorySuite{
+		Repo: NewTeamRepository(),
+	}
+	suite.Run(t, s)
+}
+
+func TestTeamMembershipRepository(t *testing.T) {
+	s := &testsuite.TeamMembershipRepositorySuite{
+		Repo: NewTeamMembershipRepository(),
+	}
+	suite.Run(t, s)
+}
+
+func TestFileActivityRepository(t *testing.T) {
+	s := &testsuite.FileActivityRepositorySuite{
+		Repo: NewFileActivityRepository(),
+	}
+	suite.Run(t, s)
+}
+
+func TestToolCallRepository(t *testing.T) {
+	s := &testsuite.ToolCallRepositorySuite{
+		Repo: NewToolCallRepository(),
+	}
+	suite.Run(t, s)
+}
+
+func TestAnalyticsRepository(t *testing.T) {
+	s := &testsuite.AnalyticsRepositorySuite{
+		Repo: NewAnalyticsRepository(),
+	}
+	suite.Run(t, s)
+}
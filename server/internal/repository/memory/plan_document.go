@@ -43,8 +43,11 @@ func (r *PlanDocumentRepository) Create(ctx context.Context, doc *domain.PlanDoc
 	if doc.ProjectID == "" {
 		doc.ProjectID = domain.DefaultProjectID
 	}
+	doc.Revision = 1
 
-	r.documents[doc.ID] = doc
+	// Store a copy so callers cannot modify stored documents without Update
+	stored := *doc
+	r.documents[doc.ID] = &stored
 	return nil
 }
 
@@ -56,7 +59,8 @@ func (r *PlanDocumentRepository) FindByID(ctx context.Context, id string) (*doma
 	if !ok {
 		return nil, nil
 	}
-	return doc, nil
+	found := *doc
+	return &found, nil
 }
 
 func (r *PlanDocumentRepository) Find(ctx context.Context, query domain.PlanDocumentQuery) ([]*domain.PlanDocument, string, error) {
@@ -81,6 +85,11 @@ func (r *PlanDocumentRepository) Find(ctx context.Context, query domain.PlanDocu
 		}
 	}
 
+	excludedProjects := make(map[string]bool, len(query.ExcludeProjectIDs))
+	for _, id := range query.ExcludeProjectIDs {
+		excludedProjects[id] = true
+	}
+
 	// Prepare description filter
 	lowerDescFilter := strings.ToLower(query.DescriptionContains)
 
@@ -99,11 +108,15 @@ func (r *PlanDocumentRepository) Find(ctx context.Context, query domain.PlanDocu
 		if query.ProjectID != "" && d.ProjectID != query.ProjectID {
 			continue
 		}
+		if excludedProjects[d.ProjectID] {
+			continue
+		}
 		// Check description filter (case-insensitive)
 		if lowerDescFilter != "" && !strings.Contains(strings.ToLower(d.Description), lowerDescFilter) {
 			continue
 		}
-		docs = append(docs, d)
+		found := *d
+		docs = append(docs, &found)
 	}
 
 	// Sort by specified field descending (newest first)
@@ -156,12 +169,15 @@ func (r *PlanDocumentRepository) Update(ctx context.Context, doc *domain.PlanDoc
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, ok := r.documents[doc.ID]; !ok {
-		return nil
+	existing, ok := r.documents[doc.ID]
+	if !ok || existing.Revision != doc.Revision {
+		return repository.ErrRevisionConflict
 	}
 
+	doc.Revision++
 	doc.UpdatedAt = time.Now()
-	r.documents[doc.ID] = doc
+	stored := *doc
+	r.documents[doc.ID] = &stored
 	return nil
 }
 
@@ -173,16 +189,17 @@ func (r *PlanDocumentRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (r *PlanDocumentRepository) SetStatus(ctx context.Context, id string, status domain.PlanDocumentStatus) error {
+func (r *PlanDocumentRepository) SetStatus(ctx context.Context, id string, status domain.PlanDocumentStatus, expectedRevision int) (int, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	doc, ok := r.documents[id]
-	if !ok {
-		return nil
+	if !ok || doc.Revision != expectedRevision {
+		return 0, repository.ErrRevisionConflict
 	}
 
 	doc.Status = status
+	doc.Revision++
 	doc.UpdatedAt = time.Now()
-	return nil
+	return doc.Revision, nil
 }
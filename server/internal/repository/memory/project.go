@@ -147,3 +147,28 @@ func (r *ProjectRepository) FindAll(ctx context.Context, limit int, cursor strin
 func (r *ProjectRepository) GetDefaultProject(ctx context.Context) (*domain.Project, error) {
 	return r.FindByID(ctx, domain.DefaultProjectID)
 }
+
+func (r *ProjectRepository) FindTeamOwned(ctx context.Context) ([]*domain.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var projects []*domain.Project
+	for _, p := range r.projects {
+		if p.TeamID != nil {
+			projects = append(projects, p)
+		}
+	}
+	return projects, nil
+}
+
+func (r *ProjectRepository) UpdateTeamID(ctx context.Context, id string, teamID *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	project, ok := r.projects[id]
+	if !ok {
+		return nil
+	}
+	project.TeamID = teamID
+	return nil
+}
@@ -3,10 +3,11 @@ package memory
 import "github.com/satetsu888/agentrace/server/internal/repository"
 
 func NewRepositories() *repository.Repositories {
+	sessions := NewSessionRepository()
 	return &repository.Repositories{
 		Project:            NewProjectRepository(),
-		Session:            NewSessionRepository(),
-		Event:              NewEventRepository(),
+		Session:            sessions,
+		Event:              NewEventRepository(sessions),
 		User:               NewUserRepository(),
 		APIKey:             NewAPIKeyRepository(),
 		WebSession:         NewWebSessionRepository(),
@@ -15,5 +16,11 @@ func NewRepositories() *repository.Repositories {
 		PlanDocument:       NewPlanDocumentRepository(),
 		PlanDocumentEvent:  NewPlanDocumentEventRepository(),
 		UserFavorite:       NewUserFavoriteRepository(),
+		Team:               NewTeamRepository(),
+		TeamMembership:     NewTeamMembershipRepository(),
+		Analytics:          NewAnalyticsRepository(),
+		FileActivity:       NewFileActivityRepository(),
+		ToolCall:           NewToolCallRepository(),
+		Tx:                 repository.NoTransaction{},
 	}
 }
@@ -41,6 +41,9 @@ func (r *SessionRepository) Create(ctx context.Context, session *domain.Session)
 	if session.ProjectID == "" {
 		session.ProjectID = domain.DefaultProjectID
 	}
+	if session.Visibility == "" {
+		session.Visibility = domain.DefaultSessionVisibility
+	}
 
 	r.sessions[session.ID] = session
 	return nil
@@ -69,13 +72,15 @@ func (r *SessionRepository) FindByClaudeSessionID(ctx context.Context, claudeSes
 	return nil, nil
 }
 
-func (r *SessionRepository) FindAll(ctx context.Context, limit int, cursor string, sortBy string) ([]*domain.Session, string, error) {
+func (r *SessionRepository) FindAll(ctx context.Context, viewer domain.SessionViewer, state domain.SessionState, limit int, cursor string, sortBy string) ([]*domain.Session, string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	sessions := make([]*domain.Session, 0, len(r.sessions))
 	for _, s := range r.sessions {
-		sessions = append(sessions, s)
+		if viewer.CanView(s) && (state == "" || s.State() == state) {
+			sessions = append(sessions, s)
+		}
 	}
 
 	// Sort by specified field descending (newest first)
@@ -124,13 +129,13 @@ func (r *SessionRepository) FindAll(ctx context.Context, limit int, cursor strin
 	return sessions, nextCursor, nil
 }
 
-func (r *SessionRepository) FindByProjectID(ctx context.Context, projectID string, limit int, cursor string, sortBy string) ([]*domain.Session, string, error) {
+func (r *SessionRepository) FindByProjectID(ctx context.Context, projectID string, viewer domain.SessionViewer, state domain.SessionState, limit int, cursor string, sortBy string) ([]*domain.Session, string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	sessions := make([]*domain.Session, 0)
 	for _, s := range r.sessions {
-		if s.ProjectID == projectID {
+		if s.ProjectID == projectID && viewer.CanView(s) && (state == "" || s.State() == state) {
 			sessions = append(sessions, s)
 		}
 	}
@@ -206,11 +211,34 @@ func (r *SessionRepository) FindOrCreateByClaudeSessionID(ctx context.Context, c
 		StartedAt:       now,
 		UpdatedAt:       now,
 		CreatedAt:       now,
+		Visibility:      domain.DefaultSessionVisibility,
 	}
 	r.sessions[session.ID] = session
 	return session, nil
 }
 
+func (r *SessionRepository) FindByParentSessionID(ctx context.Context, parentSessionID string) ([]*domain.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sessions := make([]*domain.Session, 0)
+	for _, s := range r.sessions {
+		if s.ParentSessionID != nil && *s.ParentSessionID == parentSessionID {
+			sessions = append(sessions, s)
+		}
+	}
+
+	// Oldest first
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].StartedAt.Equal(sessions[j].StartedAt) {
+			return sessions[i].ID < sessions[j].ID
+		}
+		return sessions[i].StartedAt.Before(sessions[j].StartedAt)
+	})
+
+	return sessions, nil
+}
+
 func (r *SessionRepository) UpdateUserID(ctx context.Context, id string, userID string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -282,3 +310,95 @@ func (r *SessionRepository) UpdateUpdatedAt(ctx context.Context, id string, upda
 	session.UpdatedAt = updatedAt
 	return nil
 }
+
+func (r *SessionRepository) UpdateStartedAt(ctx context.Context, id string, startedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil
+	}
+	session.StartedAt = startedAt
+	return nil
+}
+
+func (r *SessionRepository) UpdateParentSessionID(ctx context.Context, id string, parentSessionID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil
+	}
+	session.ParentSessionID = &parentSessionID
+	return nil
+}
+
+func (r *SessionRepository) UpdateVisibility(ctx context.Context, id string, visibility domain.SessionVisibility) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil
+	}
+	session.Visibility = visibility
+	return nil
+}
+
+func (r *SessionRepository) MarkEnded(ctx context.Context, id string, endedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil
+	}
+	session.EndedAt = &endedAt
+	return nil
+}
+
+func (r *SessionRepository) AddRedactionCount(ctx context.Context, id string, count int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil
+	}
+	session.RedactionCount += count
+	return nil
+}
+
+func (r *SessionRepository) AddSubagentCount(ctx context.Context, id string, count int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil
+	}
+	session.SubagentCount += count
+	return nil
+}
+
+func (r *SessionRepository) AddUsage(ctx context.Context, id string, usage domain.TokenUsage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil
+	}
+	session.Usage.Add(usage)
+	return nil
+}
+
+func (r *SessionRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.sessions, id)
+	return nil
+}
@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+type TeamRepository struct {
+	mu    sync.RWMutex
+	teams map[string]*domain.Team
+}
+
+func NewTeamRepository() *TeamRepository {
+	return &TeamRepository{
+		teams: make(map[string]*domain.Team),
+	}
+}
+
+func (r *TeamRepository) Create(ctx context.Context, team *domain.Team) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if team.ID == "" {
+		team.ID = uuid.New().String()
+	}
+	if team.CreatedAt.IsZero() {
+		team.CreatedAt = time.Now()
+	}
+
+	r.teams[team.ID] = team
+	return nil
+}
+
+func (r *TeamRepository) FindByID(ctx context.Context, id string) (*domain.Team, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	team, ok := r.teams[id]
+	if !ok {
+		return nil, nil
+	}
+	return team, nil
+}
+
+func (r *TeamRepository) FindAll(ctx context.Context) ([]*domain.Team, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	teams := make([]*domain.Team, 0, len(r.teams))
+	for _, t := range r.teams {
+		teams = append(teams, t)
+	}
+
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].Name < teams[j].Name
+	})
+
+	return teams, nil
+}
+
+func (r *TeamRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.teams, id)
+	return nil
+}
+
+type TeamMembershipRepository struct {
+	mu          sync.RWMutex
+	memberships []*domain.TeamMembership
+}
+
+func NewTeamMembershipRepository() *TeamMembershipRepository {
+	return &TeamMembershipRepository{}
+}
+
+func (r *TeamMembershipRepository) Create(ctx context.Context, membership *domain.TeamMembership) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, m := range r.memberships {
+		if m.TeamID == membership.TeamID && m.UserID == membership.UserID {
+			return nil
+		}
+	}
+
+	if membership.CreatedAt.IsZero() {
+		membership.CreatedAt = time.Now()
+	}
+
+	r.memberships = append(r.memberships, membership)
+	return nil
+}
+
+func (r *TeamMembershipRepository) Delete(ctx context.Context, teamID string, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.remove(func(m *domain.TeamMembership) bool {
+		return m.TeamID == teamID && m.UserID == userID
+	})
+	return nil
+}
+
+func (r *TeamMembershipRepository) DeleteByTeamID(ctx context.Context, teamID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.remove(func(m *domain.TeamMembership) bool {
+		return m.TeamID == teamID
+	})
+	return nil
+}
+
+func (r *TeamMembershipRepository) FindByTeamID(ctx context.Context, teamID string) ([]*domain.TeamMembership, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var memberships []*domain.TeamMembership
+	for _, m := range r.memberships {
+		if m.TeamID == teamID {
+			memberships = append(memberships, m)
+		}
+	}
+	return memberships, nil
+}
+
+func (r *TeamMembershipRepository) FindByUserID(ctx context.Context, userID string) ([]*domain.TeamMembership, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var memberships []*domain.TeamMembership
+	for _, m := range r.memberships {
+		if m.UserID == userID {
+			memberships = append(memberships, m)
+		}
+	}
+	return memberships, nil
+}
+
+// remove drops the memberships matching the predicate; the caller holds the lock
+func (r *TeamMembershipRepository) remove(match func(*domain.TeamMembership) bool) {
+	kept := r.memberships[:0]
+	for _, m := range r.memberships {
+		if !match(m) {
+			kept = append(kept, m)
+		}
+	}
+	r.memberships = kept
+}
@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+type ToolCallRepository struct {
+	mu    sync.RWMutex
+	calls map[string]*domain.ToolCall // "session_id:tool_use_id" -> call
+}
+
+func NewToolCallRepository() *ToolCallRepository {
+	return &ToolCallRepository{
+		calls: make(map[string]*domain.ToolCall),
+	}
+}
+
+func (r *ToolCallRepository) Create(ctx context.Context, call *domain.ToolCall) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := call.SessionID + ":" + call.ToolUseID
+	if _, exists := r.calls[key]; exists {
+		return nil
+	}
+	if call.ID == "" {
+		call.ID = uuid.New().String()
+	}
+	if call.StartedAt.IsZero() {
+		call.StartedAt = time.Now()
+	}
+
+	r.calls[key] = call
+	return nil
+}
+
+func (r *ToolCallRepository) Complete(ctx context.Context, sessionID, toolUseID string, isError bool, completedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if call, ok := r.calls[sessionID+":"+toolUseID]; ok && call.CompletedAt == nil {
+		call.IsError = isError
+		call.CompletedAt = &completedAt
+	}
+	return nil
+}
+
+func (r *ToolCallRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*domain.ToolCall, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	calls := make([]*domain.ToolCall, 0)
+	for _, c := range r.calls {
+		if c.SessionID == sessionID {
+			calls = append(calls, c)
+		}
+	}
+	sortToolCalls(calls)
+	return calls, nil
+}
+
+func (r *ToolCallRepository) FindByProjectID(ctx context.Context, projectID string, from, to time.Time) ([]*domain.ToolCall, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	calls := make([]*domain.ToolCall, 0)
+	for _, c := range r.calls {
+		if c.ProjectID != projectID {
+			continue
+		}
+		if (!from.IsZero() && c.StartedAt.Before(from)) || (!to.IsZero() && !c.StartedAt.Before(to)) {
+			continue
+		}
+		calls = append(calls, c)
+	}
+	sortToolCalls(calls)
+	return calls, nil
+}
+
+func (r *ToolCallRepository) UpdateProjectIDBySessionID(ctx context.Context, sessionID string, projectID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range r.calls {
+		if c.SessionID == sessionID {
+			c.ProjectID = projectID
+		}
+	}
+	return nil
+}
+
+func (r *ToolCallRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, c := range r.calls {
+		if c.SessionID == sessionID {
+			delete(r.calls, key)
+		}
+	}
+	return nil
+}
+
+// sortToolCalls sorts calls oldest first
+func sortToolCalls(calls []*domain.ToolCall) {
+	sort.Slice(calls, func(i, j int) bool {
+		if !calls[i].StartedAt.Equal(calls[j].StartedAt) {
+			return calls[i].StartedAt.Before(calls[j].StartedAt)
+		}
+		return calls[i].ToolUseID < calls[j].ToolUseID
+	})
+}
@@ -30,6 +30,9 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	if user.CreatedAt.IsZero() {
 		user.CreatedAt = time.Now()
 	}
+	if user.Role == "" {
+		user.Role = domain.DefaultUserRole
+	}
 
 	r.users[user.ID] = user
 	return nil
@@ -80,3 +83,39 @@ func (r *UserRepository) UpdateDisplayName(ctx context.Context, id string, displ
 	user.DisplayName = displayName
 	return nil
 }
+
+func (r *UserRepository) UpdateDefaultSessionVisibility(ctx context.Context, id string, visibility domain.SessionVisibility) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil
+	}
+	user.DefaultSessionVisibility = visibility
+	return nil
+}
+
+func (r *UserRepository) UpdateRole(ctx context.Context, id string, role domain.UserRole) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil
+	}
+	user.Role = role
+	return nil
+}
+
+func (r *UserRepository) UpdateDisabled(ctx context.Context, id string, disabled bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil
+	}
+	user.Disabled = disabled
+	return nil
+}
@@ -55,6 +55,18 @@ func (r *WebSessionRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+func (r *WebSessionRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, s := range r.sessions {
+		if s.UserID == userID {
+			delete(r.sessions, id)
+		}
+	}
+	return nil
+}
+
 func (r *WebSessionRepository) DeleteExpired(ctx context.Context) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -25,25 +25,39 @@ func (r *APIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error
 		key.CreatedAt = time.Now()
 	}
 
+	// Use sql.NullString so legacy keys without lookup ID stay out of the unique index
+	var lookupID sql.NullString
+	if key.LookupID != "" {
+		lookupID = sql.NullString{String: key.LookupID, Valid: true}
+	}
+
 	_, err := r.db.ExecContext(ctx,
-		`INSERT INTO api_keys (id, user_id, name, key_hash, key_prefix, last_used_at, created_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
-		key.ID, key.UserID, key.Name, key.KeyHash, key.KeyPrefix, key.LastUsedAt, key.CreatedAt,
+		`INSERT INTO api_keys (id, user_id, name, lookup_id, key_hash, key_prefix, scopes, expires_at, last_used_at, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		key.ID, key.UserID, key.Name, lookupID, key.KeyHash, key.KeyPrefix, domain.FormatAPIKeyScopes(key.Scopes), key.ExpiresAt, key.LastUsedAt, key.CreatedAt,
 	)
 	return err
 }
 
 func (r *APIKeyRepository) FindByKeyHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
 	return r.scanKey(r.db.QueryRowContext(ctx,
-		`SELECT id, user_id, name, key_hash, key_prefix, last_used_at, created_at
+		`SELECT id, user_id, name, lookup_id, key_hash, key_prefix, scopes, expires_at, last_used_at, created_at
 		 FROM api_keys WHERE key_hash = $1`,
 		keyHash,
 	))
 }
 
+func (r *APIKeyRepository) FindByLookupID(ctx context.Context, lookupID string) (*domain.APIKey, error) {
+	return r.scanKey(r.db.QueryRowContext(ctx,
+		`SELECT id, user_id, name, lookup_id, key_hash, key_prefix, scopes, expires_at, last_used_at, created_at
+		 FROM api_keys WHERE lookup_id = $1`,
+		lookupID,
+	))
+}
+
 func (r *APIKeyRepository) FindByUserID(ctx context.Context, userID string) ([]*domain.APIKey, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, user_id, name, key_hash, key_prefix, last_used_at, created_at
+		`SELECT id, user_id, name, lookup_id, key_hash, key_prefix, scopes, expires_at, last_used_at, created_at
 		 FROM api_keys WHERE user_id = $1 ORDER BY created_at DESC`,
 		userID,
 	)
@@ -64,9 +78,31 @@ func (r *APIKeyRepository) FindByUserID(ctx context.Context, userID string) ([]*
 	return keys, rows.Err()
 }
 
+func (r *APIKeyRepository) FindLegacy(ctx context.Context) ([]*domain.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, user_id, name, lookup_id, key_hash, key_prefix, scopes, expires_at, last_used_at, created_at
+		 FROM api_keys WHERE lookup_id IS NULL`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*domain.APIKey
+	for rows.Next() {
+		key, err := r.scanKeyFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
 func (r *APIKeyRepository) FindByID(ctx context.Context, id string) (*domain.APIKey, error) {
 	return r.scanKey(r.db.QueryRowContext(ctx,
-		`SELECT id, user_id, name, key_hash, key_prefix, last_used_at, created_at
+		`SELECT id, user_id, name, lookup_id, key_hash, key_prefix, scopes, expires_at, last_used_at, created_at
 		 FROM api_keys WHERE id = $1`,
 		id,
 	))
@@ -85,11 +121,21 @@ func (r *APIKeyRepository) UpdateLastUsedAt(ctx context.Context, id string) erro
 	return err
 }
 
+func (r *APIKeyRepository) UpdateLookupID(ctx context.Context, id string, lookupID string, keyHash string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE api_keys SET lookup_id = $1, key_hash = $2 WHERE id = $3`,
+		lookupID, keyHash, id,
+	)
+	return err
+}
+
 func (r *APIKeyRepository) scanKey(row *sql.Row) (*domain.APIKey, error) {
 	var key domain.APIKey
-	var lastUsedAt sql.NullTime
+	var scopes string
+	var lookupID sql.NullString
+	var expiresAt, lastUsedAt sql.NullTime
 
-	err := row.Scan(&key.ID, &key.UserID, &key.Name, &key.KeyHash, &key.KeyPrefix, &lastUsedAt, &key.CreatedAt)
+	err := row.Scan(&key.ID, &key.UserID, &key.Name, &lookupID, &key.KeyHash, &key.KeyPrefix, &scopes, &expiresAt, &lastUsedAt, &key.CreatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -97,6 +143,13 @@ func (r *APIKeyRepository) scanKey(row *sql.Row) (*domain.APIKey, error) {
 		return nil, err
 	}
 
+	if lookupID.Valid {
+		key.LookupID = lookupID.String
+	}
+	key.Scopes = domain.ParseAPIKeyScopes(scopes)
+	if expiresAt.Valid {
+		key.ExpiresAt = &expiresAt.Time
+	}
 	if lastUsedAt.Valid {
 		key.LastUsedAt = &lastUsedAt.Time
 	}
@@ -106,13 +159,22 @@ func (r *APIKeyRepository) scanKey(row *sql.Row) (*domain.APIKey, error) {
 
 func (r *APIKeyRepository) scanKeyFromRows(rows *sql.Rows) (*domain.APIKey, error) {
 	var key domain.APIKey
-	var lastUsedAt sql.NullTime
+	var scopes string
+	var lookupID sql.NullString
+	var expiresAt, lastUsedAt sql.NullTime
 
-	err := rows.Scan(&key.ID, &key.UserID, &key.Name, &key.KeyHash, &key.KeyPrefix, &lastUsedAt, &key.CreatedAt)
+	err := rows.Scan(&key.ID, &key.UserID, &key.Name, &lookupID, &key.KeyHash, &key.KeyPrefix, &scopes, &expiresAt, &lastUsedAt, &key.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
 
+	if lookupID.Valid {
+		key.LookupID = lookupID.String
+	}
+	key.Scopes = domain.ParseAPIKeyScopes(scopes)
+	if expiresAt.Valid {
+		key.ExpiresAt = &expiresAt.Time
+	}
 	if lastUsedAt.Valid {
 		key.LastUsedAt = &lastUsedAt.Time
 	}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -38,11 +40,25 @@ func (r *EventRepository) Create(ctx context.Context, event *domain.Event) error
 	if event.UUID != "" {
 		uuidValue = sql.NullString{String: event.UUID, Valid: true}
 	}
+	var parentUUID sql.NullString
+	if event.ParentUUID != "" {
+		parentUUID = sql.NullString{String: event.ParentUUID, Valid: true}
+	}
+	var raw sql.NullString
+	if event.Raw != nil {
+		raw = sql.NullString{String: string(event.Raw), Valid: true}
+	}
+
+	// Searchable text for full-text search (NULL when the event has none)
+	var searchText sql.NullString
+	if text := event.SearchText(); text != "" {
+		searchText = sql.NullString{String: text, Valid: true}
+	}
 
 	_, err = r.db.ExecContext(ctx,
-		`INSERT INTO events (id, session_id, uuid, event_type, payload, created_at)
-		 VALUES ($1, $2, $3, $4, $5, $6)`,
-		event.ID, event.SessionID, uuidValue, event.EventType, payloadJSON, event.CreatedAt,
+		`INSERT INTO events (id, session_id, uuid, parent_uuid, is_sidechain, event_type, payload, raw, search_text, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		event.ID, event.SessionID, uuidValue, parentUUID, event.IsSidechain, event.EventType, payloadJSON, raw, searchText, event.CreatedAt,
 	)
 	if err != nil {
 		// Check for UNIQUE constraint violation (duplicate uuid)
@@ -55,12 +71,105 @@ func (r *EventRepository) Create(ctx context.Context, event *domain.Event) error
 	return nil
 }
 
+// eventBatchSize is the number of rows per INSERT statement of CreateBatch
+const eventBatchSize = 100
+
+func (r *EventRepository) CreateBatch(ctx context.Context, events []*domain.Event) (*repository.EventBatchResult, error) {
+	result := &repository.EventBatchResult{Created: make([]*domain.Event, 0, len(events))}
+	err := r.db.RunInTx(ctx, func(ctx context.Context) error {
+		for start := 0; start < len(events); start += eventBatchSize {
+			created, err := r.insertBatch(ctx, events[start:min(start+eventBatchSize, len(events))])
+			if err != nil {
+				return err
+			}
+			result.Created = append(result.Created, created...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Duplicates = len(events) - len(result.Created)
+	return result, nil
+}
+
+// insertBatch stores events with one INSERT, skipping duplicate uuids, and returns the stored ones
+func (r *EventRepository) insertBatch(ctx context.Context, events []*domain.Event) ([]*domain.Event, error) {
+	values := make([]string, 0, len(events))
+	args := make([]interface{}, 0, len(events)*9)
+	for _, event := range events {
+		if event.ID == "" {
+			event.ID = uuid.New().String()
+		}
+		if event.CreatedAt.IsZero() {
+			event.CreatedAt = time.Now()
+		}
+
+		payloadJSON, err := json.Marshal(event.Payload)
+		if err != nil {
+			return nil, err
+		}
+
+		var uuidValue sql.NullString
+		if event.UUID != "" {
+			uuidValue = sql.NullString{String: event.UUID, Valid: true}
+		}
+		var parentUUID sql.NullString
+		if event.ParentUUID != "" {
+			parentUUID = sql.NullString{String: event.ParentUUID, Valid: true}
+		}
+		var raw sql.NullString
+		if event.Raw != nil {
+			raw = sql.NullString{String: string(event.Raw), Valid: true}
+		}
+		var searchText sql.NullString
+		if text := event.SearchText(); text != "" {
+			searchText = sql.NullString{String: text, Valid: true}
+		}
+
+		n := len(args)
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9, n+10))
+		args = append(args, event.ID, event.SessionID, uuidValue, parentUUID, event.IsSidechain, event.EventType, payloadJSON, raw, searchText, event.CreatedAt)
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`INSERT INTO events (id, session_id, uuid, parent_uuid, is_sidechain, event_type, payload, raw, search_text, created_at)
+		 VALUES `+strings.Join(values, ", ")+`
+		 ON CONFLICT DO NOTHING
+		 RETURNING id`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	inserted := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		inserted[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	created := make([]*domain.Event, 0, len(inserted))
+	for _, event := range events {
+		if inserted[event.ID] {
+			created = append(created, event)
+		}
+	}
+	return created, nil
+}
+
 func (r *EventRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*domain.Event, error) {
-	// Order by payload->>'timestamp' if available, otherwise by created_at
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, session_id, uuid, event_type, payload, created_at
+		`SELECT id, session_id, uuid, parent_uuid, is_sidechain, event_type, payload, raw, created_at
 		 FROM events WHERE session_id = $1
-		 ORDER BY COALESCE(payload->>'timestamp', created_at::text) ASC`,
+		 ORDER BY created_at ASC`,
 		sessionID,
 	)
 	if err != nil {
@@ -77,15 +186,18 @@ func (r *EventRepository) FindBySessionID(ctx context.Context, sessionID string)
 		events = append(events, event)
 	}
 
+	// Events stored before created_at carried the line's timestamp are placed by their payload timestamp
+	domain.SortEventsByTime(events)
+
 	return events, rows.Err()
 }
 
 func (r *EventRepository) scanEvent(rows *sql.Rows) (*domain.Event, error) {
 	var event domain.Event
-	var uuidValue sql.NullString
+	var uuidValue, parentUUID, raw sql.NullString
 	var payloadBytes []byte
 
-	err := rows.Scan(&event.ID, &event.SessionID, &uuidValue, &event.EventType, &payloadBytes, &event.CreatedAt)
+	err := rows.Scan(&event.ID, &event.SessionID, &uuidValue, &parentUUID, &event.IsSidechain, &event.EventType, &payloadBytes, &raw, &event.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -93,6 +205,12 @@ func (r *EventRepository) scanEvent(rows *sql.Rows) (*domain.Event, error) {
 	if uuidValue.Valid {
 		event.UUID = uuidValue.String
 	}
+	if parentUUID.Valid {
+		event.ParentUUID = parentUUID.String
+	}
+	if raw.Valid {
+		event.Raw = []byte(raw.String)
+	}
 
 	if err := json.Unmarshal(payloadBytes, &event.Payload); err != nil {
 		// If unmarshal fails, use empty map
@@ -113,3 +231,82 @@ func (r *EventRepository) CountBySessionID(ctx context.Context, sessionID string
 	}
 	return count, nil
 }
+
+func (r *EventRepository) FindSessionIDsByUUID(ctx context.Context, eventUUID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT DISTINCT session_id FROM events WHERE uuid = $1`,
+		eventUUID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessionIDs []string
+	for rows.Next() {
+		var sessionID string
+		if err := rows.Scan(&sessionID); err != nil {
+			return nil, err
+		}
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+	return sessionIDs, rows.Err()
+}
+
+func (r *EventRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM events WHERE session_id = $1`, sessionID)
+	return err
+}
+
+// Search uses the GIN tsvector index on search_text, ordered by rank then recency.
+// Each term is matched as a word prefix.
+func (r *EventRepository) Search(ctx context.Context, query string, viewer domain.SessionViewer, limit int) ([]*domain.EventSearchMatch, error) {
+	tsq := tsQuery(domain.ParseSearchTerms(query))
+	if tsq == "" {
+		return []*domain.EventSearchMatch{}, nil
+	}
+
+	condition, viewerArgs := visibilityCondition(viewer, 2)
+	args := append([]any{tsq}, viewerArgs...)
+	args = append(args, limit)
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, session_id, search_text, created_at
+		 FROM events
+		 WHERE to_tsvector('simple', COALESCE(search_text, '')) @@ to_tsquery('simple', $1)
+		   AND session_id IN (SELECT id FROM sessions WHERE `+condition+`)
+		 ORDER BY ts_rank(to_tsvector('simple', COALESCE(search_text, '')), to_tsquery('simple', $1)) DESC, created_at DESC
+		 LIMIT `+fmt.Sprintf("$%d", len(args)),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := make([]*domain.EventSearchMatch, 0)
+	for rows.Next() {
+		var match domain.EventSearchMatch
+		if err := rows.Scan(&match.EventID, &match.SessionID, &match.Text, &match.CreatedAt); err != nil {
+			return nil, err
+		}
+		matches = append(matches, &match)
+	}
+
+	return matches, rows.Err()
+}
+
+var tsQueryWordSeparator = regexp.MustCompile(`[^\p{L}\p{N}_]+`)
+
+// tsQuery builds a to_tsquery expression requiring every word of every term as a prefix.
+// Operators and punctuation are stripped so user input cannot break the query syntax.
+func tsQuery(terms []string) string {
+	var words []string
+	for _, t := range terms {
+		for _, w := range tsQueryWordSeparator.Split(t, -1) {
+			if w != "" {
+				words = append(words, w+":*")
+			}
+		}
+	}
+	return strings.Join(words, " & ")
+}
@@ -37,25 +37,26 @@ func (r *PlanDocumentRepository) Create(ctx context.Context, doc *domain.PlanDoc
 	if doc.ProjectID == "" {
 		doc.ProjectID = domain.DefaultProjectID
 	}
+	doc.Revision = 1
 
 	_, err := r.db.ExecContext(ctx,
-		`INSERT INTO plan_documents (id, project_id, description, body, status, created_at, updated_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
-		doc.ID, doc.ProjectID, doc.Description, doc.Body, string(doc.Status), doc.CreatedAt, doc.UpdatedAt,
+		`INSERT INTO plan_documents (id, project_id, description, body, status, revision, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		doc.ID, doc.ProjectID, doc.Description, doc.Body, string(doc.Status), doc.Revision, doc.CreatedAt, doc.UpdatedAt,
 	)
 	return err
 }
 
 func (r *PlanDocumentRepository) FindByID(ctx context.Context, id string) (*domain.PlanDocument, error) {
 	return r.scanDocument(r.db.QueryRowContext(ctx,
-		`SELECT id, project_id, description, body, status, created_at, updated_at
+		`SELECT id, project_id, description, body, status, revision, created_at, updated_at
 		 FROM plan_documents WHERE id = $1`,
 		id,
 	))
 }
 
 func (r *PlanDocumentRepository) Find(ctx context.Context, query domain.PlanDocumentQuery) ([]*domain.PlanDocument, string, error) {
-	baseQuery := `SELECT id, project_id, description, body, status, created_at, updated_at FROM plan_documents`
+	baseQuery := `SELECT id, project_id, description, body, status, revision, created_at, updated_at FROM plan_documents`
 	var conditions []string
 	var args []any
 	paramIdx := 1
@@ -93,6 +94,16 @@ func (r *PlanDocumentRepository) Find(ctx context.Context, query domain.PlanDocu
 		paramIdx++
 	}
 
+	if len(query.ExcludeProjectIDs) > 0 {
+		placeholders := make([]string, len(query.ExcludeProjectIDs))
+		for i, id := range query.ExcludeProjectIDs {
+			placeholders[i] = fmt.Sprintf("$%d", paramIdx)
+			args = append(args, id)
+			paramIdx++
+		}
+		conditions = append(conditions, "project_id NOT IN ("+strings.Join(placeholders, ", ")+")")
+	}
+
 	if query.DescriptionContains != "" {
 		conditions = append(conditions, fmt.Sprintf("description ILIKE $%d", paramIdx))
 		args = append(args, "%"+query.DescriptionContains+"%")
@@ -161,14 +172,25 @@ func (r *PlanDocumentRepository) Find(ctx context.Context, query domain.PlanDocu
 }
 
 func (r *PlanDocumentRepository) Update(ctx context.Context, doc *domain.PlanDocument) error {
-	doc.UpdatedAt = time.Now()
+	updatedAt := time.Now()
 
-	_, err := r.db.ExecContext(ctx,
-		`UPDATE plan_documents SET project_id = $1, description = $2, body = $3, status = $4, updated_at = $5
-		 WHERE id = $6`,
-		doc.ProjectID, doc.Description, doc.Body, string(doc.Status), doc.UpdatedAt, doc.ID,
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE plan_documents SET project_id = $1, description = $2, body = $3, status = $4, revision = revision + 1, updated_at = $5
+		 WHERE id = $6 AND revision = $7`,
+		doc.ProjectID, doc.Description, doc.Body, string(doc.Status), updatedAt, doc.ID, doc.Revision,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return repository.ErrRevisionConflict
+	}
+
+	doc.Revision++
+	doc.UpdatedAt = updatedAt
+	return nil
 }
 
 func (r *PlanDocumentRepository) Delete(ctx context.Context, id string) error {
@@ -179,12 +201,20 @@ func (r *PlanDocumentRepository) Delete(ctx context.Context, id string) error {
 	return err
 }
 
-func (r *PlanDocumentRepository) SetStatus(ctx context.Context, id string, status domain.PlanDocumentStatus) error {
-	_, err := r.db.ExecContext(ctx,
-		`UPDATE plan_documents SET status = $1, updated_at = $2 WHERE id = $3`,
-		string(status), time.Now(), id,
+func (r *PlanDocumentRepository) SetStatus(ctx context.Context, id string, status domain.PlanDocumentStatus, expectedRevision int) (int, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE plan_documents SET status = $1, revision = revision + 1, updated_at = $2 WHERE id = $3 AND revision = $4`,
+		string(status), time.Now(), id, expectedRevision,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return 0, err
+	} else if n == 0 {
+		return 0, repository.ErrRevisionConflict
+	}
+	return expectedRevision + 1, nil
 }
 
 func (r *PlanDocumentRepository) scanDocument(row *sql.Row) (*domain.PlanDocument, error) {
@@ -193,7 +223,7 @@ func (r *PlanDocumentRepository) scanDocument(row *sql.Row) (*domain.PlanDocumen
 	var status string
 	var createdAt, updatedAt sql.NullTime
 
-	err := row.Scan(&doc.ID, &projectID, &doc.Description, &doc.Body, &status, &createdAt, &updatedAt)
+	err := row.Scan(&doc.ID, &projectID, &doc.Description, &doc.Body, &status, &doc.Revision, &createdAt, &updatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -223,7 +253,7 @@ func (r *PlanDocumentRepository) scanDocumentFromRows(rows *sql.Rows) (*domain.P
 	var status string
 	var createdAt, updatedAt sql.NullTime
 
-	err := rows.Scan(&doc.ID, &projectID, &doc.Description, &doc.Body, &status, &createdAt, &updatedAt)
+	err := rows.Scan(&doc.ID, &projectID, &doc.Description, &doc.Body, &status, &doc.Revision, &createdAt, &updatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -32,6 +32,8 @@ func testDB(t *testing.T) (*DB, func()) {
 			"plan_document_events",
 			"plan_documents",
 			"user_favorites",
+			"file_activities",
+			"tool_calls",
 			"events",
 			"sessions",
 			"web_sessions",
@@ -52,6 +54,8 @@ func testDB(t *testing.T) (*DB, func()) {
 		"plan_document_events",
 		"plan_documents",
 		"user_favorites",
+		"file_activities",
+		"tool_calls",
 		"events",
 		"sessions",
 		"web_sessions",
@@ -73,7 +77,8 @@ func TestProjectRepository(t *testing.T) {
 	defer cleanup()
 
 	s := &testsuite.ProjectRepositorySuite{
-		Repo: NewProjectRepository(db),
+		Repo:     NewProjectRepository(db),
+		TeamRepo: NewTeamRepository(db),
 	}
 	suite.Run(t, s)
 }
@@ -189,3 +194,74 @@ func TestUserFavoriteRepository(t *testing.T) {
 	}
 	suite.Run(t, s)
 }
+
+func TestTeamRepository(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	s := &testsuite.TeamRepositorySuite{
+		Repo: NewTeamRepository(db),
+	}
+	suite.Run(t, s)
+}
+
+func TestTeamMembershipRepository(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	s := &testsuite.TeamMembershipRepositorySuite{
+		Repo:     NewTeamMembershipRepository(db),
+		TeamRepo: NewTeamRepository(db),
+		UserRepo: NewUserRepository(db),
+	}
+	suite.Run(t, s)
+}
+
+func TestFileActivityRepository(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	s := &testsuite.FileActivityRepositorySuite{
+		Repo:        NewFileActivityRepository(db),
+		SessionRepo: NewSessionRepository(db),
+	}
+	suite.Run(t, s)
+}
+
+func TestToolCallRepository(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	s := &testsuite.ToolCallRepositorySuite{
+		Repo:        NewToolCallRepository(db),
+		SessionRepo: NewSessionRepository(db),
+	}
+	suite.Run(t, s)
+}
+
+func TestTransactor(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	s := &testsuite.TransactorSuite{
+		Tx:          db,
+		SessionRepo: NewSessionRepository(db),
+	}
+	suite.Run(t, s)
+}
+
+func TestAnalyticsRepository(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	s := &testsuite.AnalyticsRepositorySuite{
+		Repo:             NewAnalyticsRepository(db),
+		SessionRepo:      NewSessionRepository(db),
+		EventRepo:        NewEventRepository(db),
+		PlanDocRepo:      NewPlanDocumentRepository(db),
+		PlanDocEventRepo: NewPlanDocumentEventRepository(db),
+		ProjectRepo:      NewProjectRepository(db),
+		UserRepo:         NewUserRepository(db),
+	}
+	suite.Run(t, s)
+}
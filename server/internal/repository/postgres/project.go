@@ -28,16 +28,16 @@ func (r *ProjectRepository) Create(ctx context.Context, project *domain.Project)
 	}
 
 	_, err := r.db.ExecContext(ctx,
-		`INSERT INTO projects (id, canonical_git_repository, created_at)
-		 VALUES ($1, $2, $3)`,
-		project.ID, project.CanonicalGitRepository, project.CreatedAt,
+		`INSERT INTO projects (id, canonical_git_repository, team_id, created_at)
+		 VALUES ($1, $2, $3, $4)`,
+		project.ID, project.CanonicalGitRepository, project.TeamID, project.CreatedAt,
 	)
 	return err
 }
 
 func (r *ProjectRepository) FindByID(ctx context.Context, id string) (*domain.Project, error) {
 	return r.scanProject(r.db.QueryRowContext(ctx,
-		`SELECT id, canonical_git_repository, created_at
+		`SELECT id, canonical_git_repository, team_id, created_at
 		 FROM projects WHERE id = $1`,
 		id,
 	))
@@ -45,7 +45,7 @@ func (r *ProjectRepository) FindByID(ctx context.Context, id string) (*domain.Pr
 
 func (r *ProjectRepository) FindByCanonicalGitRepository(ctx context.Context, canonicalGitRepo string) (*domain.Project, error) {
 	return r.scanProject(r.db.QueryRowContext(ctx,
-		`SELECT id, canonical_git_repository, created_at
+		`SELECT id, canonical_git_repository, team_id, created_at
 		 FROM projects WHERE canonical_git_repository = $1`,
 		canonicalGitRepo,
 	))
@@ -85,7 +85,7 @@ func (r *ProjectRepository) FindOrCreateByCanonicalGitRepository(ctx context.Con
 }
 
 func (r *ProjectRepository) FindAll(ctx context.Context, limit int, cursor string) ([]*domain.Project, string, error) {
-	query := `SELECT id, canonical_git_repository, created_at
+	query := `SELECT id, canonical_git_repository, team_id, created_at
 		 FROM projects`
 
 	var args []any
@@ -145,11 +145,39 @@ func (r *ProjectRepository) GetDefaultProject(ctx context.Context) (*domain.Proj
 	return r.FindByID(ctx, domain.DefaultProjectID)
 }
 
+func (r *ProjectRepository) FindTeamOwned(ctx context.Context) ([]*domain.Project, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, canonical_git_repository, team_id, created_at
+		 FROM projects WHERE team_id IS NOT NULL`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []*domain.Project
+	for rows.Next() {
+		project, err := r.scanProjectFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+
+	return projects, rows.Err()
+}
+
+func (r *ProjectRepository) UpdateTeamID(ctx context.Context, id string, teamID *string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE projects SET team_id = $1 WHERE id = $2`, teamID, id)
+	return err
+}
+
 func (r *ProjectRepository) scanProject(row *sql.Row) (*domain.Project, error) {
 	var project domain.Project
+	var teamID sql.NullString
 	var createdAt sql.NullTime
 
-	err := row.Scan(&project.ID, &project.CanonicalGitRepository, &createdAt)
+	err := row.Scan(&project.ID, &project.CanonicalGitRepository, &teamID, &createdAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -157,6 +185,9 @@ func (r *ProjectRepository) scanProject(row *sql.Row) (*domain.Project, error) {
 		return nil, err
 	}
 
+	if teamID.Valid {
+		project.TeamID = &teamID.String
+	}
 	if createdAt.Valid {
 		project.CreatedAt = createdAt.Time
 	}
@@ -166,13 +197,17 @@ func (r *ProjectRepository) scanProject(row *sql.Row) (*domain.Project, error) {
 
 func (r *ProjectRepository) scanProjectFromRows(rows *sql.Rows) (*domain.Project, error) {
 	var project domain.Project
+	var teamID sql.NullString
 	var createdAt sql.NullTime
 
-	err := rows.Scan(&project.ID, &project.CanonicalGitRepository, &createdAt)
+	err := rows.Scan(&project.ID, &project.CanonicalGitRepository, &teamID, &createdAt)
 	if err != nil {
 		return nil, err
 	}
 
+	if teamID.Valid {
+		project.TeamID = &teamID.String
+	}
 	if createdAt.Valid {
 		project.CreatedAt = createdAt.Time
 	}
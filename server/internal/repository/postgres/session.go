@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -35,20 +36,24 @@ func (r *SessionRepository) Create(ctx context.Context, session *domain.Session)
 	if session.ProjectID == "" {
 		session.ProjectID = domain.DefaultProjectID
 	}
+	if session.Visibility == "" {
+		session.Visibility = domain.DefaultSessionVisibility
+	}
 
 	_, err := r.db.ExecContext(ctx,
-		`INSERT INTO sessions (id, user_id, project_id, claude_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
-		session.ID, session.UserID, session.ProjectID, session.ClaudeSessionID, session.ProjectPath,
+		`INSERT INTO sessions (id, user_id, project_id, claude_session_id, parent_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at, visibility)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		session.ID, session.UserID, session.ProjectID, session.ClaudeSessionID, session.ParentSessionID, session.ProjectPath,
 		session.GitBranch, session.Title,
-		session.StartedAt, session.EndedAt, session.UpdatedAt, session.CreatedAt,
+		session.StartedAt, session.EndedAt, session.UpdatedAt, session.CreatedAt, session.Visibility,
 	)
 	return err
 }
 
 func (r *SessionRepository) FindByID(ctx context.Context, id string) (*domain.Session, error) {
 	return r.scanSession(r.db.QueryRowContext(ctx,
-		`SELECT id, user_id, project_id, claude_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at
+		`SELECT id, user_id, project_id, claude_session_id, parent_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at, redaction_count, subagent_count,
+		       input_tokens, output_tokens, cache_read_tokens, cache_creation_tokens, estimated_cost_usd, visibility
 		 FROM sessions WHERE id = $1`,
 		id,
 	))
@@ -56,24 +61,27 @@ func (r *SessionRepository) FindByID(ctx context.Context, id string) (*domain.Se
 
 func (r *SessionRepository) FindByClaudeSessionID(ctx context.Context, claudeSessionID string) (*domain.Session, error) {
 	return r.scanSession(r.db.QueryRowContext(ctx,
-		`SELECT id, user_id, project_id, claude_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at
+		`SELECT id, user_id, project_id, claude_session_id, parent_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at, redaction_count, subagent_count,
+		       input_tokens, output_tokens, cache_read_tokens, cache_creation_tokens, estimated_cost_usd, visibility
 		 FROM sessions WHERE claude_session_id = $1`,
 		claudeSessionID,
 	))
 }
 
-func (r *SessionRepository) FindAll(ctx context.Context, limit int, cursor string, sortBy string) ([]*domain.Session, string, error) {
+func (r *SessionRepository) FindAll(ctx context.Context, viewer domain.SessionViewer, state domain.SessionState, limit int, cursor string, sortBy string) ([]*domain.Session, string, error) {
 	// Validate sortBy to prevent SQL injection
 	orderColumn := "updated_at"
 	if sortBy == "created_at" {
 		orderColumn = "created_at"
 	}
 
-	query := `SELECT id, user_id, project_id, claude_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at
-		 FROM sessions`
+	query := `SELECT id, user_id, project_id, claude_session_id, parent_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at, redaction_count, subagent_count,
+		       input_tokens, output_tokens, cache_read_tokens, cache_creation_tokens, estimated_cost_usd, visibility
+		 FROM sessions WHERE `
 
-	var args []any
-	paramIdx := 1
+	condition, args := visibilityCondition(viewer, 1)
+	query += condition + stateCondition(state)
+	paramIdx := len(args) + 1
 
 	// Apply cursor filter
 	if cursor != "" {
@@ -81,9 +89,9 @@ func (r *SessionRepository) FindAll(ctx context.Context, limit int, cursor strin
 		if cursorInfo != nil {
 			cursorTime, err := cursorInfo.ParseSortTime()
 			if err == nil {
-				query += fmt.Sprintf(` WHERE (%s < $1 OR (%s = $2 AND id < $3))`, orderColumn, orderColumn)
+				query += fmt.Sprintf(` AND (%s < $%d OR (%s = $%d AND id < $%d))`, orderColumn, paramIdx, orderColumn, paramIdx+1, paramIdx+2)
 				args = append(args, cursorTime, cursorTime, cursorInfo.ID)
-				paramIdx = 4
+				paramIdx += 3
 			}
 		}
 	}
@@ -131,18 +139,21 @@ func (r *SessionRepository) FindAll(ctx context.Context, limit int, cursor strin
 	return sessions, nextCursor, nil
 }
 
-func (r *SessionRepository) FindByProjectID(ctx context.Context, projectID string, limit int, cursor string, sortBy string) ([]*domain.Session, string, error) {
+func (r *SessionRepository) FindByProjectID(ctx context.Context, projectID string, viewer domain.SessionViewer, state domain.SessionState, limit int, cursor string, sortBy string) ([]*domain.Session, string, error) {
 	// Validate sortBy to prevent SQL injection
 	orderColumn := "updated_at"
 	if sortBy == "created_at" {
 		orderColumn = "created_at"
 	}
 
-	query := `SELECT id, user_id, project_id, claude_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at
-		 FROM sessions WHERE project_id = $1`
+	query := `SELECT id, user_id, project_id, claude_session_id, parent_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at, redaction_count, subagent_count,
+		       input_tokens, output_tokens, cache_read_tokens, cache_creation_tokens, estimated_cost_usd, visibility
+		 FROM sessions WHERE project_id = $1 AND `
 
-	args := []any{projectID}
-	paramIdx := 2
+	condition, viewerArgs := visibilityCondition(viewer, 2)
+	query += condition + stateCondition(state)
+	args := append([]any{projectID}, viewerArgs...)
+	paramIdx := len(args) + 1
 
 	// Apply cursor filter
 	if cursor != "" {
@@ -203,7 +214,8 @@ func (r *SessionRepository) FindByProjectID(ctx context.Context, projectID strin
 func (r *SessionRepository) FindOrCreateByClaudeSessionID(ctx context.Context, claudeSessionID string, userID *string) (*domain.Session, error) {
 	// First try to find existing session
 	session, err := r.scanSession(r.db.QueryRowContext(ctx,
-		`SELECT id, user_id, project_id, claude_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at
+		`SELECT id, user_id, project_id, claude_session_id, parent_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at, redaction_count, subagent_count,
+		       input_tokens, output_tokens, cache_read_tokens, cache_creation_tokens, estimated_cost_usd, visibility
 		 FROM sessions WHERE claude_session_id = $1`,
 		claudeSessionID,
 	))
@@ -244,6 +256,30 @@ func (r *SessionRepository) FindOrCreateByClaudeSessionID(ctx context.Context, c
 	return newSession, nil
 }
 
+func (r *SessionRepository) FindByParentSessionID(ctx context.Context, parentSessionID string) ([]*domain.Session, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, user_id, project_id, claude_session_id, parent_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at, redaction_count, subagent_count,
+		       input_tokens, output_tokens, cache_read_tokens, cache_creation_tokens, estimated_cost_usd, visibility
+		 FROM sessions WHERE parent_session_id = $1
+		 ORDER BY started_at ASC, id ASC`,
+		parentSessionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*domain.Session
+	for rows.Next() {
+		session, err := r.scanSessionFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
 func (r *SessionRepository) UpdateUserID(ctx context.Context, id string, userID string) error {
 	_, err := r.db.ExecContext(ctx,
 		`UPDATE sessions SET user_id = $1 WHERE id = $2`,
@@ -292,12 +328,112 @@ func (r *SessionRepository) UpdateUpdatedAt(ctx context.Context, id string, upda
 	return err
 }
 
+func (r *SessionRepository) UpdateStartedAt(ctx context.Context, id string, startedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET started_at = $1 WHERE id = $2`,
+		startedAt, id,
+	)
+	return err
+}
+
+func (r *SessionRepository) UpdateParentSessionID(ctx context.Context, id string, parentSessionID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET parent_session_id = $1 WHERE id = $2`,
+		parentSessionID, id,
+	)
+	return err
+}
+
+func (r *SessionRepository) UpdateVisibility(ctx context.Context, id string, visibility domain.SessionVisibility) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET visibility = $1 WHERE id = $2`,
+		visibility, id,
+	)
+	return err
+}
+
+func (r *SessionRepository) MarkEnded(ctx context.Context, id string, endedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET ended_at = $1 WHERE id = $2`,
+		endedAt, id,
+	)
+	return err
+}
+
+func (r *SessionRepository) AddRedactionCount(ctx context.Context, id string, count int) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET redaction_count = redaction_count + $1 WHERE id = $2`,
+		count, id,
+	)
+	return err
+}
+
+func (r *SessionRepository) AddSubagentCount(ctx context.Context, id string, count int) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET subagent_count = subagent_count + $1 WHERE id = $2`,
+		count, id,
+	)
+	return err
+}
+
+func (r *SessionRepository) AddUsage(ctx context.Context, id string, usage domain.TokenUsage) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET input_tokens = input_tokens + $1, output_tokens = output_tokens + $2,
+		 cache_read_tokens = cache_read_tokens + $3, cache_creation_tokens = cache_creation_tokens + $4,
+		 estimated_cost_usd = estimated_cost_usd + $5 WHERE id = $6`,
+		usage.InputTokens, usage.OutputTokens, usage.CacheReadTokens, usage.CacheCreationTokens, usage.EstimatedCostUSD, id,
+	)
+	return err
+}
+
+func (r *SessionRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM sessions WHERE id = $1`, id)
+	return err
+}
+
+// visibilityCondition returns the WHERE condition limiting sessions to those
+// the viewer can read, numbering its placeholders from paramIdx
+func visibilityCondition(viewer domain.SessionViewer, paramIdx int) (string, []any) {
+	condition := `visibility = 'public'`
+	var args []any
+	if viewer.UserID != "" {
+		condition = `visibility IN ('public', 'team')`
+		args = append(args, viewer.UserID)
+	}
+	if len(viewer.HiddenProjectIDs) > 0 {
+		placeholders := make([]string, len(viewer.HiddenProjectIDs))
+		for i, id := range viewer.HiddenProjectIDs {
+			placeholders[i] = fmt.Sprintf("$%d", paramIdx+len(args))
+			args = append(args, id)
+		}
+		condition += ` AND project_id NOT IN (` + strings.Join(placeholders, ", ") + `)`
+	}
+	if viewer.UserID != "" {
+		// Owners always read their own sessions, even in projects of teams they are not in
+		condition = fmt.Sprintf(`(user_id = $%d OR (%s))`, paramIdx, condition)
+	}
+	return condition, args
+}
+
+// stateCondition returns the WHERE condition (with a leading AND) limiting
+// sessions to the given state, matching domain.Session.State
+func stateCondition(state domain.SessionState) string {
+	switch state {
+	case domain.SessionStateEnded:
+		return ` AND ended_at IS NOT NULL AND updated_at <= ended_at`
+	case domain.SessionStateActive:
+		return ` AND (ended_at IS NULL OR updated_at > ended_at)`
+	}
+	return ""
+}
+
 func (r *SessionRepository) scanSession(row *sql.Row) (*domain.Session, error) {
 	var session domain.Session
-	var userID, projectID, projectPath, gitBranch, title sql.NullString
+	var userID, projectID, parentSessionID, projectPath, gitBranch, title sql.NullString
 	var startedAt, endedAt, updatedAt, createdAt sql.NullTime
 
-	err := row.Scan(&session.ID, &userID, &projectID, &session.ClaudeSessionID, &projectPath, &gitBranch, &title, &startedAt, &endedAt, &updatedAt, &createdAt)
+	err := row.Scan(&session.ID, &userID, &projectID, &session.ClaudeSessionID, &parentSessionID, &projectPath, &gitBranch, &title, &startedAt, &endedAt, &updatedAt, &createdAt, &session.RedactionCount, &session.SubagentCount,
+		&session.Usage.InputTokens, &session.Usage.OutputTokens, &session.Usage.CacheReadTokens, &session.Usage.CacheCreationTokens, &session.Usage.EstimatedCostUSD, &session.Visibility)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -308,6 +444,9 @@ func (r *SessionRepository) scanSession(row *sql.Row) (*domain.Session, error) {
 	if userID.Valid {
 		session.UserID = &userID.String
 	}
+	if parentSessionID.Valid {
+		session.ParentSessionID = &parentSessionID.String
+	}
 	if projectID.Valid {
 		session.ProjectID = projectID.String
 	} else {
@@ -340,10 +479,11 @@ func (r *SessionRepository) scanSession(row *sql.Row) (*domain.Session, error) {
 
 func (r *SessionRepository) scanSessionFromRows(rows *sql.Rows) (*domain.Session, error) {
 	var session domain.Session
-	var userID, projectID, projectPath, gitBranch, title sql.NullString
+	var userID, projectID, parentSessionID, projectPath, gitBranch, title sql.NullString
 	var startedAt, endedAt, updatedAt, createdAt sql.NullTime
 
-	err := rows.Scan(&session.ID, &userID, &projectID, &session.ClaudeSessionID, &projectPath, &gitBranch, &title, &startedAt, &endedAt, &updatedAt, &createdAt)
+	err := rows.Scan(&session.ID, &userID, &projectID, &session.ClaudeSessionID, &parentSessionID, &projectPath, &gitBranch, &title, &startedAt, &endedAt, &updatedAt, &createdAt, &session.RedactionCount, &session.SubagentCount,
+		&session.Usage.InputTokens, &session.Usage.OutputTokens, &session.Usage.CacheReadTokens, &session.Usage.CacheCreationTokens, &session.Usage.EstimatedCostUSD, &session.Visibility)
 	if err != nil {
 		return nil, err
 	}
@@ -351,6 +491,9 @@ func (r *SessionRepository) scanSessionFromRows(rows *sql.Rows) (*domain.Session
 	if userID.Valid {
 		session.UserID = &userID.String
 	}
+	if parentSessionID.Valid {
+		session.ParentSessionID = &parentSessionID.String
+	}
 	if projectID.Valid {
 		session.ProjectID = projectID.String
 	} else {
@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+type TeamRepository struct {
+	db *DB
+}
+
+func NewTeamRepository(db *DB) *TeamRepository {
+	return &TeamRepository{db: db}
+}
+
+func (r *TeamRepository) Create(ctx context.Context, team *domain.Team) error {
+	if team.ID == "" {
+		team.ID = uuid.New().String()
+	}
+	if team.CreatedAt.IsZero() {
+		team.CreatedAt = time.Now()
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO teams (id, name, created_at) VALUES ($1, $2, $3)`,
+		team.ID, team.Name, team.CreatedAt,
+	)
+	return err
+}
+
+func (r *TeamRepository) FindByID(ctx context.Context, id string) (*domain.Team, error) {
+	var team domain.Team
+
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, name, created_at FROM teams WHERE id = $1`,
+		id,
+	).Scan(&team.ID, &team.Name, &team.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &team, nil
+}
+
+func (r *TeamRepository) FindAll(ctx context.Context) ([]*domain.Team, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, created_at FROM teams ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []*domain.Team
+	for rows.Next() {
+		var team domain.Team
+		if err := rows.Scan(&team.ID, &team.Name, &team.CreatedAt); err != nil {
+			return nil, err
+		}
+		teams = append(teams, &team)
+	}
+
+	return teams, rows.Err()
+}
+
+func (r *TeamRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM teams WHERE id = $1`, id)
+	return err
+}
+
+type TeamMembershipRepository struct {
+	db *DB
+}
+
+func NewTeamMembershipRepository(db *DB) *TeamMembershipRepository {
+	return &TeamMembershipRepository{db: db}
+}
+
+func (r *TeamMembershipRepository) Create(ctx context.Context, membership *domain.TeamMembership) error {
+	if membership.CreatedAt.IsZero() {
+		membership.CreatedAt = time.Now()
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO team_memberships (team_id, user_id, created_at) VALUES ($1, $2, $3)
+		 ON CONFLICT (team_id, user_id) DO NOTHING`,
+		membership.TeamID, membership.UserID, membership.CreatedAt,
+	)
+	return err
+}
+
+func (r *TeamMembershipRepository) Delete(ctx context.Context, teamID string, userID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM team_memberships WHERE team_id = $1 AND user_id = $2`,
+		teamID, userID,
+	)
+	return err
+}
+
+func (r *TeamMembershipRepository) DeleteByTeamID(ctx context.Context, teamID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM team_memberships WHERE team_id = $1`, teamID)
+	return err
+}
+
+func (r *TeamMembershipRepository) FindByTeamID(ctx context.Context, teamID string) ([]*domain.TeamMembership, error) {
+	return r.findMemberships(ctx,
+		`SELECT team_id, user_id, created_at FROM team_memberships WHERE team_id = $1 ORDER BY created_at, user_id`,
+		teamID,
+	)
+}
+
+func (r *TeamMembershipRepository) FindByUserID(ctx context.Context, userID string) ([]*domain.TeamMembership, error) {
+	return r.findMemberships(ctx,
+		`SELECT team_id, user_id, created_at FROM team_memberships WHERE user_id = $1 ORDER BY created_at, team_id`,
+		userID,
+	)
+}
+
+func (r *TeamMembershipRepository) findMemberships(ctx context.Context, query string, args ...any) ([]*domain.TeamMembership, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memberships []*domain.TeamMembership
+	for rows.Next() {
+		var membership domain.TeamMembership
+		if err := rows.Scan(&membership.TeamID, &membership.UserID, &membership.CreatedAt); err != nil {
+			return nil, err
+		}
+		memberships = append(memberships, &membership)
+	}
+
+	return memberships, rows.Err()
+}
@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+type ToolCallRepository struct {
+	db *DB
+}
+
+func NewToolCallRepository(db *DB) *ToolCallRepository {
+	return &ToolCallRepository{db: db}
+}
+
+func (r *ToolCallRepository) Create(ctx context.Context, call *domain.ToolCall) error {
+	if call.ID == "" {
+		call.ID = uuid.New().String()
+	}
+	if call.StartedAt.IsZero() {
+		call.StartedAt = time.Now()
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO tool_calls (id, session_id, project_id, event_id, tool_use_id, tool_name, is_error, started_at, completed_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		 ON CONFLICT (session_id, tool_use_id) DO NOTHING`,
+		call.ID, call.SessionID, call.ProjectID, call.EventID, call.ToolUseID, call.ToolName, call.IsError, call.StartedAt, call.CompletedAt,
+	)
+	return err
+}
+
+func (r *ToolCallRepository) Complete(ctx context.Context, sessionID, toolUseID string, isError bool, completedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE tool_calls SET is_error = $1, completed_at = $2
+		 WHERE session_id = $3 AND tool_use_id = $4 AND completed_at IS NULL`,
+		isError, completedAt, sessionID, toolUseID,
+	)
+	return err
+}
+
+func (r *ToolCallRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*domain.ToolCall, error) {
+	return r.query(ctx,
+		`SELECT id, session_id, project_id, event_id, tool_use_id, tool_name, is_error, started_at, completed_at
+		 FROM tool_calls WHERE session_id = $1
+		 ORDER BY started_at ASC`,
+		sessionID,
+	)
+}
+
+func (r *ToolCallRepository) FindByProjectID(ctx context.Context, projectID string, from, to time.Time) ([]*domain.ToolCall, error) {
+	conditions := []string{"project_id = $1"}
+	args := []interface{}{projectID}
+	if !from.IsZero() {
+		args = append(args, from)
+		conditions = append(conditions, fmt.Sprintf("started_at >= $%d", len(args)))
+	}
+	if !to.IsZero() {
+		args = append(args, to)
+		conditions = append(conditions, fmt.Sprintf("started_at < $%d", len(args)))
+	}
+
+	return r.query(ctx,
+		`SELECT id, session_id, project_id, event_id, tool_use_id, tool_name, is_error, started_at, completed_at
+		 FROM tool_calls WHERE `+strings.Join(conditions, " AND ")+`
+		 ORDER BY started_at ASC`,
+		args...,
+	)
+}
+
+func (r *ToolCallRepository) UpdateProjectIDBySessionID(ctx context.Context, sessionID string, projectID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE tool_calls SET project_id = $1 WHERE session_id = $2`,
+		projectID, sessionID,
+	)
+	return err
+}
+
+func (r *ToolCallRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM tool_calls WHERE session_id = $1`, sessionID)
+	return err
+}
+
+func (r *ToolCallRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.ToolCall, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	calls := make([]*domain.ToolCall, 0)
+	for rows.Next() {
+		call, err := r.scanToolCall(rows)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, call)
+	}
+	return calls, rows.Err()
+}
+
+func (r *ToolCallRepository) scanToolCall(rows *sql.Rows) (*domain.ToolCall, error) {
+	var call domain.ToolCall
+	var completedAt sql.NullTime
+
+	err := rows.Scan(&call.ID, &call.SessionID, &call.ProjectID, &call.EventID, &call.ToolUseID, &call.ToolName, &call.IsError, &call.StartedAt, &completedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if completedAt.Valid {
+		call.CompletedAt = &completedAt.Time
+	}
+
+	return &call, nil
+}
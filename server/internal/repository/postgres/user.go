@@ -24,10 +24,13 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	if user.CreatedAt.IsZero() {
 		user.CreatedAt = time.Now()
 	}
+	if user.Role == "" {
+		user.Role = domain.DefaultUserRole
+	}
 
 	_, err := r.db.ExecContext(ctx,
-		`INSERT INTO users (id, email, display_name, created_at) VALUES ($1, $2, $3, $4)`,
-		user.ID, user.Email, user.DisplayName, user.CreatedAt,
+		`INSERT INTO users (id, email, display_name, created_at, default_session_visibility, role, disabled) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		user.ID, user.Email, user.DisplayName, user.CreatedAt, user.DefaultSessionVisibility, user.Role, user.Disabled,
 	)
 	return err
 }
@@ -37,9 +40,9 @@ func (r *UserRepository) FindByID(ctx context.Context, id string) (*domain.User,
 	var displayName sql.NullString
 
 	err := r.db.QueryRowContext(ctx,
-		`SELECT id, email, display_name, created_at FROM users WHERE id = $1`,
+		`SELECT id, email, display_name, created_at, default_session_visibility, role, disabled FROM users WHERE id = $1`,
 		id,
-	).Scan(&user.ID, &user.Email, &displayName, &user.CreatedAt)
+	).Scan(&user.ID, &user.Email, &displayName, &user.CreatedAt, &user.DefaultSessionVisibility, &user.Role, &user.Disabled)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -57,9 +60,9 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*domain
 	var displayName sql.NullString
 
 	err := r.db.QueryRowContext(ctx,
-		`SELECT id, email, display_name, created_at FROM users WHERE email = $1`,
+		`SELECT id, email, display_name, created_at, default_session_visibility, role, disabled FROM users WHERE email = $1`,
 		email,
-	).Scan(&user.ID, &user.Email, &displayName, &user.CreatedAt)
+	).Scan(&user.ID, &user.Email, &displayName, &user.CreatedAt, &user.DefaultSessionVisibility, &user.Role, &user.Disabled)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -73,7 +76,7 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*domain
 }
 
 func (r *UserRepository) FindAll(ctx context.Context) ([]*domain.User, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT id, email, display_name, created_at FROM users ORDER BY created_at DESC`)
+	rows, err := r.db.QueryContext(ctx, `SELECT id, email, display_name, created_at, default_session_visibility, role, disabled FROM users ORDER BY created_at DESC`)
 	if err != nil {
 		return nil, err
 	}
@@ -83,7 +86,7 @@ func (r *UserRepository) FindAll(ctx context.Context) ([]*domain.User, error) {
 	for rows.Next() {
 		var user domain.User
 		var displayName sql.NullString
-		if err := rows.Scan(&user.ID, &user.Email, &displayName, &user.CreatedAt); err != nil {
+		if err := rows.Scan(&user.ID, &user.Email, &displayName, &user.CreatedAt, &user.DefaultSessionVisibility, &user.Role, &user.Disabled); err != nil {
 			return nil, err
 		}
 		user.DisplayName = displayName.String
@@ -100,3 +103,27 @@ func (r *UserRepository) UpdateDisplayName(ctx context.Context, id string, displ
 	)
 	return err
 }
+
+func (r *UserRepository) UpdateDefaultSessionVisibility(ctx context.Context, id string, visibility domain.SessionVisibility) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE users SET default_session_visibility = $1 WHERE id = $2`,
+		visibility, id,
+	)
+	return err
+}
+
+func (r *UserRepository) UpdateRole(ctx context.Context, id string, role domain.UserRole) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE users SET role = $1 WHERE id = $2`,
+		role, id,
+	)
+	return err
+}
+
+func (r *UserRepository) UpdateDisabled(ctx context.Context, id string, disabled bool) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE users SET disabled = $1 WHERE id = $2`,
+		disabled, id,
+	)
+	return err
+}
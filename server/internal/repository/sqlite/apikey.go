@@ -31,25 +31,45 @@ func (r *APIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error
 		lastUsedAt = &s
 	}
 
+	var expiresAt *string
+	if key.ExpiresAt != nil {
+		s := key.ExpiresAt.Format(time.RFC3339)
+		expiresAt = &s
+	}
+
+	// Use sql.NullString so legacy keys without lookup ID stay out of the unique index
+	var lookupID sql.NullString
+	if key.LookupID != "" {
+		lookupID = sql.NullString{String: key.LookupID, Valid: true}
+	}
+
 	_, err := r.db.ExecContext(ctx,
-		`INSERT INTO api_keys (id, user_id, name, key_hash, key_prefix, last_used_at, created_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
-		key.ID, key.UserID, key.Name, key.KeyHash, key.KeyPrefix, lastUsedAt, key.CreatedAt.Format(time.RFC3339),
+		`INSERT INTO api_keys (id, user_id, name, lookup_id, key_hash, key_prefix, scopes, expires_at, last_used_at, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		key.ID, key.UserID, key.Name, lookupID, key.KeyHash, key.KeyPrefix, domain.FormatAPIKeyScopes(key.Scopes), expiresAt, lastUsedAt, key.CreatedAt.Format(time.RFC3339),
 	)
 	return err
 }
 
 func (r *APIKeyRepository) FindByKeyHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
 	return r.scanKey(r.db.QueryRowContext(ctx,
-		`SELECT id, user_id, name, key_hash, key_prefix, last_used_at, created_at
+		`SELECT id, user_id, name, lookup_id, key_hash, key_prefix, scopes, expires_at, last_used_at, created_at
 		 FROM api_keys WHERE key_hash = ?`,
 		keyHash,
 	))
 }
 
+func (r *APIKeyRepository) FindByLookupID(ctx context.Context, lookupID string) (*domain.APIKey, error) {
+	return r.scanKey(r.db.QueryRowContext(ctx,
+		`SELECT id, user_id, name, lookup_id, key_hash, key_prefix, scopes, expires_at, last_used_at, created_at
+		 FROM api_keys WHERE lookup_id = ?`,
+		lookupID,
+	))
+}
+
 func (r *APIKeyRepository) FindByUserID(ctx context.Context, userID string) ([]*domain.APIKey, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, user_id, name, key_hash, key_prefix, last_used_at, created_at
+		`SELECT id, user_id, name, lookup_id, key_hash, key_prefix, scopes, expires_at, last_used_at, created_at
 		 FROM api_keys WHERE user_id = ? ORDER BY created_at DESC`,
 		userID,
 	)
@@ -70,9 +90,31 @@ func (r *APIKeyRepository) FindByUserID(ctx context.Context, userID string) ([]*
 	return keys, rows.Err()
 }
 
+func (r *APIKeyRepository) FindLegacy(ctx context.Context) ([]*domain.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, user_id, name, lookup_id, key_hash, key_prefix, scopes, expires_at, last_used_at, created_at
+		 FROM api_keys WHERE lookup_id IS NULL`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*domain.APIKey
+	for rows.Next() {
+		key, err := r.scanKeyFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
 func (r *APIKeyRepository) FindByID(ctx context.Context, id string) (*domain.APIKey, error) {
 	return r.scanKey(r.db.QueryRowContext(ctx,
-		`SELECT id, user_id, name, key_hash, key_prefix, last_used_at, created_at
+		`SELECT id, user_id, name, lookup_id, key_hash, key_prefix, scopes, expires_at, last_used_at, created_at
 		 FROM api_keys WHERE id = ?`,
 		id,
 	))
@@ -91,11 +133,20 @@ func (r *APIKeyRepository) UpdateLastUsedAt(ctx context.Context, id string) erro
 	return err
 }
 
+func (r *APIKeyRepository) UpdateLookupID(ctx context.Context, id string, lookupID string, keyHash string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE api_keys SET lookup_id = ?, key_hash = ? WHERE id = ?`,
+		lookupID, keyHash, id,
+	)
+	return err
+}
+
 func (r *APIKeyRepository) scanKey(row *sql.Row) (*domain.APIKey, error) {
 	var key domain.APIKey
-	var lastUsedAt, createdAt sql.NullString
+	var scopes string
+	var lookupID, expiresAt, lastUsedAt, createdAt sql.NullString
 
-	err := row.Scan(&key.ID, &key.UserID, &key.Name, &key.KeyHash, &key.KeyPrefix, &lastUsedAt, &createdAt)
+	err := row.Scan(&key.ID, &key.UserID, &key.Name, &lookupID, &key.KeyHash, &key.KeyPrefix, &scopes, &expiresAt, &lastUsedAt, &createdAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -103,6 +154,14 @@ func (r *APIKeyRepository) scanKey(row *sql.Row) (*domain.APIKey, error) {
 		return nil, err
 	}
 
+	if lookupID.Valid {
+		key.LookupID = lookupID.String
+	}
+	key.Scopes = domain.ParseAPIKeyScopes(scopes)
+	if expiresAt.Valid {
+		t, _ := time.Parse(time.RFC3339, expiresAt.String)
+		key.ExpiresAt = &t
+	}
 	if lastUsedAt.Valid {
 		t, _ := time.Parse(time.RFC3339, lastUsedAt.String)
 		key.LastUsedAt = &t
@@ -116,13 +175,22 @@ func (r *APIKeyRepository) scanKey(row *sql.Row) (*domain.APIKey, error) {
 
 func (r *APIKeyRepository) scanKeyFromRows(rows *sql.Rows) (*domain.APIKey, error) {
 	var key domain.APIKey
-	var lastUsedAt, createdAt sql.NullString
+	var scopes string
+	var lookupID, expiresAt, lastUsedAt, createdAt sql.NullString
 
-	err := rows.Scan(&key.ID, &key.UserID, &key.Name, &key.KeyHash, &key.KeyPrefix, &lastUsedAt, &createdAt)
+	err := rows.Scan(&key.ID, &key.UserID, &key.Name, &lookupID, &key.KeyHash, &key.KeyPrefix, &scopes, &expiresAt, &lastUsedAt, &createdAt)
 	if err != nil {
 		return nil, err
 	}
 
+	if lookupID.Valid {
+		key.LookupID = lookupID.String
+	}
+	key.Scopes = domain.ParseAPIKeyScopes(scopes)
+	if expiresAt.Valid {
+		t, _ := time.Parse(time.RFC3339, expiresAt.String)
+		key.ExpiresAt = &t
+	}
 	if lastUsedAt.Valid {
 		t, _ := time.Parse(time.RFC3339, lastUsedAt.String)
 		key.LastUsedAt = &t
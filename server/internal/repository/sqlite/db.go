@@ -1,6 +1,7 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
@@ -25,7 +26,8 @@ func Open(databaseURL string) (*DB, error) {
 		}
 	}
 
-	db, err := sql.Open("sqlite3", databaseURL+"?_foreign_keys=on")
+	// Transactions take the write lock when they begin, and writers wait for each other instead of failing
+	db, err := sql.Open("sqlite3", databaseURL+"?_foreign_keys=on&_busy_timeout=5000&_txlock=immediate")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -49,3 +51,49 @@ func runMigrations(db *sql.DB) error {
 	runner := migrations.NewRunner(db, migrations.DialectSQLite)
 	return runner.Run()
 }
+
+// txKey is the context key of the transaction started by RunInTx
+type txKey struct{}
+
+// RunInTx calls fn with a context whose queries run in one transaction,
+// committed if fn returns nil. Calls nested in fn join the outer transaction.
+func (db *DB) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // no-op after Commit
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ExecContext runs the query in the context's transaction, if any
+func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx.ExecContext(ctx, query, args...)
+	}
+	return db.DB.ExecContext(ctx, query, args...)
+}
+
+// QueryContext runs the query in the context's transaction, if any
+func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx.QueryContext(ctx, query, args...)
+	}
+	return db.DB.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext runs the query in the context's transaction, if any
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx.QueryRowContext(ctx, query, args...)
+	}
+	return db.DB.QueryRowContext(ctx, query, args...)
+}
@@ -36,11 +36,12 @@ func (r *PlanDocumentRepository) Create(ctx context.Context, doc *domain.PlanDoc
 	if doc.ProjectID == "" {
 		doc.ProjectID = domain.DefaultProjectID
 	}
+	doc.Revision = 1
 
 	_, err := r.db.ExecContext(ctx,
-		`INSERT INTO plan_documents (id, project_id, description, body, status, created_at, updated_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
-		doc.ID, doc.ProjectID, doc.Description, doc.Body, string(doc.Status),
+		`INSERT INTO plan_documents (id, project_id, description, body, status, revision, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		doc.ID, doc.ProjectID, doc.Description, doc.Body, string(doc.Status), doc.Revision,
 		doc.CreatedAt.Format(time.RFC3339), doc.UpdatedAt.Format(time.RFC3339),
 	)
 	return err
@@ -48,14 +49,14 @@ func (r *PlanDocumentRepository) Create(ctx context.Context, doc *domain.PlanDoc
 
 func (r *PlanDocumentRepository) FindByID(ctx context.Context, id string) (*domain.PlanDocument, error) {
 	return r.scanDocument(r.db.QueryRowContext(ctx,
-		`SELECT id, project_id, description, body, status, created_at, updated_at
+		`SELECT id, project_id, description, body, status, revision, created_at, updated_at
 		 FROM plan_documents WHERE id = ?`,
 		id,
 	))
 }
 
 func (r *PlanDocumentRepository) Find(ctx context.Context, query domain.PlanDocumentQuery) ([]*domain.PlanDocument, string, error) {
-	baseQuery := `SELECT id, project_id, description, body, status, created_at, updated_at FROM plan_documents`
+	baseQuery := `SELECT id, project_id, description, body, status, revision, created_at, updated_at FROM plan_documents`
 	var conditions []string
 	var args []any
 
@@ -89,6 +90,15 @@ func (r *PlanDocumentRepository) Find(ctx context.Context, query domain.PlanDocu
 		args = append(args, query.ProjectID)
 	}
 
+	if len(query.ExcludeProjectIDs) > 0 {
+		placeholders := make([]string, len(query.ExcludeProjectIDs))
+		for i, id := range query.ExcludeProjectIDs {
+			placeholders[i] = "?"
+			args = append(args, id)
+		}
+		conditions = append(conditions, "project_id NOT IN ("+strings.Join(placeholders, ", ")+")")
+	}
+
 	if query.DescriptionContains != "" {
 		conditions = append(conditions, "LOWER(description) LIKE LOWER(?)")
 		args = append(args, "%"+query.DescriptionContains+"%")
@@ -156,14 +166,25 @@ func (r *PlanDocumentRepository) Find(ctx context.Context, query domain.PlanDocu
 }
 
 func (r *PlanDocumentRepository) Update(ctx context.Context, doc *domain.PlanDocument) error {
-	doc.UpdatedAt = time.Now()
+	updatedAt := time.Now()
 
-	_, err := r.db.ExecContext(ctx,
-		`UPDATE plan_documents SET project_id = ?, description = ?, body = ?, status = ?, updated_at = ?
-		 WHERE id = ?`,
-		doc.ProjectID, doc.Description, doc.Body, string(doc.Status), doc.UpdatedAt.Format(time.RFC3339), doc.ID,
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE plan_documents SET project_id = ?, description = ?, body = ?, status = ?, revision = revision + 1, updated_at = ?
+		 WHERE id = ? AND revision = ?`,
+		doc.ProjectID, doc.Description, doc.Body, string(doc.Status), updatedAt.Format(time.RFC3339), doc.ID, doc.Revision,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return repository.ErrRevisionConflict
+	}
+
+	doc.Revision++
+	doc.UpdatedAt = updatedAt
+	return nil
 }
 
 func (r *PlanDocumentRepository) Delete(ctx context.Context, id string) error {
@@ -174,12 +195,20 @@ func (r *PlanDocumentRepository) Delete(ctx context.Context, id string) error {
 	return err
 }
 
-func (r *PlanDocumentRepository) SetStatus(ctx context.Context, id string, status domain.PlanDocumentStatus) error {
-	_, err := r.db.ExecContext(ctx,
-		`UPDATE plan_documents SET status = ?, updated_at = ? WHERE id = ?`,
-		string(status), time.Now().Format(time.RFC3339), id,
+func (r *PlanDocumentRepository) SetStatus(ctx context.Context, id string, status domain.PlanDocumentStatus, expectedRevision int) (int, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE plan_documents SET status = ?, revision = revision + 1, updated_at = ? WHERE id = ? AND revision = ?`,
+		string(status), time.Now().Format(time.RFC3339), id, expectedRevision,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return 0, err
+	} else if n == 0 {
+		return 0, repository.ErrRevisionConflict
+	}
+	return expectedRevision + 1, nil
 }
 
 func (r *PlanDocumentRepository) scanDocument(row *sql.Row) (*domain.PlanDocument, error) {
@@ -188,7 +217,7 @@ func (r *PlanDocumentRepository) scanDocument(row *sql.Row) (*domain.PlanDocumen
 	var status string
 	var createdAt, updatedAt string
 
-	err := row.Scan(&doc.ID, &projectID, &doc.Description, &doc.Body, &status, &createdAt, &updatedAt)
+	err := row.Scan(&doc.ID, &projectID, &doc.Description, &doc.Body, &status, &doc.Revision, &createdAt, &updatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -214,7 +243,7 @@ func (r *PlanDocumentRepository) scanDocumentFromRows(rows *sql.Rows) (*domain.P
 	var status string
 	var createdAt, updatedAt string
 
-	err := rows.Scan(&doc.ID, &projectID, &doc.Description, &doc.Body, &status, &createdAt, &updatedAt)
+	err := rows.Scan(&doc.ID, &projectID, &doc.Description, &doc.Body, &status, &doc.Revision, &createdAt, &updatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,147 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+type TeamRepository struct {
+	db *DB
+}
+
+func NewTeamRepository(db *DB) *TeamRepository {
+	return &TeamRepository{db: db}
+}
+
+func (r *TeamRepository) Create(ctx context.Context, team *domain.Team) error {
+	if team.ID == "" {
+		team.ID = uuid.New().String()
+	}
+	if team.CreatedAt.IsZero() {
+		team.CreatedAt = time.Now()
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO teams (id, name, created_at) VALUES (?, ?, ?)`,
+		team.ID, team.Name, team.CreatedAt.Format(time.RFC3339Nano),
+	)
+	return err
+}
+
+func (r *TeamRepository) FindByID(ctx context.Context, id string) (*domain.Team, error) {
+	var team domain.Team
+	var createdAt string
+
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, name, created_at FROM teams WHERE id = ?`,
+		id,
+	).Scan(&team.ID, &team.Name, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	team.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+	return &team, nil
+}
+
+func (r *TeamRepository) FindAll(ctx context.Context) ([]*domain.Team, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, created_at FROM teams ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []*domain.Team
+	for rows.Next() {
+		var team domain.Team
+		var createdAt string
+		if err := rows.Scan(&team.ID, &team.Name, &createdAt); err != nil {
+			return nil, err
+		}
+		team.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+		teams = append(teams, &team)
+	}
+
+	return teams, rows.Err()
+}
+
+func (r *TeamRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM teams WHERE id = ?`, id)
+	return err
+}
+
+type TeamMembershipRepository struct {
+	db *DB
+}
+
+func NewTeamMembershipRepository(db *DB) *TeamMembershipRepository {
+	return &TeamMembershipRepository{db: db}
+}
+
+func (r *TeamMembershipRepository) Create(ctx context.Context, membership *domain.TeamMembership) error {
+	if membership.CreatedAt.IsZero() {
+		membership.CreatedAt = time.Now()
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO team_memberships (team_id, user_id, created_at) VALUES (?, ?, ?)
+		 ON CONFLICT (team_id, user_id) DO NOTHING`,
+		membership.TeamID, membership.UserID, membership.CreatedAt.Format(time.RFC3339Nano),
+	)
+	return err
+}
+
+func (r *TeamMembershipRepository) Delete(ctx context.Context, teamID string, userID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM team_memberships WHERE team_id = ? AND user_id = ?`,
+		teamID, userID,
+	)
+	return err
+}
+
+func (r *TeamMembershipRepository) DeleteByTeamID(ctx context.Context, teamID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM team_memberships WHERE team_id = ?`, teamID)
+	return err
+}
+
+func (r *TeamMembershipRepository) FindByTeamID(ctx context.Context, teamID string) ([]*domain.TeamMembership, error) {
+	return r.findMemberships(ctx,
+		`SELECT team_id, user_id, created_at FROM team_memberships WHERE team_id = ? ORDER BY created_at, user_id`,
+		teamID,
+	)
+}
+
+func (r *TeamMembershipRepository) FindByUserID(ctx context.Context, userID string) ([]*domain.TeamMembership, error) {
+	return r.findMemberships(ctx,
+		`SELECT team_id, user_id, created_at FROM team_memberships WHERE user_id = ? ORDER BY created_at, team_id`,
+		userID,
+	)
+}
+
+func (r *TeamMembershipRepository) findMemberships(ctx context.Context, query string, args ...any) ([]*domain.TeamMembership, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memberships []*domain.TeamMembership
+	for rows.Next() {
+		var membership domain.TeamMembership
+		var createdAt string
+		if err := rows.Scan(&membership.TeamID, &membership.UserID, &createdAt); err != nil {
+			return nil, err
+		}
+		membership.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+		memberships = append(memberships, &membership)
+	}
+
+	return memberships, rows.Err()
+}
@@ -0,0 +1,137 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+type ToolCallRepository struct {
+	db *DB
+}
+
+func NewToolCallRepository(db *DB) *ToolCallRepository {
+	return &ToolCallRepository{db: db}
+}
+
+func (r *ToolCallRepository) Create(ctx context.Context, call *domain.ToolCall) error {
+	if call.ID == "" {
+		call.ID = uuid.New().String()
+	}
+	if call.StartedAt.IsZero() {
+		call.StartedAt = time.Now()
+	}
+
+	var completedAt sql.NullString
+	if call.CompletedAt != nil {
+		completedAt = sql.NullString{String: call.CompletedAt.Format(time.RFC3339Nano), Valid: true}
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT OR IGNORE INTO tool_calls (id, session_id, project_id, event_id, tool_use_id, tool_name, is_error, started_at, completed_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		call.ID, call.SessionID, call.ProjectID, call.EventID, call.ToolUseID, call.ToolName, call.IsError,
+		call.StartedAt.Format(time.RFC3339Nano), completedAt,
+	)
+	return err
+}
+
+func (r *ToolCallRepository) Complete(ctx context.Context, sessionID, toolUseID string, isError bool, completedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE tool_calls SET is_error = ?, completed_at = ?
+		 WHERE session_id = ? AND tool_use_id = ? AND completed_at IS NULL`,
+		isError, completedAt.Format(time.RFC3339Nano), sessionID, toolUseID,
+	)
+	return err
+}
+
+func (r *ToolCallRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*domain.ToolCall, error) {
+	return r.query(ctx,
+		`SELECT id, session_id, project_id, event_id, tool_use_id, tool_name, is_error, started_at, completed_at
+		 FROM tool_calls WHERE session_id = ?`,
+		sessionID,
+	)
+}
+
+func (r *ToolCallRepository) FindByProjectID(ctx context.Context, projectID string, from, to time.Time) ([]*domain.ToolCall, error) {
+	// started_at strings do not compare chronologically across precisions and zones
+	conditions := []string{"project_id = ?"}
+	args := []interface{}{projectID}
+	if !from.IsZero() {
+		conditions = append(conditions, "julianday(started_at) >= julianday(?)")
+		args = append(args, from.Format(time.RFC3339Nano))
+	}
+	if !to.IsZero() {
+		conditions = append(conditions, "julianday(started_at) < julianday(?)")
+		args = append(args, to.Format(time.RFC3339Nano))
+	}
+
+	return r.query(ctx,
+		`SELECT id, session_id, project_id, event_id, tool_use_id, tool_name, is_error, started_at, completed_at
+		 FROM tool_calls WHERE `+strings.Join(conditions, " AND "),
+		args...,
+	)
+}
+
+func (r *ToolCallRepository) UpdateProjectIDBySessionID(ctx context.Context, sessionID string, projectID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE tool_calls SET project_id = ? WHERE session_id = ?`,
+		projectID, sessionID,
+	)
+	return err
+}
+
+func (r *ToolCallRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM tool_calls WHERE session_id = ?`, sessionID)
+	return err
+}
+
+// query returns the tool calls selected by the query, oldest first
+func (r *ToolCallRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.ToolCall, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	calls := make([]*domain.ToolCall, 0)
+	for rows.Next() {
+		call, err := r.scanToolCall(rows)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, call)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(calls, func(i, j int) bool {
+		return calls[i].StartedAt.Before(calls[j].StartedAt)
+	})
+	return calls, nil
+}
+
+func (r *ToolCallRepository) scanToolCall(rows *sql.Rows) (*domain.ToolCall, error) {
+	var call domain.ToolCall
+	var startedAt string
+	var completedAt sql.NullString
+
+	err := rows.Scan(&call.ID, &call.SessionID, &call.ProjectID, &call.EventID, &call.ToolUseID, &call.ToolName, &call.IsError, &startedAt, &completedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	call.StartedAt, _ = time.Parse(time.RFC3339Nano, startedAt)
+	if completedAt.Valid {
+		t, _ := time.Parse(time.RFC3339Nano, completedAt.String)
+		call.CompletedAt = &t
+	}
+
+	return &call, nil
+}
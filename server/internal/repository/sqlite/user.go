@@ -24,10 +24,13 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	if user.CreatedAt.IsZero() {
 		user.CreatedAt = time.Now()
 	}
+	if user.Role == "" {
+		user.Role = domain.DefaultUserRole
+	}
 
 	_, err := r.db.ExecContext(ctx,
-		`INSERT INTO users (id, email, display_name, created_at) VALUES (?, ?, ?, ?)`,
-		user.ID, user.Email, user.DisplayName, user.CreatedAt.Format(time.RFC3339),
+		`INSERT INTO users (id, email, display_name, created_at, default_session_visibility, role, disabled) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		user.ID, user.Email, user.DisplayName, user.CreatedAt.Format(time.RFC3339), user.DefaultSessionVisibility, user.Role, user.Disabled,
 	)
 	return err
 }
@@ -38,9 +41,9 @@ func (r *UserRepository) FindByID(ctx context.Context, id string) (*domain.User,
 	var displayName sql.NullString
 
 	err := r.db.QueryRowContext(ctx,
-		`SELECT id, email, display_name, created_at FROM users WHERE id = ?`,
+		`SELECT id, email, display_name, created_at, default_session_visibility, role, disabled FROM users WHERE id = ?`,
 		id,
-	).Scan(&user.ID, &user.Email, &displayName, &createdAt)
+	).Scan(&user.ID, &user.Email, &displayName, &createdAt, &user.DefaultSessionVisibility, &user.Role, &user.Disabled)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -60,9 +63,9 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*domain
 	var displayName sql.NullString
 
 	err := r.db.QueryRowContext(ctx,
-		`SELECT id, email, display_name, created_at FROM users WHERE email = ?`,
+		`SELECT id, email, display_name, created_at, default_session_visibility, role, disabled FROM users WHERE email = ?`,
 		email,
-	).Scan(&user.ID, &user.Email, &displayName, &createdAt)
+	).Scan(&user.ID, &user.Email, &displayName, &createdAt, &user.DefaultSessionVisibility, &user.Role, &user.Disabled)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -77,7 +80,7 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*domain
 }
 
 func (r *UserRepository) FindAll(ctx context.Context) ([]*domain.User, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT id, email, display_name, created_at FROM users ORDER BY created_at DESC`)
+	rows, err := r.db.QueryContext(ctx, `SELECT id, email, display_name, created_at, default_session_visibility, role, disabled FROM users ORDER BY created_at DESC`)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +92,7 @@ func (r *UserRepository) FindAll(ctx context.Context) ([]*domain.User, error) {
 		var createdAt string
 		var displayName sql.NullString
 
-		if err := rows.Scan(&user.ID, &user.Email, &displayName, &createdAt); err != nil {
+		if err := rows.Scan(&user.ID, &user.Email, &displayName, &createdAt, &user.DefaultSessionVisibility, &user.Role, &user.Disabled); err != nil {
 			return nil, err
 		}
 
@@ -108,3 +111,27 @@ func (r *UserRepository) UpdateDisplayName(ctx context.Context, id string, displ
 	)
 	return err
 }
+
+func (r *UserRepository) UpdateDefaultSessionVisibility(ctx context.Context, id string, visibility domain.SessionVisibility) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE users SET default_session_visibility = ? WHERE id = ?`,
+		visibility, id,
+	)
+	return err
+}
+
+func (r *UserRepository) UpdateRole(ctx context.Context, id string, role domain.UserRole) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE users SET role = ? WHERE id = ?`,
+		role, id,
+	)
+	return err
+}
+
+func (r *UserRepository) UpdateDisabled(ctx context.Context, id string, disabled bool) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE users SET disabled = ? WHERE id = ?`,
+		disabled, id,
+	)
+	return err
+}
@@ -0,0 +1,235 @@
+package testsuite
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+	"github.com/satetsu888/agentrace/server/internal/repository"
+	"github.com/stretchr/testify/suite"
+)
+
+// AnalyticsRepositorySuite tests AnalyticsRepository implementations.
+//
+// Backends that aggregate the source tables need the optional repositories;
+// counter-based backends only see RecordActivity. The suite does both, the
+// same way the API handlers do, so either approach must yield the same series.
+type AnalyticsRepositorySuite struct {
+	suite.Suite
+	Repo             repository.AnalyticsRepository
+	SessionRepo      repository.SessionRepository           // Optional: for aggregating backends
+	EventRepo        repository.EventRepository             // Optional: for aggregating backends
+	PlanDocRepo      repository.PlanDocumentRepository      // Optional: for aggregating backends
+	PlanDocEventRepo repository.PlanDocumentEventRepository // Optional: for aggregating backends
+	ProjectRepo      repository.ProjectRepository           // Optional: for FK constraint support
+	UserRepo         repository.UserRepository              // Optional: for FK constraint support
+	projectID        string
+	otherProjectID   string
+	userA            string
+	userB            string
+	week1, week2     time.Time
+}
+
+// SetupSuite records the fixture shared by all tests:
+//
+//	week1 Mon: session 1 (user A) starts; 3 events, one with 2 tool_use blocks
+//	week1 Wed: session 2 (user B) starts; 2 events; user A changes a plan status
+//	week2 Mon: 1 event in session 1
+//	week1 Tue: session 3 (user A, other project) starts; 1 event
+func (s *AnalyticsRepositorySuite) SetupSuite() {
+	ctx := context.Background()
+	s.projectID = uuid.New().String()
+	s.otherProjectID = uuid.New().String()
+	s.userA = uuid.New().String()
+	s.userB = uuid.New().String()
+	s.week1 = time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC) // Monday
+	s.week2 = s.week1.AddDate(0, 0, 7)
+
+	for _, id := range []string{s.projectID, s.otherProjectID} {
+		if s.ProjectRepo != nil {
+			s.Require().NoError(s.ProjectRepo.Create(ctx, &domain.Project{ID: id, CanonicalGitRepository: "https://github.com/test/" + id}))
+		}
+	}
+	for _, id := range []string{s.userA, s.userB} {
+		if s.UserRepo != nil {
+			s.Require().NoError(s.UserRepo.Create(ctx, &domain.User{ID: id, Email: id + "@example.com"}))
+		}
+	}
+
+	toolUse := map[string]interface{}{
+		"type": "assistant",
+		"message": map[string]interface{}{
+			"content": []interface{}{
+				map[string]interface{}{"type": "text", "text": "Running"},
+				map[string]interface{}{"type": "tool_use", "name": "Bash"},
+				map[string]interface{}{"type": "tool_use", "name": "Read"},
+			},
+		},
+	}
+	text := map[string]interface{}{
+		"type":    "user",
+		"message": map[string]interface{}{"content": "hello"},
+	}
+
+	session1 := s.startSession(s.projectID, s.userA, s.week1.Add(9*time.Hour))
+	s.ingest(session1, s.projectID, s.userA, s.week1.Add(10*time.Hour), text, toolUse, text)
+	session2 := s.startSession(s.projectID, s.userB, s.week1.AddDate(0, 0, 2).Add(9*time.Hour))
+	s.ingest(session2, s.projectID, s.userB, s.week1.AddDate(0, 0, 2).Add(10*time.Hour), text, text)
+	s.changePlanStatus(s.projectID, s.userA, s.week1.AddDate(0, 0, 2).Add(11*time.Hour))
+	s.ingest(session1, s.projectID, s.userA, s.week2.Add(10*time.Hour), text)
+	session3 := s.startSession(s.otherProjectID, s.userA, s.week1.AddDate(0, 0, 1).Add(9*time.Hour))
+	s.ingest(session3, s.otherProjectID, s.userA, s.week1.AddDate(0, 0, 1).Add(10*time.Hour), text)
+}
+
+func (s *AnalyticsRepositorySuite) startSession(projectID, userID string, at time.Time) string {
+	ctx := context.Background()
+	sessionID := uuid.New().String()
+	if s.SessionRepo != nil {
+		uid := userID
+		s.Require().NoError(s.SessionRepo.Create(ctx, &domain.Session{
+			ID:              sessionID,
+			UserID:          &uid,
+			ProjectID:       projectID,
+			ClaudeSessionID: "analytics-" + sessionID,
+			StartedAt:       at,
+			UpdatedAt:       at,
+			CreatedAt:       at,
+		}))
+	}
+	s.Require().NoError(s.Repo.RecordActivity(ctx, domain.AnalyticsActivity{
+		Time: at, ProjectID: projectID, UserID: userID,
+		AnalyticsCounts: domain.AnalyticsCounts{SessionsStarted: 1},
+	}))
+	return sessionID
+}
+
+func (s *AnalyticsRepositorySuite) ingest(sessionID, projectID, userID string, at time.Time, payloads ...map[string]interface{}) {
+	ctx := context.Background()
+	counts := domain.AnalyticsCounts{EventsIngested: len(payloads)}
+	for _, payload := range payloads {
+		event := &domain.Event{SessionID: sessionID, EventType: payload["type"].(string), Payload: payload, CreatedAt: at}
+		counts.ToolInvocations += event.ToolUseCount()
+		if s.EventRepo != nil {
+			s.Require().NoError(s.EventRepo.Create(ctx, event))
+		}
+	}
+	s.Require().NoError(s.Repo.RecordActivity(ctx, domain.AnalyticsActivity{
+		Time: at, ProjectID: projectID, UserID: userID, AnalyticsCounts: counts,
+	}))
+}
+
+func (s *AnalyticsRepositorySuite) changePlanStatus(projectID, userID string, at time.Time) {
+	ctx := context.Background()
+	if s.PlanDocRepo != nil && s.PlanDocEventRepo != nil {
+		doc := &domain.PlanDocument{ProjectID: projectID, Description: "Analytics Plan", Status: domain.PlanDocumentStatusPlanning}
+		s.Require().NoError(s.PlanDocRepo.Create(ctx, doc))
+		uid := userID
+		s.Require().NoError(s.PlanDocEventRepo.Create(ctx, &domain.PlanDocumentEvent{
+			PlanDocumentID: doc.ID,
+			UserID:         &uid,
+			EventType:      domain.PlanDocumentEventTypeStatusChange,
+			Patch:          "planning -> implementation",
+			CreatedAt:      at,
+		}))
+	}
+	s.Require().NoError(s.Repo.RecordActivity(ctx, domain.AnalyticsActivity{
+		Time: at, ProjectID: projectID, UserID: userID,
+		AnalyticsCounts: domain.AnalyticsCounts{PlanStatusChanges: 1},
+	}))
+}
+
+func (s *AnalyticsRepositorySuite) TestGetSeries_WeekByProject() {
+	buckets, err := s.Repo.GetSeries(context.Background(), domain.AnalyticsQuery{
+		Bucket:    domain.AnalyticsBucketWeek,
+		From:      s.week1,
+		To:        s.week2.AddDate(0, 0, 7),
+		ProjectID: s.projectID,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(buckets, 2)
+
+	s.True(s.week1.Equal(buckets[0].Start))
+	s.Equal(domain.AnalyticsCounts{SessionsStarted: 2, EventsIngested: 5, ToolInvocations: 2, PlanStatusChanges: 1}, buckets[0].AnalyticsCounts)
+	s.Equal(2, buckets[0].ActiveUsers)
+
+	s.True(s.week2.Equal(buckets[1].Start))
+	s.Equal(domain.AnalyticsCounts{EventsIngested: 1}, buckets[1].AnalyticsCounts)
+	s.Equal(1, buckets[1].ActiveUsers)
+}
+
+func (s *AnalyticsRepositorySuite) TestGetSeries_DayByProjectAndUser() {
+	buckets, err := s.Repo.GetSeries(context.Background(), domain.AnalyticsQuery{
+		Bucket:    domain.AnalyticsBucketDay,
+		From:      s.week1,
+		To:        s.week1.AddDate(0, 0, 3),
+		ProjectID: s.projectID,
+		UserID:    s.userA,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(buckets, 3)
+
+	s.Equal(domain.AnalyticsCounts{SessionsStarted: 1, EventsIngested: 3, ToolInvocations: 2}, buckets[0].AnalyticsCounts)
+	s.Equal(1, buckets[0].ActiveUsers)
+	s.Equal(domain.AnalyticsCounts{}, buckets[1].AnalyticsCounts)
+	s.Equal(0, buckets[1].ActiveUsers)
+	s.Equal(domain.AnalyticsCounts{PlanStatusChanges: 1}, buckets[2].AnalyticsCounts)
+	s.Equal(0, buckets[2].ActiveUsers)
+}
+
+func (s *AnalyticsRepositorySuite) TestGetSeries_ExcludeProjectIDs() {
+	buckets, err := s.Repo.GetSeries(context.Background(), domain.AnalyticsQuery{
+		Bucket:            domain.AnalyticsBucketDay,
+		From:              s.week1,
+		To:                s.week1.AddDate(0, 0, 3),
+		ExcludeProjectIDs: []string{s.otherProjectID},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(buckets, 3)
+
+	s.Equal(domain.AnalyticsCounts{SessionsStarted: 1, EventsIngested: 3, ToolInvocations: 2}, buckets[0].AnalyticsCounts)
+	s.Equal(1, buckets[0].ActiveUsers)
+	// User A was only active in the excluded project on Tuesday
+	s.Equal(domain.AnalyticsCounts{}, buckets[1].AnalyticsCounts)
+	s.Equal(0, buckets[1].ActiveUsers)
+	s.Equal(domain.AnalyticsCounts{SessionsStarted: 1, EventsIngested: 2, PlanStatusChanges: 1}, buckets[2].AnalyticsCounts)
+	s.Equal(1, buckets[2].ActiveUsers)
+
+	buckets, err = s.Repo.GetSeries(context.Background(), domain.AnalyticsQuery{
+		Bucket:            domain.AnalyticsBucketWeek,
+		From:              s.week1,
+		To:                s.week1.AddDate(0, 0, 7),
+		UserID:            s.userA,
+		ExcludeProjectIDs: []string{s.otherProjectID},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(buckets, 1)
+	s.Equal(domain.AnalyticsCounts{SessionsStarted: 1, EventsIngested: 3, ToolInvocations: 2, PlanStatusChanges: 1}, buckets[0].AnalyticsCounts)
+	s.Equal(1, buckets[0].ActiveUsers)
+
+	buckets, err = s.Repo.GetSeries(context.Background(), domain.AnalyticsQuery{
+		Bucket:            domain.AnalyticsBucketWeek,
+		From:              s.week1,
+		To:                s.week1.AddDate(0, 0, 7),
+		ProjectID:         s.otherProjectID,
+		ExcludeProjectIDs: []string{s.otherProjectID},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(buckets, 1)
+	s.Equal(domain.AnalyticsCounts{}, buckets[0].AnalyticsCounts)
+	s.Equal(0, buckets[0].ActiveUsers)
+}
+
+func (s *AnalyticsRepositorySuite) TestGetSeries_ByUserAcrossProjects() {
+	buckets, err := s.Repo.GetSeries(context.Background(), domain.AnalyticsQuery{
+		Bucket: domain.AnalyticsBucketWeek,
+		From:   s.week1,
+		To:     s.week1.AddDate(0, 0, 7),
+		UserID: s.userA,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(buckets, 1)
+
+	s.Equal(domain.AnalyticsCounts{SessionsStarted: 2, EventsIngested: 4, ToolInvocations: 2, PlanStatusChanges: 1}, buckets[0].AnalyticsCounts)
+	s.Equal(1, buckets[0].ActiveUsers)
+}
@@ -201,3 +201,138 @@ func (s *APIKeyRepositorySuite) TestUpdateLastUsedAt() {
 	s.Require().NotNil(found.LastUsedAt)
 	s.WithinDuration(time.Now(), *found.LastUsedAt, 2*time.Second)
 }
+
+func (s *APIKeyRepositorySuite) TestFindByLookupID() {
+	ctx := context.Background()
+
+	s.createTestUser("user-6")
+
+	key := &domain.APIKey{
+		UserID:    "user-6",
+		Name:      "Lookup Key",
+		LookupID:  "0123456789abcdef",
+		KeyHash:   "lookup-hash",
+		KeyPrefix: "agtr_0123456",
+	}
+	err := s.Repo.Create(ctx, key)
+	s.Require().NoError(err)
+
+	found, err := s.Repo.FindByLookupID(ctx, "0123456789abcdef")
+	s.Require().NoError(err)
+	s.Require().NotNil(found)
+	s.Equal(key.ID, found.ID)
+	s.Equal("0123456789abcdef", found.LookupID)
+	s.Equal("lookup-hash", found.KeyHash)
+	s.Equal("agtr_0123456", found.KeyPrefix)
+}
+
+func (s *APIKeyRepositorySuite) TestCreate_ScopesAndExpiry() {
+	ctx := context.Background()
+
+	s.createTestUser("user-8")
+
+	expiresAt := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	key := &domain.APIKey{
+		UserID:    "user-8",
+		Name:      "Scoped Key",
+		LookupID:  "00112233aabbccdd",
+		KeyHash:   "scoped-hash",
+		KeyPrefix: "agtr_0011223",
+		Scopes:    []domain.APIKeyScope{domain.APIKeyScopeIngest, domain.APIKeyScopePlans},
+		ExpiresAt: &expiresAt,
+	}
+	err := s.Repo.Create(ctx, key)
+	s.Require().NoError(err)
+
+	found, err := s.Repo.FindByLookupID(ctx, "00112233aabbccdd")
+	s.Require().NoError(err)
+	s.Require().NotNil(found)
+	s.Equal([]domain.APIKeyScope{domain.APIKeyScopeIngest, domain.APIKeyScopePlans}, found.Scopes)
+	s.Require().NotNil(found.ExpiresAt)
+	s.True(found.ExpiresAt.Equal(expiresAt))
+
+	// Keys without scopes or expiry keep full access
+	keys, err := s.Repo.FindByUserID(ctx, "user-1")
+	s.Require().NoError(err)
+	for _, k := range keys {
+		s.Empty(k.Scopes)
+		s.Nil(k.ExpiresAt)
+	}
+}
+
+func (s *APIKeyRepositorySuite) TestFindByLookupID_NotFound() {
+	ctx := context.Background()
+
+	found, err := s.Repo.FindByLookupID(ctx, "non-existing-lookup")
+	s.NoError(err)
+	s.Nil(found)
+}
+
+func (s *APIKeyRepositorySuite) TestFindLegacy() {
+	ctx := context.Background()
+
+	s.createTestUser("user-9")
+
+	legacy := &domain.APIKey{
+		UserID:    "user-9",
+		Name:      "Legacy Key",
+		KeyHash:   "$2a$10$legacy-find",
+		KeyPrefix: "agtr_legacy",
+	}
+	s.Require().NoError(s.Repo.Create(ctx, legacy))
+	indexed := &domain.APIKey{
+		UserID:    "user-9",
+		Name:      "Indexed Key",
+		LookupID:  "aaaabbbbccccdddd",
+		KeyHash:   "indexed-hash",
+		KeyPrefix: "agtr_aaaabbb",
+	}
+	s.Require().NoError(s.Repo.Create(ctx, indexed))
+
+	keys, err := s.Repo.FindLegacy(ctx)
+	s.Require().NoError(err)
+	var ids []string
+	for _, k := range keys {
+		s.Empty(k.LookupID)
+		ids = append(ids, k.ID)
+	}
+	s.Contains(ids, legacy.ID)
+	s.NotContains(ids, indexed.ID)
+
+	// Upgraded keys are no longer legacy
+	s.Require().NoError(s.Repo.UpdateLookupID(ctx, legacy.ID, "1111222233334444", "upgraded-find-hash"))
+	keys, err = s.Repo.FindLegacy(ctx)
+	s.Require().NoError(err)
+	for _, k := range keys {
+		s.NotEqual(legacy.ID, k.ID)
+	}
+}
+
+func (s *APIKeyRepositorySuite) TestUpdateLookupID() {
+	ctx := context.Background()
+
+	s.createTestUser("user-7")
+
+	// Legacy key without lookup ID
+	key := &domain.APIKey{
+		UserID:    "user-7",
+		Name:      "Legacy Key",
+		KeyHash:   "$2a$10$legacy",
+		KeyPrefix: "agtr_legacy",
+	}
+	err := s.Repo.Create(ctx, key)
+	s.Require().NoError(err)
+
+	found, err := s.Repo.FindByID(ctx, key.ID)
+	s.Require().NoError(err)
+	s.Empty(found.LookupID)
+
+	err = s.Repo.UpdateLookupID(ctx, key.ID, "fedcba9876543210", "upgraded-hash")
+	s.Require().NoError(err)
+
+	found, err = s.Repo.FindByLookupID(ctx, "fedcba9876543210")
+	s.Require().NoError(err)
+	s.Require().NotNil(found)
+	s.Equal(key.ID, found.ID)
+	s.Equal("upgraded-hash", found.KeyHash)
+}
@@ -114,6 +114,71 @@ func (s *EventRepositorySuite) TestCreate_SameUUIDDifferentSession() {
 	s.Require().NoError(err)
 }
 
+func (s *EventRepositorySuite) TestCreateBatch() {
+	ctx := context.Background()
+
+	s.createTestSession("session-batch")
+
+	stored := &domain.Event{
+		SessionID: "session-batch",
+		UUID:      "batch-uuid-1",
+		EventType: "user",
+		Payload:   map[string]interface{}{},
+	}
+	s.Require().NoError(s.Repo.Create(ctx, stored))
+
+	base := time.Now().Truncate(time.Second)
+	events := []*domain.Event{
+		// Already stored
+		{SessionID: "session-batch", UUID: "batch-uuid-1", EventType: "user", Payload: map[string]interface{}{}, CreatedAt: base},
+		{SessionID: "session-batch", UUID: "batch-uuid-2", EventType: "assistant", Payload: map[string]interface{}{}, CreatedAt: base.Add(time.Second)},
+		// Repeated within the batch
+		{SessionID: "session-batch", UUID: "batch-uuid-2", EventType: "assistant", Payload: map[string]interface{}{}, CreatedAt: base.Add(2 * time.Second)},
+		// Lines without uuid are never duplicates
+		{SessionID: "session-batch", EventType: "summary", Payload: map[string]interface{}{}, CreatedAt: base.Add(3 * time.Second)},
+		{
+			SessionID: "session-batch",
+			UUID:      "batch-uuid-3",
+			EventType: "user",
+			Payload: map[string]interface{}{
+				"type":    "user",
+				"message": map[string]interface{}{"content": "Deploy the zanzibar service"},
+			},
+			CreatedAt: base.Add(4 * time.Second),
+		},
+	}
+
+	result, err := s.Repo.CreateBatch(ctx, events)
+	s.Require().NoError(err)
+	s.Equal(2, result.Duplicates)
+	s.Require().Len(result.Created, 3)
+	s.Same(events[1], result.Created[0])
+	s.Same(events[3], result.Created[1])
+	s.Same(events[4], result.Created[2])
+	for _, event := range result.Created {
+		s.NotEmpty(event.ID)
+	}
+
+	found, err := s.Repo.FindBySessionID(ctx, "session-batch")
+	s.Require().NoError(err)
+	s.Len(found, 4)
+
+	// Batched events are searchable
+	matches, err := s.Repo.Search(ctx, "zanzibar", domain.SessionViewer{}, 10)
+	s.Require().NoError(err)
+	s.Require().Len(matches, 1)
+	s.Equal(events[4].ID, matches[0].EventID)
+}
+
+func (s *EventRepositorySuite) TestCreateBatch_Empty() {
+	ctx := context.Background()
+
+	result, err := s.Repo.CreateBatch(ctx, nil)
+	s.Require().NoError(err)
+	s.Empty(result.Created)
+	s.Zero(result.Duplicates)
+}
+
 func (s *EventRepositorySuite) TestFindBySessionID() {
 	ctx := context.Background()
 
@@ -204,6 +269,101 @@ func (s *EventRepositorySuite) TestFindBySessionID_ChronologicalOrder() {
 	s.Equal(baseTime.Add(500*time.Millisecond).UnixNano(), events[4].CreatedAt.UnixNano(), "Last event should have latest timestamp")
 }
 
+func (s *EventRepositorySuite) TestFindBySessionID_EventTimeOrder() {
+	ctx := context.Background()
+
+	sessionID := "session-event-time"
+	s.createTestSession(sessionID)
+
+	baseTime := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	events := []*domain.Event{
+		// Whole second: its stored form sorts after fractional ones as a string
+		{UUID: "event-time-c", CreatedAt: baseTime.Add(time.Second)},
+		{UUID: "event-time-b", CreatedAt: baseTime.Add(500 * time.Millisecond)},
+		// Stored at ingest time, placed by its transcript timestamp
+		{UUID: "event-time-a", CreatedAt: baseTime.Add(time.Hour), Payload: map[string]interface{}{"timestamp": "2026-03-02T09:00:00.1Z"}},
+	}
+	for _, event := range events {
+		event.SessionID = sessionID
+		event.EventType = "message"
+		if event.Payload == nil {
+			event.Payload = map[string]interface{}{}
+		}
+		s.Require().NoError(s.Repo.Create(ctx, event))
+	}
+
+	found, err := s.Repo.FindBySessionID(ctx, sessionID)
+	s.Require().NoError(err)
+	s.Require().Len(found, 3)
+	s.Equal("event-time-a", found[0].UUID)
+	s.Equal("event-time-b", found[1].UUID)
+	s.Equal("event-time-c", found[2].UUID)
+}
+
+func (s *EventRepositorySuite) TestFindBySessionID_SidechainLinks() {
+	ctx := context.Background()
+
+	sessionID := "session-sidechain"
+	s.createTestSession(sessionID)
+
+	baseTime := time.Now()
+	events := []*domain.Event{
+		{UUID: "main-1", CreatedAt: baseTime},
+		{UUID: "side-1", ParentUUID: "main-1", IsSidechain: true, CreatedAt: baseTime.Add(time.Millisecond)},
+		{UUID: "side-2", ParentUUID: "side-1", IsSidechain: true, CreatedAt: baseTime.Add(2 * time.Millisecond)},
+	}
+	for _, event := range events {
+		event.SessionID = sessionID
+		event.EventType = "message"
+		event.Payload = map[string]interface{}{}
+		s.Require().NoError(s.Repo.Create(ctx, event))
+	}
+
+	found, err := s.Repo.FindBySessionID(ctx, sessionID)
+	s.Require().NoError(err)
+	s.Require().Len(found, 3)
+	s.Equal("", found[0].ParentUUID)
+	s.False(found[0].IsSidechain)
+	s.Equal("main-1", found[1].ParentUUID)
+	s.True(found[1].IsSidechain)
+	s.Equal("side-1", found[2].ParentUUID)
+	s.True(found[2].IsSidechain)
+}
+
+func (s *EventRepositorySuite) TestFindBySessionID_Raw() {
+	ctx := context.Background()
+
+	sessionID := "session-raw"
+	s.createTestSession(sessionID)
+
+	baseTime := time.Now()
+	created := &domain.Event{
+		SessionID: sessionID,
+		UUID:      "raw-1",
+		EventType: "user",
+		Payload:   map[string]interface{}{"uuid": "raw-1", "cost": 1.5},
+		Raw:       []byte(`{"uuid":"raw-1", "cost":1.50}`),
+		CreatedAt: baseTime,
+	}
+	s.Require().NoError(s.Repo.Create(ctx, created))
+
+	_, err := s.Repo.CreateBatch(ctx, []*domain.Event{
+		{SessionID: sessionID, UUID: "raw-2", EventType: "assistant", Payload: map[string]interface{}{"uuid": "raw-2"}, Raw: []byte(`{"uuid":"raw-2"}`), CreatedAt: baseTime.Add(time.Second)},
+		// Events stored before lines were kept have no raw line
+		{SessionID: sessionID, UUID: "raw-3", EventType: "assistant", Payload: map[string]interface{}{"uuid": "raw-3"}, CreatedAt: baseTime.Add(2 * time.Second)},
+	})
+	s.Require().NoError(err)
+
+	found, err := s.Repo.FindBySessionID(ctx, sessionID)
+	s.Require().NoError(err)
+	s.Require().Len(found, 3)
+	s.Equal(`{"uuid":"raw-1", "cost":1.50}`, string(found[0].Raw))
+	s.Equal(1.5, found[0].Payload["cost"])
+	s.Equal(`{"uuid":"raw-2"}`, string(found[1].Raw))
+	s.Nil(found[2].Raw)
+	s.Equal("raw-3", found[2].Payload["uuid"])
+}
+
 func (s *EventRepositorySuite) TestFindBySessionID_Empty() {
 	ctx := context.Background()
 
@@ -242,3 +402,181 @@ func (s *EventRepositorySuite) TestCountBySessionID_Empty() {
 	s.Require().NoError(err)
 	s.Equal(0, count)
 }
+
+func (s *EventRepositorySuite) TestFindSessionIDsByUUID() {
+	ctx := context.Background()
+
+	s.createTestSession("session-original")
+	s.createTestSession("session-resumed")
+
+	events := []*domain.Event{
+		{SessionID: "session-original", UUID: "lookup-shared"},
+		{SessionID: "session-original", UUID: "lookup-original-only"},
+		// A resumed session replaying a line of the original
+		{SessionID: "session-resumed", UUID: "lookup-shared"},
+	}
+	for _, event := range events {
+		event.EventType = "message"
+		event.Payload = map[string]interface{}{}
+		s.Require().NoError(s.Repo.Create(ctx, event))
+	}
+
+	sessionIDs, err := s.Repo.FindSessionIDsByUUID(ctx, "lookup-shared")
+	s.Require().NoError(err)
+	s.ElementsMatch([]string{"session-original", "session-resumed"}, sessionIDs)
+
+	sessionIDs, err = s.Repo.FindSessionIDsByUUID(ctx, "lookup-original-only")
+	s.Require().NoError(err)
+	s.Equal([]string{"session-original"}, sessionIDs)
+
+	sessionIDs, err = s.Repo.FindSessionIDsByUUID(ctx, "lookup-unknown")
+	s.Require().NoError(err)
+	s.Empty(sessionIDs)
+}
+
+func (s *EventRepositorySuite) TestSearch() {
+	ctx := context.Background()
+
+	s.createTestSession("session-search-1")
+	s.createTestSession("session-search-2")
+
+	userEvent := &domain.Event{
+		SessionID: "session-search-1",
+		UUID:      "search-uuid-1",
+		EventType: "user",
+		Payload: map[string]interface{}{
+			"type":    "user",
+			"message": map[string]interface{}{"content": "Please fix the flaky migration test"},
+		},
+	}
+	toolEvent := &domain.Event{
+		SessionID: "session-search-2",
+		UUID:      "search-uuid-2",
+		EventType: "assistant",
+		Payload: map[string]interface{}{
+			"type": "assistant",
+			"message": map[string]interface{}{
+				"content": []interface{}{
+					map[string]interface{}{
+						"type":  "tool_use",
+						"name":  "Bash",
+						"input": map[string]interface{}{"command": "go test ./migrations -run TestFlakyMigration"},
+					},
+				},
+			},
+		},
+	}
+	otherEvent := &domain.Event{
+		SessionID: "session-search-2",
+		UUID:      "search-uuid-3",
+		EventType: "user",
+		Payload: map[string]interface{}{
+			"type":    "user",
+			"message": map[string]interface{}{"content": "Update the README"},
+		},
+	}
+	s.Require().NoError(s.Repo.Create(ctx, userEvent))
+	s.Require().NoError(s.Repo.Create(ctx, toolEvent))
+	s.Require().NoError(s.Repo.Create(ctx, otherEvent))
+
+	matches, err := s.Repo.Search(ctx, "flaky migration", domain.SessionViewer{}, 10)
+	s.Require().NoError(err)
+	s.Require().Len(matches, 1)
+	s.Equal(userEvent.ID, matches[0].EventID)
+	s.Equal("session-search-1", matches[0].SessionID)
+	s.Contains(matches[0].Text, "flaky migration test")
+
+	// Tool inputs are searchable too
+	matches, err = s.Repo.Search(ctx, "migrations", domain.SessionViewer{}, 10)
+	s.Require().NoError(err)
+	s.Require().Len(matches, 1)
+	s.Equal(toolEvent.ID, matches[0].EventID)
+}
+
+func (s *EventRepositorySuite) TestSearch_Visibility() {
+	if s.SessionRepo == nil {
+		s.T().Skip("SessionRepo is required to set session visibility")
+	}
+	ctx := context.Background()
+
+	public := &domain.Session{ID: "session-search-public", ClaudeSessionID: "claude-session-search-public"}
+	team := &domain.Session{ID: "session-search-team", ClaudeSessionID: "claude-session-search-team", Visibility: domain.SessionVisibilityTeam}
+	s.Require().NoError(s.SessionRepo.Create(ctx, public))
+	s.Require().NoError(s.SessionRepo.Create(ctx, team))
+
+	// More matches in the hidden session than the limit
+	for i := 0; i < 3; i++ {
+		s.Require().NoError(s.Repo.Create(ctx, &domain.Event{
+			SessionID: team.ID,
+			UUID:      "search-visibility-team-" + string(rune('a'+i)),
+			EventType: "user",
+			Payload:   map[string]interface{}{"message": map[string]interface{}{"content": "quokka in the team session"}},
+		}))
+	}
+	publicEvent := &domain.Event{
+		SessionID: public.ID,
+		UUID:      "search-visibility-public",
+		EventType: "user",
+		Payload:   map[string]interface{}{"message": map[string]interface{}{"content": "quokka in the public session"}},
+		CreatedAt: time.Now().Add(-time.Hour),
+	}
+	s.Require().NoError(s.Repo.Create(ctx, publicEvent))
+
+	matches, err := s.Repo.Search(ctx, "quokka", domain.SessionViewer{}, 2)
+	s.Require().NoError(err)
+	s.Require().Len(matches, 1)
+	s.Equal(publicEvent.ID, matches[0].EventID)
+
+	matches, err = s.Repo.Search(ctx, "quokka", domain.SessionViewer{UserID: "search-visibility-user"}, 10)
+	s.Require().NoError(err)
+	s.Len(matches, 4)
+
+	matches, err = s.Repo.Search(ctx, "quokka", domain.SessionViewer{UserID: "search-visibility-user", HiddenProjectIDs: []string{domain.DefaultProjectID}}, 10)
+	s.Require().NoError(err)
+	s.Empty(matches)
+}
+
+func (s *EventRepositorySuite) TestSearch_NoMatch() {
+	ctx := context.Background()
+
+	matches, err := s.Repo.Search(ctx, "nonexistentsearchterm", domain.SessionViewer{}, 10)
+	s.Require().NoError(err)
+	s.Empty(matches)
+
+	matches, err = s.Repo.Search(ctx, "   ", domain.SessionViewer{}, 10)
+	s.Require().NoError(err)
+	s.Empty(matches)
+}
+
+func (s *EventRepositorySuite) TestDeleteBySessionID() {
+	ctx := context.Background()
+
+	s.createTestSession("session-delete-1")
+	s.createTestSession("session-delete-2")
+
+	for _, sessionID := range []string{"session-delete-1", "session-delete-2"} {
+		_, err := s.Repo.CreateBatch(ctx, []*domain.Event{
+			{SessionID: sessionID, UUID: "delete-uuid-1", EventType: "user", Payload: map[string]interface{}{"type": "user"}},
+			{SessionID: sessionID, UUID: "delete-uuid-2", EventType: "assistant", Payload: map[string]interface{}{"type": "assistant"}},
+		})
+		s.Require().NoError(err)
+	}
+
+	err := s.Repo.DeleteBySessionID(ctx, "session-delete-1")
+	s.Require().NoError(err)
+
+	count, err := s.Repo.CountBySessionID(ctx, "session-delete-1")
+	s.Require().NoError(err)
+	s.Equal(0, count)
+	count, err = s.Repo.CountBySessionID(ctx, "session-delete-2")
+	s.Require().NoError(err)
+	s.Equal(2, count)
+
+	sessionIDs, err := s.Repo.FindSessionIDsByUUID(ctx, "delete-uuid-1")
+	s.Require().NoError(err)
+	s.Equal([]string{"session-delete-2"}, sessionIDs)
+
+	// The uuids are free again in the deleted session
+	err = s.Repo.Create(ctx, &domain.Event{SessionID: "session-delete-1", UUID: "delete-uuid-1", EventType: "user", Payload: map[string]interface{}{"type": "user"}})
+	s.NoError(err)
+}
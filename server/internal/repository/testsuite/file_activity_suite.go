@@ -0,0 +1,180 @@
+package testsuite
+
+import (
+	"context"
+	"time"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+	"github.com/satetsu888/agentrace/server/internal/repository"
+	"github.com/stretchr/testify/suite"
+)
+
+// FileActivityRepositorySuite tests FileActivityRepository implementations
+type FileActivityRepositorySuite struct {
+	suite.Suite
+	Repo        repository.FileActivityRepository
+	SessionRepo repository.SessionRepository // Optional: for FK constraint support
+	Cleanup     func()
+}
+
+// createTestSession creates a session for FK constraint tests
+func (s *FileActivityRepositorySuite) createTestSession(id string) {
+	if s.SessionRepo == nil {
+		return
+	}
+	ctx := context.Background()
+	session := &domain.Session{
+		ID:              id,
+		ClaudeSessionID: "claude-" + id,
+	}
+	_ = s.SessionRepo.Create(ctx, session)
+}
+
+func (s *FileActivityRepositorySuite) TearDownTest() {
+	if s.Cleanup != nil {
+		s.Cleanup()
+	}
+}
+
+func (s *FileActivityRepositorySuite) createActivity(sessionID, projectID, path string, operation domain.FileOperation, createdAt time.Time) *domain.FileActivity {
+	activity := &domain.FileActivity{
+		SessionID: sessionID,
+		ProjectID: projectID,
+		EventID:   "event-" + path,
+		Path:      path,
+		Operation: operation,
+		CreatedAt: createdAt,
+	}
+	s.Require().NoError(s.Repo.Create(context.Background(), activity))
+	return activity
+}
+
+func (s *FileActivityRepositorySuite) TestCreate() {
+	ctx := context.Background()
+
+	s.createTestSession("session-1")
+
+	activity := &domain.FileActivity{
+		SessionID: "session-1",
+		ProjectID: "project-1",
+		EventID:   "event-1",
+		Path:      "main.go",
+		Operation: domain.FileOperationEdit,
+	}
+
+	err := s.Repo.Create(ctx, activity)
+	s.Require().NoError(err)
+
+	// ID should be auto-generated
+	s.NotEmpty(activity.ID)
+
+	// CreatedAt should be set
+	s.False(activity.CreatedAt.IsZero())
+}
+
+func (s *FileActivityRepositorySuite) TestFindBySessionID() {
+	ctx := context.Background()
+
+	s.createTestSession("find-session-1")
+	s.createTestSession("find-session-2")
+
+	base := time.Now().Truncate(time.Second)
+	s.createActivity("find-session-1", "find-project-1", "b.go", domain.FileOperationWrite, base.Add(2*time.Second))
+	s.createActivity("find-session-1", "find-project-1", "a.go", domain.FileOperationEdit, base)
+	s.createActivity("find-session-2", "find-project-1", "a.go", domain.FileOperationEdit, base.Add(time.Second))
+
+	activities, err := s.Repo.FindBySessionID(ctx, "find-session-1")
+	s.Require().NoError(err)
+	s.Require().Len(activities, 2)
+
+	// Oldest first
+	s.Equal("a.go", activities[0].Path)
+	s.Equal(domain.FileOperationEdit, activities[0].Operation)
+	s.Equal("b.go", activities[1].Path)
+	s.Equal(domain.FileOperationWrite, activities[1].Operation)
+	s.Equal("find-project-1", activities[1].ProjectID)
+	s.Equal("event-b.go", activities[1].EventID)
+	s.True(activities[1].CreatedAt.Equal(base.Add(2 * time.Second)))
+
+	activities, err = s.Repo.FindBySessionID(ctx, "non-existent")
+	s.Require().NoError(err)
+	s.Empty(activities)
+}
+
+func (s *FileActivityRepositorySuite) TestFindByProjectIDAndPath() {
+	ctx := context.Background()
+
+	s.createTestSession("path-session-1")
+	s.createTestSession("path-session-2")
+
+	base := time.Now().Truncate(time.Second)
+	s.createActivity("path-session-1", "path-project-1", "main.go", domain.FileOperationEdit, base)
+	s.createActivity("path-session-2", "path-project-1", "main.go", domain.FileOperationWrite, base.Add(time.Second))
+	s.createActivity("path-session-2", "path-project-1", "other.go", domain.FileOperationEdit, base)
+	s.createActivity("path-session-2", "path-project-2", "main.go", domain.FileOperationEdit, base)
+
+	activities, err := s.Repo.FindByProjectIDAndPath(ctx, "path-project-1", "main.go")
+	s.Require().NoError(err)
+	s.Require().Len(activities, 2)
+
+	// Newest first
+	s.Equal("path-session-2", activities[0].SessionID)
+	s.Equal("path-session-1", activities[1].SessionID)
+
+	activities, err = s.Repo.FindByProjectIDAndPath(ctx, "path-project-1", "missing.go")
+	s.Require().NoError(err)
+	s.Empty(activities)
+}
+
+func (s *FileActivityRepositorySuite) TestUpdateProjectIDBySessionID() {
+	ctx := context.Background()
+
+	s.createTestSession("move-session-1")
+
+	base := time.Now().Truncate(time.Second)
+	s.createActivity("move-session-1", "move-project-1", "main.go", domain.FileOperationEdit, base)
+	s.createActivity("move-session-1", "move-project-1", "go.mod", domain.FileOperationEdit, base.Add(time.Second))
+
+	err := s.Repo.UpdateProjectIDBySessionID(ctx, "move-session-1", "move-project-2")
+	s.Require().NoError(err)
+
+	activities, err := s.Repo.FindByProjectIDAndPath(ctx, "move-project-1", "main.go")
+	s.Require().NoError(err)
+	s.Empty(activities)
+
+	activities, err = s.Repo.FindByProjectIDAndPath(ctx, "move-project-2", "main.go")
+	s.Require().NoError(err)
+	s.Require().Len(activities, 1)
+	s.Equal("move-project-2", activities[0].ProjectID)
+
+	activities, err = s.Repo.FindBySessionID(ctx, "move-session-1")
+	s.Require().NoError(err)
+	s.Require().Len(activities, 2)
+	for _, a := range activities {
+		s.Equal("move-project-2", a.ProjectID)
+	}
+}
+
+func (s *FileActivityRepositorySuite) TestDeleteBySessionID() {
+	ctx := context.Background()
+
+	s.createTestSession("delete-session-1")
+	s.createTestSession("delete-session-2")
+
+	base := time.Now().Truncate(time.Second)
+	s.createActivity("delete-session-1", "delete-project", "main.go", domain.FileOperationEdit, base)
+	s.createActivity("delete-session-1", "delete-project", "go.mod", domain.FileOperationEdit, base.Add(time.Second))
+	s.createActivity("delete-session-2", "delete-project", "main.go", domain.FileOperationEdit, base.Add(2*time.Second))
+
+	err := s.Repo.DeleteBySessionID(ctx, "delete-session-1")
+	s.Require().NoError(err)
+
+	activities, err := s.Repo.FindBySessionID(ctx, "delete-session-1")
+	s.Require().NoError(err)
+	s.Empty(activities)
+
+	activities, err = s.Repo.FindByProjectIDAndPath(ctx, "delete-project", "main.go")
+	s.Require().NoError(err)
+	s.Require().Len(activities, 1)
+	s.Equal("delete-session-2", activities[0].SessionID)
+}
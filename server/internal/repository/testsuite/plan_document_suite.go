@@ -128,6 +128,41 @@ func (s *PlanDocumentRepositorySuite) TestFind_ByProjectID() {
 	}
 }
 
+func (s *PlanDocumentRepositorySuite) TestFind_ExcludeProjectIDs() {
+	ctx := context.Background()
+
+	s.createTestProject("exclude-project-shared")
+	s.createTestProject("exclude-project-team")
+
+	shared := &domain.PlanDocument{
+		ProjectID:   "exclude-project-shared",
+		Description: "Exclude shared plan",
+		Status:      domain.PlanDocumentStatusPlanning,
+	}
+	s.Require().NoError(s.Repo.Create(ctx, shared))
+	team := &domain.PlanDocument{
+		ProjectID:   "exclude-project-team",
+		Description: "Exclude team plan",
+		Status:      domain.PlanDocumentStatusPlanning,
+	}
+	s.Require().NoError(s.Repo.Create(ctx, team))
+
+	docs, _, err := s.Repo.Find(ctx, domain.PlanDocumentQuery{
+		PlanDocumentIDs:   []string{shared.ID, team.ID},
+		ExcludeProjectIDs: []string{"exclude-project-team"},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(docs, 1)
+	s.Equal(shared.ID, docs[0].ID)
+
+	docs, _, err = s.Repo.Find(ctx, domain.PlanDocumentQuery{
+		ProjectID:         "exclude-project-team",
+		ExcludeProjectIDs: []string{"exclude-project-team"},
+	})
+	s.Require().NoError(err)
+	s.Empty(docs)
+}
+
 func (s *PlanDocumentRepositorySuite) TestFind_ByStatuses() {
 	ctx := context.Background()
 
@@ -329,6 +364,44 @@ func (s *PlanDocumentRepositorySuite) TestUpdate() {
 	s.Require().NoError(err)
 	s.Equal("Updated Description", found.Description)
 	s.Equal("Updated Body", found.Body)
+	s.Equal(2, found.Revision)
+}
+
+func (s *PlanDocumentRepositorySuite) TestUpdate_RevisionConflict() {
+	ctx := context.Background()
+
+	s.createTestProject("update-conflict-project")
+
+	doc := &domain.PlanDocument{
+		ProjectID:   "update-conflict-project",
+		Description: "Conflict Plan",
+		Body:        "Original Body",
+		Status:      domain.PlanDocumentStatusPlanning,
+	}
+	err := s.Repo.Create(ctx, doc)
+	s.Require().NoError(err)
+	s.Equal(1, doc.Revision)
+
+	// Two writers read the same revision
+	first, err := s.Repo.FindByID(ctx, doc.ID)
+	s.Require().NoError(err)
+	second, err := s.Repo.FindByID(ctx, doc.ID)
+	s.Require().NoError(err)
+
+	first.Body = "First Body"
+	err = s.Repo.Update(ctx, first)
+	s.Require().NoError(err)
+	s.Equal(2, first.Revision)
+
+	// The second writer is stale and must not overwrite the first
+	second.Body = "Second Body"
+	err = s.Repo.Update(ctx, second)
+	s.ErrorIs(err, repository.ErrRevisionConflict)
+
+	found, err := s.Repo.FindByID(ctx, doc.ID)
+	s.Require().NoError(err)
+	s.Equal("First Body", found.Body)
+	s.Equal(2, found.Revision)
 }
 
 func (s *PlanDocumentRepositorySuite) TestDelete() {
@@ -369,11 +442,17 @@ func (s *PlanDocumentRepositorySuite) TestSetStatus() {
 	s.Require().NoError(err)
 
 	// Change status
-	err = s.Repo.SetStatus(ctx, doc.ID, domain.PlanDocumentStatusImplementation)
+	revision, err := s.Repo.SetStatus(ctx, doc.ID, domain.PlanDocumentStatusImplementation, doc.Revision)
 	s.Require().NoError(err)
+	s.Equal(2, revision)
 
 	// Verify
 	found, err := s.Repo.FindByID(ctx, doc.ID)
 	s.Require().NoError(err)
 	s.Equal(domain.PlanDocumentStatusImplementation, found.Status)
+	s.Equal(2, found.Revision)
+
+	// A stale revision is rejected
+	_, err = s.Repo.SetStatus(ctx, doc.ID, domain.PlanDocumentStatusComplete, doc.Revision)
+	s.ErrorIs(err, repository.ErrRevisionConflict)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/satetsu888/agentrace/server/internal/domain"
 	"github.com/satetsu888/agentrace/server/internal/repository"
 	"github.com/stretchr/testify/suite"
@@ -12,8 +13,19 @@ import (
 // ProjectRepositorySuite tests ProjectRepository implementations
 type ProjectRepositorySuite struct {
 	suite.Suite
-	Repo    repository.ProjectRepository
-	Cleanup func()
+	Repo     repository.ProjectRepository
+	TeamRepo repository.TeamRepository // Optional: for FK constraint support
+	Cleanup  func()
+}
+
+// createTestTeam creates a team for FK constraint tests and returns its ID
+func (s *ProjectRepositorySuite) createTestTeam(name string) string {
+	if s.TeamRepo == nil {
+		return uuid.New().String()
+	}
+	team := &domain.Team{Name: name}
+	s.Require().NoError(s.TeamRepo.Create(context.Background(), team))
+	return team.ID
 }
 
 func (s *ProjectRepositorySuite) SetupTest() {
@@ -214,3 +226,43 @@ func (s *ProjectRepositorySuite) TestGetDefaultProject() {
 	s.Equal(domain.DefaultProjectID, defaultProject.ID)
 	s.Empty(defaultProject.CanonicalGitRepository)
 }
+
+func (s *ProjectRepositorySuite) TestUpdateTeamID() {
+	ctx := context.Background()
+
+	teamID := s.createTestTeam("project-team")
+
+	project := &domain.Project{CanonicalGitRepository: "https://github.com/example/team-repo"}
+	s.Require().NoError(s.Repo.Create(ctx, project))
+	other := &domain.Project{CanonicalGitRepository: "https://github.com/example/shared-repo"}
+	s.Require().NoError(s.Repo.Create(ctx, other))
+
+	// Projects are visible to everyone until a team is set
+	found, err := s.Repo.FindByID(ctx, project.ID)
+	s.Require().NoError(err)
+	s.Nil(found.TeamID)
+
+	err = s.Repo.UpdateTeamID(ctx, project.ID, &teamID)
+	s.Require().NoError(err)
+
+	found, err = s.Repo.FindByCanonicalGitRepository(ctx, project.CanonicalGitRepository)
+	s.Require().NoError(err)
+	s.Require().NotNil(found.TeamID)
+	s.Equal(teamID, *found.TeamID)
+
+	owned, err := s.Repo.FindTeamOwned(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(owned, 1)
+	s.Equal(project.ID, owned[0].ID)
+
+	err = s.Repo.UpdateTeamID(ctx, project.ID, nil)
+	s.Require().NoError(err)
+
+	found, err = s.Repo.FindByID(ctx, project.ID)
+	s.Require().NoError(err)
+	s.Nil(found.TeamID)
+
+	owned, err = s.Repo.FindTeamOwned(ctx)
+	s.Require().NoError(err)
+	s.Empty(owned)
+}
@@ -151,7 +151,7 @@ func (s *SessionRepositorySuite) TestFindAll() {
 	}
 
 	// Find all with limit, default sort (updated_at), cursor-based pagination
-	sessions, nextCursor, err := s.Repo.FindAll(ctx, 3, "", "")
+	sessions, nextCursor, err := s.Repo.FindAll(ctx, domain.SessionViewer{}, "", 3, "", "")
 	s.Require().NoError(err)
 	s.Len(sessions, 3)
 	s.NotEmpty(nextCursor) // More items available
@@ -171,7 +171,7 @@ func (s *SessionRepositorySuite) TestFindAll_SortByCreatedAt() {
 	}
 
 	// Find all sorted by created_at (cursor-based pagination)
-	sessions, _, err := s.Repo.FindAll(ctx, 5, "", "created_at")
+	sessions, _, err := s.Repo.FindAll(ctx, domain.SessionViewer{}, "", 5, "", "created_at")
 	s.Require().NoError(err)
 	s.GreaterOrEqual(len(sessions), 5)
 
@@ -208,7 +208,7 @@ func (s *SessionRepositorySuite) TestFindByProjectID() {
 	s.Require().NoError(err)
 
 	// Find by project ID (cursor-based pagination)
-	sessions, _, err := s.Repo.FindByProjectID(ctx, projectID, 10, "", "")
+	sessions, _, err := s.Repo.FindByProjectID(ctx, projectID, domain.SessionViewer{}, "", 10, "", "")
 	s.Require().NoError(err)
 	s.Len(sessions, 3)
 
@@ -217,6 +217,160 @@ func (s *SessionRepositorySuite) TestFindByProjectID() {
 	}
 }
 
+func (s *SessionRepositorySuite) TestFindAll_HiddenProjects() {
+	ctx := context.Background()
+
+	s.createTestProject("hidden-project-shared")
+	s.createTestProject("hidden-project-team")
+
+	shared := &domain.Session{ClaudeSessionID: "hidden-shared", ProjectID: "hidden-project-shared"}
+	s.Require().NoError(s.Repo.Create(ctx, shared))
+	team := &domain.Session{ClaudeSessionID: "hidden-team", ProjectID: "hidden-project-team"}
+	s.Require().NoError(s.Repo.Create(ctx, team))
+	// The viewer's own private session, left in the project after it was given to a team
+	s.createTestUser("hidden-viewer")
+	ownerID := "hidden-viewer"
+	own := &domain.Session{ClaudeSessionID: "hidden-own", UserID: &ownerID, ProjectID: "hidden-project-team", Visibility: domain.SessionVisibilityPrivate}
+	s.Require().NoError(s.Repo.Create(ctx, own))
+
+	ids := func(viewer domain.SessionViewer) map[string]bool {
+		sessions, _, err := s.Repo.FindAll(ctx, viewer, "", 100, "", "")
+		s.Require().NoError(err)
+		ids := make(map[string]bool)
+		for _, sess := range sessions {
+			ids[sess.ID] = true
+		}
+		return ids
+	}
+
+	found := ids(domain.SessionViewer{UserID: "hidden-viewer"})
+	s.True(found[shared.ID])
+	s.True(found[team.ID])
+
+	viewer := domain.SessionViewer{UserID: "hidden-viewer", HiddenProjectIDs: []string{"hidden-project-team"}}
+	found = ids(viewer)
+	s.True(found[shared.ID])
+	s.False(found[team.ID])
+	s.True(found[own.ID])
+
+	sessions, _, err := s.Repo.FindByProjectID(ctx, "hidden-project-team", viewer, "", 10, "", "")
+	s.Require().NoError(err)
+	s.Require().Len(sessions, 1)
+	s.Equal(own.ID, sessions[0].ID)
+
+	// Others do not see it
+	found = ids(domain.SessionViewer{UserID: "hidden-other", HiddenProjectIDs: []string{"hidden-project-team"}})
+	s.False(found[own.ID])
+}
+
+func (s *SessionRepositorySuite) TestFindByProjectID_Visibility() {
+	ctx := context.Background()
+
+	projectID := "visibility-project-id"
+	s.createTestProject(projectID)
+	s.createTestUser("visibility-owner")
+	s.createTestUser("visibility-other")
+	owner, other := "visibility-owner", "visibility-other"
+
+	// One second apart so the order survives backends storing whole seconds
+	startedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	create := func(claudeSessionID string, userID *string, visibility domain.SessionVisibility) *domain.Session {
+		startedAt = startedAt.Add(time.Second)
+		session := &domain.Session{
+			ClaudeSessionID: claudeSessionID,
+			UserID:          userID,
+			ProjectID:       projectID,
+			Visibility:      visibility,
+			StartedAt:       startedAt,
+		}
+		s.Require().NoError(s.Repo.Create(ctx, session))
+		return session
+	}
+	public := create("visibility-public", &owner, "")
+	team := create("visibility-team", &owner, domain.SessionVisibilityTeam)
+	private := create("visibility-private", &owner, domain.SessionVisibilityPrivate)
+	otherPrivate := create("visibility-other-private", &other, domain.SessionVisibilityPrivate)
+
+	// Sessions are public unless stated otherwise
+	found, err := s.Repo.FindByID(ctx, public.ID)
+	s.Require().NoError(err)
+	s.Equal(domain.SessionVisibilityPublic, found.Visibility)
+
+	ids := func(viewer domain.SessionViewer) []string {
+		sessions, _, err := s.Repo.FindByProjectID(ctx, projectID, viewer, "", 10, "", "")
+		s.Require().NoError(err)
+		var ids []string
+		for _, sess := range sessions {
+			ids = append(ids, sess.ID)
+		}
+		return ids
+	}
+
+	s.Equal([]string{public.ID}, ids(domain.SessionViewer{}))
+	s.Equal([]string{private.ID, team.ID, public.ID}, ids(domain.SessionViewer{UserID: owner}))
+	s.Equal([]string{otherPrivate.ID, team.ID, public.ID}, ids(domain.SessionViewer{UserID: other}))
+
+	// Pages are filled with readable sessions only
+	sessions, nextCursor, err := s.Repo.FindByProjectID(ctx, projectID, domain.SessionViewer{UserID: other}, "", 2, "", "")
+	s.Require().NoError(err)
+	s.Require().Len(sessions, 2)
+	s.Equal(otherPrivate.ID, sessions[0].ID)
+	s.Equal(team.ID, sessions[1].ID)
+	s.Require().NotEmpty(nextCursor)
+
+	sessions, nextCursor, err = s.Repo.FindByProjectID(ctx, projectID, domain.SessionViewer{UserID: other}, "", 2, nextCursor, "")
+	s.Require().NoError(err)
+	s.Require().Len(sessions, 1)
+	s.Equal(public.ID, sessions[0].ID)
+	s.Empty(nextCursor)
+}
+
+func (s *SessionRepositorySuite) TestFindByProjectID_State() {
+	ctx := context.Background()
+
+	projectID := "state-project-id"
+	s.createTestProject(projectID)
+
+	create := func(claudeSessionID string) *domain.Session {
+		session := &domain.Session{ClaudeSessionID: claudeSessionID, ProjectID: projectID}
+		s.Require().NoError(s.Repo.Create(ctx, session))
+		return session
+	}
+	active := create("state-active")
+	ended := create("state-ended")
+	endedToo := create("state-ended-too")
+	resumed := create("state-resumed")
+
+	later := time.Now().Add(time.Hour)
+	s.Require().NoError(s.Repo.MarkEnded(ctx, ended.ID, later))
+	s.Require().NoError(s.Repo.MarkEnded(ctx, endedToo.ID, later))
+	// Activity after the end makes the session active again
+	s.Require().NoError(s.Repo.MarkEnded(ctx, resumed.ID, time.Now().Add(-time.Hour)))
+	s.Require().NoError(s.Repo.UpdateUpdatedAt(ctx, resumed.ID, time.Now()))
+
+	// Pages of one session each, so that the state is filtered before the limit
+	ids := func(state domain.SessionState) map[string]bool {
+		ids := make(map[string]bool)
+		cursor := ""
+		for {
+			sessions, nextCursor, err := s.Repo.FindByProjectID(ctx, projectID, domain.SessionViewer{}, state, 1, cursor, "")
+			s.Require().NoError(err)
+			for _, sess := range sessions {
+				s.Equal(state, sess.State())
+				ids[sess.ID] = true
+			}
+			if nextCursor == "" {
+				return ids
+			}
+			s.Require().Len(sessions, 1)
+			cursor = nextCursor
+		}
+	}
+
+	s.Equal(map[string]bool{ended.ID: true, endedToo.ID: true}, ids(domain.SessionStateEnded))
+	s.Equal(map[string]bool{active.ID: true, resumed.ID: true}, ids(domain.SessionStateActive))
+}
+
 func (s *SessionRepositorySuite) TestFindOrCreateByClaudeSessionID_Create() {
 	ctx := context.Background()
 
@@ -389,3 +543,179 @@ func (s *SessionRepositorySuite) TestUpdateUpdatedAt() {
 	s.Require().NoError(err)
 	s.WithinDuration(newTime, found.UpdatedAt, time.Second)
 }
+
+func (s *SessionRepositorySuite) TestUpdateStartedAt() {
+	ctx := context.Background()
+
+	session := &domain.Session{
+		ClaudeSessionID: "session-update-startedat",
+	}
+	err := s.Repo.Create(ctx, session)
+	s.Require().NoError(err)
+
+	earlier := time.Now().Add(-3 * time.Hour)
+	err = s.Repo.UpdateStartedAt(ctx, session.ID, earlier)
+	s.Require().NoError(err)
+
+	found, err := s.Repo.FindByID(ctx, session.ID)
+	s.Require().NoError(err)
+	s.WithinDuration(earlier, found.StartedAt, time.Second)
+}
+
+func (s *SessionRepositorySuite) TestUpdateParentSessionID() {
+	ctx := context.Background()
+
+	base := time.Now().Add(-time.Hour)
+	parent := &domain.Session{ClaudeSessionID: "session-chain-parent", StartedAt: base}
+	s.Require().NoError(s.Repo.Create(ctx, parent))
+	// Created out of order: children are returned oldest first
+	fork := &domain.Session{ClaudeSessionID: "session-chain-fork", StartedAt: base.Add(20 * time.Minute)}
+	s.Require().NoError(s.Repo.Create(ctx, fork))
+	resumed := &domain.Session{ClaudeSessionID: "session-chain-resumed", StartedAt: base.Add(10 * time.Minute)}
+	s.Require().NoError(s.Repo.Create(ctx, resumed))
+
+	s.Require().NoError(s.Repo.UpdateParentSessionID(ctx, fork.ID, parent.ID))
+	s.Require().NoError(s.Repo.UpdateParentSessionID(ctx, resumed.ID, parent.ID))
+
+	found, err := s.Repo.FindByID(ctx, resumed.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(found.ParentSessionID)
+	s.Equal(parent.ID, *found.ParentSessionID)
+
+	found, err = s.Repo.FindByID(ctx, parent.ID)
+	s.Require().NoError(err)
+	s.Nil(found.ParentSessionID)
+
+	children, err := s.Repo.FindByParentSessionID(ctx, parent.ID)
+	s.Require().NoError(err)
+	s.Require().Len(children, 2)
+	s.Equal(resumed.ID, children[0].ID)
+	s.Equal(fork.ID, children[1].ID)
+
+	children, err = s.Repo.FindByParentSessionID(ctx, resumed.ID)
+	s.Require().NoError(err)
+	s.Empty(children)
+}
+
+func (s *SessionRepositorySuite) TestUpdateVisibility() {
+	ctx := context.Background()
+
+	session := &domain.Session{ClaudeSessionID: "update-visibility-session"}
+	s.Require().NoError(s.Repo.Create(ctx, session))
+
+	err := s.Repo.UpdateVisibility(ctx, session.ID, domain.SessionVisibilityPrivate)
+	s.Require().NoError(err)
+
+	found, err := s.Repo.FindByID(ctx, session.ID)
+	s.Require().NoError(err)
+	s.Equal(domain.SessionVisibilityPrivate, found.Visibility)
+}
+
+func (s *SessionRepositorySuite) TestMarkEnded() {
+	ctx := context.Background()
+
+	session := &domain.Session{
+		ClaudeSessionID: "session-mark-ended",
+	}
+	err := s.Repo.Create(ctx, session)
+	s.Require().NoError(err)
+
+	found, err := s.Repo.FindByID(ctx, session.ID)
+	s.Require().NoError(err)
+	s.Nil(found.EndedAt)
+	s.Equal(domain.SessionStateActive, found.State())
+
+	endedAt := time.Now().Add(1 * time.Hour)
+	err = s.Repo.MarkEnded(ctx, session.ID, endedAt)
+	s.Require().NoError(err)
+
+	found, err = s.Repo.FindByID(ctx, session.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(found.EndedAt)
+	s.WithinDuration(endedAt, *found.EndedAt, time.Second)
+	s.Equal(domain.SessionStateEnded, found.State())
+}
+
+func (s *SessionRepositorySuite) TestAddRedactionCount() {
+	ctx := context.Background()
+
+	session := &domain.Session{
+		ClaudeSessionID: "session-redaction-count",
+	}
+	err := s.Repo.Create(ctx, session)
+	s.Require().NoError(err)
+
+	err = s.Repo.AddRedactionCount(ctx, session.ID, 2)
+	s.Require().NoError(err)
+	err = s.Repo.AddRedactionCount(ctx, session.ID, 3)
+	s.Require().NoError(err)
+
+	found, err := s.Repo.FindByID(ctx, session.ID)
+	s.Require().NoError(err)
+	s.Equal(5, found.RedactionCount)
+}
+
+func (s *SessionRepositorySuite) TestAddSubagentCount() {
+	ctx := context.Background()
+
+	session := &domain.Session{
+		ClaudeSessionID: "session-subagent-count",
+	}
+	err := s.Repo.Create(ctx, session)
+	s.Require().NoError(err)
+
+	err = s.Repo.AddSubagentCount(ctx, session.ID, 2)
+	s.Require().NoError(err)
+	err = s.Repo.AddSubagentCount(ctx, session.ID, 3)
+	s.Require().NoError(err)
+
+	found, err := s.Repo.FindByID(ctx, session.ID)
+	s.Require().NoError(err)
+	s.Equal(5, found.SubagentCount)
+}
+
+func (s *SessionRepositorySuite) TestAddUsage() {
+	ctx := context.Background()
+
+	session := &domain.Session{
+		ClaudeSessionID: "session-add-usage",
+	}
+	err := s.Repo.Create(ctx, session)
+	s.Require().NoError(err)
+
+	err = s.Repo.AddUsage(ctx, session.ID, domain.TokenUsage{InputTokens: 10, OutputTokens: 200, CacheReadTokens: 3000, EstimatedCostUSD: 0.25})
+	s.Require().NoError(err)
+	err = s.Repo.AddUsage(ctx, session.ID, domain.TokenUsage{InputTokens: 5, CacheCreationTokens: 400, EstimatedCostUSD: 0.5})
+	s.Require().NoError(err)
+
+	found, err := s.Repo.FindByID(ctx, session.ID)
+	s.Require().NoError(err)
+	s.Equal(int64(15), found.Usage.InputTokens)
+	s.Equal(int64(200), found.Usage.OutputTokens)
+	s.Equal(int64(3000), found.Usage.CacheReadTokens)
+	s.Equal(int64(400), found.Usage.CacheCreationTokens)
+	s.InDelta(0.75, found.Usage.EstimatedCostUSD, 1e-9)
+}
+
+func (s *SessionRepositorySuite) TestDelete() {
+	ctx := context.Background()
+
+	session := &domain.Session{ClaudeSessionID: "session-delete"}
+	s.Require().NoError(s.Repo.Create(ctx, session))
+	other := &domain.Session{ClaudeSessionID: "session-delete-other"}
+	s.Require().NoError(s.Repo.Create(ctx, other))
+
+	err := s.Repo.Delete(ctx, session.ID)
+	s.Require().NoError(err)
+
+	found, err := s.Repo.FindByID(ctx, session.ID)
+	s.Require().NoError(err)
+	s.Nil(found)
+	found, err = s.Repo.FindByClaudeSessionID(ctx, "session-delete")
+	s.Require().NoError(err)
+	s.Nil(found)
+
+	found, err = s.Repo.FindByID(ctx, other.ID)
+	s.Require().NoError(err)
+	s.NotNil(found)
+}
@@ -0,0 +1,138 @@
+package testsuite
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+	"github.com/satetsu888/agentrace/server/internal/repository"
+	"github.com/stretchr/testify/suite"
+)
+
+// TeamMembershipRepositorySuite tests TeamMembershipRepository implementations
+type TeamMembershipRepositorySuite struct {
+	suite.Suite
+	Repo     repository.TeamMembershipRepository
+	TeamRepo repository.TeamRepository // Optional: for FK constraint support
+	UserRepo repository.UserRepository // Optional: for FK constraint support
+	Cleanup  func()
+}
+
+// createTestTeam creates a team for FK constraint tests and returns its ID
+func (s *TeamMembershipRepositorySuite) createTestTeam(name string) string {
+	if s.TeamRepo == nil {
+		return uuid.New().String()
+	}
+	team := &domain.Team{Name: name}
+	s.Require().NoError(s.TeamRepo.Create(context.Background(), team))
+	return team.ID
+}
+
+// createTestUser creates a user for FK constraint tests and returns its ID
+func (s *TeamMembershipRepositorySuite) createTestUser(email string) string {
+	if s.UserRepo == nil {
+		return uuid.New().String()
+	}
+	user := &domain.User{Email: email}
+	s.Require().NoError(s.UserRepo.Create(context.Background(), user))
+	return user.ID
+}
+
+func (s *TeamMembershipRepositorySuite) TearDownTest() {
+	if s.Cleanup != nil {
+		s.Cleanup()
+	}
+}
+
+func (s *TeamMembershipRepositorySuite) TestCreate() {
+	ctx := context.Background()
+
+	teamID := s.createTestTeam("membership-create-team")
+	userID := s.createTestUser("membership-create@example.com")
+
+	membership := &domain.TeamMembership{TeamID: teamID, UserID: userID}
+	err := s.Repo.Create(ctx, membership)
+	s.Require().NoError(err)
+
+	// CreatedAt should be set
+	s.False(membership.CreatedAt.IsZero())
+
+	// Adding an existing member is a no-op
+	err = s.Repo.Create(ctx, &domain.TeamMembership{TeamID: teamID, UserID: userID})
+	s.Require().NoError(err)
+
+	memberships, err := s.Repo.FindByTeamID(ctx, teamID)
+	s.Require().NoError(err)
+	s.Require().Len(memberships, 1)
+	s.Equal(userID, memberships[0].UserID)
+	s.Equal(teamID, memberships[0].TeamID)
+}
+
+func (s *TeamMembershipRepositorySuite) TestFindByUserID() {
+	ctx := context.Background()
+
+	team1 := s.createTestTeam("membership-user-team-1")
+	team2 := s.createTestTeam("membership-user-team-2")
+	user := s.createTestUser("membership-user@example.com")
+	other := s.createTestUser("membership-user-other@example.com")
+
+	s.Require().NoError(s.Repo.Create(ctx, &domain.TeamMembership{TeamID: team1, UserID: user}))
+	s.Require().NoError(s.Repo.Create(ctx, &domain.TeamMembership{TeamID: team2, UserID: user}))
+	s.Require().NoError(s.Repo.Create(ctx, &domain.TeamMembership{TeamID: team1, UserID: other}))
+
+	memberships, err := s.Repo.FindByUserID(ctx, user)
+	s.Require().NoError(err)
+	s.Require().Len(memberships, 2)
+	s.ElementsMatch([]string{team1, team2}, []string{memberships[0].TeamID, memberships[1].TeamID})
+
+	memberships, err = s.Repo.FindByTeamID(ctx, team1)
+	s.Require().NoError(err)
+	s.Len(memberships, 2)
+}
+
+func (s *TeamMembershipRepositorySuite) TestDelete() {
+	ctx := context.Background()
+
+	teamID := s.createTestTeam("membership-delete-team")
+	user := s.createTestUser("membership-delete@example.com")
+	other := s.createTestUser("membership-delete-other@example.com")
+
+	s.Require().NoError(s.Repo.Create(ctx, &domain.TeamMembership{TeamID: teamID, UserID: user}))
+	s.Require().NoError(s.Repo.Create(ctx, &domain.TeamMembership{TeamID: teamID, UserID: other}))
+
+	err := s.Repo.Delete(ctx, teamID, user)
+	s.Require().NoError(err)
+
+	memberships, err := s.Repo.FindByTeamID(ctx, teamID)
+	s.Require().NoError(err)
+	s.Require().Len(memberships, 1)
+	s.Equal(other, memberships[0].UserID)
+
+	memberships, err = s.Repo.FindByUserID(ctx, user)
+	s.Require().NoError(err)
+	s.Empty(memberships)
+}
+
+func (s *TeamMembershipRepositorySuite) TestDeleteByTeamID() {
+	ctx := context.Background()
+
+	teamID := s.createTestTeam("membership-delete-all-team")
+	otherTeamID := s.createTestTeam("membership-delete-all-other-team")
+	user := s.createTestUser("membership-delete-all@example.com")
+
+	s.Require().NoError(s.Repo.Create(ctx, &domain.TeamMembership{TeamID: teamID, UserID: user}))
+	s.Require().NoError(s.Repo.Create(ctx, &domain.TeamMembership{TeamID: otherTeamID, UserID: user}))
+
+	err := s.Repo.DeleteByTeamID(ctx, teamID)
+	s.Require().NoError(err)
+
+	memberships, err := s.Repo.FindByTeamID(ctx, teamID)
+	s.Require().NoError(err)
+	s.Empty(memberships)
+
+	// Memberships of other teams are kept
+	memberships, err = s.Repo.FindByUserID(ctx, user)
+	s.Require().NoError(err)
+	s.Require().Len(memberships, 1)
+	s.Equal(otherTeamID, memberships[0].TeamID)
+}
@@ -0,0 +1,84 @@
+package testsuite
+
+import (
+	"context"
+	"strings"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+	"github.com/satetsu888/agentrace/server/internal/repository"
+	"github.com/stretchr/testify/suite"
+)
+
+// TeamRepositorySuite tests TeamRepository implementations
+type TeamRepositorySuite struct {
+	suite.Suite
+	Repo    repository.TeamRepository
+	Cleanup func()
+}
+
+func (s *TeamRepositorySuite) TearDownTest() {
+	if s.Cleanup != nil {
+		s.Cleanup()
+	}
+}
+
+func (s *TeamRepositorySuite) TestCreate() {
+	ctx := context.Background()
+
+	team := &domain.Team{Name: "create-team"}
+	err := s.Repo.Create(ctx, team)
+	s.Require().NoError(err)
+
+	// ID should be auto-generated
+	s.NotEmpty(team.ID)
+
+	// CreatedAt should be set
+	s.False(team.CreatedAt.IsZero())
+
+	found, err := s.Repo.FindByID(ctx, team.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(found)
+	s.Equal("create-team", found.Name)
+}
+
+func (s *TeamRepositorySuite) TestFindByID_NotFound() {
+	ctx := context.Background()
+
+	found, err := s.Repo.FindByID(ctx, "00000000-0000-0000-0000-00000000dead")
+	s.NoError(err)
+	s.Nil(found)
+}
+
+func (s *TeamRepositorySuite) TestFindAll() {
+	ctx := context.Background()
+
+	for _, name := range []string{"find-all-b", "find-all-c", "find-all-a"} {
+		s.Require().NoError(s.Repo.Create(ctx, &domain.Team{Name: name}))
+	}
+
+	teams, err := s.Repo.FindAll(ctx)
+	s.Require().NoError(err)
+
+	// Sorted by name
+	var names []string
+	for _, t := range teams {
+		if strings.HasPrefix(t.Name, "find-all-") {
+			names = append(names, t.Name)
+		}
+	}
+	s.Equal([]string{"find-all-a", "find-all-b", "find-all-c"}, names)
+}
+
+func (s *TeamRepositorySuite) TestDelete() {
+	ctx := context.Background()
+
+	team := &domain.Team{Name: "delete-team"}
+	s.Require().NoError(s.Repo.Create(ctx, team))
+
+	err := s.Repo.Delete(ctx, team.ID)
+	s.Require().NoError(err)
+
+	found, err := s.Repo.FindByID(ctx, team.ID)
+	s.NoError(err)
+	s.Nil(found)
+}
@@ -0,0 +1,194 @@
+package testsuite
+
+import (
+	"context"
+	"time"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+	"github.com/satetsu888/agentrace/server/internal/repository"
+	"github.com/stretchr/testify/suite"
+)
+
+// ToolCallRepositorySuite tests ToolCallRepository implementations
+type ToolCallRepositorySuite struct {
+	suite.Suite
+	Repo        repository.ToolCallRepository
+	SessionRepo repository.SessionRepository // Optional: for FK constraint support
+	Cleanup     func()
+}
+
+// createTestSession creates a session for FK constraint tests
+func (s *ToolCallRepositorySuite) createTestSession(id string) {
+	if s.SessionRepo == nil {
+		return
+	}
+	ctx := context.Background()
+	session := &domain.Session{
+		ID:              id,
+		ClaudeSessionID: "claude-" + id,
+	}
+	_ = s.SessionRepo.Create(ctx, session)
+}
+
+func (s *ToolCallRepositorySuite) TearDownTest() {
+	if s.Cleanup != nil {
+		s.Cleanup()
+	}
+}
+
+func (s *ToolCallRepositorySuite) createCall(sessionID, projectID, toolUseID, toolName string, startedAt time.Time) *domain.ToolCall {
+	call := &domain.ToolCall{
+		SessionID: sessionID,
+		ProjectID: projectID,
+		EventID:   "event-" + toolUseID,
+		ToolUseID: toolUseID,
+		ToolName:  toolName,
+		StartedAt: startedAt,
+	}
+	s.Require().NoError(s.Repo.Create(context.Background(), call))
+	return call
+}
+
+func (s *ToolCallRepositorySuite) TestCreate() {
+	ctx := context.Background()
+
+	s.createTestSession("tools-create-session")
+
+	call := &domain.ToolCall{
+		SessionID: "tools-create-session",
+		ProjectID: "tools-create-project",
+		EventID:   "event-1",
+		ToolUseID: "toolu_create",
+		ToolName:  "Bash",
+	}
+
+	err := s.Repo.Create(ctx, call)
+	s.Require().NoError(err)
+
+	// ID should be auto-generated
+	s.NotEmpty(call.ID)
+
+	// StartedAt should be set
+	s.False(call.StartedAt.IsZero())
+
+	// A call already recorded for the session is ignored
+	err = s.Repo.Create(ctx, &domain.ToolCall{
+		SessionID: "tools-create-session",
+		ProjectID: "tools-create-project",
+		EventID:   "event-2",
+		ToolUseID: "toolu_create",
+		ToolName:  "Read",
+	})
+	s.Require().NoError(err)
+
+	calls, err := s.Repo.FindBySessionID(ctx, "tools-create-session")
+	s.Require().NoError(err)
+	s.Require().Len(calls, 1)
+	s.Equal("Bash", calls[0].ToolName)
+	s.Nil(calls[0].CompletedAt)
+}
+
+func (s *ToolCallRepositorySuite) TestComplete() {
+	ctx := context.Background()
+
+	s.createTestSession("tools-complete-session")
+
+	base := time.Now().Truncate(time.Second)
+	s.createCall("tools-complete-session", "tools-complete-project", "toolu_1", "Bash", base)
+	s.createCall("tools-complete-session", "tools-complete-project", "toolu_2", "Read", base.Add(time.Second))
+
+	s.Require().NoError(s.Repo.Complete(ctx, "tools-complete-session", "toolu_1", true, base.Add(3*time.Second)))
+	// A result for an unknown call is ignored
+	s.Require().NoError(s.Repo.Complete(ctx, "tools-complete-session", "toolu_unknown", false, base))
+	// Only the first result of a call is recorded
+	s.Require().NoError(s.Repo.Complete(ctx, "tools-complete-session", "toolu_1", false, base.Add(5*time.Second)))
+
+	calls, err := s.Repo.FindBySessionID(ctx, "tools-complete-session")
+	s.Require().NoError(err)
+	s.Require().Len(calls, 2)
+
+	// Oldest first
+	s.Equal("toolu_1", calls[0].ToolUseID)
+	s.True(calls[0].IsError)
+	s.Require().NotNil(calls[0].CompletedAt)
+	s.True(calls[0].CompletedAt.Equal(base.Add(3 * time.Second)))
+	s.Equal("event-toolu_1", calls[0].EventID)
+
+	s.Equal("toolu_2", calls[1].ToolUseID)
+	s.False(calls[1].IsError)
+	s.Nil(calls[1].CompletedAt)
+}
+
+func (s *ToolCallRepositorySuite) TestFindByProjectID() {
+	ctx := context.Background()
+
+	s.createTestSession("tools-project-session-1")
+	s.createTestSession("tools-project-session-2")
+
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	s.createCall("tools-project-session-1", "tools-project-1", "toolu_1", "Bash", base)
+	s.createCall("tools-project-session-2", "tools-project-1", "toolu_2", "Read", base.Add(24*time.Hour))
+	s.createCall("tools-project-session-2", "tools-project-2", "toolu_3", "Bash", base)
+
+	calls, err := s.Repo.FindByProjectID(ctx, "tools-project-1", time.Time{}, time.Time{})
+	s.Require().NoError(err)
+	s.Require().Len(calls, 2)
+	s.Equal("toolu_1", calls[0].ToolUseID)
+	s.Equal("toolu_2", calls[1].ToolUseID)
+
+	// from is inclusive, to is exclusive
+	calls, err = s.Repo.FindByProjectID(ctx, "tools-project-1", base, base.Add(24*time.Hour))
+	s.Require().NoError(err)
+	s.Require().Len(calls, 1)
+	s.Equal("toolu_1", calls[0].ToolUseID)
+
+	calls, err = s.Repo.FindByProjectID(ctx, "tools-project-1", base.Add(time.Hour), time.Time{})
+	s.Require().NoError(err)
+	s.Require().Len(calls, 1)
+	s.Equal("toolu_2", calls[0].ToolUseID)
+}
+
+func (s *ToolCallRepositorySuite) TestUpdateProjectIDBySessionID() {
+	ctx := context.Background()
+
+	s.createTestSession("tools-move-session")
+
+	base := time.Now().Truncate(time.Second)
+	s.createCall("tools-move-session", "tools-move-project-1", "toolu_1", "Bash", base)
+	s.createCall("tools-move-session", "tools-move-project-1", "toolu_2", "Edit", base.Add(time.Second))
+
+	err := s.Repo.UpdateProjectIDBySessionID(ctx, "tools-move-session", "tools-move-project-2")
+	s.Require().NoError(err)
+
+	calls, err := s.Repo.FindByProjectID(ctx, "tools-move-project-1", time.Time{}, time.Time{})
+	s.Require().NoError(err)
+	s.Empty(calls)
+
+	calls, err = s.Repo.FindByProjectID(ctx, "tools-move-project-2", time.Time{}, time.Time{})
+	s.Require().NoError(err)
+	s.Len(calls, 2)
+}
+
+func (s *ToolCallRepositorySuite) TestDeleteBySessionID() {
+	ctx := context.Background()
+
+	s.createTestSession("tools-delete-session-1")
+	s.createTestSession("tools-delete-session-2")
+
+	base := time.Now().Truncate(time.Second)
+	s.createCall("tools-delete-session-1", "tools-delete-project", "toolu_delete_1", "Bash", base)
+	s.createCall("tools-delete-session-1", "tools-delete-project", "toolu_delete_2", "Read", base.Add(time.Second))
+	s.createCall("tools-delete-session-2", "tools-delete-project", "toolu_delete_3", "Bash", base.Add(2*time.Second))
+
+	err := s.Repo.DeleteBySessionID(ctx, "tools-delete-session-1")
+	s.Require().NoError(err)
+
+	calls, err := s.Repo.FindBySessionID(ctx, "tools-delete-session-1")
+	s.Require().NoError(err)
+	s.Empty(calls)
+
+	calls, err = s.Repo.FindByProjectID(ctx, "tools-delete-project", time.Time{}, time.Time{})
+	s.Require().NoError(err)
+	s.Require().Len(calls, 1)
+	s.Equal("toolu_delete_3", calls[0].ToolUseID)
+}
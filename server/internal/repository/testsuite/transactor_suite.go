@@ -0,0 +1,93 @@
+package testsuite
+
+import (
+	"context"
+	"errors"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+	"github.com/satetsu888/agentrace/server/internal/repository"
+	"github.com/stretchr/testify/suite"
+)
+
+// TransactorSuite tests Transactor implementations of backends with transactions
+type TransactorSuite struct {
+	suite.Suite
+	Tx          repository.Transactor
+	SessionRepo repository.SessionRepository
+	Cleanup     func()
+}
+
+func (s *TransactorSuite) TearDownTest() {
+	if s.Cleanup != nil {
+		s.Cleanup()
+	}
+}
+
+func (s *TransactorSuite) createSession(ctx context.Context, id string) error {
+	return s.SessionRepo.Create(ctx, &domain.Session{
+		ID:              id,
+		ClaudeSessionID: "claude-" + id,
+	})
+}
+
+func (s *TransactorSuite) TestRunInTx_Commit() {
+	ctx := context.Background()
+
+	err := s.Tx.RunInTx(ctx, func(ctx context.Context) error {
+		if err := s.createSession(ctx, "tx-commit-1"); err != nil {
+			return err
+		}
+		// Reads in the transaction see its writes
+		session, err := s.SessionRepo.FindByID(ctx, "tx-commit-1")
+		if err != nil {
+			return err
+		}
+		s.NotNil(session)
+		return s.createSession(ctx, "tx-commit-2")
+	})
+	s.Require().NoError(err)
+
+	for _, id := range []string{"tx-commit-1", "tx-commit-2"} {
+		session, err := s.SessionRepo.FindByID(ctx, id)
+		s.Require().NoError(err)
+		s.NotNil(session)
+	}
+}
+
+func (s *TransactorSuite) TestRunInTx_Rollback() {
+	ctx := context.Background()
+	errFailed := errors.New("failed")
+
+	err := s.Tx.RunInTx(ctx, func(ctx context.Context) error {
+		if err := s.createSession(ctx, "tx-rollback"); err != nil {
+			return err
+		}
+		return errFailed
+	})
+	s.ErrorIs(err, errFailed)
+
+	session, err := s.SessionRepo.FindByID(ctx, "tx-rollback")
+	s.Require().NoError(err)
+	s.Nil(session)
+}
+
+func (s *TransactorSuite) TestRunInTx_Nested() {
+	ctx := context.Background()
+	errFailed := errors.New("failed")
+
+	// A nested call joins the outer transaction, so the outer failure discards its writes
+	err := s.Tx.RunInTx(ctx, func(ctx context.Context) error {
+		err := s.Tx.RunInTx(ctx, func(ctx context.Context) error {
+			return s.createSession(ctx, "tx-nested")
+		})
+		if err != nil {
+			return err
+		}
+		return errFailed
+	})
+	s.ErrorIs(err, errFailed)
+
+	session, err := s.SessionRepo.FindByID(ctx, "tx-nested")
+	s.Require().NoError(err)
+	s.Nil(session)
+}
@@ -37,6 +37,9 @@ func (s *UserRepositorySuite) TestCreate() {
 
 	// CreatedAt should be set
 	s.False(user.CreatedAt.IsZero())
+
+	// Role should default to member
+	s.Equal(domain.DefaultUserRole, user.Role)
 }
 
 func (s *UserRepositorySuite) TestCreate_WithID() {
@@ -140,3 +143,68 @@ func (s *UserRepositorySuite) TestUpdateDisplayName() {
 	s.Require().NoError(err)
 	s.Equal("Updated Name", found.DisplayName)
 }
+
+func (s *UserRepositorySuite) TestUpdateDefaultSessionVisibility() {
+	ctx := context.Background()
+
+	user := &domain.User{Email: "default-visibility@example.com"}
+	err := s.Repo.Create(ctx, user)
+	s.Require().NoError(err)
+
+	found, err := s.Repo.FindByID(ctx, user.ID)
+	s.Require().NoError(err)
+	s.Empty(found.DefaultSessionVisibility)
+
+	err = s.Repo.UpdateDefaultSessionVisibility(ctx, user.ID, domain.SessionVisibilityTeam)
+	s.Require().NoError(err)
+
+	found, err = s.Repo.FindByEmail(ctx, user.Email)
+	s.Require().NoError(err)
+	s.Equal(domain.SessionVisibilityTeam, found.DefaultSessionVisibility)
+}
+
+func (s *UserRepositorySuite) TestUpdateRole() {
+	ctx := context.Background()
+
+	user := &domain.User{Email: "role@example.com"}
+	err := s.Repo.Create(ctx, user)
+	s.Require().NoError(err)
+
+	found, err := s.Repo.FindByID(ctx, user.ID)
+	s.Require().NoError(err)
+	s.Equal(domain.UserRoleMember, found.Role)
+
+	err = s.Repo.UpdateRole(ctx, user.ID, domain.UserRoleAdmin)
+	s.Require().NoError(err)
+
+	found, err = s.Repo.FindByEmail(ctx, user.Email)
+	s.Require().NoError(err)
+	s.Equal(domain.UserRoleAdmin, found.Role)
+}
+
+func (s *UserRepositorySuite) TestUpdateDisabled() {
+	ctx := context.Background()
+
+	user := &domain.User{Email: "disabled@example.com", Role: domain.UserRoleViewer}
+	err := s.Repo.Create(ctx, user)
+	s.Require().NoError(err)
+
+	found, err := s.Repo.FindByID(ctx, user.ID)
+	s.Require().NoError(err)
+	s.Equal(domain.UserRoleViewer, found.Role)
+	s.False(found.Disabled)
+
+	err = s.Repo.UpdateDisabled(ctx, user.ID, true)
+	s.Require().NoError(err)
+
+	found, err = s.Repo.FindByID(ctx, user.ID)
+	s.Require().NoError(err)
+	s.True(found.Disabled)
+
+	err = s.Repo.UpdateDisabled(ctx, user.ID, false)
+	s.Require().NoError(err)
+
+	found, err = s.Repo.FindByID(ctx, user.ID)
+	s.Require().NoError(err)
+	s.False(found.Disabled)
+}
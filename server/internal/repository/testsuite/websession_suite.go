@@ -145,3 +145,40 @@ func (s *WebSessionRepositorySuite) TestDeleteExpired() {
 	s.Require().NoError(err)
 	s.NotNil(found)
 }
+
+func (s *WebSessionRepositorySuite) TestDeleteByUserID() {
+	ctx := context.Background()
+
+	s.createTestUser("user-6")
+	s.createTestUser("user-7")
+
+	for _, token := range []string{"user-6-token-1", "user-6-token-2"} {
+		err := s.Repo.Create(ctx, &domain.WebSession{
+			UserID:    "user-6",
+			Token:     token,
+			ExpiresAt: time.Now().Add(7 * 24 * time.Hour),
+		})
+		s.Require().NoError(err)
+	}
+	err := s.Repo.Create(ctx, &domain.WebSession{
+		UserID:    "user-7",
+		Token:     "user-7-token",
+		ExpiresAt: time.Now().Add(7 * 24 * time.Hour),
+	})
+	s.Require().NoError(err)
+
+	err = s.Repo.DeleteByUserID(ctx, "user-6")
+	s.Require().NoError(err)
+
+	// Every session of the user should be gone
+	for _, token := range []string{"user-6-token-1", "user-6-token-2"} {
+		found, err := s.Repo.FindByToken(ctx, token)
+		s.NoError(err)
+		s.Nil(found)
+	}
+
+	// Other users stay signed in
+	found, err := s.Repo.FindByToken(ctx, "user-7-token")
+	s.Require().NoError(err)
+	s.NotNil(found)
+}
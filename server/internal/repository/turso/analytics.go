@@ -0,0 +1,143 @@
+package turso
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+// AnalyticsRepository aggregates analytics from the sessions, events and plan tables
+type AnalyticsRepository struct {
+	db *DB
+}
+
+func NewAnalyticsRepository(db *DB) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+// RecordActivity is a no-op: analytics are aggregated from the source tables
+func (r *AnalyticsRepository) RecordActivity(ctx context.Context, activity domain.AnalyticsActivity) error {
+	return nil
+}
+
+func (r *AnalyticsRepository) GetSeries(ctx context.Context, query domain.AnalyticsQuery) ([]*domain.AnalyticsBucket, error) {
+	series := domain.NewAnalyticsSeries(query)
+	from := series.From().Format("2006-01-02")
+	to := query.To.UTC().Format("2006-01-02")
+
+	// Sessions started
+	q, args := analyticsFilters(
+		`SELECT date(s.started_at) AS day, COUNT(*) FROM sessions s
+		 WHERE date(s.started_at) >= ? AND date(s.started_at) < ?`,
+		[]any{from, to}, query, "s.project_id", "s.user_id")
+	if err := r.addDailyCounts(ctx, series, q+" GROUP BY day", args, func(c *domain.AnalyticsCounts, n int) { c.SessionsStarted = n }); err != nil {
+		return nil, err
+	}
+
+	// Events ingested
+	q, args = analyticsFilters(
+		`SELECT date(e.created_at) AS day, COUNT(*) FROM events e
+		 JOIN sessions s ON s.id = e.session_id
+		 WHERE date(e.created_at) >= ? AND date(e.created_at) < ?`,
+		[]any{from, to}, query, "s.project_id", "s.user_id")
+	if err := r.addDailyCounts(ctx, series, q+" GROUP BY day", args, func(c *domain.AnalyticsCounts, n int) { c.EventsIngested = n }); err != nil {
+		return nil, err
+	}
+
+	// Tool invocations (tool_use blocks in message content; non-object elements are skipped)
+	q, args = analyticsFilters(
+		`SELECT date(e.created_at) AS day, COUNT(*) FROM events e
+		 JOIN sessions s ON s.id = e.session_id
+		 JOIN json_each(e.payload, '$.message.content') AS block
+		 WHERE json_extract(CASE WHEN block.type = 'object' THEN block.value END, '$.type') = 'tool_use'
+		   AND date(e.created_at) >= ? AND date(e.created_at) < ?`,
+		[]any{from, to}, query, "s.project_id", "s.user_id")
+	if err := r.addDailyCounts(ctx, series, q+" GROUP BY day", args, func(c *domain.AnalyticsCounts, n int) { c.ToolInvocations = n }); err != nil {
+		return nil, err
+	}
+
+	// Plan status transitions
+	q, args = analyticsFilters(
+		`SELECT date(pe.created_at) AS day, COUNT(*) FROM plan_document_events pe
+		 JOIN plan_documents pd ON pd.id = pe.plan_document_id
+		 WHERE pe.event_type = 'status_change'
+		   AND date(pe.created_at) >= ? AND date(pe.created_at) < ?`,
+		[]any{from, to}, query, "pd.project_id", "pe.user_id")
+	if err := r.addDailyCounts(ctx, series, q+" GROUP BY day", args, func(c *domain.AnalyticsCounts, n int) { c.PlanStatusChanges = n }); err != nil {
+		return nil, err
+	}
+
+	// Active users: distinct (day, user) pairs so that weekly buckets count each user once
+	q, args = analyticsFilters(
+		`SELECT DISTINCT date(e.created_at) AS day, s.user_id FROM events e
+		 JOIN sessions s ON s.id = e.session_id
+		 WHERE s.user_id IS NOT NULL
+		   AND date(e.created_at) >= ? AND date(e.created_at) < ?`,
+		[]any{from, to}, query, "s.project_id", "s.user_id")
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var day, userID string
+		if err := rows.Scan(&day, &userID); err != nil {
+			return nil, err
+		}
+		t, _ := time.Parse("2006-01-02", day)
+		series.AddActiveUser(t, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return series.Buckets(), nil
+}
+
+// addDailyCounts runs a (day, count) query and adds each row to the series via set
+func (r *AnalyticsRepository) addDailyCounts(ctx context.Context, series *domain.AnalyticsSeries, query string, args []any, set func(*domain.AnalyticsCounts, int)) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var day string
+		var count int
+		if err := rows.Scan(&day, &count); err != nil {
+			return err
+		}
+		t, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			continue
+		}
+		var counts domain.AnalyticsCounts
+		set(&counts, count)
+		series.Add(t, counts)
+	}
+	return rows.Err()
+}
+
+// analyticsFilters appends the project, user and excluded project filters of query to a WHERE clause
+func analyticsFilters(sqlQuery string, args []any, query domain.AnalyticsQuery, projectColumn, userColumn string) (string, []any) {
+	if query.ProjectID != "" {
+		sqlQuery += " AND " + projectColumn + " = ?"
+		args = append(args, query.ProjectID)
+	}
+	if query.UserID != "" {
+		sqlQuery += " AND " + userColumn + " = ?"
+		args = append(args, query.UserID)
+	}
+	if len(query.ExcludeProjectIDs) > 0 {
+		placeholders := make([]string, len(query.ExcludeProjectIDs))
+		for i, id := range query.ExcludeProjectIDs {
+			placeholders[i] = "?"
+			args = append(args, id)
+		}
+		sqlQuery += " AND " + projectColumn + " NOT IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+	return sqlQuery, args
+}
@@ -38,11 +38,19 @@ func (r *EventRepository) Create(ctx context.Context, event *domain.Event) error
 	if event.UUID != "" {
 		uuidValue = sql.NullString{String: event.UUID, Valid: true}
 	}
+	var parentUUID sql.NullString
+	if event.ParentUUID != "" {
+		parentUUID = sql.NullString{String: event.ParentUUID, Valid: true}
+	}
+	var raw sql.NullString
+	if event.Raw != nil {
+		raw = sql.NullString{String: string(event.Raw), Valid: true}
+	}
 
 	_, err = r.db.ExecContext(ctx,
-		`INSERT INTO events (id, session_id, uuid, event_type, payload, created_at)
-		 VALUES (?, ?, ?, ?, ?, ?)`,
-		event.ID, event.SessionID, uuidValue, event.EventType, string(payloadJSON), event.CreatedAt.Format(time.RFC3339),
+		`INSERT INTO events (id, session_id, uuid, parent_uuid, is_sidechain, event_type, payload, raw, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.ID, event.SessionID, uuidValue, parentUUID, event.IsSidechain, event.EventType, string(payloadJSON), raw, event.CreatedAt.Format(time.RFC3339),
 	)
 	if err != nil {
 		// Check for UNIQUE constraint violation (duplicate uuid)
@@ -51,14 +59,126 @@ func (r *EventRepository) Create(ctx context.Context, event *domain.Event) error
 		}
 		return err
 	}
+
+	// Index searchable text for full-text search
+	if text := event.SearchText(); text != "" {
+		_, err = r.db.ExecContext(ctx,
+			`INSERT INTO events_fts (body, event_id, session_id) VALUES (?, ?, ?)`,
+			text, event.ID, event.SessionID,
+		)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// eventBatchSize is the number of rows per INSERT statement of CreateBatch
+const eventBatchSize = 100
+
+func (r *EventRepository) CreateBatch(ctx context.Context, events []*domain.Event) (*repository.EventBatchResult, error) {
+	result := &repository.EventBatchResult{Created: make([]*domain.Event, 0, len(events))}
+	err := r.db.RunInTx(ctx, func(ctx context.Context) error {
+		for start := 0; start < len(events); start += eventBatchSize {
+			created, err := r.insertBatch(ctx, events[start:min(start+eventBatchSize, len(events))])
+			if err != nil {
+				return err
+			}
+			result.Created = append(result.Created, created...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Duplicates = len(events) - len(result.Created)
+	return result, nil
+}
+
+// insertBatch stores events with one INSERT, skipping duplicate uuids, and returns the stored ones
+func (r *EventRepository) insertBatch(ctx context.Context, events []*domain.Event) ([]*domain.Event, error) {
+	values := make([]string, 0, len(events))
+	args := make([]interface{}, 0, len(events)*9)
+	for _, event := range events {
+		if event.ID == "" {
+			event.ID = uuid.New().String()
+		}
+		if event.CreatedAt.IsZero() {
+			event.CreatedAt = time.Now()
+		}
+
+		payloadJSON, err := json.Marshal(event.Payload)
+		if err != nil {
+			return nil, err
+		}
+
+		var uuidValue sql.NullString
+		if event.UUID != "" {
+			uuidValue = sql.NullString{String: event.UUID, Valid: true}
+		}
+		var parentUUID sql.NullString
+		if event.ParentUUID != "" {
+			parentUUID = sql.NullString{String: event.ParentUUID, Valid: true}
+		}
+		var raw sql.NullString
+		if event.Raw != nil {
+			raw = sql.NullString{String: string(event.Raw), Valid: true}
+		}
+
+		values = append(values, "(?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		args = append(args, event.ID, event.SessionID, uuidValue, parentUUID, event.IsSidechain, event.EventType, string(payloadJSON), raw, event.CreatedAt.Format(time.RFC3339))
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`INSERT INTO events (id, session_id, uuid, parent_uuid, is_sidechain, event_type, payload, raw, created_at)
+		 VALUES `+strings.Join(values, ", ")+`
+		 ON CONFLICT DO NOTHING
+		 RETURNING id`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	inserted := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		inserted[id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	created := make([]*domain.Event, 0, len(inserted))
+	for _, event := range events {
+		if !inserted[event.ID] {
+			continue
+		}
+		created = append(created, event)
+
+		// Index searchable text for full-text search
+		if text := event.SearchText(); text != "" {
+			_, err = r.db.ExecContext(ctx,
+				`INSERT INTO events_fts (body, event_id, session_id) VALUES (?, ?, ?)`,
+				text, event.ID, event.SessionID,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return created, nil
+}
+
 func (r *EventRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*domain.Event, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, session_id, uuid, event_type, payload, created_at
+		`SELECT id, session_id, uuid, parent_uuid, is_sidechain, event_type, payload, raw, created_at
 		 FROM events WHERE session_id = ?
-		 ORDER BY created_at ASC`,
+		 ORDER BY created_at ASC, rowid ASC`,
 		sessionID,
 	)
 	if err != nil {
@@ -75,18 +195,18 @@ func (r *EventRepository) FindBySessionID(ctx context.Context, sessionID string)
 		events = append(events, event)
 	}
 
-	// Sort by payload.timestamp if available (like memory implementation)
-	sortEventsByPayloadTimestamp(events)
+	// created_at strings do not sort chronologically across precisions and zones
+	domain.SortEventsByTime(events)
 
 	return events, rows.Err()
 }
 
 func (r *EventRepository) scanEvent(rows *sql.Rows) (*domain.Event, error) {
 	var event domain.Event
-	var uuidValue sql.NullString
+	var uuidValue, parentUUID, raw sql.NullString
 	var payloadStr, createdAt string
 
-	err := rows.Scan(&event.ID, &event.SessionID, &uuidValue, &event.EventType, &payloadStr, &createdAt)
+	err := rows.Scan(&event.ID, &event.SessionID, &uuidValue, &parentUUID, &event.IsSidechain, &event.EventType, &payloadStr, &raw, &createdAt)
 	if err != nil {
 		return nil, err
 	}
@@ -94,6 +214,12 @@ func (r *EventRepository) scanEvent(rows *sql.Rows) (*domain.Event, error) {
 	if uuidValue.Valid {
 		event.UUID = uuidValue.String
 	}
+	if parentUUID.Valid {
+		event.ParentUUID = parentUUID.String
+	}
+	if raw.Valid {
+		event.Raw = []byte(raw.String)
+	}
 
 	if err := json.Unmarshal([]byte(payloadStr), &event.Payload); err != nil {
 		// If unmarshal fails, use empty map
@@ -105,34 +231,6 @@ func (r *EventRepository) scanEvent(rows *sql.Rows) (*domain.Event, error) {
 	return &event, nil
 }
 
-// sortEventsByPayloadTimestamp sorts events by payload.timestamp (ascending)
-// This mirrors the behavior in memory/event.go
-func sortEventsByPayloadTimestamp(events []*domain.Event) {
-	// Simple bubble sort to maintain stability
-	for i := 0; i < len(events)-1; i++ {
-		for j := 0; j < len(events)-i-1; j++ {
-			t1 := getTimestampFromPayload(events[j])
-			t2 := getTimestampFromPayload(events[j+1])
-			if t1.After(t2) {
-				events[j], events[j+1] = events[j+1], events[j]
-			}
-		}
-	}
-}
-
-func getTimestampFromPayload(e *domain.Event) time.Time {
-	if ts, ok := e.Payload["timestamp"].(string); ok {
-		if parsed, err := time.Parse(time.RFC3339, ts); err == nil {
-			return parsed
-		}
-		// Try parsing without timezone
-		if parsed, err := time.Parse("2006-01-02T15:04:05.000Z", ts); err == nil {
-			return parsed
-		}
-	}
-	return e.CreatedAt
-}
-
 func (r *EventRepository) CountBySessionID(ctx context.Context, sessionID string) (int, error) {
 	var count int
 	err := r.db.QueryRowContext(ctx,
@@ -144,3 +242,81 @@ func (r *EventRepository) CountBySessionID(ctx context.Context, sessionID string
 	}
 	return count, nil
 }
+
+func (r *EventRepository) FindSessionIDsByUUID(ctx context.Context, eventUUID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT DISTINCT session_id FROM events WHERE uuid = ?`,
+		eventUUID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessionIDs []string
+	for rows.Next() {
+		var sessionID string
+		if err := rows.Scan(&sessionID); err != nil {
+			return nil, err
+		}
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+	return sessionIDs, rows.Err()
+}
+
+// DeleteBySessionID deletes the session's events and their events_fts rows
+func (r *EventRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM events_fts WHERE session_id = ?`, sessionID); err != nil {
+		return err
+	}
+	_, err := r.db.ExecContext(ctx, `DELETE FROM events WHERE session_id = ?`, sessionID)
+	return err
+}
+
+// Search uses the events_fts FTS4 index created by the migrations shared with SQLite.
+// Each term is matched as a token prefix; results are ordered by recency.
+func (r *EventRepository) Search(ctx context.Context, query string, viewer domain.SessionViewer, limit int) ([]*domain.EventSearchMatch, error) {
+	terms := domain.ParseSearchTerms(query)
+	if len(terms) == 0 {
+		return []*domain.EventSearchMatch{}, nil
+	}
+
+	condition, viewerArgs := visibilityCondition(viewer)
+	args := append([]any{ftsQuery(terms)}, viewerArgs...)
+	args = append(args, limit)
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT events_fts.event_id, events_fts.session_id, events_fts.body, events.created_at
+		 FROM events_fts JOIN events ON events.id = events_fts.event_id
+		 WHERE events_fts MATCH ?
+		   AND events.session_id IN (SELECT id FROM sessions WHERE `+condition+`)
+		 ORDER BY events.created_at DESC
+		 LIMIT ?`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := make([]*domain.EventSearchMatch, 0)
+	for rows.Next() {
+		var match domain.EventSearchMatch
+		var createdAt string
+		if err := rows.Scan(&match.EventID, &match.SessionID, &match.Text, &createdAt); err != nil {
+			return nil, err
+		}
+		match.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		matches = append(matches, &match)
+	}
+
+	return matches, rows.Err()
+}
+
+// ftsQuery builds an FTS MATCH expression requiring every term as a quoted prefix phrase
+func ftsQuery(terms []string) string {
+	phrases := make([]string, len(terms))
+	for i, t := range terms {
+		phrases[i] = `"` + strings.ReplaceAll(t, `"`, `""`) + `*"`
+	}
+	return strings.Join(phrases, " ")
+}
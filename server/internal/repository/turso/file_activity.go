@@ -0,0 +1,117 @@
+package turso
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+type FileActivityRepository struct {
+	db *DB
+}
+
+func NewFileActivityRepository(db *DB) *FileActivityRepository {
+	return &FileActivityRepository{db: db}
+}
+
+func (r *FileActivityRepository) Create(ctx context.Context, activity *domain.FileActivity) error {
+	if activity.ID == "" {
+		activity.ID = uuid.New().String()
+	}
+	if activity.CreatedAt.IsZero() {
+		activity.CreatedAt = time.Now()
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO file_activities (id, session_id, project_id, event_id, path, operation, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		activity.ID, activity.SessionID, activity.ProjectID, activity.EventID, activity.Path, string(activity.Operation),
+		activity.CreatedAt.Format(time.RFC3339),
+	)
+	return err
+}
+
+func (r *FileActivityRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*domain.FileActivity, error) {
+	activities, err := r.query(ctx,
+		`SELECT id, session_id, project_id, event_id, path, operation, created_at
+		 FROM file_activities WHERE session_id = ?
+		 ORDER BY created_at ASC, rowid ASC`,
+		sessionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// created_at strings do not sort chronologically across precisions and zones
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].CreatedAt.Before(activities[j].CreatedAt)
+	})
+	return activities, nil
+}
+
+func (r *FileActivityRepository) FindByProjectIDAndPath(ctx context.Context, projectID, path string) ([]*domain.FileActivity, error) {
+	activities, err := r.query(ctx,
+		`SELECT id, session_id, project_id, event_id, path, operation, created_at
+		 FROM file_activities WHERE project_id = ? AND path = ?
+		 ORDER BY created_at DESC, rowid DESC`,
+		projectID, path,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].CreatedAt.After(activities[j].CreatedAt)
+	})
+	return activities, nil
+}
+
+func (r *FileActivityRepository) UpdateProjectIDBySessionID(ctx context.Context, sessionID string, projectID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE file_activities SET project_id = ? WHERE session_id = ?`,
+		projectID, sessionID,
+	)
+	return err
+}
+
+func (r *FileActivityRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM file_activities WHERE session_id = ?`, sessionID)
+	return err
+}
+
+func (r *FileActivityRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.FileActivity, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activities := make([]*domain.FileActivity, 0)
+	for rows.Next() {
+		activity, err := r.scanActivity(rows)
+		if err != nil {
+			return nil, err
+		}
+		activities = append(activities, activity)
+	}
+	return activities, rows.Err()
+}
+
+func (r *FileActivityRepository) scanActivity(rows *sql.Rows) (*domain.FileActivity, error) {
+	var activity domain.FileActivity
+	var operation, createdAt string
+
+	err := rows.Scan(&activity.ID, &activity.SessionID, &activity.ProjectID, &activity.EventID, &activity.Path, &operation, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Operation = domain.FileOperation(operation)
+	activity.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+
+	return &activity, nil
+}
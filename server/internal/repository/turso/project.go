@@ -27,16 +27,16 @@ func (r *ProjectRepository) Create(ctx context.Context, project *domain.Project)
 	}
 
 	_, err := r.db.ExecContext(ctx,
-		`INSERT INTO projects (id, canonical_git_repository, created_at)
-		 VALUES (?, ?, ?)`,
-		project.ID, project.CanonicalGitRepository, project.CreatedAt.Format(time.RFC3339),
+		`INSERT INTO projects (id, canonical_git_repository, team_id, created_at)
+		 VALUES (?, ?, ?, ?)`,
+		project.ID, project.CanonicalGitRepository, project.TeamID, project.CreatedAt.Format(time.RFC3339),
 	)
 	return err
 }
 
 func (r *ProjectRepository) FindByID(ctx context.Context, id string) (*domain.Project, error) {
 	return r.scanProject(r.db.QueryRowContext(ctx,
-		`SELECT id, canonical_git_repository, created_at
+		`SELECT id, canonical_git_repository, team_id, created_at
 		 FROM projects WHERE id = ?`,
 		id,
 	))
@@ -44,7 +44,7 @@ func (r *ProjectRepository) FindByID(ctx context.Context, id string) (*domain.Pr
 
 func (r *ProjectRepository) FindByCanonicalGitRepository(ctx context.Context, canonicalGitRepo string) (*domain.Project, error) {
 	return r.scanProject(r.db.QueryRowContext(ctx,
-		`SELECT id, canonical_git_repository, created_at
+		`SELECT id, canonical_git_repository, team_id, created_at
 		 FROM projects WHERE canonical_git_repository = ?`,
 		canonicalGitRepo,
 	))
@@ -84,7 +84,7 @@ func (r *ProjectRepository) FindOrCreateByCanonicalGitRepository(ctx context.Con
 }
 
 func (r *ProjectRepository) FindAll(ctx context.Context, limit int, cursor string) ([]*domain.Project, string, error) {
-	query := `SELECT id, canonical_git_repository, created_at
+	query := `SELECT id, canonical_git_repository, team_id, created_at
 		 FROM projects`
 
 	var args []any
@@ -143,11 +143,38 @@ func (r *ProjectRepository) GetDefaultProject(ctx context.Context) (*domain.Proj
 	return r.FindByID(ctx, domain.DefaultProjectID)
 }
 
+func (r *ProjectRepository) FindTeamOwned(ctx context.Context) ([]*domain.Project, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, canonical_git_repository, team_id, created_at
+		 FROM projects WHERE team_id IS NOT NULL`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []*domain.Project
+	for rows.Next() {
+		project, err := r.scanProjectFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+
+	return projects, rows.Err()
+}
+
+func (r *ProjectRepository) UpdateTeamID(ctx context.Context, id string, teamID *string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE projects SET team_id = ? WHERE id = ?`, teamID, id)
+	return err
+}
+
 func (r *ProjectRepository) scanProject(row *sql.Row) (*domain.Project, error) {
 	var project domain.Project
-	var createdAt sql.NullString
+	var teamID, createdAt sql.NullString
 
-	err := row.Scan(&project.ID, &project.CanonicalGitRepository, &createdAt)
+	err := row.Scan(&project.ID, &project.CanonicalGitRepository, &teamID, &createdAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -155,6 +182,9 @@ func (r *ProjectRepository) scanProject(row *sql.Row) (*domain.Project, error) {
 		return nil, err
 	}
 
+	if teamID.Valid {
+		project.TeamID = &teamID.String
+	}
 	if createdAt.Valid {
 		project.CreatedAt, _ = time.Parse(time.RFC3339, createdAt.String)
 	}
@@ -164,13 +194,16 @@ func (r *ProjectRepository) scanProject(row *sql.Row) (*domain.Project, error) {
 
 func (r *ProjectRepository) scanProjectFromRows(rows *sql.Rows) (*domain.Project, error) {
 	var project domain.Project
-	var createdAt sql.NullString
+	var teamID, createdAt sql.NullString
 
-	err := rows.Scan(&project.ID, &project.CanonicalGitRepository, &createdAt)
+	err := rows.Scan(&project.ID, &project.CanonicalGitRepository, &teamID, &createdAt)
 	if err != nil {
 		return nil, err
 	}
 
+	if teamID.Valid {
+		project.TeamID = &teamID.String
+	}
 	if createdAt.Valid {
 		project.CreatedAt, _ = time.Parse(time.RFC3339, createdAt.String)
 	}
@@ -3,6 +3,7 @@ package turso
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -34,6 +35,9 @@ func (r *SessionRepository) Create(ctx context.Context, session *domain.Session)
 	if session.ProjectID == "" {
 		session.ProjectID = domain.DefaultProjectID
 	}
+	if session.Visibility == "" {
+		session.Visibility = domain.DefaultSessionVisibility
+	}
 
 	var endedAt *string
 	if session.EndedAt != nil {
@@ -42,18 +46,19 @@ func (r *SessionRepository) Create(ctx context.Context, session *domain.Session)
 	}
 
 	_, err := r.db.ExecContext(ctx,
-		`INSERT INTO sessions (id, user_id, project_id, claude_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		session.ID, session.UserID, session.ProjectID, session.ClaudeSessionID, session.ProjectPath,
+		`INSERT INTO sessions (id, user_id, project_id, claude_session_id, parent_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at, visibility)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		session.ID, session.UserID, session.ProjectID, session.ClaudeSessionID, session.ParentSessionID, session.ProjectPath,
 		session.GitBranch, session.Title,
-		session.StartedAt.Format(time.RFC3339), endedAt, session.UpdatedAt.Format(time.RFC3339), session.CreatedAt.Format(time.RFC3339),
+		session.StartedAt.Format(time.RFC3339), endedAt, session.UpdatedAt.Format(time.RFC3339), session.CreatedAt.Format(time.RFC3339), session.Visibility,
 	)
 	return err
 }
 
 func (r *SessionRepository) FindByID(ctx context.Context, id string) (*domain.Session, error) {
 	return r.scanSession(r.db.QueryRowContext(ctx,
-		`SELECT id, user_id, project_id, claude_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at
+		`SELECT id, user_id, project_id, claude_session_id, parent_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at, redaction_count, subagent_count,
+		       input_tokens, output_tokens, cache_read_tokens, cache_creation_tokens, estimated_cost_usd, visibility
 		 FROM sessions WHERE id = ?`,
 		id,
 	))
@@ -61,23 +66,26 @@ func (r *SessionRepository) FindByID(ctx context.Context, id string) (*domain.Se
 
 func (r *SessionRepository) FindByClaudeSessionID(ctx context.Context, claudeSessionID string) (*domain.Session, error) {
 	return r.scanSession(r.db.QueryRowContext(ctx,
-		`SELECT id, user_id, project_id, claude_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at
+		`SELECT id, user_id, project_id, claude_session_id, parent_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at, redaction_count, subagent_count,
+		       input_tokens, output_tokens, cache_read_tokens, cache_creation_tokens, estimated_cost_usd, visibility
 		 FROM sessions WHERE claude_session_id = ?`,
 		claudeSessionID,
 	))
 }
 
-func (r *SessionRepository) FindAll(ctx context.Context, limit int, cursor string, sortBy string) ([]*domain.Session, string, error) {
+func (r *SessionRepository) FindAll(ctx context.Context, viewer domain.SessionViewer, state domain.SessionState, limit int, cursor string, sortBy string) ([]*domain.Session, string, error) {
 	// Validate sortBy to prevent SQL injection
 	orderColumn := "updated_at"
 	if sortBy == "created_at" {
 		orderColumn = "created_at"
 	}
 
-	query := `SELECT id, user_id, project_id, claude_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at
-		 FROM sessions`
+	query := `SELECT id, user_id, project_id, claude_session_id, parent_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at, redaction_count, subagent_count,
+		       input_tokens, output_tokens, cache_read_tokens, cache_creation_tokens, estimated_cost_usd, visibility
+		 FROM sessions WHERE `
 
-	var args []any
+	condition, args := visibilityCondition(viewer)
+	query += condition + stateCondition(state)
 
 	// Apply cursor filter
 	if cursor != "" {
@@ -85,7 +93,7 @@ func (r *SessionRepository) FindAll(ctx context.Context, limit int, cursor strin
 		if cursorInfo != nil {
 			cursorTime, err := cursorInfo.ParseSortTime()
 			if err == nil {
-				query += ` WHERE (` + orderColumn + ` < ? OR (` + orderColumn + ` = ? AND id < ?))`
+				query += ` AND (` + orderColumn + ` < ? OR (` + orderColumn + ` = ? AND id < ?))`
 				cursorTimeStr := cursorTime.Format(time.RFC3339Nano)
 				args = append(args, cursorTimeStr, cursorTimeStr, cursorInfo.ID)
 			}
@@ -135,17 +143,20 @@ func (r *SessionRepository) FindAll(ctx context.Context, limit int, cursor strin
 	return sessions, nextCursor, nil
 }
 
-func (r *SessionRepository) FindByProjectID(ctx context.Context, projectID string, limit int, cursor string, sortBy string) ([]*domain.Session, string, error) {
+func (r *SessionRepository) FindByProjectID(ctx context.Context, projectID string, viewer domain.SessionViewer, state domain.SessionState, limit int, cursor string, sortBy string) ([]*domain.Session, string, error) {
 	// Validate sortBy to prevent SQL injection
 	orderColumn := "updated_at"
 	if sortBy == "created_at" {
 		orderColumn = "created_at"
 	}
 
-	query := `SELECT id, user_id, project_id, claude_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at
-		 FROM sessions WHERE project_id = ?`
+	query := `SELECT id, user_id, project_id, claude_session_id, parent_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at, redaction_count, subagent_count,
+		       input_tokens, output_tokens, cache_read_tokens, cache_creation_tokens, estimated_cost_usd, visibility
+		 FROM sessions WHERE project_id = ? AND `
 
-	args := []any{projectID}
+	condition, viewerArgs := visibilityCondition(viewer)
+	query += condition + stateCondition(state)
+	args := append([]any{projectID}, viewerArgs...)
 
 	// Apply cursor filter
 	if cursor != "" {
@@ -206,7 +217,8 @@ func (r *SessionRepository) FindByProjectID(ctx context.Context, projectID strin
 func (r *SessionRepository) FindOrCreateByClaudeSessionID(ctx context.Context, claudeSessionID string, userID *string) (*domain.Session, error) {
 	// First try to find existing session
 	session, err := r.scanSession(r.db.QueryRowContext(ctx,
-		`SELECT id, user_id, project_id, claude_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at
+		`SELECT id, user_id, project_id, claude_session_id, parent_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at, redaction_count, subagent_count,
+		       input_tokens, output_tokens, cache_read_tokens, cache_creation_tokens, estimated_cost_usd, visibility
 		 FROM sessions WHERE claude_session_id = ?`,
 		claudeSessionID,
 	))
@@ -247,6 +259,30 @@ func (r *SessionRepository) FindOrCreateByClaudeSessionID(ctx context.Context, c
 	return newSession, nil
 }
 
+func (r *SessionRepository) FindByParentSessionID(ctx context.Context, parentSessionID string) ([]*domain.Session, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, user_id, project_id, claude_session_id, parent_session_id, project_path, git_branch, title, started_at, ended_at, updated_at, created_at, redaction_count, subagent_count,
+		       input_tokens, output_tokens, cache_read_tokens, cache_creation_tokens, estimated_cost_usd, visibility
+		 FROM sessions WHERE parent_session_id = ?
+		 ORDER BY started_at ASC, id ASC`,
+		parentSessionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*domain.Session
+	for rows.Next() {
+		session, err := r.scanSessionFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
 func (r *SessionRepository) UpdateUserID(ctx context.Context, id string, userID string) error {
 	_, err := r.db.ExecContext(ctx,
 		`UPDATE sessions SET user_id = ? WHERE id = ?`,
@@ -295,11 +331,111 @@ func (r *SessionRepository) UpdateUpdatedAt(ctx context.Context, id string, upda
 	return err
 }
 
+func (r *SessionRepository) UpdateStartedAt(ctx context.Context, id string, startedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET started_at = ? WHERE id = ?`,
+		startedAt.Format(time.RFC3339), id,
+	)
+	return err
+}
+
+func (r *SessionRepository) UpdateParentSessionID(ctx context.Context, id string, parentSessionID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET parent_session_id = ? WHERE id = ?`,
+		parentSessionID, id,
+	)
+	return err
+}
+
+func (r *SessionRepository) UpdateVisibility(ctx context.Context, id string, visibility domain.SessionVisibility) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET visibility = ? WHERE id = ?`,
+		visibility, id,
+	)
+	return err
+}
+
+func (r *SessionRepository) MarkEnded(ctx context.Context, id string, endedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET ended_at = ? WHERE id = ?`,
+		endedAt.Format(time.RFC3339), id,
+	)
+	return err
+}
+
+func (r *SessionRepository) AddRedactionCount(ctx context.Context, id string, count int) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET redaction_count = redaction_count + ? WHERE id = ?`,
+		count, id,
+	)
+	return err
+}
+
+func (r *SessionRepository) AddSubagentCount(ctx context.Context, id string, count int) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET subagent_count = subagent_count + ? WHERE id = ?`,
+		count, id,
+	)
+	return err
+}
+
+func (r *SessionRepository) AddUsage(ctx context.Context, id string, usage domain.TokenUsage) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET input_tokens = input_tokens + ?, output_tokens = output_tokens + ?,
+		 cache_read_tokens = cache_read_tokens + ?, cache_creation_tokens = cache_creation_tokens + ?,
+		 estimated_cost_usd = estimated_cost_usd + ? WHERE id = ?`,
+		usage.InputTokens, usage.OutputTokens, usage.CacheReadTokens, usage.CacheCreationTokens, usage.EstimatedCostUSD, id,
+	)
+	return err
+}
+
+func (r *SessionRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM sessions WHERE id = ?`, id)
+	return err
+}
+
+// visibilityCondition returns the WHERE condition limiting sessions to those
+// the viewer can read
+func visibilityCondition(viewer domain.SessionViewer) (string, []any) {
+	condition := `visibility = 'public'`
+	if viewer.UserID != "" {
+		condition = `visibility IN ('public', 'team')`
+	}
+	var args []any
+	if len(viewer.HiddenProjectIDs) > 0 {
+		placeholders := make([]string, len(viewer.HiddenProjectIDs))
+		for i, id := range viewer.HiddenProjectIDs {
+			placeholders[i] = "?"
+			args = append(args, id)
+		}
+		condition += ` AND project_id NOT IN (` + strings.Join(placeholders, ", ") + `)`
+	}
+	if viewer.UserID != "" {
+		// Owners always read their own sessions, even in projects of teams they are not in
+		condition = `(user_id = ? OR (` + condition + `))`
+		args = append([]any{viewer.UserID}, args...)
+	}
+	return condition, args
+}
+
+// stateCondition returns the WHERE condition (with a leading AND) limiting
+// sessions to the given state, matching domain.Session.State
+func stateCondition(state domain.SessionState) string {
+	switch state {
+	case domain.SessionStateEnded:
+		return ` AND ended_at IS NOT NULL AND updated_at <= ended_at`
+	case domain.SessionStateActive:
+		return ` AND (ended_at IS NULL OR updated_at > ended_at)`
+	}
+	return ""
+}
+
 func (r *SessionRepository) scanSession(row *sql.Row) (*domain.Session, error) {
 	var session domain.Session
-	var userID, projectID, projectPath, gitBranch, title, startedAt, endedAt, updatedAt, createdAt sql.NullString
+	var userID, projectID, parentSessionID, projectPath, gitBranch, title, startedAt, endedAt, updatedAt, createdAt sql.NullString
 
-	err := row.Scan(&session.ID, &userID, &projectID, &session.ClaudeSessionID, &projectPath, &gitBranch, &title, &startedAt, &endedAt, &updatedAt, &createdAt)
+	err := row.Scan(&session.ID, &userID, &projectID, &session.ClaudeSessionID, &parentSessionID, &projectPath, &gitBranch, &title, &startedAt, &endedAt, &updatedAt, &createdAt, &session.RedactionCount, &session.SubagentCount,
+		&session.Usage.InputTokens, &session.Usage.OutputTokens, &session.Usage.CacheReadTokens, &session.Usage.CacheCreationTokens, &session.Usage.EstimatedCostUSD, &session.Visibility)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -310,6 +446,9 @@ func (r *SessionRepository) scanSession(row *sql.Row) (*domain.Session, error) {
 	if userID.Valid {
 		session.UserID = &userID.String
 	}
+	if parentSessionID.Valid {
+		session.ParentSessionID = &parentSessionID.String
+	}
 	if projectID.Valid {
 		session.ProjectID = projectID.String
 	} else {
@@ -343,9 +482,10 @@ func (r *SessionRepository) scanSession(row *sql.Row) (*domain.Session, error) {
 
 func (r *SessionRepository) scanSessionFromRows(rows *sql.Rows) (*domain.Session, error) {
 	var session domain.Session
-	var userID, projectID, projectPath, gitBranch, title, startedAt, endedAt, updatedAt, createdAt sql.NullString
+	var userID, projectID, parentSessionID, projectPath, gitBranch, title, startedAt, endedAt, updatedAt, createdAt sql.NullString
 
-	err := rows.Scan(&session.ID, &userID, &projectID, &session.ClaudeSessionID, &projectPath, &gitBranch, &title, &startedAt, &endedAt, &updatedAt, &createdAt)
+	err := rows.Scan(&session.ID, &userID, &projectID, &session.ClaudeSessionID, &parentSessionID, &projectPath, &gitBranch, &title, &startedAt, &endedAt, &updatedAt, &createdAt, &session.RedactionCount, &session.SubagentCount,
+		&session.Usage.InputTokens, &session.Usage.OutputTokens, &session.Usage.CacheReadTokens, &session.Usage.CacheCreationTokens, &session.Usage.EstimatedCostUSD, &session.Visibility)
 	if err != nil {
 		return nil, err
 	}
@@ -353,6 +493,9 @@ func (r *SessionRepository) scanSessionFromRows(rows *sql.Rows) (*domain.Session
 	if userID.Valid {
 		session.UserID = &userID.String
 	}
+	if parentSessionID.Valid {
+		session.ParentSessionID = &parentSessionID.String
+	}
 	if projectID.Valid {
 		session.ProjectID = projectID.String
 	} else {
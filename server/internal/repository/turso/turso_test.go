@@ -73,7 +73,8 @@ func TestProjectRepository(t *testing.T) {
 	defer cleanup()
 
 	s := &testsuite.ProjectRepositorySuite{
-		Repo: NewProjectRepository(db),
+		Repo:     NewProjectRepository(db),
+		TeamRepo: NewTeamRepository(db),
 	}
 	suite.Run(t, s)
 }
@@ -189,3 +190,25 @@ func TestUserFavoriteRepository(t *testing.T) {
 	}
 	suite.Run(t, s)
 }
+
+func TestTeamRepository(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	s := &testsuite.TeamRepositorySuite{
+		Repo: NewTeamRepository(db),
+	}
+	suite.Run(t, s)
+}
+
+func TestTeamMembershipRepository(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	s := &testsuite.TeamMembershipRepositorySuite{
+		Repo:     NewTeamMembershipRepository(db),
+		TeamRepo: NewTeamRepository(db),
+		UserRepo: NewUserRepository(db),
+	}
+	suite.Run(t, s)
+}
@@ -62,6 +62,11 @@ func (r *WebSessionRepository) Delete(ctx context.Context, id string) error {
 	return err
 }
 
+func (r *WebSessionRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM web_sessions WHERE user_id = ?`, userID)
+	return err
+}
+
 func (r *WebSessionRepository) DeleteExpired(ctx context.Context) error {
 	_, err := r.db.ExecContext(ctx,
 		`DELETE FROM web_sessions WHERE expires_at < ?`,
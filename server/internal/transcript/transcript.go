@@ -0,0 +1,108 @@
+// Package transcript reads Claude Code session transcripts, the JSONL files
+// Claude Code keeps under ~/.claude/projects/<project>/<session id>.jsonl.
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Extension is the file extension of transcript files
+const Extension = ".jsonl"
+
+// SubagentPrefix starts the file names of subagent transcripts (agent-<id>.jsonl),
+// which Claude Code writes next to the transcript of the session that spawned them
+const SubagentPrefix = "agent-"
+
+// ErrNoLines is returned when a transcript has no valid lines
+var ErrNoLines = errors.New("transcript has no valid lines")
+
+// ErrNoSessionID is returned when neither the file name nor any line identifies the session
+var ErrNoSessionID = errors.New("transcript has no session id")
+
+// Transcript is a parsed transcript file
+type Transcript struct {
+	ClaudeSessionID string
+	Cwd             string            // first working directory recorded in the transcript
+	GitBranch       string            // first git branch recorded in the transcript
+	Lines           []json.RawMessage // valid lines as they appear in the file
+	SkippedLines    int               // blank lines are ignored; malformed lines are counted here
+	StartedAt       time.Time         // earliest line timestamp (zero if none)
+	EndedAt         time.Time         // latest line timestamp (zero if none)
+}
+
+// Parse reads a transcript. name is the file name; Claude Code names
+// transcripts after their session, so its stem is preferred as the session ID
+// over the sessionId of the lines (which may differ for resumed sessions).
+// Subagent transcripts take the sessionId of their lines, the parent session.
+func Parse(r io.Reader, name string) (*Transcript, error) {
+	t := &Transcript{}
+	if stem, ok := strings.CutSuffix(filepath.Base(name), Extension); ok && stem != "" && !IsSubagentFile(name) {
+		t.ClaudeSessionID = stem
+	}
+
+	// Lines can be very long (tool results, images), so read without a size limit
+	br := bufio.NewReader(r)
+	for {
+		raw, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(raw)) > 0 {
+			var line map[string]interface{}
+			if jsonErr := json.Unmarshal(raw, &line); jsonErr != nil || line == nil {
+				t.SkippedLines++
+			} else {
+				t.addLine(bytes.TrimSpace(raw), line)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(t.Lines) == 0 {
+		return nil, ErrNoLines
+	}
+	if t.ClaudeSessionID == "" {
+		return nil, ErrNoSessionID
+	}
+	return t, nil
+}
+
+// IsSubagentFile reports whether name is the file name of a subagent transcript
+func IsSubagentFile(name string) bool {
+	return strings.HasPrefix(filepath.Base(name), SubagentPrefix)
+}
+
+func (t *Transcript) addLine(raw []byte, line map[string]interface{}) {
+	t.Lines = append(t.Lines, raw)
+
+	if t.ClaudeSessionID == "" {
+		t.ClaudeSessionID, _ = line["sessionId"].(string)
+	}
+	if t.Cwd == "" {
+		t.Cwd, _ = line["cwd"].(string)
+	}
+	if t.GitBranch == "" {
+		t.GitBranch, _ = line["gitBranch"].(string)
+	}
+
+	ts, _ := line["timestamp"].(string)
+	at, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return
+	}
+	if t.StartedAt.IsZero() || at.Before(t.StartedAt) {
+		t.StartedAt = at
+	}
+	if at.After(t.EndedAt) {
+		t.EndedAt = at
+	}
+}
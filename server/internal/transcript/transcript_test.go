@@ -0,0 +1,109 @@
+package transcript
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name              string
+		fileName          string
+		input             string
+		expectedErr       error
+		expectedSessionID string
+		expectedCwd       string
+		expectedBranch    string
+		expectedLines     int
+		expectedSkipped   int
+		expectedStartedAt time.Time
+		expectedEndedAt   time.Time
+	}{
+		{
+			name:     "session from file name",
+			fileName: "/home/u/.claude/projects/-home-u-app/0b9c-session.jsonl",
+			input: `{"type":"summary","summary":"Fix build"}
+{"type":"user","sessionId":"other","cwd":"/home/u/app","gitBranch":"","timestamp":"2026-03-02T09:00:05.000Z","message":{"content":"hi"}}
+{"type":"assistant","sessionId":"other","cwd":"/home/u/app","gitBranch":"main","timestamp":"2026-03-02T09:00:01.500Z"}
+
+{"type":"assistant","timestamp":"2026-03-02T09:10:00Z"}
+`,
+			expectedSessionID: "0b9c-session",
+			expectedCwd:       "/home/u/app",
+			expectedBranch:    "main",
+			expectedLines:     4,
+			expectedStartedAt: time.Date(2026, 3, 2, 9, 0, 1, 500000000, time.UTC),
+			expectedEndedAt:   time.Date(2026, 3, 2, 9, 10, 0, 0, time.UTC),
+		},
+		{
+			name:     "subagent transcript belongs to its parent session",
+			fileName: "/home/u/.claude/projects/-home-u-app/agent-5f2a.jsonl",
+			input: `{"type":"user","sessionId":"0b9c-session","isSidechain":true,"parentUuid":null,"message":{"content":"Find the tests"}}
+`,
+			expectedSessionID: "0b9c-session",
+			expectedLines:     1,
+		},
+		{
+			name:     "session from lines, malformed and unterminated lines",
+			fileName: "upload",
+			input: `{"type":"user","sessionId":"abc"
+{"type":"user","sessionId":"abc","message":{"content":"hi"}}
+[1,2]
+{"type":"assistant","sessionId":"abc"}`,
+			expectedSessionID: "abc",
+			expectedLines:     2,
+			expectedSkipped:   2,
+		},
+		{
+			name:        "no valid lines",
+			fileName:    "session.jsonl",
+			input:       "not json\n\n",
+			expectedErr: ErrNoLines,
+		},
+		{
+			name:        "no session id",
+			fileName:    "upload",
+			input:       `{"type":"user"}`,
+			expectedErr: ErrNoSessionID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := Parse(strings.NewReader(tt.input), tt.fileName)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Fatalf("Parse() error = %v, want %v", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if tr.ClaudeSessionID != tt.expectedSessionID {
+				t.Errorf("ClaudeSessionID = %q, want %q", tr.ClaudeSessionID, tt.expectedSessionID)
+			}
+			if tr.Cwd != tt.expectedCwd {
+				t.Errorf("Cwd = %q, want %q", tr.Cwd, tt.expectedCwd)
+			}
+			if tr.GitBranch != tt.expectedBranch {
+				t.Errorf("GitBranch = %q, want %q", tr.GitBranch, tt.expectedBranch)
+			}
+			if len(tr.Lines) != tt.expectedLines {
+				t.Errorf("len(Lines) = %d, want %d", len(tr.Lines), tt.expectedLines)
+			}
+			if tr.SkippedLines != tt.expectedSkipped {
+				t.Errorf("SkippedLines = %d, want %d", tr.SkippedLines, tt.expectedSkipped)
+			}
+			if !tr.StartedAt.Equal(tt.expectedStartedAt) {
+				t.Errorf("StartedAt = %v, want %v", tr.StartedAt, tt.expectedStartedAt)
+			}
+			if !tr.EndedAt.Equal(tt.expectedEndedAt) {
+				t.Errorf("EndedAt = %v, want %v", tr.EndedAt, tt.expectedEndedAt)
+			}
+		})
+	}
+}
@@ -14,11 +14,13 @@ type DynamoDBMigration struct {
 // DynamoDBMigrations returns the list of DynamoDB migrations to be applied.
 // The actual migration functions are defined in the dynamodb package
 // to avoid circular dependencies.
-// This is a registry of versions for documentation purposes.
+// This is a registry of versions for documentation purposes; it must list the
+// same migrations as the dynamodb package registers.
 func DynamoDBMigrations() []DynamoDBMigration {
 	return []DynamoDBMigration{
-		// Migrations will be added here as they are created
-		// Example:
-		// {Version: "0.0.1", Description: "Add parent_session_id GSI for subagent support"},
+		{Version: "0.0.1", Description: "Add lookup_id GSI to api_keys for indexed API key authentication"},
+		{Version: "0.0.2", Description: "Add uuid GSI to events and parent_session_id GSI to sessions for conversation chains"},
+		{Version: "0.0.3", Description: "Promote the first registered user to admin"},
+		{Version: "0.0.4", Description: "Backfill event_uuids with the uuids of stored events for batched duplicate checks"},
 	}
 }
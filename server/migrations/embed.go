@@ -1,6 +1,7 @@
 package migrations
 
 import (
+	"database/sql"
 	_ "embed"
 )
 
@@ -13,17 +14,146 @@ var SQLiteInitialSchema string
 //go:embed postgres/initial.up.sql
 var PostgresInitialSchema string
 
+// v0.0.1: API key lookup ID
+
+//go:embed sqlite/0.0.1.sql
+var SQLiteMigration_0_0_1 string
+
+//go:embed postgres/0.0.1.up.sql
+var PostgresMigration_0_0_1 string
+
+// v0.0.2: Session redaction count
+
+//go:embed sqlite/0.0.2.sql
+var SQLiteMigration_0_0_2 string
+
+//go:embed postgres/0.0.2.up.sql
+var PostgresMigration_0_0_2 string
+
+// v0.0.3: Event full-text search
+
+//go:embed sqlite/0.0.3.sql
+var SQLiteMigration_0_0_3 string
+
+//go:embed postgres/0.0.3.up.sql
+var PostgresMigration_0_0_3 string
+
+// v0.0.4: Plan document revision
+
+//go:embed sqlite/0.0.4.sql
+var SQLiteMigration_0_0_4 string
+
+//go:embed postgres/0.0.4.up.sql
+var PostgresMigration_0_0_4 string
+
+// v0.0.5: Session token usage and estimated cost
+
+//go:embed sqlite/0.0.5.sql
+var SQLiteMigration_0_0_5 string
+
+//go:embed postgres/0.0.5.up.sql
+var PostgresMigration_0_0_5 string
+
+// v0.0.6: Subagent (sidechain) linking
+
+//go:embed sqlite/0.0.6.sql
+var SQLiteMigration_0_0_6 string
+
+//go:embed postgres/0.0.6.up.sql
+var PostgresMigration_0_0_6 string
+
+// v0.0.7: Conversation chains of resumed and forked sessions
+
+//go:embed sqlite/0.0.7.sql
+var SQLiteMigration_0_0_7 string
+
+//go:embed postgres/0.0.7.up.sql
+var PostgresMigration_0_0_7 string
+
+// v0.0.8: Files touched per session
+
+//go:embed sqlite/0.0.8.sql
+var SQLiteMigration_0_0_8 string
+
+//go:embed postgres/0.0.8.up.sql
+var PostgresMigration_0_0_8 string
+
+// v0.0.9: Tool calls paired with their results
+
+//go:embed sqlite/0.0.9.sql
+var SQLiteMigration_0_0_9 string
+
+//go:embed postgres/0.0.9.up.sql
+var PostgresMigration_0_0_9 string
+
+// v0.0.10: Session visibility and per-user default
+
+//go:embed sqlite/0.0.10.sql
+var SQLiteMigration_0_0_10 string
+
+//go:embed postgres/0.0.10.up.sql
+var PostgresMigration_0_0_10 string
+
+// v0.0.11: User roles and disabled users
+
+//go:embed sqlite/0.0.11.sql
+var SQLiteMigration_0_0_11 string
+
+//go:embed postgres/0.0.11.up.sql
+var PostgresMigration_0_0_11 string
+
+// v0.0.12: Teams and project ownership
+
+//go:embed sqlite/0.0.12.sql
+var SQLiteMigration_0_0_12 string
+
+//go:embed postgres/0.0.12.up.sql
+var PostgresMigration_0_0_12 string
+
+// v0.0.13: Scoped and expiring API keys
+
+//go:embed sqlite/0.0.13.sql
+var SQLiteMigration_0_0_13 string
+
+//go:embed postgres/0.0.13.up.sql
+var PostgresMigration_0_0_13 string
+
+// v0.0.14: Re-index event search text with Event.SearchText (see search_text.go)
+
+// v0.0.15: Keep the raw transcript line of events
+
+//go:embed sqlite/0.0.15.sql
+var SQLiteMigration_0_0_15 string
+
+//go:embed postgres/0.0.15.up.sql
+var PostgresMigration_0_0_15 string
+
 // Migration represents a single versioned migration
 type Migration struct {
 	Version string // Semantic version (e.g., "0.0.1", "0.1.0")
 	SQL     string
+	Go      func(db *sql.DB, dialect Dialect) error // Optional step run after SQL, for data that SQL cannot derive
 }
 
 // SQLiteMigrations returns all SQLite migrations with semantic versions
 // Add new migrations here as they are created
 func SQLiteMigrations() []Migration {
 	return []Migration{
-		// Example: {Version: "0.0.1", SQL: SQLiteMigration_0_0_1},
+		{Version: "0.0.1", SQL: SQLiteMigration_0_0_1},
+		{Version: "0.0.2", SQL: SQLiteMigration_0_0_2},
+		{Version: "0.0.3", SQL: SQLiteMigration_0_0_3},
+		{Version: "0.0.4", SQL: SQLiteMigration_0_0_4},
+		{Version: "0.0.5", SQL: SQLiteMigration_0_0_5},
+		{Version: "0.0.6", SQL: SQLiteMigration_0_0_6},
+		{Version: "0.0.7", SQL: SQLiteMigration_0_0_7},
+		{Version: "0.0.8", SQL: SQLiteMigration_0_0_8},
+		{Version: "0.0.9", SQL: SQLiteMigration_0_0_9},
+		{Version: "0.0.10", SQL: SQLiteMigration_0_0_10},
+		{Version: "0.0.11", SQL: SQLiteMigration_0_0_11},
+		{Version: "0.0.12", SQL: SQLiteMigration_0_0_12},
+		{Version: "0.0.13", SQL: SQLiteMigration_0_0_13},
+		{Version: "0.0.14", Go: backfillSearchText},
+		{Version: "0.0.15", SQL: SQLiteMigration_0_0_15},
 	}
 }
 
@@ -31,6 +161,20 @@ func SQLiteMigrations() []Migration {
 // Add new migrations here as they are created
 func PostgresMigrations() []Migration {
 	return []Migration{
-		// Example: {Version: "0.0.1", SQL: PostgresMigration_0_0_1},
+		{Version: "0.0.1", SQL: PostgresMigration_0_0_1},
+		{Version: "0.0.2", SQL: PostgresMigration_0_0_2},
+		{Version: "0.0.3", SQL: PostgresMigration_0_0_3},
+		{Version: "0.0.4", SQL: PostgresMigration_0_0_4},
+		{Version: "0.0.5", SQL: PostgresMigration_0_0_5},
+		{Version: "0.0.6", SQL: PostgresMigration_0_0_6},
+		{Version: "0.0.7", SQL: PostgresMigration_0_0_7},
+		{Version: "0.0.8", SQL: PostgresMigration_0_0_8},
+		{Version: "0.0.9", SQL: PostgresMigration_0_0_9},
+		{Version: "0.0.10", SQL: PostgresMigration_0_0_10},
+		{Version: "0.0.11", SQL: PostgresMigration_0_0_11},
+		{Version: "0.0.12", SQL: PostgresMigration_0_0_12},
+		{Version: "0.0.13", SQL: PostgresMigration_0_0_13},
+		{Version: "0.0.14", Go: backfillSearchText},
+		{Version: "0.0.15", SQL: PostgresMigration_0_0_15},
 	}
 }
@@ -0,0 +1,5 @@
+-- API key lookup ID (indexed lookup instead of bcrypt-scanning every key)
+-- Legacy keys keep lookup_id NULL until they are upgraded on first use
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS lookup_id VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_lookup ON api_keys(lookup_id) WHERE lookup_id IS NOT NULL;
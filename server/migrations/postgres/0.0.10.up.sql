@@ -0,0 +1,6 @@
+-- Session visibility: private (owner only), team (signed-in users) or public.
+-- Existing sessions stay public, which is how they have been served so far
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS visibility VARCHAR(16) NOT NULL DEFAULT 'public';
+
+-- Visibility of each user's new sessions; empty uses the server default
+ALTER TABLE users ADD COLUMN IF NOT EXISTS default_session_visibility VARCHAR(16) NOT NULL DEFAULT '';
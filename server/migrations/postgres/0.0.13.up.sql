@@ -0,0 +1,3 @@
+-- Scoped and expiring API keys (empty scopes = full access)
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS scopes VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,2 @@
+-- Number of secrets redacted from ingested events per session
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS redaction_count INTEGER NOT NULL DEFAULT 0;
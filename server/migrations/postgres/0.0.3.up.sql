@@ -0,0 +1,5 @@
+-- Full-text search over event text (message text, tool inputs and tool results)
+ALTER TABLE events ADD COLUMN IF NOT EXISTS search_text TEXT;
+-- Existing events are indexed by the Go backfill of 0.0.14
+
+CREATE INDEX IF NOT EXISTS idx_events_search ON events USING GIN (to_tsvector('simple', COALESCE(search_text, '')));
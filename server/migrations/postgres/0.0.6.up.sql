@@ -0,0 +1,21 @@
+-- Subagent (sidechain) linking: transcript parent line and sidechain flag per event,
+-- and the number of subagent invocations per session
+ALTER TABLE events ADD COLUMN IF NOT EXISTS parent_uuid VARCHAR(255);
+ALTER TABLE events ADD COLUMN IF NOT EXISTS is_sidechain BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS subagent_count INTEGER NOT NULL DEFAULT 0;
+
+-- Backfill from the stored transcript lines
+UPDATE events SET
+    parent_uuid = payload->>'parentUuid',
+    is_sidechain = COALESCE(payload->'isSidechain' = 'true'::jsonb, FALSE)
+WHERE payload IS NOT NULL;
+
+UPDATE sessions SET subagent_count = (
+    SELECT COUNT(*) FROM events e
+    WHERE e.session_id = sessions.id AND e.is_sidechain AND (
+        e.parent_uuid IS NULL OR EXISTS (
+            SELECT 1 FROM events p
+            WHERE p.session_id = e.session_id AND p.uuid = e.parent_uuid AND NOT p.is_sidechain
+        )
+    )
+);
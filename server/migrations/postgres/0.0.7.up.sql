@@ -0,0 +1,19 @@
+-- Conversation chains: the session a resumed or forked session continues
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS parent_session_id UUID REFERENCES sessions(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_sessions_parent ON sessions(parent_session_id);
+
+-- Transcript lines are looked up across sessions to find the session a line continues
+CREATE INDEX IF NOT EXISTS idx_events_uuid ON events(uuid);
+
+-- Backfill: link each session to the earlier session holding the line its own lines
+-- (or summaries) continue from
+UPDATE sessions SET parent_session_id = (
+    SELECT p.session_id FROM events e
+    JOIN events p ON p.uuid = COALESCE(e.parent_uuid, e.payload->>'leafUuid')
+        AND p.session_id != e.session_id
+    JOIN sessions ps ON ps.id = p.session_id AND ps.started_at < sessions.started_at
+    WHERE e.session_id = sessions.id
+    ORDER BY ps.started_at DESC
+    LIMIT 1
+)
+WHERE parent_session_id IS NULL;
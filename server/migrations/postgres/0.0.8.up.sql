@@ -0,0 +1,41 @@
+-- Files modified by Edit/MultiEdit/NotebookEdit/Write tool calls, with the path
+-- relative to the session's project path. project_id follows the session's project
+-- so the sessions that touched a file can be looked up per project
+CREATE TABLE IF NOT EXISTS file_activities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL,
+    event_id UUID NOT NULL,
+    path TEXT NOT NULL,
+    operation VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_file_activities_session ON file_activities(session_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_file_activities_project_path ON file_activities(project_id, path);
+
+-- Backfill from the tool_use blocks of the stored transcript lines
+INSERT INTO file_activities (session_id, project_id, event_id, path, operation, created_at)
+SELECT
+    t.session_id, t.project_id, t.event_id,
+    CASE WHEN t.prefix != '/' AND starts_with(t.file_path, t.prefix) AND length(t.file_path) > length(t.prefix)
+        THEN substr(t.file_path, length(t.prefix) + 1)
+        ELSE t.file_path END,
+    t.operation, t.created_at
+FROM (
+    SELECT
+        e.session_id, s.project_id, e.id AS event_id, e.created_at, b.ordinality AS block_index,
+        rtrim(COALESCE(s.project_path, ''), '/') || '/' AS prefix,
+        b.block->>'name' AS tool,
+        CASE b.block->>'name' WHEN 'NotebookEdit' THEN b.block->'input'->>'notebook_path'
+            ELSE b.block->'input'->>'file_path' END AS file_path,
+        CASE WHEN b.block->>'name' = 'Write' THEN 'write' ELSE 'edit' END AS operation
+    FROM events e
+    JOIN sessions s ON s.id = e.session_id
+    CROSS JOIN LATERAL jsonb_array_elements(
+        CASE WHEN jsonb_typeof(e.payload->'message'->'content') = 'array'
+            THEN e.payload->'message'->'content' ELSE '[]'::jsonb END
+    ) WITH ORDINALITY AS b(block, ordinality)
+    WHERE jsonb_typeof(b.block) = 'object' AND b.block->>'type' = 'tool_use'
+) t
+WHERE t.tool IN ('Edit', 'MultiEdit', 'NotebookEdit', 'Write') AND t.file_path IS NOT NULL AND t.file_path != ''
+ORDER BY t.created_at, t.event_id, t.block_index;
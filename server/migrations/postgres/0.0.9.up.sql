@@ -0,0 +1,49 @@
+-- Tool calls: each tool_use block paired with its tool_result by tool_use_id.
+-- started_at and completed_at are the times of the two transcript lines
+CREATE TABLE IF NOT EXISTS tool_calls (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL,
+    event_id UUID NOT NULL,
+    tool_use_id VARCHAR(255) NOT NULL,
+    tool_name VARCHAR(255) NOT NULL,
+    is_error BOOLEAN NOT NULL DEFAULT FALSE,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE(session_id, tool_use_id)
+);
+CREATE INDEX IF NOT EXISTS idx_tool_calls_project ON tool_calls(project_id, started_at);
+
+-- Backfill from the tool_use and tool_result blocks of the stored transcript lines
+INSERT INTO tool_calls (session_id, project_id, event_id, tool_use_id, tool_name, is_error, started_at, completed_at)
+SELECT
+    u.session_id, s.project_id, u.event_id, u.tool_use_id, u.tool_name,
+    COALESCE(r.is_error, FALSE), u.started_at, r.completed_at
+FROM (
+    SELECT
+        e.session_id, e.id AS event_id, e.created_at AS started_at,
+        b.block->>'id' AS tool_use_id,
+        COALESCE(b.block->>'name', '') AS tool_name
+    FROM events e
+    CROSS JOIN LATERAL jsonb_array_elements(
+        CASE WHEN jsonb_typeof(e.payload->'message'->'content') = 'array'
+            THEN e.payload->'message'->'content' ELSE '[]'::jsonb END
+    ) AS b(block)
+    WHERE jsonb_typeof(b.block) = 'object' AND b.block->>'type' = 'tool_use'
+) u
+JOIN sessions s ON s.id = u.session_id
+LEFT JOIN (
+    SELECT
+        e.session_id, b.block->>'tool_use_id' AS tool_use_id,
+        bool_or(COALESCE(b.block->'is_error' = 'true'::jsonb, FALSE)) AS is_error,
+        MIN(e.created_at) AS completed_at
+    FROM events e
+    CROSS JOIN LATERAL jsonb_array_elements(
+        CASE WHEN jsonb_typeof(e.payload->'message'->'content') = 'array'
+            THEN e.payload->'message'->'content' ELSE '[]'::jsonb END
+    ) AS b(block)
+    WHERE jsonb_typeof(b.block) = 'object' AND b.block->>'type' = 'tool_result'
+    GROUP BY e.session_id, b.block->>'tool_use_id'
+) r ON r.session_id = u.session_id AND r.tool_use_id = u.tool_use_id
+WHERE u.tool_use_id IS NOT NULL AND u.tool_use_id != ''
+ON CONFLICT (session_id, tool_use_id) DO NOTHING;
@@ -134,8 +134,15 @@ func (r *Runner) runVersionedMigrations() error {
 			continue
 		}
 
-		if _, err := r.db.Exec(m.SQL); err != nil {
-			return fmt.Errorf("failed to run migration %s: %w", m.Version, err)
+		if m.SQL != "" {
+			if _, err := r.db.Exec(m.SQL); err != nil {
+				return fmt.Errorf("failed to run migration %s: %w", m.Version, err)
+			}
+		}
+		if m.Go != nil {
+			if err := m.Go(r.db, r.dialect); err != nil {
+				return fmt.Errorf("failed to run migration %s: %w", m.Version, err)
+			}
 		}
 
 		if err := r.recordVersion(m.Version); err != nil {
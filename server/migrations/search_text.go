@@ -0,0 +1,81 @@
+package migrations
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/satetsu888/agentrace/server/internal/domain"
+)
+
+// searchTextBatchSize is the number of events re-indexed per query
+const searchTextBatchSize = 500
+
+// backfillSearchText rebuilds the full-text index of existing events from
+// Event.SearchText, the text new events are indexed with on insert.
+// Events are read in pages by id so that large tables are not held in memory.
+func backfillSearchText(db *sql.DB, dialect Dialect) error {
+	selectQuery := `SELECT id, session_id, payload FROM events WHERE ?1 IS NULL OR id > ?1 ORDER BY id LIMIT ?2`
+	if dialect == DialectPostgres {
+		selectQuery = `SELECT id, session_id, payload FROM events WHERE $1::uuid IS NULL OR id > $1::uuid ORDER BY id LIMIT $2`
+	}
+
+	if dialect == DialectSQLite {
+		if _, err := db.Exec(`DELETE FROM events_fts`); err != nil {
+			return err
+		}
+	}
+
+	var lastID sql.NullString
+	for {
+		events, err := searchTextBatch(db, selectQuery, lastID)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		for _, event := range events {
+			text := event.SearchText()
+			switch dialect {
+			case DialectSQLite:
+				if text == "" {
+					continue
+				}
+				_, err = db.Exec(`INSERT INTO events_fts (body, event_id, session_id) VALUES (?, ?, ?)`, text, event.ID, event.SessionID)
+			case DialectPostgres:
+				var searchText sql.NullString
+				if text != "" {
+					searchText = sql.NullString{String: text, Valid: true}
+				}
+				_, err = db.Exec(`UPDATE events SET search_text = $1 WHERE id = $2`, searchText, event.ID)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		lastID = sql.NullString{String: events[len(events)-1].ID, Valid: true}
+	}
+}
+
+// searchTextBatch reads the next page of events after lastID
+func searchTextBatch(db *sql.DB, query string, lastID sql.NullString) ([]*domain.Event, error) {
+	rows, err := db.Query(query, lastID, searchTextBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domain.Event
+	for rows.Next() {
+		var event domain.Event
+		var payload []byte
+		if err := rows.Scan(&event.ID, &event.SessionID, &payload); err != nil {
+			return nil, err
+		}
+		// Events whose payload cannot be decoded are left unindexed
+		_ = json.Unmarshal(payload, &event.Payload)
+		events = append(events, &event)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,7 @@
+-- User roles (admin, member, viewer) and disabled users
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'member';
+ALTER TABLE users ADD COLUMN disabled INTEGER NOT NULL DEFAULT 0;
+
+-- The first registered user becomes the admin
+UPDATE users SET role = 'admin'
+WHERE id = (SELECT id FROM users ORDER BY created_at ASC, id ASC LIMIT 1);
@@ -0,0 +1,3 @@
+-- Scoped and expiring API keys (empty scopes = full access)
+ALTER TABLE api_keys ADD COLUMN scopes TEXT NOT NULL DEFAULT '';
+ALTER TABLE api_keys ADD COLUMN expires_at TEXT;
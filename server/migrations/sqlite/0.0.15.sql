@@ -0,0 +1,2 @@
+-- Transcript lines as received (after redaction), so that exports reproduce them byte for byte
+ALTER TABLE events ADD COLUMN raw TEXT;
@@ -0,0 +1,2 @@
+-- Number of secrets redacted from ingested events per session
+ALTER TABLE sessions ADD COLUMN redaction_count INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,13 @@
+-- Full-text search index over event text (message text, tool inputs and tool results)
+-- FTS4 is used because go-sqlite3 only includes FTS5 with the sqlite_fts5 build tag
+-- (Turso runs the same migrations). FTS4 has no bm25, so search results are ordered by recency
+CREATE VIRTUAL TABLE IF NOT EXISTS events_fts USING fts4(
+    body,
+    event_id,
+    session_id,
+    notindexed=event_id,
+    notindexed=session_id,
+    tokenize=unicode61
+);
+
+-- Existing events are indexed by the Go backfill of 0.0.14
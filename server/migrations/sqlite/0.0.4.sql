@@ -0,0 +1,2 @@
+-- Plan document revision counter for optimistic concurrency (compare-and-swap updates)
+ALTER TABLE plan_documents ADD COLUMN revision INTEGER NOT NULL DEFAULT 1;
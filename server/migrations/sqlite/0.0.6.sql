@@ -0,0 +1,21 @@
+-- Subagent (sidechain) linking: transcript parent line and sidechain flag per event,
+-- and the number of subagent invocations per session
+ALTER TABLE events ADD COLUMN parent_uuid TEXT;
+ALTER TABLE events ADD COLUMN is_sidechain INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE sessions ADD COLUMN subagent_count INTEGER NOT NULL DEFAULT 0;
+
+-- Backfill from the stored transcript lines
+UPDATE events SET
+    parent_uuid = json_extract(payload, '$.parentUuid'),
+    is_sidechain = CASE WHEN json_extract(payload, '$.isSidechain') = 1 THEN 1 ELSE 0 END
+WHERE json_valid(payload);
+
+UPDATE sessions SET subagent_count = (
+    SELECT COUNT(*) FROM events e
+    WHERE e.session_id = sessions.id AND e.is_sidechain = 1 AND (
+        e.parent_uuid IS NULL OR EXISTS (
+            SELECT 1 FROM events p
+            WHERE p.session_id = e.session_id AND p.uuid = e.parent_uuid AND p.is_sidechain = 0
+        )
+    )
+);
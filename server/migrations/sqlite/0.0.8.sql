@@ -0,0 +1,43 @@
+-- Files modified by Edit/MultiEdit/NotebookEdit/Write tool calls, with the path
+-- relative to the session's project path. project_id follows the session's project
+-- so the sessions that touched a file can be looked up per project
+CREATE TABLE IF NOT EXISTS file_activities (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    project_id TEXT NOT NULL,
+    event_id TEXT NOT NULL,
+    path TEXT NOT NULL,
+    operation TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+CREATE INDEX IF NOT EXISTS idx_file_activities_session ON file_activities(session_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_file_activities_project_path ON file_activities(project_id, path);
+
+-- Backfill from the tool_use blocks of the stored transcript lines
+INSERT INTO file_activities (id, session_id, project_id, event_id, path, operation, created_at)
+SELECT
+    lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-'
+        || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6))),
+    t.session_id, t.project_id, t.event_id,
+    CASE WHEN t.prefix != '/' AND substr(t.file_path, 1, length(t.prefix)) = t.prefix AND length(t.file_path) > length(t.prefix)
+        THEN substr(t.file_path, length(t.prefix) + 1)
+        ELSE t.file_path END,
+    t.operation, t.created_at
+FROM (
+    SELECT
+        e.session_id, s.project_id, e.id AS event_id, e.created_at, j.id AS block_index,
+        rtrim(COALESCE(s.project_path, ''), '/') || '/' AS prefix,
+        CASE WHEN j.type = 'object' THEN json_extract(j.value, '$.name') END AS tool,
+        CASE WHEN j.type = 'object' THEN
+            CASE json_extract(j.value, '$.name') WHEN 'NotebookEdit' THEN json_extract(j.value, '$.input.notebook_path')
+                ELSE json_extract(j.value, '$.input.file_path') END
+        END AS file_path,
+        CASE WHEN j.type = 'object' AND json_extract(j.value, '$.name') = 'Write' THEN 'write' ELSE 'edit' END AS operation
+    FROM events e
+    JOIN sessions s ON s.id = e.session_id
+    JOIN json_each(CASE WHEN json_valid(e.payload) AND json_type(e.payload, '$.message.content') = 'array'
+        THEN json_extract(e.payload, '$.message.content') ELSE '[]' END) j
+    WHERE j.type = 'object' AND json_extract(j.value, '$.type') = 'tool_use'
+) t
+WHERE t.tool IN ('Edit', 'MultiEdit', 'NotebookEdit', 'Write') AND t.file_path IS NOT NULL AND t.file_path != ''
+ORDER BY t.created_at, t.event_id, t.block_index;
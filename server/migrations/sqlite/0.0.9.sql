@@ -0,0 +1,47 @@
+-- Tool calls: each tool_use block paired with its tool_result by tool_use_id.
+-- started_at and completed_at are the times of the two transcript lines
+CREATE TABLE IF NOT EXISTS tool_calls (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    project_id TEXT NOT NULL,
+    event_id TEXT NOT NULL,
+    tool_use_id TEXT NOT NULL,
+    tool_name TEXT NOT NULL,
+    is_error INTEGER NOT NULL DEFAULT 0,
+    started_at TEXT NOT NULL,
+    completed_at TEXT,
+    UNIQUE(session_id, tool_use_id)
+);
+CREATE INDEX IF NOT EXISTS idx_tool_calls_project ON tool_calls(project_id, started_at);
+
+-- Backfill from the tool_use and tool_result blocks of the stored transcript lines
+INSERT OR IGNORE INTO tool_calls (id, session_id, project_id, event_id, tool_use_id, tool_name, is_error, started_at, completed_at)
+SELECT
+    lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-'
+        || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6))),
+    u.session_id, s.project_id, u.event_id, u.tool_use_id, u.tool_name,
+    COALESCE(r.is_error, 0), u.started_at, r.completed_at
+FROM (
+    SELECT
+        e.session_id, e.id AS event_id, e.created_at AS started_at,
+        json_extract(j.value, '$.id') AS tool_use_id,
+        COALESCE(json_extract(j.value, '$.name'), '') AS tool_name
+    FROM events e
+    JOIN json_each(CASE WHEN json_valid(e.payload) AND json_type(e.payload, '$.message.content') = 'array'
+        THEN json_extract(e.payload, '$.message.content') ELSE '[]' END) j
+    WHERE CASE WHEN j.type = 'object' THEN json_extract(j.value, '$.type') END = 'tool_use'
+) u
+JOIN sessions s ON s.id = u.session_id
+LEFT JOIN (
+    SELECT
+        e.session_id, json_extract(j.value, '$.tool_use_id') AS tool_use_id,
+        MAX(CASE WHEN json_extract(j.value, '$.is_error') THEN 1 ELSE 0 END) AS is_error,
+        MIN(e.created_at) AS completed_at
+    FROM events e
+    JOIN json_each(CASE WHEN json_valid(e.payload) AND json_type(e.payload, '$.message.content') = 'array'
+        THEN json_extract(e.payload, '$.message.content') ELSE '[]' END) j
+    WHERE CASE WHEN j.type = 'object' THEN json_extract(j.value, '$.type') END = 'tool_result'
+    GROUP BY e.session_id, json_extract(j.value, '$.tool_use_id')
+) r ON r.session_id = u.session_id AND r.tool_use_id = u.tool_use_id
+WHERE u.tool_use_id IS NOT NULL AND u.tool_use_id != ''
+ORDER BY u.started_at;
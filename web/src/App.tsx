@@ -24,6 +24,7 @@ interface AuthContextType {
   user: User | null
   isLoading: boolean
   isAuthenticated: boolean
+  requireAuth: boolean
   refetch: () => Promise<void>
 }
 
@@ -40,6 +41,7 @@ export function useAuthContext() {
 function AuthProvider({ children }: { children: React.ReactNode }) {
   const [user, setUser] = useState<User | null>(null)
   const [isLoading, setIsLoading] = useState(true)
+  const [requireAuth, setRequireAuth] = useState(false)
   const queryClient = useQueryClient()
 
   const fetchUser = async () => {
@@ -57,7 +59,13 @@ function AuthProvider({ children }: { children: React.ReactNode }) {
   }
 
   useEffect(() => {
-    fetchUser()
+    // Load the server's auth config first so that reads are not attempted
+    // before we know whether they require login
+    authApi
+      .getAuthConfig()
+      .then((config) => setRequireAuth(config.require_auth))
+      .catch(() => setRequireAuth(false))
+      .finally(fetchUser)
   }, [])
 
   const refetch = async () => {
@@ -66,7 +74,7 @@ function AuthProvider({ children }: { children: React.ReactNode }) {
   }
 
   return (
-    <AuthContext.Provider value={{ user, isLoading, isAuthenticated: !!user, refetch }}>
+    <AuthContext.Provider value={{ user, isLoading, isAuthenticated: !!user, requireAuth, refetch }}>
       {children}
     </AuthContext.Provider>
   )
@@ -90,6 +98,25 @@ function ProtectedRoute({ children }: { children: React.ReactNode }) {
   return <>{children}</>
 }
 
+// ReadRoute requires login only when the server requires it for reads
+function ReadRoute({ children }: { children: React.ReactNode }) {
+  const { user, isLoading, requireAuth } = useAuthContext()
+
+  if (isLoading) {
+    return (
+      <div className="flex h-screen items-center justify-center">
+        <Spinner size="lg" />
+      </div>
+    )
+  }
+
+  if (requireAuth && !user) {
+    return <Navigate to="/login" replace />
+  }
+
+  return <>{children}</>
+}
+
 function PublicRoute({ children }: { children: React.ReactNode }) {
   const { user, isLoading } = useAuthContext()
 
@@ -129,8 +156,15 @@ export default function App() {
         {/* Setup route - handles auth internally */}
         <Route path="/setup" element={<SetupPage />} />
 
-        {/* Main routes - accessible without auth */}
-        <Route path="/" element={<Layout />}>
+        {/* Main routes - accessible without auth unless the server requires it */}
+        <Route
+          path="/"
+          element={
+            <ReadRoute>
+              <Layout />
+            </ReadRoute>
+          }
+        >
           <Route index element={<ProjectsPage />} />
           <Route path="projects/:projectId" element={<ProjectDetailPage />} />
           <Route path="projects/:projectId/sessions" element={<SessionsPage />} />
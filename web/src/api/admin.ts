@@ -0,0 +1,67 @@
+import { fetchAPI } from './client'
+import type { User, UserRole } from '@/types/auth'
+import type { Project } from '@/types/project'
+
+export async function getAdminUsers(): Promise<{ users: User[] }> {
+  return fetchAPI('/api/admin/users')
+}
+
+export interface UpdateUserParams {
+  role?: UserRole
+  disabled?: boolean
+}
+
+export async function updateUser(id: string, params: UpdateUserParams): Promise<User> {
+  const response = await fetchAPI<{ user: User }>(`/api/admin/users/${id}`, {
+    method: 'PATCH',
+    body: JSON.stringify(params),
+  })
+  return response.user
+}
+
+// Deletes all API keys of the user and signs them out everywhere
+export async function revokeUser(id: string): Promise<{ revoked_keys: number }> {
+  return fetchAPI(`/api/admin/users/${id}/revoke`, { method: 'POST' })
+}
+
+export interface Team {
+  id: string
+  name: string
+  member_ids: string[]
+  created_at: string
+}
+
+export async function getTeams(): Promise<{ teams: Team[] }> {
+  return fetchAPI('/api/admin/teams')
+}
+
+export async function createTeam(name: string): Promise<Team> {
+  return fetchAPI('/api/admin/teams', {
+    method: 'POST',
+    body: JSON.stringify({ name }),
+  })
+}
+
+// Projects of the team become visible to everyone
+export async function deleteTeam(id: string): Promise<void> {
+  return fetchAPI(`/api/admin/teams/${id}`, { method: 'DELETE' })
+}
+
+export async function addTeamMember(teamId: string, userId: string): Promise<Team> {
+  return fetchAPI(`/api/admin/teams/${teamId}/members`, {
+    method: 'POST',
+    body: JSON.stringify({ user_id: userId }),
+  })
+}
+
+export async function removeTeamMember(teamId: string, userId: string): Promise<void> {
+  return fetchAPI(`/api/admin/teams/${teamId}/members/${userId}`, { method: 'DELETE' })
+}
+
+// A null teamId makes the project visible to everyone
+export async function setProjectTeam(projectId: string, teamId: string | null): Promise<Project> {
+  return fetchAPI(`/api/admin/projects/${projectId}`, {
+    method: 'PATCH',
+    body: JSON.stringify({ team_id: teamId }),
+  })
+}
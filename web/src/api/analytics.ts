@@ -0,0 +1,21 @@
+import { fetchAPI } from './client'
+import type { AnalyticsBucketSize, AnalyticsSeries } from '@/types/analytics'
+
+interface GetAnalyticsParams {
+  bucket?: AnalyticsBucketSize
+  from?: string // YYYY-MM-DD (UTC, inclusive)
+  to?: string // YYYY-MM-DD (UTC, inclusive)
+  projectId?: string
+  userId?: string
+}
+
+export async function getAnalytics(params?: GetAnalyticsParams): Promise<AnalyticsSeries> {
+  const searchParams = new URLSearchParams()
+  if (params?.bucket) searchParams.set('bucket', params.bucket)
+  if (params?.from) searchParams.set('from', params.from)
+  if (params?.to) searchParams.set('to', params.to)
+  if (params?.projectId) searchParams.set('project_id', params.projectId)
+  if (params?.userId) searchParams.set('user_id', params.userId)
+  const query = searchParams.toString()
+  return fetchAPI(`/api/analytics${query ? `?${query}` : ''}`)
+}
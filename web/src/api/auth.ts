@@ -1,5 +1,6 @@
 import { fetchAPI } from './client'
-import type { User } from '@/types/auth'
+import type { Member, User } from '@/types/auth'
+import type { SessionVisibility } from '@/types/session'
 
 export interface RegisterParams {
   email: string
@@ -42,7 +43,8 @@ export async function getMe(): Promise<User> {
 }
 
 export interface UpdateMeParams {
-  display_name: string
+  display_name?: string
+  default_session_visibility?: SessionVisibility | ''
 }
 
 export async function updateMe(params: UpdateMeParams): Promise<User> {
@@ -53,12 +55,13 @@ export async function updateMe(params: UpdateMeParams): Promise<User> {
   return response.user
 }
 
-export async function getUsers(): Promise<{ users: User[] }> {
+export async function getUsers(): Promise<{ users: Member[] }> {
   return fetchAPI('/api/users')
 }
 
 export interface AuthConfig {
   github_enabled: boolean
+  require_auth: boolean // reading sessions, plans and projects requires login
 }
 
 export async function getAuthConfig(): Promise<AuthConfig> {
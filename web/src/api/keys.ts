@@ -1,14 +1,22 @@
 import { fetchAPI } from './client'
-import type { ApiKey } from '@/types/auth'
+import type { ApiKey, ApiKeyScope } from '@/types/auth'
 
 export async function getKeys(): Promise<{ keys: ApiKey[] }> {
   return fetchAPI('/api/keys')
 }
 
-export async function createKey(name: string): Promise<{ key: ApiKey; api_key: string }> {
+export interface CreateKeyOptions {
+  scopes?: ApiKeyScope[] // omit for full access
+  expires_at?: string // omit for a key that never expires
+}
+
+export async function createKey(
+  name: string,
+  options: CreateKeyOptions = {}
+): Promise<{ key: ApiKey; api_key: string }> {
   return fetchAPI('/api/keys', {
     method: 'POST',
-    body: JSON.stringify({ name }),
+    body: JSON.stringify({ name, ...options }),
   })
 }
 
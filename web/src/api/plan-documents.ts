@@ -83,3 +83,34 @@ export async function deletePlan(id: string): Promise<void> {
     method: 'DELETE',
   })
 }
+
+export interface PlanRevision {
+  event_id: string
+  plan_document_id: string
+  body: string
+  created_at: string
+}
+
+export async function getPlanRevision(id: string, eventId: string): Promise<PlanRevision> {
+  return fetchAPI(`/api/plans/${id}/revisions/${eventId}`)
+}
+
+export interface PlanDiff {
+  from: string
+  to: string
+  patch: string
+}
+
+// to defaults to the current body
+export async function getPlanDiff(id: string, from: string, to?: string): Promise<PlanDiff> {
+  const searchParams = new URLSearchParams({ from })
+  if (to) searchParams.set('to', to)
+  return fetchAPI(`/api/plans/${id}/diff?${searchParams.toString()}`)
+}
+
+export async function restorePlan(id: string, eventId: string, message?: string): Promise<PlanDocument> {
+  return fetchAPI(`/api/plans/${id}/restore`, {
+    method: 'POST',
+    body: JSON.stringify({ event_id: eventId, message }),
+  })
+}
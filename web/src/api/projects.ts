@@ -1,5 +1,6 @@
 import { fetchAPI } from './client'
 import type { Project } from '@/types/project'
+import type { ProjectFileSessions, ProjectTools } from '@/types/session'
 
 interface ProjectListItem extends Project {
   created_at: string
@@ -26,3 +27,21 @@ export async function getProjects(params?: GetProjectsParams): Promise<GetProjec
 export async function getProject(id: string): Promise<ProjectListItem> {
   return fetchAPI(`/api/projects/${id}`)
 }
+
+export async function getProjectFileSessions(id: string, path: string): Promise<ProjectFileSessions> {
+  const searchParams = new URLSearchParams({ path })
+  return fetchAPI(`/api/projects/${id}/files?${searchParams.toString()}`)
+}
+
+interface GetProjectToolsParams {
+  from?: string // YYYY-MM-DD, inclusive
+  to?: string // YYYY-MM-DD, inclusive
+}
+
+export async function getProjectTools(id: string, params?: GetProjectToolsParams): Promise<ProjectTools> {
+  const searchParams = new URLSearchParams()
+  if (params?.from) searchParams.set('from', params.from)
+  if (params?.to) searchParams.set('to', params.to)
+  const query = searchParams.toString()
+  return fetchAPI(`/api/projects/${id}/tools${query ? `?${query}` : ''}`)
+}
@@ -1,5 +1,5 @@
 import { fetchAPI } from './client'
-import type { Session, SessionDetail } from '@/types/session'
+import type { Session, SessionChain, SessionDetail, SessionDiff, SessionFiles, SessionTools, SessionTree, SessionVisibility } from '@/types/session'
 
 export type SortBy = 'updated_at' | 'created_at'
 
@@ -25,10 +25,47 @@ export async function getSessions(params?: GetSessionsParams): Promise<GetSessio
   return fetchAPI(`/api/sessions${query ? `?${query}` : ''}`)
 }
 
+export interface SessionSearchResult {
+  session: Session
+  event_ids: string[]
+  snippets: string[] // HTML-escaped, matches wrapped in <mark>
+}
+
+interface SearchSessionsResponse {
+  results: SessionSearchResult[]
+}
+
+export async function searchSessions(q: string, limit?: number): Promise<SearchSessionsResponse> {
+  const searchParams = new URLSearchParams({ q })
+  if (limit) searchParams.set('limit', limit.toString())
+  return fetchAPI(`/api/sessions/search?${searchParams.toString()}`)
+}
+
 export async function getSession(id: string): Promise<SessionDetail> {
   return fetchAPI(`/api/sessions/${id}`)
 }
 
+export async function getSessionTree(id: string): Promise<SessionTree> {
+  return fetchAPI(`/api/sessions/${id}/tree`)
+}
+
+export async function getSessionChain(id: string): Promise<SessionChain> {
+  return fetchAPI(`/api/sessions/${id}/chain`)
+}
+
+export async function getSessionFiles(id: string): Promise<SessionFiles> {
+  return fetchAPI(`/api/sessions/${id}/files`)
+}
+
+export async function getSessionDiff(id: string, path?: string): Promise<SessionDiff> {
+  const query = path ? `?${new URLSearchParams({ path }).toString()}` : ''
+  return fetchAPI(`/api/sessions/${id}/diff${query}`)
+}
+
+export async function getSessionTools(id: string): Promise<SessionTools> {
+  return fetchAPI(`/api/sessions/${id}/tools`)
+}
+
 export async function updateSessionTitle(id: string, title: string): Promise<Session> {
   return fetchAPI(`/api/sessions/${id}`, {
     method: 'PATCH',
@@ -39,6 +76,7 @@ export async function updateSessionTitle(id: string, title: string): Promise<Ses
 interface UpdateSessionParams {
   title?: string
   project_id?: string
+  visibility?: SessionVisibility // owner only
 }
 
 export async function updateSession(id: string, params: UpdateSessionParams): Promise<Session> {
@@ -1,10 +1,10 @@
 import { User as UserIcon, Mail, Calendar } from 'lucide-react'
 import { format } from 'date-fns'
 import { Card } from '@/components/ui/Card'
-import type { User } from '@/types/auth'
+import type { Member } from '@/types/auth'
 
 interface MemberListProps {
-  members: User[]
+  members: Member[]
 }
 
 export function MemberList({ members }: MemberListProps) {
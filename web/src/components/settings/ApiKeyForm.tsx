@@ -2,20 +2,32 @@ import { useState } from 'react'
 import { useMutation, useQueryClient } from '@tanstack/react-query'
 import { Button } from '@/components/ui/Button'
 import { Input } from '@/components/ui/Input'
+import { Select } from '@/components/ui/Select'
 import { Modal } from '@/components/ui/Modal'
 import { CopyButton } from '@/components/ui/CopyButton'
 import * as keysApi from '@/api/keys'
+import type { ApiKeyScope } from '@/types/auth'
 
 export function ApiKeyForm() {
   const [name, setName] = useState('')
+  const [scope, setScope] = useState<ApiKeyScope | ''>('')
+  const [expiresInDays, setExpiresInDays] = useState('')
   const [newApiKey, setNewApiKey] = useState<string | null>(null)
   const queryClient = useQueryClient()
 
   const createMutation = useMutation({
-    mutationFn: () => keysApi.createKey(name),
+    mutationFn: () =>
+      keysApi.createKey(name, {
+        scopes: scope ? [scope] : undefined,
+        expires_at: expiresInDays
+          ? new Date(Date.now() + Number(expiresInDays) * 24 * 60 * 60 * 1000).toISOString()
+          : undefined,
+      }),
     onSuccess: (data) => {
       setNewApiKey(data.api_key)
       setName('')
+      setScope('')
+      setExpiresInDays('')
       queryClient.invalidateQueries({ queryKey: ['keys'] })
     },
   })
@@ -42,6 +54,28 @@ export function ApiKeyForm() {
           disabled={createMutation.isPending}
           error={createMutation.error?.message}
         />
+        <Select
+          label="Access"
+          value={scope}
+          onChange={(e) => setScope(e.target.value as ApiKeyScope | '')}
+          disabled={createMutation.isPending}
+        >
+          <option value="">Full access</option>
+          <option value="ingest">Send sessions only</option>
+          <option value="plans">Read and write plans</option>
+          <option value="read">Read only</option>
+        </Select>
+        <Select
+          label="Expires"
+          value={expiresInDays}
+          onChange={(e) => setExpiresInDays(e.target.value)}
+          disabled={createMutation.isPending}
+        >
+          <option value="">Never</option>
+          <option value="30">In 30 days</option>
+          <option value="90">In 90 days</option>
+          <option value="365">In 1 year</option>
+        </Select>
         <Button
           type="submit"
           loading={createMutation.isPending}
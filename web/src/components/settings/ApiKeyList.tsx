@@ -36,13 +36,24 @@ export function ApiKeyList({ keys }: ApiKeyListProps) {
             <div className="flex items-start gap-3">
               <Laptop className="mt-0.5 h-5 w-5 flex-shrink-0 text-gray-400" />
               <div>
-                <p className="font-medium text-gray-900">{key.name}</p>
+                <p className="font-medium text-gray-900">
+                  {key.name}
+                  {key.expired && (
+                    <span className="ml-2 rounded bg-red-100 px-1.5 py-0.5 text-xs font-medium text-red-700">
+                      Expired
+                    </span>
+                  )}
+                </p>
                 <p className="mt-1 text-sm text-gray-500">
                   <code className="font-mono">{key.key_prefix}...</code>
                   {' · '}
                   {key.last_used_at
                     ? `Last used ${formatDistanceToNow(new Date(key.last_used_at), { addSuffix: true })}`
                     : 'Never used'}
+                  {key.scopes.length > 0 && ` · Scopes: ${key.scopes.join(', ')}`}
+                  {key.expires_at &&
+                    !key.expired &&
+                    ` · Expires ${formatDistanceToNow(new Date(key.expires_at), { addSuffix: true })}`}
                 </p>
               </div>
             </div>
@@ -0,0 +1,17 @@
+export type AnalyticsBucketSize = 'day' | 'week'
+
+export interface AnalyticsBucket {
+  start: string
+  sessions_started: number
+  events_ingested: number
+  active_users: number
+  tool_invocations: number
+  plan_status_changes: number
+}
+
+export interface AnalyticsSeries {
+  bucket: AnalyticsBucketSize
+  from: string
+  to: string
+  series: AnalyticsBucket[]
+}
@@ -1,14 +1,29 @@
+import type { SessionVisibility } from './session'
+
+export type UserRole = 'admin' | 'member' | 'viewer'
+
 export interface User {
   id: string
   email: string
   display_name: string
   created_at: string
+  default_session_visibility: SessionVisibility | '' // '' uses the server default
+  role: UserRole
+  disabled: boolean
 }
 
+// A user as listed on /api/users, visible to every reader
+export type Member = Pick<User, 'id' | 'email' | 'display_name' | 'created_at'>
+
+export type ApiKeyScope = 'ingest' | 'plans' | 'read'
+
 export interface ApiKey {
   id: string
   name: string
   key_prefix: string
+  scopes: ApiKeyScope[] // empty = full access
+  expires_at?: string
+  expired: boolean
   last_used_at: string | null
   created_at: string
 }
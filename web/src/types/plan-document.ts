@@ -17,6 +17,7 @@ export interface PlanDocument {
   created_at: string
   updated_at: string
   is_favorited: boolean
+  revision: number
 }
 
 export type PlanDocumentEventType = 'body_change' | 'status_change'
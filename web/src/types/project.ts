@@ -1,4 +1,5 @@
 export interface Project {
   id: string
   canonical_git_repository: string
+  team_id?: string | null // the team owning the project; null = visible to everyone
 }
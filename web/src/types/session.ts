@@ -1,22 +1,159 @@
 import type { Event } from './event'
 import type { Project } from './project'
 
+export type SessionVisibility = 'private' | 'team' | 'public'
+
 export interface Session {
   id: string
   user_id: string | null
   user_name: string | null
   project: Project | null
   claude_session_id: string
+  parent_session_id: string | null
   project_path: string
   git_branch: string
   title: string | null
   started_at: string
   ended_at: string | null
+  duration_seconds: number
+  state: 'active' | 'ended'
   updated_at: string
   event_count: number
+  redaction_count: number
+  subagent_count: number
+  usage: SessionUsage
+  visibility: SessionVisibility
   is_favorited: boolean
 }
 
+export interface SessionUsage {
+  input_tokens: number
+  output_tokens: number
+  cache_read_tokens: number
+  cache_creation_tokens: number
+  total_tokens: number
+  estimated_cost_usd: number
+}
+
 export interface SessionDetail extends Session {
   events: Event[]
 }
+
+export interface TreeTurn {
+  event: Event
+  subagents: SubagentTree[]
+}
+
+export interface SubagentTree {
+  root_uuid: string
+  turns: TreeTurn[]
+}
+
+export interface SessionTree {
+  session_id: string
+  subagent_count: number
+  turns: TreeTurn[]
+  subagents: SubagentTree[] // subagents not attached to any turn
+}
+
+export interface SessionChain {
+  session_id: string
+  root_session_id: string
+  sessions: Session[] // oldest first
+}
+
+export type FileOperation = 'edit' | 'write'
+
+export interface SessionFile {
+  path: string // relative to the session's project path when inside it
+  operations: FileOperation[]
+  count: number
+  first_touched_at: string
+  last_touched_at: string
+  event_ids: string[]
+}
+
+export interface SessionFiles {
+  session_id: string
+  project_path: string
+  files: SessionFile[]
+}
+
+export interface FileSession {
+  session: Session
+  operations: FileOperation[]
+  count: number
+  first_touched_at: string
+  last_touched_at: string
+}
+
+export interface ProjectFileSessions {
+  project_id: string
+  path: string
+  sessions: FileSession[] // most recently touched first
+}
+
+export interface DiffStep {
+  event_id: string
+  tool_use_id: string
+  tool: 'Edit' | 'MultiEdit' | 'Write'
+  created_at: string
+  diff: string // unified diff
+  exact: boolean // false when the line numbers are relative to the edited snippets
+  additions: number
+  deletions: number
+}
+
+export interface FileDiff {
+  path: string
+  created: boolean
+  exact: boolean // false when the diff joins the steps' hunks
+  diff: string // cumulative unified diff
+  additions: number
+  deletions: number
+  steps: DiffStep[]
+}
+
+export interface SessionDiff {
+  session_id: string
+  project_path: string
+  files: FileDiff[]
+}
+
+export interface ToolStats {
+  tool_name?: string // omitted for the total
+  calls: number
+  errors: number
+  pending: number // calls without a result yet
+  error_rate: number // errors / completed calls
+  avg_duration_ms: number
+  p50_duration_ms: number
+  p95_duration_ms: number
+  max_duration_ms: number
+}
+
+export interface ToolCall {
+  id: string
+  event_id: string
+  tool_use_id: string
+  tool_name: string
+  is_error: boolean
+  started_at: string
+  completed_at: string | null
+  duration_ms: number | null
+}
+
+export interface SessionTools {
+  session_id: string
+  total: ToolStats
+  tools: ToolStats[] // most called first
+  calls: ToolCall[] // oldest first
+}
+
+export interface ProjectTools {
+  project_id: string
+  from?: string
+  to?: string
+  total: ToolStats
+  tools: ToolStats[] // most called first
+}